@@ -167,6 +167,29 @@ const (
 	CmdGetFeeEstimateResponseMessage
 	CmdSubmitTransactionReplacementRequestMessage
 	CmdSubmitTransactionReplacementResponseMessage
+	CmdGetTransactionRequestMessage
+	CmdGetTransactionResponseMessage
+	CmdGetTransactionAcceptanceRequestMessage
+	CmdGetTransactionAcceptanceResponseMessage
+	CmdGetAddressTransactionsRequestMessage
+	CmdGetAddressTransactionsResponseMessage
+	CmdGetEmissionInfoRequestMessage
+	CmdGetEmissionInfoResponseMessage
+	CmdSubmitTransactionPackageRequestMessage
+	CmdSubmitTransactionPackageResponseMessage
+	CmdGetMempoolInfoRequestMessage
+	CmdGetMempoolInfoResponseMessage
+	CmdNotifyMempoolChangedRequestMessage
+	CmdNotifyMempoolChangedResponseMessage
+	CmdMempoolChangedNotificationMessage
+	CmdGetBlockCandidateStatusRequestMessage
+	CmdGetBlockCandidateStatusResponseMessage
+	CmdGetDroppedTransactionsRequestMessage
+	CmdGetDroppedTransactionsResponseMessage
+	CmdPrioritiseTransactionRequestMessage
+	CmdPrioritiseTransactionResponseMessage
+	CmdTestMempoolAcceptRequestMessage
+	CmdTestMempoolAcceptResponseMessage
 )
 
 // ProtocolMessageCommandToString maps all MessageCommands to their string representation
@@ -308,6 +331,29 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdGetFeeEstimateResponseMessage:                              "GetFeeEstimateResponse",
 	CmdSubmitTransactionReplacementRequestMessage:                 "SubmitTransactionReplacementRequest",
 	CmdSubmitTransactionReplacementResponseMessage:                "SubmitTransactionReplacementResponse",
+	CmdGetTransactionRequestMessage:                               "GetTransactionRequest",
+	CmdGetTransactionResponseMessage:                              "GetTransactionResponse",
+	CmdGetTransactionAcceptanceRequestMessage:                     "GetTransactionAcceptanceRequest",
+	CmdGetTransactionAcceptanceResponseMessage:                    "GetTransactionAcceptanceResponse",
+	CmdGetAddressTransactionsRequestMessage:                       "GetAddressTransactionsRequest",
+	CmdGetAddressTransactionsResponseMessage:                      "GetAddressTransactionsResponse",
+	CmdGetEmissionInfoRequestMessage:                              "GetEmissionInfoRequest",
+	CmdGetEmissionInfoResponseMessage:                             "GetEmissionInfoResponse",
+	CmdSubmitTransactionPackageRequestMessage:                     "SubmitTransactionPackageRequest",
+	CmdSubmitTransactionPackageResponseMessage:                    "SubmitTransactionPackageResponse",
+	CmdGetMempoolInfoRequestMessage:                               "GetMempoolInfoRequest",
+	CmdGetMempoolInfoResponseMessage:                              "GetMempoolInfoResponse",
+	CmdNotifyMempoolChangedRequestMessage:                         "NotifyMempoolChangedRequest",
+	CmdNotifyMempoolChangedResponseMessage:                        "NotifyMempoolChangedResponse",
+	CmdMempoolChangedNotificationMessage:                          "MempoolChangedNotification",
+	CmdGetBlockCandidateStatusRequestMessage:                      "GetBlockCandidateStatusRequest",
+	CmdGetBlockCandidateStatusResponseMessage:                     "GetBlockCandidateStatusResponse",
+	CmdGetDroppedTransactionsRequestMessage:                       "GetDroppedTransactionsRequest",
+	CmdGetDroppedTransactionsResponseMessage:                      "GetDroppedTransactionsResponse",
+	CmdPrioritiseTransactionRequestMessage:                        "PrioritiseTransactionRequest",
+	CmdPrioritiseTransactionResponseMessage:                       "PrioritiseTransactionResponse",
+	CmdTestMempoolAcceptRequestMessage:                            "TestMempoolAcceptRequest",
+	CmdTestMempoolAcceptResponseMessage:                           "TestMempoolAcceptResponse",
 }
 
 // Message is an interface that describes a kaspa message. A type that
@@ -6,6 +6,7 @@ package appmessage
 
 import (
 	"net"
+	"strconv"
 
 	"github.com/stokesnetwork/stokes/util/mstime"
 )
@@ -16,12 +17,21 @@ type NetAddress struct {
 	// Last time the address was seen.
 	Timestamp mstime.Time
 
-	// IP address of the peer.
+	// IP address of the peer. For addresses in overlay networks this is a
+	// placeholder derived from the overlay address - see OverlayIP.
 	IP net.IP
 
 	// Port the peer is using. This is encoded in big endian on the appmessage
 	// which differs from most everything else.
 	Port uint16
+
+	// NetworkID is the overlay network of the peer, or NetworkIDIP if the
+	// peer is reached by its IP.
+	NetworkID NetworkID
+
+	// OverlayAddress is the address of the peer in its overlay network, such as the
+	// public key of a Tor v3 onion service.
+	OverlayAddress []byte
 }
 
 // TCPAddress converts the NetAddress to *net.TCPAddr
@@ -59,6 +69,23 @@ func NewNetAddress(addr *net.TCPAddr) *NetAddress {
 	return NewNetAddressIPPort(addr.IP, uint16(addr.Port))
 }
 
+// IsOverlay returns whether the NetAddress is in an overlay network, such as Tor or I2P,
+// rather than reached by its IP.
+func (na *NetAddress) IsOverlay() bool {
+	return na.NetworkID != NetworkIDIP
+}
+
+// Host returns the host name of an address in an overlay network, or the IP of any other address
+func (na *NetAddress) Host() string {
+	if na.IsOverlay() {
+		return overlayHost(na.NetworkID, na.OverlayAddress)
+	}
+	return na.IP.String()
+}
+
 func (na NetAddress) String() string {
+	if na.IsOverlay() {
+		return net.JoinHostPort(na.Host(), strconv.Itoa(int(na.Port)))
+	}
 	return na.TCPAddress().String()
 }
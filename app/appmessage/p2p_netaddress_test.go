@@ -6,7 +6,10 @@ package appmessage
 
 import (
 	"net"
+	"strings"
 	"testing"
+
+	"github.com/stokesnetwork/stokes/util/mstime"
 )
 
 // TestNetAddress tests the NetAddress API.
@@ -26,3 +29,87 @@ func TestNetAddress(t *testing.T) {
 			port)
 	}
 }
+
+// TestOverlayNetAddress tests the encoding of Tor v3 and I2P addresses.
+func TestOverlayNetAddress(t *testing.T) {
+	const onionHost = "2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion"
+
+	if !IsOverlayHost(onionHost) || IsOverlayHost("example.com") || IsOverlayHost("127.0.0.1") {
+		t.Fatalf("IsOverlayHost: wrong result")
+	}
+	networkID, publicKey, err := DecodeOverlayHost(onionHost)
+	if err != nil {
+		t.Fatalf("DecodeOverlayHost: %s", err)
+	}
+	if networkID != NetworkIDTorV3 || len(publicKey) != OverlayAddressSize {
+		t.Fatalf("DecodeOverlayHost: got network %s and a %d byte address", networkID, len(publicKey))
+	}
+
+	na, err := NewOverlayNetAddress(mstime.Now(), networkID, publicKey, 16111)
+	if err != nil {
+		t.Fatalf("NewOverlayNetAddress: %s", err)
+	}
+	if !na.IsOverlay() {
+		t.Fatalf("IsOverlay: expected an overlay address")
+	}
+	if na.String() != onionHost+":16111" {
+		t.Fatalf("String: got %s, want %s:16111", na, onionHost)
+	}
+	if OverlayNetworkOfIP(na.IP) != NetworkIDTorV3 {
+		t.Fatalf("OverlayNetworkOfIP: got %s for the IP %s of an onion address", OverlayNetworkOfIP(na.IP), na.IP)
+	}
+	if OverlayNetworkOfIP(net.ParseIP("fd00::1")) != NetworkIDIP || OverlayNetworkOfIP(net.ParseIP("1.2.3.4")) != NetworkIDIP {
+		t.Fatalf("OverlayNetworkOfIP: expected other IPs to not be overlay IPs")
+	}
+
+	// Upper case host names decode to the same address
+	_, upperCasePublicKey, err := DecodeOverlayHost(strings.ToUpper(onionHost))
+	if err != nil || string(upperCasePublicKey) != string(publicKey) {
+		t.Fatalf("DecodeOverlayHost: upper case host decoded to %x: %v", upperCasePublicKey, err)
+	}
+
+	i2pAddress := make([]byte, OverlayAddressSize)
+	for i := range i2pAddress {
+		i2pAddress[i] = byte(i)
+	}
+	i2pNetAddress, err := NewOverlayNetAddress(mstime.Now(), NetworkIDI2P, i2pAddress, 0)
+	if err != nil {
+		t.Fatalf("NewOverlayNetAddress: %s", err)
+	}
+	if !strings.HasSuffix(i2pNetAddress.Host(), ".b32.i2p") || len(i2pNetAddress.Host()) != 52+len(".b32.i2p") {
+		t.Fatalf("Host: unexpected I2P host %s", i2pNetAddress.Host())
+	}
+	networkID, decodedI2PAddress, err := DecodeOverlayHost(i2pNetAddress.Host())
+	if err != nil || networkID != NetworkIDI2P || string(decodedI2PAddress) != string(i2pAddress) {
+		t.Fatalf("DecodeOverlayHost: I2P host decoded to %s %x: %v", networkID, decodedI2PAddress, err)
+	}
+	if OverlayNetworkOfIP(i2pNetAddress.IP) != NetworkIDI2P {
+		t.Fatalf("OverlayNetworkOfIP: got %s for the IP of an I2P address", OverlayNetworkOfIP(i2pNetAddress.IP))
+	}
+
+	invalidHosts := []string{
+		// Wrong checksum
+		"2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wia.onion",
+		// Tor v2
+		"expyuzz4wqqyqhjn.onion",
+		// Not base32
+		"2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wi1.onion",
+		"tooshort.b32.i2p",
+		"example.com",
+	}
+	for _, host := range invalidHosts {
+		_, _, err := DecodeOverlayHost(host)
+		if err == nil {
+			t.Fatalf("DecodeOverlayHost: expected an error for %s", host)
+		}
+	}
+
+	_, err = NewOverlayNetAddress(mstime.Now(), NetworkIDTorV3, publicKey[:16], 16111)
+	if err == nil {
+		t.Fatalf("NewOverlayNetAddress: expected an error for a short address")
+	}
+	_, err = NewOverlayNetAddress(mstime.Now(), 6, publicKey, 16111)
+	if err == nil {
+		t.Fatalf("NewOverlayNetAddress: expected an error for an unknown network")
+	}
+}
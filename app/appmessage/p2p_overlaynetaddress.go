@@ -0,0 +1,185 @@
+package appmessage
+
+import (
+	"encoding/base32"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/util/mstime"
+	"golang.org/x/crypto/sha3"
+)
+
+// NetworkID identifies the network of an address. The IDs of the overlay networks
+// are the ones of the address-v2 encoding of BIP 155.
+type NetworkID uint8
+
+const (
+	// NetworkIDIP is the network of addresses that are reached by their IP
+	NetworkIDIP NetworkID = 0
+
+	// NetworkIDTorV3 is the network of Tor v3 onion services. Their overlay address
+	// is the ed25519 public key of the service.
+	NetworkIDTorV3 NetworkID = 4
+
+	// NetworkIDI2P is the network of I2P destinations. Their overlay address is the
+	// SHA256 hash of the destination.
+	NetworkIDI2P NetworkID = 5
+)
+
+// OverlayAddressSize is the size of the overlay address of both Tor v3 and I2P addresses
+const OverlayAddressSize = 32
+
+func (networkID NetworkID) String() string {
+	switch networkID {
+	case NetworkIDIP:
+		return "ip"
+	case NetworkIDTorV3:
+		return "onion"
+	case NetworkIDI2P:
+		return "i2p"
+	default:
+		return "unknown"
+	}
+}
+
+// IsKnownOverlayNetwork returns whether the given network is an overlay network this node understands
+func IsKnownOverlayNetwork(networkID NetworkID) bool {
+	return networkID == NetworkIDTorV3 || networkID == NetworkIDI2P
+}
+
+// OverlayIPPrefixSize is the size of the prefix of the placeholder IPs of overlay addresses.
+// The rest of the IP is the start of the overlay address.
+const OverlayIPPrefixSize = 6
+
+// Overlay addresses are given IPs in unique local ranges - the ranges of OnionCat and GarliCat -
+// so that they can be stored, banned and told apart by IP like any other address
+var (
+	torV3IPPrefix = []byte{0xfd, 0x87, 0xd8, 0x7e, 0xeb, 0x43}
+	i2pIPPrefix   = []byte{0xfd, 0x60, 0xdb, 0x4d, 0xdd, 0xb5}
+)
+
+// OverlayIP returns the placeholder IP of the given overlay address: the prefix
+// of its network followed by the start of the address
+func OverlayIP(networkID NetworkID, overlayAddress []byte) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	switch networkID {
+	case NetworkIDTorV3:
+		copy(ip, torV3IPPrefix)
+	case NetworkIDI2P:
+		copy(ip, i2pIPPrefix)
+	}
+	copy(ip[OverlayIPPrefixSize:], overlayAddress)
+	return ip
+}
+
+// OverlayNetworkOfIP returns the overlay network the given placeholder IP belongs to,
+// or NetworkIDIP if it isn't a placeholder IP
+func OverlayNetworkOfIP(ip net.IP) NetworkID {
+	ip = ip.To16()
+	if len(ip) != net.IPv6len {
+		return NetworkIDIP
+	}
+	switch {
+	case string(ip[:OverlayIPPrefixSize]) == string(torV3IPPrefix):
+		return NetworkIDTorV3
+	case string(ip[:OverlayIPPrefixSize]) == string(i2pIPPrefix):
+		return NetworkIDI2P
+	default:
+		return NetworkIDIP
+	}
+}
+
+// NewOverlayNetAddress returns a new NetAddress of the given address in the given overlay network
+func NewOverlayNetAddress(timestamp mstime.Time, networkID NetworkID, overlayAddress []byte,
+	port uint16) (*NetAddress, error) {
+
+	if !IsKnownOverlayNetwork(networkID) {
+		return nil, errors.Errorf("unknown overlay network %d", networkID)
+	}
+	if len(overlayAddress) != OverlayAddressSize {
+		return nil, errors.Errorf("%s addresses are %d bytes long, but got %d bytes",
+			networkID, OverlayAddressSize, len(overlayAddress))
+	}
+	return &NetAddress{
+		Timestamp:      timestamp,
+		IP:             OverlayIP(networkID, overlayAddress),
+		Port:           port,
+		NetworkID:      networkID,
+		OverlayAddress: append([]byte(nil), overlayAddress...),
+	}, nil
+}
+
+const (
+	onionSuffix = ".onion"
+	i2pSuffix   = ".b32.i2p"
+
+	torV3Version        = 3
+	torV3ChecksumSize   = 2
+	torV3ChecksumPrefix = ".onion checksum"
+)
+
+var overlayHostEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// torV3Checksum returns the checksum that is part of the host name of a Tor v3 onion service
+func torV3Checksum(publicKey []byte) []byte {
+	hasher := sha3.New256()
+	hasher.Write([]byte(torV3ChecksumPrefix))
+	hasher.Write(publicKey)
+	hasher.Write([]byte{torV3Version})
+	return hasher.Sum(nil)[:torV3ChecksumSize]
+}
+
+func overlayHost(networkID NetworkID, overlayAddress []byte) string {
+	switch networkID {
+	case NetworkIDTorV3:
+		encoded := make([]byte, 0, len(overlayAddress)+torV3ChecksumSize+1)
+		encoded = append(encoded, overlayAddress...)
+		encoded = append(encoded, torV3Checksum(overlayAddress)...)
+		encoded = append(encoded, torV3Version)
+		return strings.ToLower(overlayHostEncoding.EncodeToString(encoded)) + onionSuffix
+	case NetworkIDI2P:
+		return strings.ToLower(overlayHostEncoding.EncodeToString(overlayAddress)) + i2pSuffix
+	default:
+		return ""
+	}
+}
+
+// IsOverlayHost returns whether the given host name is an address in an overlay network
+func IsOverlayHost(host string) bool {
+	host = strings.ToLower(host)
+	return strings.HasSuffix(host, onionSuffix) || strings.HasSuffix(host, i2pSuffix)
+}
+
+// DecodeOverlayHost decodes the given .onion or .b32.i2p host name into its overlay network
+// and address. Only Tor v3 onion services are supported.
+func DecodeOverlayHost(host string) (NetworkID, []byte, error) {
+	host = strings.ToLower(host)
+	switch {
+	case strings.HasSuffix(host, i2pSuffix):
+		overlayAddress, err := overlayHostEncoding.DecodeString(strings.ToUpper(strings.TrimSuffix(host, i2pSuffix)))
+		if err != nil || len(overlayAddress) != OverlayAddressSize {
+			return 0, nil, errors.Errorf("malformed I2P host %s", host)
+		}
+		return NetworkIDI2P, overlayAddress, nil
+
+	case strings.HasSuffix(host, onionSuffix):
+		decoded, err := overlayHostEncoding.DecodeString(strings.ToUpper(strings.TrimSuffix(host, onionSuffix)))
+		if err != nil || len(decoded) != OverlayAddressSize+torV3ChecksumSize+1 {
+			return 0, nil, errors.Errorf("malformed or unsupported onion host %s", host)
+		}
+		publicKey := decoded[:OverlayAddressSize]
+		checksum := decoded[OverlayAddressSize : OverlayAddressSize+torV3ChecksumSize]
+		version := decoded[OverlayAddressSize+torV3ChecksumSize]
+		if version != torV3Version {
+			return 0, nil, errors.Errorf("unsupported onion service version %d in %s", version, host)
+		}
+		if string(checksum) != string(torV3Checksum(publicKey)) {
+			return 0, nil, errors.Errorf("wrong checksum in onion host %s", host)
+		}
+		return NetworkIDTorV3, publicKey, nil
+
+	default:
+		return 0, nil, errors.Errorf("%s is not in an overlay network", host)
+	}
+}
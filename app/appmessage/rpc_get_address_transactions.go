@@ -0,0 +1,59 @@
+package appmessage
+
+// GetAddressTransactionsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetAddressTransactionsRequestMessage struct {
+	baseMessage
+	Address   string
+	PageToken string
+	Limit     uint32
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetAddressTransactionsRequestMessage) Command() MessageCommand {
+	return CmdGetAddressTransactionsRequestMessage
+}
+
+// NewGetAddressTransactionsRequestMessage returns a instance of the message
+func NewGetAddressTransactionsRequestMessage(address string, pageToken string,
+	limit uint32) *GetAddressTransactionsRequestMessage {
+
+	return &GetAddressTransactionsRequestMessage{
+		Address:   address,
+		PageToken: pageToken,
+		Limit:     limit,
+	}
+}
+
+// GetAddressTransactionsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetAddressTransactionsResponseMessage struct {
+	baseMessage
+	Transactions  []*AddressTransaction
+	NextPageToken string
+
+	Error *RPCError
+}
+
+// AddressTransaction is a transaction that credited or debited an address
+type AddressTransaction struct {
+	TransactionID     string
+	AcceptingDAAScore uint64
+	IsCredit          bool
+	IsDebit           bool
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetAddressTransactionsResponseMessage) Command() MessageCommand {
+	return CmdGetAddressTransactionsResponseMessage
+}
+
+// NewGetAddressTransactionsResponseMessage returns a instance of the message
+func NewGetAddressTransactionsResponseMessage(transactions []*AddressTransaction,
+	nextPageToken string) *GetAddressTransactionsResponseMessage {
+
+	return &GetAddressTransactionsResponseMessage{
+		Transactions:  transactions,
+		NextPageToken: nextPageToken,
+	}
+}
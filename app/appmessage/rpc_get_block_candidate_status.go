@@ -0,0 +1,51 @@
+package appmessage
+
+// GetBlockCandidateStatusRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBlockCandidateStatusRequestMessage struct {
+	baseMessage
+	TransactionID string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBlockCandidateStatusRequestMessage) Command() MessageCommand {
+	return CmdGetBlockCandidateStatusRequestMessage
+}
+
+// NewGetBlockCandidateStatusRequestMessage returns a instance of the message
+func NewGetBlockCandidateStatusRequestMessage(transactionID string) *GetBlockCandidateStatusRequestMessage {
+	return &GetBlockCandidateStatusRequestMessage{
+		TransactionID: transactionID,
+	}
+}
+
+// GetBlockCandidateStatusResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBlockCandidateStatusResponseMessage struct {
+	baseMessage
+	IsInTransactionPool bool
+	IsOrphan            bool
+	IsBlockCandidate    bool
+	Kind                string
+	Reason              string
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBlockCandidateStatusResponseMessage) Command() MessageCommand {
+	return CmdGetBlockCandidateStatusResponseMessage
+}
+
+// NewGetBlockCandidateStatusResponseMessage returns a instance of the message
+func NewGetBlockCandidateStatusResponseMessage(isInTransactionPool bool, isOrphan bool, isBlockCandidate bool,
+	kind string, reason string) *GetBlockCandidateStatusResponseMessage {
+
+	return &GetBlockCandidateStatusResponseMessage{
+		IsInTransactionPool: isInTransactionPool,
+		IsOrphan:            isOrphan,
+		IsBlockCandidate:    isBlockCandidate,
+		Kind:                kind,
+		Reason:              reason,
+	}
+}
@@ -0,0 +1,53 @@
+package appmessage
+
+// GetDroppedTransactionsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDroppedTransactionsRequestMessage struct {
+	baseMessage
+	TransactionIDs []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDroppedTransactionsRequestMessage) Command() MessageCommand {
+	return CmdGetDroppedTransactionsRequestMessage
+}
+
+// NewGetDroppedTransactionsRequestMessage returns a instance of the message
+func NewGetDroppedTransactionsRequestMessage(transactionIDs []string) *GetDroppedTransactionsRequestMessage {
+	return &GetDroppedTransactionsRequestMessage{
+		TransactionIDs: transactionIDs,
+	}
+}
+
+// GetDroppedTransactionsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDroppedTransactionsResponseMessage struct {
+	baseMessage
+	DroppedTransactions []*RPCDroppedTransaction
+
+	Error *RPCError
+}
+
+// RPCDroppedTransaction is a transaction that recently expired or was evicted
+// from the mempool
+type RPCDroppedTransaction struct {
+	TransactionID      string
+	Reason             string
+	IsOrphan           bool
+	Fee                uint64
+	Mass               uint64
+	DroppedAtTimestamp int64
+	DroppedAtDAAScore  uint64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDroppedTransactionsResponseMessage) Command() MessageCommand {
+	return CmdGetDroppedTransactionsResponseMessage
+}
+
+// NewGetDroppedTransactionsResponseMessage returns a instance of the message
+func NewGetDroppedTransactionsResponseMessage(droppedTransactions []*RPCDroppedTransaction) *GetDroppedTransactionsResponseMessage {
+	return &GetDroppedTransactionsResponseMessage{
+		DroppedTransactions: droppedTransactions,
+	}
+}
@@ -0,0 +1,53 @@
+package appmessage
+
+// GetEmissionInfoRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetEmissionInfoRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetEmissionInfoRequestMessage) Command() MessageCommand {
+	return CmdGetEmissionInfoRequestMessage
+}
+
+// NewGetEmissionInfoRequestMessage returns a instance of the message
+func NewGetEmissionInfoRequestMessage() *GetEmissionInfoRequestMessage {
+	return &GetEmissionInfoRequestMessage{}
+}
+
+// GetEmissionInfoResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetEmissionInfoResponseMessage struct {
+	baseMessage
+	VirtualDAAScore               uint64
+	CurrentEra                    uint64
+	CurrentSubsidy                uint64
+	HalvingIntervalDAAScore       uint64
+	NextHalvingDAAScore           uint64
+	NextSubsidy                   uint64
+	EstimatedSecondsToNextHalving uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetEmissionInfoResponseMessage) Command() MessageCommand {
+	return CmdGetEmissionInfoResponseMessage
+}
+
+// NewGetEmissionInfoResponseMessage returns a instance of the message
+func NewGetEmissionInfoResponseMessage(virtualDAAScore uint64, currentEra uint64, currentSubsidy uint64,
+	halvingIntervalDAAScore uint64, nextHalvingDAAScore uint64, nextSubsidy uint64,
+	estimatedSecondsToNextHalving uint64) *GetEmissionInfoResponseMessage {
+
+	return &GetEmissionInfoResponseMessage{
+		VirtualDAAScore:               virtualDAAScore,
+		CurrentEra:                    currentEra,
+		CurrentSubsidy:                currentSubsidy,
+		HalvingIntervalDAAScore:       halvingIntervalDAAScore,
+		NextHalvingDAAScore:           nextHalvingDAAScore,
+		NextSubsidy:                   nextSubsidy,
+		EstimatedSecondsToNextHalving: estimatedSecondsToNextHalving,
+	}
+}
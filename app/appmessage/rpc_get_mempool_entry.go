@@ -29,6 +29,10 @@ type GetMempoolEntryResponseMessage struct {
 	baseMessage
 	Entry *MempoolEntry
 
+	// DroppedTransaction is set along with Error if the transaction recently
+	// expired or was evicted from the mempool
+	DroppedTransaction *RPCDroppedTransaction
+
 	Error *RPCError
 }
 
@@ -37,6 +41,7 @@ type MempoolEntry struct {
 	Fee         uint64
 	Transaction *RPCTransaction
 	IsOrphan    bool
+	FeeDelta    int64
 }
 
 // Command returns the protocol command string for the message
@@ -45,12 +50,15 @@ func (msg *GetMempoolEntryResponseMessage) Command() MessageCommand {
 }
 
 // NewGetMempoolEntryResponseMessage returns a instance of the message
-func NewGetMempoolEntryResponseMessage(fee uint64, transaction *RPCTransaction, isOrphan bool) *GetMempoolEntryResponseMessage {
+func NewGetMempoolEntryResponseMessage(fee uint64, transaction *RPCTransaction, isOrphan bool,
+	feeDelta int64) *GetMempoolEntryResponseMessage {
+
 	return &GetMempoolEntryResponseMessage{
 		Entry: &MempoolEntry{
 			Fee:         fee,
 			Transaction: transaction,
 			IsOrphan:    isOrphan,
+			FeeDelta:    feeDelta,
 		},
 	}
 }
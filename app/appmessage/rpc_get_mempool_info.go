@@ -0,0 +1,55 @@
+package appmessage
+
+// GetMempoolInfoRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolInfoRequestMessage struct {
+	baseMessage
+	FeeRateBucketBoundaries []float64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolInfoRequestMessage) Command() MessageCommand {
+	return CmdGetMempoolInfoRequestMessage
+}
+
+// NewGetMempoolInfoRequestMessage returns a instance of the message
+func NewGetMempoolInfoRequestMessage(feeRateBucketBoundaries []float64) *GetMempoolInfoRequestMessage {
+	return &GetMempoolInfoRequestMessage{
+		FeeRateBucketBoundaries: feeRateBucketBoundaries,
+	}
+}
+
+// RPCMempoolFeeRateBucket holds the transactions with a fee rate of at least
+// MinimumFeerate, and lower than the MinimumFeerate of the next bucket
+type RPCMempoolFeeRateBucket struct {
+	MinimumFeerate   float64
+	TransactionCount uint64
+	TotalMass        uint64
+}
+
+// GetMempoolInfoResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolInfoResponseMessage struct {
+	baseMessage
+	TransactionCount                     uint64
+	OrphanCount                          uint64
+	TotalMass                            uint64
+	FeeRateHistogram                     []*RPCMempoolFeeRateBucket
+	OldestTransactionDAAScore            uint64
+	EstimatedOldestTransactionAgeSeconds uint64
+	MinimumFeerate                       float64
+	SpamTransactionCount                 uint64
+	FilteredTransactionCount             uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolInfoResponseMessage) Command() MessageCommand {
+	return CmdGetMempoolInfoResponseMessage
+}
+
+// NewGetMempoolInfoResponseMessage returns a instance of the message
+func NewGetMempoolInfoResponseMessage() *GetMempoolInfoResponseMessage {
+	return &GetMempoolInfoResponseMessage{}
+}
@@ -0,0 +1,54 @@
+package appmessage
+
+// GetTransactionAcceptanceRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionAcceptanceRequestMessage struct {
+	baseMessage
+	TransactionIDs []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionAcceptanceRequestMessage) Command() MessageCommand {
+	return CmdGetTransactionAcceptanceRequestMessage
+}
+
+// NewGetTransactionAcceptanceRequestMessage returns a instance of the message
+func NewGetTransactionAcceptanceRequestMessage(transactionIDs []string) *GetTransactionAcceptanceRequestMessage {
+	return &GetTransactionAcceptanceRequestMessage{
+		TransactionIDs: transactionIDs,
+	}
+}
+
+// GetTransactionAcceptanceResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionAcceptanceResponseMessage struct {
+	baseMessage
+	TransactionAcceptances []*TransactionAcceptance
+
+	Error *RPCError
+}
+
+// TransactionAcceptance describes whether and where a transaction was accepted
+// by the virtual selected parent chain
+type TransactionAcceptance struct {
+	TransactionID           string
+	IsAccepted              bool
+	IncludingBlockHash      string
+	AcceptingBlockHash      string
+	AcceptingBlockBlueScore uint64
+	Confirmations           uint64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionAcceptanceResponseMessage) Command() MessageCommand {
+	return CmdGetTransactionAcceptanceResponseMessage
+}
+
+// NewGetTransactionAcceptanceResponseMessage returns a instance of the message
+func NewGetTransactionAcceptanceResponseMessage(
+	transactionAcceptances []*TransactionAcceptance) *GetTransactionAcceptanceResponseMessage {
+
+	return &GetTransactionAcceptanceResponseMessage{
+		TransactionAcceptances: transactionAcceptances,
+	}
+}
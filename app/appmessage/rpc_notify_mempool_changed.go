@@ -0,0 +1,63 @@
+package appmessage
+
+// NotifyMempoolChangedRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyMempoolChangedRequestMessage struct {
+	baseMessage
+	Addresses []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyMempoolChangedRequestMessage) Command() MessageCommand {
+	return CmdNotifyMempoolChangedRequestMessage
+}
+
+// NewNotifyMempoolChangedRequestMessage returns a instance of the message
+func NewNotifyMempoolChangedRequestMessage(addresses []string) *NotifyMempoolChangedRequestMessage {
+	return &NotifyMempoolChangedRequestMessage{
+		Addresses: addresses,
+	}
+}
+
+// NotifyMempoolChangedResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyMempoolChangedResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyMempoolChangedResponseMessage) Command() MessageCommand {
+	return CmdNotifyMempoolChangedResponseMessage
+}
+
+// NewNotifyMempoolChangedResponseMessage returns a instance of the message
+func NewNotifyMempoolChangedResponseMessage() *NotifyMempoolChangedResponseMessage {
+	return &NotifyMempoolChangedResponseMessage{}
+}
+
+// MempoolChangedNotificationMessage is an appmessage corresponding to
+// its respective RPC message
+type MempoolChangedNotificationMessage struct {
+	baseMessage
+	Changes []*RPCMempoolChange
+}
+
+// RPCMempoolChange is a transaction that entered or left the mempool
+type RPCMempoolChange struct {
+	TransactionID string
+	Reason        string
+	Transaction   *RPCTransaction
+}
+
+// Command returns the protocol command string for the message
+func (msg *MempoolChangedNotificationMessage) Command() MessageCommand {
+	return CmdMempoolChangedNotificationMessage
+}
+
+// NewMempoolChangedNotificationMessage returns a instance of the message
+func NewMempoolChangedNotificationMessage(changes []*RPCMempoolChange) *MempoolChangedNotificationMessage {
+	return &MempoolChangedNotificationMessage{
+		Changes: changes,
+	}
+}
@@ -0,0 +1,59 @@
+package appmessage
+
+// TestMempoolAcceptRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type TestMempoolAcceptRequestMessage struct {
+	baseMessage
+	Transactions []*RPCTransaction
+}
+
+// Command returns the protocol command string for the message
+func (msg *TestMempoolAcceptRequestMessage) Command() MessageCommand {
+	return CmdTestMempoolAcceptRequestMessage
+}
+
+// NewTestMempoolAcceptRequestMessage returns a instance of the message
+func NewTestMempoolAcceptRequestMessage(transactions []*RPCTransaction) *TestMempoolAcceptRequestMessage {
+	return &TestMempoolAcceptRequestMessage{
+		Transactions: transactions,
+	}
+}
+
+// TestMempoolAcceptResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type TestMempoolAcceptResponseMessage struct {
+	baseMessage
+	Acceptances []*RPCMempoolAcceptance
+
+	Error *RPCError
+}
+
+// RPCMempoolAcceptance is whether the mempool would accept a transaction,
+// and every reason it would reject it for
+type RPCMempoolAcceptance struct {
+	TransactionID string
+	IsAccepted    bool
+	Mass          uint64
+	Fee           uint64
+	FeeRate       float64
+	Rejections    []*RPCMempoolRejection
+}
+
+// RPCMempoolRejection is a single reason for the mempool to reject a
+// transaction
+type RPCMempoolRejection struct {
+	RejectCode string
+	Reason     string
+}
+
+// Command returns the protocol command string for the message
+func (msg *TestMempoolAcceptResponseMessage) Command() MessageCommand {
+	return CmdTestMempoolAcceptResponseMessage
+}
+
+// NewTestMempoolAcceptResponseMessage returns a instance of the message
+func NewTestMempoolAcceptResponseMessage(acceptances []*RPCMempoolAcceptance) *TestMempoolAcceptResponseMessage {
+	return &TestMempoolAcceptResponseMessage{
+		Acceptances: acceptances,
+	}
+}
@@ -2,23 +2,31 @@ package app
 
 import (
 	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
 	"sync/atomic"
 
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 
 	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool"
 
+	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/app/protocol"
 	"github.com/stokesnetwork/stokes/app/rpc"
 	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/addresshistoryindex"
 	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/txindex"
 	"github.com/stokesnetwork/stokes/domain/utxoindex"
 	"github.com/stokesnetwork/stokes/infrastructure/config"
 	infrastructuredatabase "github.com/stokesnetwork/stokes/infrastructure/db/database"
 	"github.com/stokesnetwork/stokes/infrastructure/network/addressmanager"
 	"github.com/stokesnetwork/stokes/infrastructure/network/connmanager"
+	"github.com/stokesnetwork/stokes/infrastructure/network/nat"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/id"
+	"github.com/stokesnetwork/stokes/infrastructure/network/tor"
 	"github.com/stokesnetwork/stokes/util/panics"
 )
 
@@ -30,6 +38,9 @@ type ComponentManager struct {
 	rpcManager        *rpc.Manager
 	connectionManager *connmanager.ConnectionManager
 	netAdapter        *netadapter.NetAdapter
+	portMapper        *nat.PortMapper
+	onionService      *tor.OnionService
+	stopMempoolSaving chan struct{}
 
 	started, shutdown int32
 }
@@ -49,6 +60,13 @@ func (a *ComponentManager) Start() {
 	}
 
 	a.connectionManager.Start()
+	if a.portMapper != nil {
+		a.portMapper.Start()
+	}
+	if a.onionService != nil {
+		a.onionService.Start()
+	}
+	a.startMempoolSaving()
 }
 
 // Stop gracefully shuts down all the kaspad services.
@@ -61,6 +79,12 @@ func (a *ComponentManager) Stop() {
 
 	log.Warnf("Kaspad shutting down")
 
+	if a.portMapper != nil {
+		a.portMapper.Stop()
+	}
+	if a.onionService != nil {
+		a.onionService.Stop()
+	}
 	a.connectionManager.Stop()
 
 	err := a.netAdapter.Stop()
@@ -69,6 +93,7 @@ func (a *ComponentManager) Stop() {
 	}
 
 	a.protocolManager.Close()
+	a.stopMempoolSavingAndSave()
 	close(a.protocolManager.Context().Domain().ConsensusEventsChannel())
 
 	return
@@ -86,12 +111,20 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	}
 	mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
 	mempoolConfig.MaximumOrphanTransactionCount = cfg.MaxOrphanTxs
+	mempoolConfig.TransactionExpireIntervalDAAScore = mempool.SecondsToDAAScore(&consensusConfig.Params, cfg.MempoolExpiry)
+	mempoolConfig.OrphanExpireIntervalDAAScore = mempool.SecondsToDAAScore(&consensusConfig.Params, cfg.OrphanExpiry)
+	mempoolConfig.MaximumDroppedTransactionCount = cfg.MaxDroppedTxs
+	mempoolConfig.DroppedTransactionExpireIntervalSeconds = cfg.DroppedTxExpiry
 	mempoolConfig.MinimumRelayTransactionFee = cfg.MinRelayTxFee
+	blockCandidatePolicy := mempool.DefaultBlockCandidatePolicy()
+	blockCandidatePolicy.FeePerExtraOutput = uint64(cfg.SpamFeePerExtraOutput)
+	mempoolConfig.BlockCandidatePolicy = blockCandidatePolicy
 
 	domain, err := domain.New(&consensusConfig, mempoolConfig, db)
 	if err != nil {
 		return nil, err
 	}
+	loadMempool(cfg, domain)
 
 	netAdapter, err := netadapter.NewNetAdapter(cfg)
 	if err != nil {
@@ -113,6 +146,26 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 		log.Infof("UTXO index started")
 	}
 
+	var txIndex *txindex.TXIndex
+	if cfg.TXIndex {
+		txIndex, err = txindex.New(domain, db, cfg.IsArchivalNode)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("Transaction index started")
+	}
+
+	var addressHistoryIndex *addresshistoryindex.AddressHistoryIndex
+	if cfg.AddressHistoryIndex {
+		addressHistoryIndex, err = addresshistoryindex.New(domain, db)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("Address history index started")
+	}
+
 	connectionManager, err := connmanager.New(cfg, netAdapter, addressManager)
 	if err != nil {
 		return nil, err
@@ -121,7 +174,15 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	if err != nil {
 		return nil, err
 	}
-	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, domain.ConsensusEventsChannel(), interrupt)
+	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, txIndex, addressHistoryIndex, domain.ConsensusEventsChannel(), interrupt)
+	portMapper, err := setupPortMapper(cfg, addressManager)
+	if err != nil {
+		return nil, err
+	}
+	onionService, err := setupOnionService(cfg, addressManager)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ComponentManager{
 		cfg:               cfg,
@@ -129,7 +190,10 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 		rpcManager:        rpcManager,
 		connectionManager: connectionManager,
 		netAdapter:        netAdapter,
+		portMapper:        portMapper,
+		onionService:      onionService,
 		addressManager:    addressManager,
+		stopMempoolSaving: make(chan struct{}),
 	}, nil
 
 }
@@ -142,6 +206,8 @@ func setupRPC(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	txIndex *txindex.TXIndex,
+	addressHistoryIndex *addresshistoryindex.AddressHistoryIndex,
 	consensusEventsChan chan externalapi.ConsensusEvent,
 	shutDownChan chan<- struct{},
 ) *rpc.Manager {
@@ -154,15 +220,92 @@ func setupRPC(
 		connectionManager,
 		addressManager,
 		utxoIndex,
+		txIndex,
+		addressHistoryIndex,
 		consensusEventsChan,
 		shutDownChan,
 	)
 	protocolManager.SetOnNewBlockTemplateHandler(rpcManager.NotifyNewBlockTemplate)
 	protocolManager.SetOnPruningPointUTXOSetOverrideHandler(rpcManager.NotifyPruningPointUTXOSetOverride)
+	domain.MiningManager().SetOnMempoolChangedHandler(rpcManager.NotifyMempoolChanged)
 
 	return rpcManager
 }
 
+// setupPortMapper returns a port mapper for the P2P port if --upnp is set, or nil if it isn't or if
+// mapping the port is pointless because the node doesn't listen or its external IPs are given
+func setupPortMapper(cfg *config.Config, addressManager *addressmanager.AddressManager) (*nat.PortMapper, error) {
+	if !cfg.Upnp {
+		return nil, nil
+	}
+	if cfg.DisableListen || len(cfg.Listeners) == 0 || len(cfg.ExternalIPs) > 0 {
+		log.Infof("Not mapping the P2P port through the NAT, since listening is disabled or external IPs are set")
+		return nil, nil
+	}
+
+	_, portString, err := net.SplitHostPort(cfg.Listeners[0])
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	// The port mapper reports the external address whenever it changes, and the previous one is no
+	// longer reachable by then
+	var previousNetAddress *appmessage.NetAddress
+	return nat.NewPortMapper(uint16(port), func(netAddress *appmessage.NetAddress) {
+		if previousNetAddress != nil {
+			addressManager.RemoveLocalNetAddress(previousNetAddress)
+		}
+		previousNetAddress = netAddress
+		err := addressManager.AddLocalNetAddress(netAddress, addressmanager.UpnpPrio)
+		if err != nil {
+			log.Warnf("Not advertising the external address %s: %s", netAddress, err)
+		}
+	}), nil
+}
+
+// onionServicePrivateKeyFilename is the file in the app directory that keeps the private key
+// of the onion service, so that its address stays the same across restarts
+const onionServicePrivateKeyFilename = "onion_v3_private_key"
+
+// setupOnionService returns an onion service for the P2P port if --listenonion is set, or nil if it isn't
+func setupOnionService(cfg *config.Config, addressManager *addressmanager.AddressManager) (*tor.OnionService, error) {
+	if !cfg.ListenOnion {
+		return nil, nil
+	}
+
+	// The onion service is published on the P2P port, so that its address has the usual port
+	_, portString, err := net.SplitHostPort(cfg.Listeners[0])
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	// Tor forwards connections to the onion listener, which inbound onion peers are told apart by,
+	// on the loopback interface if it listens on all of them
+	host, onionPortString, err := net.SplitHostPort(cfg.OnionListener)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	target := net.JoinHostPort(host, onionPortString)
+
+	privateKeyPath := filepath.Join(cfg.AppDir, onionServicePrivateKeyFilename)
+	return tor.NewOnionService(cfg.TorControl, cfg.TorPassword, privateKeyPath, uint16(port), target,
+		func(netAddress *appmessage.NetAddress) {
+			err := addressManager.AddLocalNetAddress(netAddress, addressmanager.ManualPrio)
+			if err != nil {
+				log.Warnf("Not advertising the onion service %s: %s", netAddress, err)
+			}
+		}), nil
+}
+
 // P2PNodeID returns the network ID associated with this ComponentManager
 func (a *ComponentManager) P2PNodeID() *id.ID {
 	return a.netAdapter.ID()
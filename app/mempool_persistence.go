@@ -0,0 +1,83 @@
+package app
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/infrastructure/config"
+	"github.com/stokesnetwork/stokes/util/panics"
+)
+
+const (
+	mempoolFilename = "mempool.dat"
+
+	// mempoolSaveInterval is how often the mempool is saved while the node is running, so
+	// that it's mostly restored even after the node wasn't shut down gracefully
+	mempoolSaveInterval = 10 * time.Minute
+)
+
+var spawn = panics.GoroutineWrapperFunc(log)
+
+func mempoolPath(cfg *config.Config) string {
+	return filepath.Join(cfg.AppDir, mempoolFilename)
+}
+
+// loadMempool inserts the transactions that were saved by the previous run of the node back
+// into the mempool. Failing to do so isn't a reason not to start the node.
+func loadMempool(cfg *config.Config, domain domain.Domain) {
+	if cfg.NoPersistMempool {
+		return
+	}
+
+	path := mempoolPath(cfg)
+	loadedCount, rejectedCount, err := domain.MiningManager().LoadMempool(path)
+	if err != nil {
+		log.Errorf("Error loading the mempool from %s: %+v", path, err)
+		return
+	}
+	if loadedCount > 0 || rejectedCount > 0 {
+		log.Infof("Loaded %d transactions into the mempool from %s, %d were rejected",
+			loadedCount, path, rejectedCount)
+	}
+}
+
+func (a *ComponentManager) saveMempool() {
+	path := mempoolPath(a.cfg)
+	err := a.protocolManager.Context().Domain().MiningManager().SaveMempool(path)
+	if err != nil {
+		log.Errorf("Error saving the mempool to %s: %+v", path, err)
+		return
+	}
+	log.Debugf("Saved the mempool to %s", path)
+}
+
+func (a *ComponentManager) startMempoolSaving() {
+	if a.cfg.NoPersistMempool {
+		return
+	}
+
+	spawn("ComponentManager.saveMempoolPeriodically", func() {
+		ticker := time.NewTicker(mempoolSaveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				a.saveMempool()
+			case <-a.stopMempoolSaving:
+				return
+			}
+		}
+	})
+}
+
+// stopMempoolSavingAndSave stops saving the mempool periodically, and saves it one last time
+func (a *ComponentManager) stopMempoolSavingAndSave() {
+	if a.cfg.NoPersistMempool {
+		return
+	}
+
+	close(a.stopMempoolSaving)
+	a.saveMempool()
+}
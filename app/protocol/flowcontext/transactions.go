@@ -22,6 +22,36 @@ func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction, allowOrp
 	return f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
 }
 
+// AddTransactionReplacement adds transaction to the mempool in place of the transaction
+// it double-spends, and propagates it. The replaced transaction is returned.
+func (f *FlowContext) AddTransactionReplacement(tx *externalapi.DomainTransaction) (
+	replacedTransaction *externalapi.DomainTransaction, err error) {
+
+	acceptedTransactions, replacedTransaction, err := f.Domain().MiningManager().ValidateAndReplaceTransaction(tx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	acceptedTransactionIDs := consensushashing.TransactionIDs(acceptedTransactions)
+	err = f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return replacedTransaction, nil
+}
+
+// AddTransactionPackage adds a child transaction together with its parents to the mempool,
+// all or none of them, and propagates them.
+func (f *FlowContext) AddTransactionPackage(transactions []*externalapi.DomainTransaction) error {
+	acceptedTransactions, err := f.Domain().MiningManager().ValidateAndInsertTransactionPackage(transactions, true)
+	if err != nil {
+		return err
+	}
+
+	acceptedTransactionIDs := consensushashing.TransactionIDs(acceptedTransactions)
+	return f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
+}
+
 func (f *FlowContext) shouldRebroadcastTransactions() bool {
 	const rebroadcastInterval = 30 * time.Second
 	return time.Since(f.lastRebroadcastTime) > rebroadcastInterval
@@ -89,7 +89,7 @@ func HandleHandshake(context HandleHandshakeContext, netConnection *netadapter.N
 	}
 
 	if peerAddress != nil {
-		err := context.AddressManager().AddAddresses(peerAddress)
+		err := context.AddressManager().AddAddressesFromSource(netConnection.NetAddress(), peerAddress)
 		if err != nil {
 			return nil, err
 		}
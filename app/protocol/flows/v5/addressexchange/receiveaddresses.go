@@ -32,8 +32,9 @@ func ReceiveAddresses(context ReceiveAddressesContext, incomingRoute *router.Rou
 
 	msgAddresses := message.(*appmessage.MsgAddresses)
 	if len(msgAddresses.AddressList) > addressmanager.GetAddressesMax {
-		return protocolerrors.Errorf(true, "address count exceeded %d", addressmanager.GetAddressesMax)
+		return protocolerrors.ErrorfWithBanScore(protocolerrors.BanScoreModerate,
+			"address count exceeded %d", addressmanager.GetAddressesMax)
 	}
 
-	return context.AddressManager().AddAddresses(msgAddresses.AddressList...)
+	return context.AddressManager().AddAddressesFromSource(peer.Connection().NetAddress(), msgAddresses.AddressList...)
 }
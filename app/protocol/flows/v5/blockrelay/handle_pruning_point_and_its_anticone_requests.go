@@ -6,6 +6,7 @@ import (
 	"github.com/stokesnetwork/stokes/app/protocol/protocolerrors"
 	"github.com/stokesnetwork/stokes/domain"
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
 	"github.com/stokesnetwork/stokes/infrastructure/config"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
 	"sync/atomic"
@@ -53,72 +54,17 @@ func HandlePruningPointAndItsAnticoneRequests(context PruningPointAndItsAnticone
 				return err
 			}
 
-			pointAndItsAnticone, err := context.Domain().Consensus().PruningPointAndItsAnticone()
+			trustedData, err := CollectPruningPointTrustedData(context.Domain().Consensus(), context.Config().NetParams())
 			if err != nil {
 				return err
 			}
 
-			windowSize := context.Config().NetParams().DifficultyAdjustmentWindowSize
-			daaWindowBlocks := make([]*externalapi.TrustedDataDataDAAHeader, 0, windowSize)
-			daaWindowHashesToIndex := make(map[externalapi.DomainHash]int, windowSize)
-			trustedDataDAABlockIndexes := make(map[externalapi.DomainHash][]uint64)
-
-			ghostdagData := make([]*externalapi.BlockGHOSTDAGDataHashPair, 0)
-			ghostdagDataHashToIndex := make(map[externalapi.DomainHash]int)
-			trustedDataGHOSTDAGDataIndexes := make(map[externalapi.DomainHash][]uint64)
-			for _, blockHash := range pointAndItsAnticone {
-				blockDAAWindowHashes, err := context.Domain().Consensus().BlockDAAWindowHashes(blockHash)
-				if err != nil {
-					return err
-				}
-
-				trustedDataDAABlockIndexes[*blockHash] = make([]uint64, 0, windowSize)
-				for i, daaBlockHash := range blockDAAWindowHashes {
-					index, exists := daaWindowHashesToIndex[*daaBlockHash]
-					if !exists {
-						trustedDataDataDAAHeader, err := context.Domain().Consensus().TrustedDataDataDAAHeader(blockHash, daaBlockHash, uint64(i))
-						if err != nil {
-							return err
-						}
-						daaWindowBlocks = append(daaWindowBlocks, trustedDataDataDAAHeader)
-						index = len(daaWindowBlocks) - 1
-						daaWindowHashesToIndex[*daaBlockHash] = index
-					}
-
-					trustedDataDAABlockIndexes[*blockHash] = append(trustedDataDAABlockIndexes[*blockHash], uint64(index))
-				}
-
-				ghostdagDataBlockHashes, err := context.Domain().Consensus().TrustedBlockAssociatedGHOSTDAGDataBlockHashes(blockHash)
-				if err != nil {
-					return err
-				}
-
-				trustedDataGHOSTDAGDataIndexes[*blockHash] = make([]uint64, 0, context.Config().NetParams().K)
-				for _, ghostdagDataBlockHash := range ghostdagDataBlockHashes {
-					index, exists := ghostdagDataHashToIndex[*ghostdagDataBlockHash]
-					if !exists {
-						data, err := context.Domain().Consensus().TrustedGHOSTDAGData(ghostdagDataBlockHash)
-						if err != nil {
-							return err
-						}
-						ghostdagData = append(ghostdagData, &externalapi.BlockGHOSTDAGDataHashPair{
-							Hash:         ghostdagDataBlockHash,
-							GHOSTDAGData: data,
-						})
-						index = len(ghostdagData) - 1
-						ghostdagDataHashToIndex[*ghostdagDataBlockHash] = index
-					}
-
-					trustedDataGHOSTDAGDataIndexes[*blockHash] = append(trustedDataGHOSTDAGDataIndexes[*blockHash], uint64(index))
-				}
-			}
-
-			err = outgoingRoute.Enqueue(appmessage.DomainTrustedDataToTrustedData(daaWindowBlocks, ghostdagData))
+			err = outgoingRoute.Enqueue(appmessage.DomainTrustedDataToTrustedData(trustedData.DAAWindow, trustedData.GHOSTDAGData))
 			if err != nil {
 				return err
 			}
 
-			for i, blockHash := range pointAndItsAnticone {
+			for i, blockHash := range trustedData.PruningPointAndItsAnticone {
 				block, found, err := context.Domain().Consensus().GetBlock(blockHash)
 				if err != nil {
 					return err
@@ -128,7 +74,7 @@ func HandlePruningPointAndItsAnticoneRequests(context PruningPointAndItsAnticone
 					return protocolerrors.Errorf(false, "pruning point anticone block %s not found", blockHash)
 				}
 
-				err = outgoingRoute.Enqueue(appmessage.DomainBlockWithTrustedDataToBlockWithTrustedDataV4(block, trustedDataDAABlockIndexes[*blockHash], trustedDataGHOSTDAGDataIndexes[*blockHash]))
+				err = outgoingRoute.Enqueue(appmessage.DomainBlockWithTrustedDataToBlockWithTrustedDataV4(block, trustedData.DAAWindowIndices[*blockHash], trustedData.GHOSTDAGDataIndices[*blockHash]))
 				if err != nil {
 					return err
 				}
@@ -160,3 +106,83 @@ func HandlePruningPointAndItsAnticoneRequests(context PruningPointAndItsAnticone
 		}
 	}
 }
+
+// PruningPointTrustedData is the data a syncee needs in order to accept the pruning point and
+// its anticone without validating their past. Every block is associated with the indices of
+// its DAA window and GHOSTDAG data within the shared DAAWindow and GHOSTDAGData slices.
+type PruningPointTrustedData struct {
+	PruningPointAndItsAnticone []*externalapi.DomainHash
+	DAAWindow                  []*externalapi.TrustedDataDataDAAHeader
+	GHOSTDAGData               []*externalapi.BlockGHOSTDAGDataHashPair
+	DAAWindowIndices           map[externalapi.DomainHash][]uint64
+	GHOSTDAGDataIndices        map[externalapi.DomainHash][]uint64
+}
+
+// CollectPruningPointTrustedData collects the trusted data of the pruning point and its anticone
+func CollectPruningPointTrustedData(consensus externalapi.Consensus, params *dagconfig.Params) (
+	*PruningPointTrustedData, error) {
+
+	pointAndItsAnticone, err := consensus.PruningPointAndItsAnticone()
+	if err != nil {
+		return nil, err
+	}
+
+	windowSize := params.DifficultyAdjustmentWindowSize
+	trustedData := &PruningPointTrustedData{
+		PruningPointAndItsAnticone: pointAndItsAnticone,
+		DAAWindow:                  make([]*externalapi.TrustedDataDataDAAHeader, 0, windowSize),
+		GHOSTDAGData:               make([]*externalapi.BlockGHOSTDAGDataHashPair, 0),
+		DAAWindowIndices:           make(map[externalapi.DomainHash][]uint64),
+		GHOSTDAGDataIndices:        make(map[externalapi.DomainHash][]uint64),
+	}
+	daaWindowHashesToIndex := make(map[externalapi.DomainHash]int, windowSize)
+	ghostdagDataHashToIndex := make(map[externalapi.DomainHash]int)
+	for _, blockHash := range pointAndItsAnticone {
+		blockDAAWindowHashes, err := consensus.BlockDAAWindowHashes(blockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		trustedData.DAAWindowIndices[*blockHash] = make([]uint64, 0, windowSize)
+		for i, daaBlockHash := range blockDAAWindowHashes {
+			index, exists := daaWindowHashesToIndex[*daaBlockHash]
+			if !exists {
+				trustedDataDataDAAHeader, err := consensus.TrustedDataDataDAAHeader(blockHash, daaBlockHash, uint64(i))
+				if err != nil {
+					return nil, err
+				}
+				trustedData.DAAWindow = append(trustedData.DAAWindow, trustedDataDataDAAHeader)
+				index = len(trustedData.DAAWindow) - 1
+				daaWindowHashesToIndex[*daaBlockHash] = index
+			}
+
+			trustedData.DAAWindowIndices[*blockHash] = append(trustedData.DAAWindowIndices[*blockHash], uint64(index))
+		}
+
+		ghostdagDataBlockHashes, err := consensus.TrustedBlockAssociatedGHOSTDAGDataBlockHashes(blockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		trustedData.GHOSTDAGDataIndices[*blockHash] = make([]uint64, 0, params.K)
+		for _, ghostdagDataBlockHash := range ghostdagDataBlockHashes {
+			index, exists := ghostdagDataHashToIndex[*ghostdagDataBlockHash]
+			if !exists {
+				data, err := consensus.TrustedGHOSTDAGData(ghostdagDataBlockHash)
+				if err != nil {
+					return nil, err
+				}
+				trustedData.GHOSTDAGData = append(trustedData.GHOSTDAGData, &externalapi.BlockGHOSTDAGDataHashPair{
+					Hash:         ghostdagDataBlockHash,
+					GHOSTDAGData: data,
+				})
+				index = len(trustedData.GHOSTDAGData) - 1
+				ghostdagDataHashToIndex[*ghostdagDataBlockHash] = index
+			}
+
+			trustedData.GHOSTDAGDataIndices[*blockHash] = append(trustedData.GHOSTDAGDataIndices[*blockHash], uint64(index))
+		}
+	}
+
+	return trustedData, nil
+}
@@ -373,6 +373,11 @@ func (flow *handleIBDFlow) validateAndInsertPruningPoints(proofPruningPoint *ext
 			"point in the list")
 	}
 
+	err = flow.validatePruningPointsCheckpoints(headers)
+	if err != nil {
+		return err
+	}
+
 	err = flow.Domain().StagingConsensus().ImportPruningPoints(headers)
 	if err != nil {
 		return err
@@ -381,6 +386,26 @@ func (flow *handleIBDFlow) validateAndInsertPruningPoints(proofPruningPoint *ext
 	return nil
 }
 
+// validatePruningPointsCheckpoints validates that none of the past pruning points contradicts a checkpoint.
+// Pruning points are selected chain blocks, so a pruning point at the DAA score of a checkpoint has to be
+// the checkpoint itself. The selected chain between the pruning points is validated against the checkpoints
+// along with the pruning point proof and the headers.
+func (flow *handleIBDFlow) validatePruningPointsCheckpoints(pruningPointHeaders []externalapi.BlockHeader) error {
+	for _, checkpoint := range flow.Config().NetParams().Checkpoints {
+		for _, header := range pruningPointHeaders {
+			if header.DAAScore() != checkpoint.DAAScore {
+				continue
+			}
+			pruningPoint := consensushashing.HeaderHash(header)
+			if !pruningPoint.Equal(checkpoint.Hash) {
+				return protocolerrors.Errorf(true, "pruning point %s contradicts checkpoint %s at DAA score %d",
+					pruningPoint, checkpoint.Hash, checkpoint.DAAScore)
+			}
+		}
+	}
+	return nil
+}
+
 func (flow *handleIBDFlow) syncPruningPointUTXOSet(consensus externalapi.Consensus,
 	pruningPoint *externalapi.DomainHash) (bool, error) {
 
@@ -70,7 +70,8 @@ func (flow *sendPingsFlow) start() error {
 		}
 		pongMessage := message.(*appmessage.MsgPong)
 		if pongMessage.Nonce != pingMessage.Nonce {
-			return protocolerrors.New(true, "nonce mismatch between ping and pong")
+			return protocolerrors.ErrorfWithBanScore(protocolerrors.BanScoreModerate,
+				"nonce mismatch between ping and pong")
 		}
 		flow.peer.SetPingIdle()
 	}
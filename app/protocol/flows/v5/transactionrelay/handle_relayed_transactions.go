@@ -123,8 +123,8 @@ func (flow *handleRelayedTransactionsFlow) readInv() (*appmessage.MsgInvTransact
 
 	inv, ok := msg.(*appmessage.MsgInvTransaction)
 	if !ok {
-		return nil, protocolerrors.Errorf(true, "unexpected %s message in the block relay flow while "+
-			"expecting an inv message", msg.Command())
+		return nil, protocolerrors.ErrorfWithBanScore(protocolerrors.BanScoreModerate, "unexpected %s message "+
+			"in the block relay flow while expecting an inv message", msg.Command())
 	}
 	return inv, nil
 }
@@ -170,8 +170,8 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 		}
 		if msgTxNotFound != nil {
 			if !msgTxNotFound.ID.Equal(expectedID) {
-				return protocolerrors.Errorf(true, "expected transaction %s, but got %s",
-					expectedID, msgTxNotFound.ID)
+				return protocolerrors.ErrorfWithBanScore(protocolerrors.BanScoreModerate,
+					"expected transaction %s, but got %s", expectedID, msgTxNotFound.ID)
 			}
 
 			continue
@@ -179,8 +179,8 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 		tx := appmessage.MsgTxToDomainTransaction(msgTx)
 		txID := consensushashing.TransactionID(tx)
 		if !txID.Equal(expectedID) {
-			return protocolerrors.Errorf(true, "expected transaction %s, but got %s",
-				expectedID, txID)
+			return protocolerrors.ErrorfWithBanScore(protocolerrors.BanScoreModerate,
+				"expected transaction %s, but got %s", expectedID, txID)
 		}
 
 		acceptedTransactions, err :=
@@ -202,7 +202,8 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 				continue
 			}
 
-			return protocolerrors.Errorf(true, "rejected transaction %s: %s", txID, ruleErr)
+			return protocolerrors.ErrorfWithBanScore(protocolerrors.BanScoreModerate,
+				"rejected transaction %s: %s", txID, ruleErr)
 		}
 		err = flow.broadcastAcceptedTransactions(consensushashing.TransactionIDs(acceptedTransactions))
 		if err != nil {
@@ -68,6 +68,18 @@ func (m *Manager) AddTransaction(tx *externalapi.DomainTransaction, allowOrphan
 	return m.context.AddTransaction(tx, allowOrphan)
 }
 
+// AddTransactionReplacement adds transaction to the mempool in place of the transaction it
+// double-spends, and propagates it. The replaced transaction is returned.
+func (m *Manager) AddTransactionReplacement(tx *externalapi.DomainTransaction) (*externalapi.DomainTransaction, error) {
+	return m.context.AddTransactionReplacement(tx)
+}
+
+// AddTransactionPackage adds a child transaction together with its parents to the mempool, all
+// or none of them, and propagates them.
+func (m *Manager) AddTransactionPackage(transactions []*externalapi.DomainTransaction) error {
+	return m.context.AddTransactionPackage(transactions)
+}
+
 // AddBlock adds the given block to the DAG and propagates it.
 func (m *Manager) AddBlock(block *externalapi.DomainBlock) error {
 	return m.context.AddBlock(block)
@@ -106,18 +106,8 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 
 func (m *Manager) handleError(err error, netConnection *netadapter.NetConnection, outgoingRoute *routerpkg.Route) {
 	if protocolErr := (protocolerrors.ProtocolError{}); errors.As(err, &protocolErr) {
-		if m.context.Config().EnableBanning && protocolErr.ShouldBan {
-			log.Warnf("Banning %s (reason: %s)", netConnection, protocolErr.Cause)
-
-			err := m.context.ConnectionManager().Ban(netConnection)
-			if err != nil && !errors.Is(err, connmanager.ErrCannotBanPermanent) {
-				panic(err)
-			}
-
-			err = outgoingRoute.Enqueue(appmessage.NewMsgReject(protocolErr.Error()))
-			if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
-				panic(err)
-			}
+		if banScore := protocolErr.BanScore(); banScore > 0 {
+			m.addBanScore(netConnection, outgoingRoute, protocolErr, banScore)
 		}
 		log.Infof("Disconnecting from %s (reason: %s)", netConnection, protocolErr.Cause)
 		netConnection.Disconnect()
@@ -134,6 +124,32 @@ func (m *Manager) handleError(err error, netConnection *netadapter.NetConnection
 	panic(err)
 }
 
+// addBanScore adds the ban score of a protocol violation to the ban score of the peer that committed
+// it, and bans the peer once its ban score reaches the ban threshold
+func (m *Manager) addBanScore(netConnection *netadapter.NetConnection, outgoingRoute *routerpkg.Route,
+	protocolErr protocolerrors.ProtocolError, banScore uint32) {
+
+	newBanScore, reachedBanThreshold := m.context.ConnectionManager().AddBanScore(netConnection, banScore)
+	if !m.context.Config().EnableBanning || !reachedBanThreshold {
+		log.Warnf("Peer %s misbehaved, and its ban score increased by %d to %d (reason: %s)",
+			netConnection, banScore, newBanScore, protocolErr.Cause)
+		return
+	}
+
+	log.Warnf("Banning %s with a ban score of %d (reason: %s)", netConnection, newBanScore, protocolErr.Cause)
+
+	err := m.context.ConnectionManager().Ban(netConnection)
+	if err != nil && !errors.Is(err, connmanager.ErrCannotBanPermanent) &&
+		!errors.Is(err, connmanager.ErrCannotBanWhitelisted) {
+		panic(err)
+	}
+
+	err = outgoingRoute.Enqueue(appmessage.NewMsgReject(protocolErr.Error()))
+	if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
+		panic(err)
+	}
+}
+
 // RegisterFlow registers a flow to the given router.
 func (m *Manager) RegisterFlow(name string, router *routerpkg.Router, messageTypes []appmessage.MessageCommand, isStopping *uint32,
 	errChan chan error, initializeFunc common.FlowInitializeFunc) *common.Flow {
@@ -5,17 +5,47 @@ import (
 	"github.com/pkg/errors"
 )
 
+// These constants are the ban scores of offences of different severity.
+// A peer is banned once its ban score reaches the ban threshold, which is
+// BanScoreSevere by default.
+const (
+	// BanScoreSevere is the ban score of offences that an honest peer
+	// can't commit, such as sending an invalid block
+	BanScoreSevere = 100
+
+	// BanScoreModerate is the ban score of offences that an honest peer
+	// may commit once in a while, such as relaying a transaction that the
+	// rules of this node reject
+	BanScoreModerate = 20
+)
+
 // ProtocolError is an error that signifies a violation
 // of the peer-to-peer protocol
 type ProtocolError struct {
 	ShouldBan bool
 	Cause     error
+
+	// Score is the ban score of the violation if ShouldBan is set.
+	// BanScoreSevere is assumed if it's 0.
+	Score uint32
 }
 
 func (e ProtocolError) Error() string {
 	return e.Cause.Error()
 }
 
+// BanScore returns how much the violation adds to the ban score of the
+// peer that committed it
+func (e ProtocolError) BanScore() uint32 {
+	if !e.ShouldBan {
+		return 0
+	}
+	if e.Score == 0 {
+		return BanScoreSevere
+	}
+	return e.Score
+}
+
 // Unwrap returns the cause of ProtocolError, to be used with `errors.Unwrap()`
 func (e ProtocolError) Unwrap() error {
 	return e.Cause
@@ -55,6 +85,16 @@ func Wrapf(shouldBan bool, err error, format string, args ...interface{}) error
 	}
 }
 
+// ErrorfWithBanScore formats according to a format specifier and returns
+// the string as a ProtocolError with the given ban score.
+func ErrorfWithBanScore(banScore uint32, format string, args ...interface{}) error {
+	return ProtocolError{
+		ShouldBan: true,
+		Cause:     errors.Errorf(format, args...),
+		Score:     banScore,
+	}
+}
+
 // ConvertToBanningProtocolErrorIfRuleError converts the given error to
 // a banning protocol error if it's a rule error, and otherwise keep it
 // as is.
@@ -5,7 +5,10 @@ import (
 	"github.com/stokesnetwork/stokes/app/protocol"
 	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
 	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/addresshistoryindex"
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+	"github.com/stokesnetwork/stokes/domain/txindex"
 	"github.com/stokesnetwork/stokes/domain/utxoindex"
 	"github.com/stokesnetwork/stokes/infrastructure/config"
 	"github.com/stokesnetwork/stokes/infrastructure/logger"
@@ -29,6 +32,8 @@ func NewManager(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	txIndex *txindex.TXIndex,
+	addressHistoryIndex *addresshistoryindex.AddressHistoryIndex,
 	consensusEventsChan chan externalapi.ConsensusEvent,
 	shutDownChan chan<- struct{}) *Manager {
 
@@ -41,6 +46,8 @@ func NewManager(
 			connectionManager,
 			addressManager,
 			utxoIndex,
+			txIndex,
+			addressHistoryIndex,
 			shutDownChan,
 		),
 	}
@@ -113,6 +120,20 @@ func (m *Manager) notifyVirtualChange(virtualChangeSet *externalapi.VirtualChang
 		}
 	}
 
+	if m.context.Config.TXIndex {
+		err := m.context.TXIndex.Update(virtualChangeSet)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.context.Config.AddressHistoryIndex {
+		err := m.context.AddressHistoryIndex.Update(virtualChangeSet)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := m.notifyVirtualSelectedParentBlueScoreChanged(virtualChangeSet.VirtualSelectedParentBlueScore)
 	if err != nil {
 		return err
@@ -151,6 +172,22 @@ func (m *Manager) NotifyPruningPointUTXOSetOverride() error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyPruningPointUTXOSetOverride")
 	defer onEnd()
 
+	// The blocks below the new pruning point are not available, so the transaction
+	// and address history indexes are rebuilt from it
+	if m.context.Config.TXIndex {
+		err := m.context.TXIndex.Reset()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.context.Config.AddressHistoryIndex {
+		err := m.context.AddressHistoryIndex.Reset()
+		if err != nil {
+			return err
+		}
+	}
+
 	if m.context.Config.UTXOIndex {
 		err := m.notifyPruningPointUTXOSetOverride()
 		if err != nil {
@@ -161,6 +198,23 @@ func (m *Manager) NotifyPruningPointUTXOSetOverride() error {
 	return nil
 }
 
+// NotifyMempoolChanged notifies the manager that transactions entered or left the mempool.
+// It's called by the mempool, which has nothing to do with a failure to notify, so errors
+// are only logged.
+func (m *Manager) NotifyMempoolChanged(changes []*miningmanagermodel.MempoolChange) {
+	if !m.context.NotificationManager.HasMempoolChangedListeners() {
+		return
+	}
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyMempoolChanged")
+	defer onEnd()
+
+	err := m.context.NotificationManager.NotifyMempoolChanged(changes)
+	if err != nil {
+		log.Errorf("Error notifying about mempool changes: %+v", err)
+	}
+}
+
 // NotifyFinalityConflict notifies the manager that there's a finality conflict in the DAG
 func (m *Manager) NotifyFinalityConflict(violatingBlockHash string) error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyFinalityConflict")
@@ -51,6 +51,19 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdNotifyNewBlockTemplateRequestMessage:                      rpchandlers.HandleNotifyNewBlockTemplate,
 	appmessage.CmdGetCoinSupplyRequestMessage:                               rpchandlers.HandleGetCoinSupply,
 	appmessage.CmdGetMempoolEntriesByAddressesRequestMessage:                rpchandlers.HandleGetMempoolEntriesByAddresses,
+	appmessage.CmdGetFeeEstimateRequestMessage:                              rpchandlers.HandleGetFeeEstimate,
+	appmessage.CmdSubmitTransactionReplacementRequestMessage:                rpchandlers.HandleSubmitTransactionReplacement,
+	appmessage.CmdGetTransactionRequestMessage:                              rpchandlers.HandleGetTransaction,
+	appmessage.CmdGetTransactionAcceptanceRequestMessage:                    rpchandlers.HandleGetTransactionAcceptance,
+	appmessage.CmdGetAddressTransactionsRequestMessage:                      rpchandlers.HandleGetAddressTransactions,
+	appmessage.CmdGetEmissionInfoRequestMessage:                             rpchandlers.HandleGetEmissionInfo,
+	appmessage.CmdSubmitTransactionPackageRequestMessage:                    rpchandlers.HandleSubmitTransactionPackage,
+	appmessage.CmdGetMempoolInfoRequestMessage:                              rpchandlers.HandleGetMempoolInfo,
+	appmessage.CmdNotifyMempoolChangedRequestMessage:                        rpchandlers.HandleNotifyMempoolChanged,
+	appmessage.CmdGetBlockCandidateStatusRequestMessage:                     rpchandlers.HandleGetBlockCandidateStatus,
+	appmessage.CmdGetDroppedTransactionsRequestMessage:                      rpchandlers.HandleGetDroppedTransactions,
+	appmessage.CmdPrioritiseTransactionRequestMessage:                       rpchandlers.HandlePrioritiseTransaction,
+	appmessage.CmdTestMempoolAcceptRequestMessage:                           rpchandlers.HandleTestMempoolAccept,
 }
 
 func (m *Manager) routerInitializer(router *router.Router, netConnection *netadapter.NetConnection) {
@@ -3,6 +3,8 @@ package rpccontext
 import (
 	"github.com/stokesnetwork/stokes/app/protocol"
 	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/addresshistoryindex"
+	"github.com/stokesnetwork/stokes/domain/txindex"
 	"github.com/stokesnetwork/stokes/domain/utxoindex"
 	"github.com/stokesnetwork/stokes/infrastructure/config"
 	"github.com/stokesnetwork/stokes/infrastructure/network/addressmanager"
@@ -12,14 +14,16 @@ import (
 
 // Context represents the RPC context
 type Context struct {
-	Config            *config.Config
-	NetAdapter        *netadapter.NetAdapter
-	Domain            domain.Domain
-	ProtocolManager   *protocol.Manager
-	ConnectionManager *connmanager.ConnectionManager
-	AddressManager    *addressmanager.AddressManager
-	UTXOIndex         *utxoindex.UTXOIndex
-	ShutDownChan      chan<- struct{}
+	Config              *config.Config
+	NetAdapter          *netadapter.NetAdapter
+	Domain              domain.Domain
+	ProtocolManager     *protocol.Manager
+	ConnectionManager   *connmanager.ConnectionManager
+	AddressManager      *addressmanager.AddressManager
+	UTXOIndex           *utxoindex.UTXOIndex
+	TXIndex             *txindex.TXIndex
+	AddressHistoryIndex *addresshistoryindex.AddressHistoryIndex
+	ShutDownChan        chan<- struct{}
 
 	NotificationManager *NotificationManager
 }
@@ -32,17 +36,21 @@ func NewContext(cfg *config.Config,
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	txIndex *txindex.TXIndex,
+	addressHistoryIndex *addresshistoryindex.AddressHistoryIndex,
 	shutDownChan chan<- struct{}) *Context {
 
 	context := &Context{
-		Config:            cfg,
-		NetAdapter:        netAdapter,
-		Domain:            domain,
-		ProtocolManager:   protocolManager,
-		ConnectionManager: connectionManager,
-		AddressManager:    addressManager,
-		UTXOIndex:         utxoIndex,
-		ShutDownChan:      shutDownChan,
+		Config:              cfg,
+		NetAdapter:          netAdapter,
+		Domain:              domain,
+		ProtocolManager:     protocolManager,
+		ConnectionManager:   connectionManager,
+		AddressManager:      addressManager,
+		UTXOIndex:           utxoIndex,
+		TXIndex:             txIndex,
+		AddressHistoryIndex: addressHistoryIndex,
+		ShutDownChan:        shutDownChan,
 	}
 	context.NotificationManager = NewNotificationManager(cfg.ActiveNetParams)
 
@@ -0,0 +1,21 @@
+package rpccontext
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// ConvertDroppedTransactionToRPCDroppedTransaction converts a dropped transaction to its RPC representation
+func ConvertDroppedTransactionToRPCDroppedTransaction(
+	droppedTransaction *miningmanagermodel.DroppedTransaction) *appmessage.RPCDroppedTransaction {
+
+	return &appmessage.RPCDroppedTransaction{
+		TransactionID:      droppedTransaction.TransactionID.String(),
+		Reason:             droppedTransaction.Reason.String(),
+		IsOrphan:           droppedTransaction.IsOrphan,
+		Fee:                droppedTransaction.Fee,
+		Mass:               droppedTransaction.Mass,
+		DroppedAtTimestamp: droppedTransaction.DroppedAt.UnixMilli(),
+		DroppedAtDAAScore:  droppedTransaction.DroppedAtDAAScore,
+	}
+}
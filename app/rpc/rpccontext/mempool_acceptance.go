@@ -0,0 +1,27 @@
+package rpccontext
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// ConvertMempoolAcceptanceToRPCMempoolAcceptance converts a mempool acceptance to its RPC representation
+func ConvertMempoolAcceptanceToRPCMempoolAcceptance(
+	acceptance *miningmanagermodel.MempoolAcceptance) *appmessage.RPCMempoolAcceptance {
+
+	rejections := make([]*appmessage.RPCMempoolRejection, len(acceptance.Rejections))
+	for i, rejection := range acceptance.Rejections {
+		rejections[i] = &appmessage.RPCMempoolRejection{
+			RejectCode: rejection.RejectCode,
+			Reason:     rejection.Reason,
+		}
+	}
+	return &appmessage.RPCMempoolAcceptance{
+		TransactionID: acceptance.TransactionID.String(),
+		IsAccepted:    acceptance.IsAccepted,
+		Mass:          acceptance.Mass,
+		Fee:           acceptance.Fee,
+		FeeRate:       acceptance.FeeRate,
+		Rejections:    rejections,
+	}
+}
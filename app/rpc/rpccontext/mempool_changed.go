@@ -0,0 +1,20 @@
+package rpccontext
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// ConvertMempoolChangesToRPCMempoolChanges converts mempool changes to their RPC representation
+func ConvertMempoolChangesToRPCMempoolChanges(changes []*miningmanagermodel.MempoolChange) []*appmessage.RPCMempoolChange {
+	rpcChanges := make([]*appmessage.RPCMempoolChange, len(changes))
+	for i, change := range changes {
+		rpcChanges[i] = &appmessage.RPCMempoolChange{
+			TransactionID: consensushashing.TransactionID(change.Transaction).String(),
+			Reason:        change.Reason.String(),
+			Transaction:   appmessage.DomainTransactionToRPCTransaction(change.Transaction),
+		}
+	}
+	return rpcChanges
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/txscript"
 
 	"github.com/stokesnetwork/stokes/app/appmessage"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 	"github.com/stokesnetwork/stokes/domain/utxoindex"
 	routerpkg "github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
@@ -41,8 +42,11 @@ type NotificationListener struct {
 	propagateVirtualDaaScoreChangedNotifications                bool
 	propagatePruningPointUTXOSetOverrideNotifications           bool
 	propagateNewBlockTemplateNotifications                      bool
+	propagateMempoolChangedNotifications                        bool
 
 	propagateUTXOsChangedNotificationAddresses                                    map[utxoindex.ScriptPublicKeyString]*UTXOsChangedNotificationAddress
+	propagateMempoolChangedNotificationAddresses                                  map[utxoindex.ScriptPublicKeyString]*UTXOsChangedNotificationAddress
+	propagateAllMempoolChangedNotifications                                       bool
 	includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications bool
 }
 
@@ -284,6 +288,49 @@ func (nm *NotificationManager) NotifyNewBlockTemplate(
 	return nil
 }
 
+// HasMempoolChangedListeners indicates if the notification manager has any listeners for `MempoolChanged` events
+func (nm *NotificationManager) HasMempoolChangedListeners() bool {
+	nm.RLock()
+	defer nm.RUnlock()
+
+	for _, listener := range nm.listeners {
+		if listener.propagateMempoolChangedNotifications {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyMempoolChanged notifies the notification manager that transactions entered or left the mempool
+func (nm *NotificationManager) NotifyMempoolChanged(changes []*miningmanagermodel.MempoolChange) error {
+	nm.RLock()
+	defer nm.RUnlock()
+
+	// The changes are converted once for all listeners, and only if there are any
+	var rpcChanges []*appmessage.RPCMempoolChange
+	for router, listener := range nm.listeners {
+		if !listener.propagateMempoolChangedNotifications {
+			continue
+		}
+		if rpcChanges == nil {
+			rpcChanges = ConvertMempoolChangesToRPCMempoolChanges(changes)
+		}
+
+		listenerChanges := listener.filterMempoolChanges(changes, rpcChanges)
+
+		// Don't send the notification if it's empty
+		if len(listenerChanges) == 0 {
+			continue
+		}
+
+		err := router.OutgoingRoute().MaybeEnqueue(appmessage.NewMempoolChangedNotificationMessage(listenerChanges))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NotifyPruningPointUTXOSetOverride notifies the notification manager that the UTXO index
 // reset due to pruning point change via IBD.
 func (nm *NotificationManager) NotifyPruningPointUTXOSetOverride() error {
@@ -313,6 +360,7 @@ func newNotificationListener(params *dagconfig.Params) *NotificationListener {
 		propagateVirtualSelectedParentBlueScoreChangedNotifications: false,
 		propagateNewBlockTemplateNotifications:                      false,
 		propagatePruningPointUTXOSetOverrideNotifications:           false,
+		propagateMempoolChangedNotifications:                        false,
 	}
 }
 
@@ -384,6 +432,67 @@ func (nm *NotificationManager) StopPropagatingUTXOsChangedNotifications(nl *Noti
 	}
 }
 
+// PropagateMempoolChangedNotifications instructs the listener to send mempool changed notifications
+// to the remote listener for transactions that spend from or pay to the given addresses, or for all
+// transactions if no addresses are given. Subsequent calls add their addresses to the old ones.
+func (nm *NotificationManager) PropagateMempoolChangedNotifications(nl *NotificationListener, addresses []*UTXOsChangedNotificationAddress) {
+	// Apply a write-lock since the internal listener address map is modified
+	nm.Lock()
+	defer nm.Unlock()
+
+	if !nl.propagateMempoolChangedNotifications {
+		nl.propagateMempoolChangedNotifications = true
+		nl.propagateMempoolChangedNotificationAddresses =
+			make(map[utxoindex.ScriptPublicKeyString]*UTXOsChangedNotificationAddress, len(addresses))
+	}
+
+	if len(addresses) == 0 {
+		nl.propagateAllMempoolChangedNotifications = true
+	}
+	for _, address := range addresses {
+		nl.propagateMempoolChangedNotificationAddresses[address.ScriptPublicKeyString] = address
+	}
+}
+
+// filterMempoolChanges returns the RPC changes, given in the same order as changes, that are
+// relevant to this listener
+func (nl *NotificationListener) filterMempoolChanges(changes []*miningmanagermodel.MempoolChange,
+	rpcChanges []*appmessage.RPCMempoolChange) []*appmessage.RPCMempoolChange {
+
+	if nl.propagateAllMempoolChangedNotifications {
+		return rpcChanges
+	}
+
+	listenerChanges := []*appmessage.RPCMempoolChange{}
+	for i, change := range changes {
+		if nl.isMempoolTransactionOfListener(change.Transaction) {
+			listenerChanges = append(listenerChanges, rpcChanges[i])
+		}
+	}
+	return listenerChanges
+}
+
+// isMempoolTransactionOfListener returns whether the given transaction spends from or pays to
+// one of the addresses of this listener
+func (nl *NotificationListener) isMempoolTransactionOfListener(transaction *externalapi.DomainTransaction) bool {
+	for _, output := range transaction.Outputs {
+		scriptPublicKeyString := utxoindex.ScriptPublicKeyString(output.ScriptPublicKey.String())
+		if _, ok := nl.propagateMempoolChangedNotificationAddresses[scriptPublicKeyString]; ok {
+			return true
+		}
+	}
+	for _, input := range transaction.Inputs {
+		if input.UTXOEntry == nil {
+			continue
+		}
+		scriptPublicKeyString := utxoindex.ScriptPublicKeyString(input.UTXOEntry.ScriptPublicKey().String())
+		if _, ok := nl.propagateMempoolChangedNotificationAddresses[scriptPublicKeyString]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (nl *NotificationListener) convertUTXOChangesToUTXOsChangedNotification(
 	utxoChanges *utxoindex.UTXOChanges) (*appmessage.UTXOsChangedNotificationMessage, error) {
 
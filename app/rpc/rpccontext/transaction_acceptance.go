@@ -0,0 +1,47 @@
+package rpccontext
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/txindex"
+)
+
+// VirtualSelectedParentBlueScore returns the blue score of the current virtual selected parent
+func (ctx *Context) VirtualSelectedParentBlueScore() (uint64, error) {
+	virtualSelectedParent, err := ctx.Domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return 0, err
+	}
+	blockInfo, err := ctx.Domain.Consensus().GetBlockInfo(virtualSelectedParent)
+	if err != nil {
+		return 0, err
+	}
+	return blockInfo.BlueScore, nil
+}
+
+// ConvertTXAcceptanceToRPCTransactionAcceptance converts the acceptance of the given transaction,
+// as found in the transaction index, to its RPC representation. A nil acceptance denotes a
+// transaction that was not accepted.
+func ConvertTXAcceptanceToRPCTransactionAcceptance(transactionID *externalapi.DomainTransactionID,
+	acceptance *txindex.TXAcceptance, virtualSelectedParentBlueScore uint64) *appmessage.TransactionAcceptance {
+
+	if acceptance == nil {
+		return &appmessage.TransactionAcceptance{
+			TransactionID: transactionID.String(),
+			IsAccepted:    false,
+		}
+	}
+
+	confirmations := uint64(0)
+	if virtualSelectedParentBlueScore > acceptance.AcceptingBlockBlueScore {
+		confirmations = virtualSelectedParentBlueScore - acceptance.AcceptingBlockBlueScore
+	}
+	return &appmessage.TransactionAcceptance{
+		TransactionID:           transactionID.String(),
+		IsAccepted:              true,
+		IncludingBlockHash:      acceptance.IncludingBlockHash.String(),
+		AcceptingBlockHash:      acceptance.AcceptingBlockHash.String(),
+		AcceptingBlockBlueScore: acceptance.AcceptingBlockBlueScore,
+		Confirmations:           confirmations,
+	}
+}
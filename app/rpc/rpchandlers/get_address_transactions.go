@@ -0,0 +1,77 @@
+package rpchandlers
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/addresshistoryindex"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/txscript"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+	"github.com/stokesnetwork/stokes/util"
+)
+
+const (
+	defaultAddressTransactionsLimit = 100
+	maxAddressTransactionsLimit     = 1000
+)
+
+// HandleGetAddressTransactions handles the respectively named RPC command
+func HandleGetAddressTransactions(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.AddressHistoryIndex {
+		errorMessage := &appmessage.GetAddressTransactionsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --addresshistoryindex")
+		return errorMessage, nil
+	}
+
+	getAddressTransactionsRequest := request.(*appmessage.GetAddressTransactionsRequestMessage)
+
+	limit := int(getAddressTransactionsRequest.Limit)
+	if limit == 0 {
+		limit = defaultAddressTransactionsLimit
+	}
+	if limit > maxAddressTransactionsLimit {
+		errorMessage := &appmessage.GetAddressTransactionsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Limit %d is higher than the maximum allowed (%d)",
+			limit, maxAddressTransactionsLimit)
+		return errorMessage, nil
+	}
+
+	address, err := util.DecodeAddress(getAddressTransactionsRequest.Address, context.Config.ActiveNetParams.Prefix)
+	if err != nil {
+		errorMessage := &appmessage.GetAddressTransactionsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Couldn't decode address '%s': %s",
+			getAddressTransactionsRequest.Address, err)
+		return errorMessage, nil
+	}
+
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		errorMessage := &appmessage.GetAddressTransactionsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s",
+			getAddressTransactionsRequest.Address, err)
+		return errorMessage, nil
+	}
+
+	addressTransactions, nextPageToken, err := context.AddressHistoryIndex.AddressTransactions(scriptPublicKey,
+		getAddressTransactionsRequest.PageToken, limit)
+	if err != nil {
+		if errors.Is(err, addresshistoryindex.ErrInvalidPageToken) {
+			errorMessage := &appmessage.GetAddressTransactionsResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Invalid page token: %s", err)
+			return errorMessage, nil
+		}
+		return nil, err
+	}
+
+	rpcAddressTransactions := make([]*appmessage.AddressTransaction, len(addressTransactions))
+	for i, addressTransaction := range addressTransactions {
+		rpcAddressTransactions[i] = &appmessage.AddressTransaction{
+			TransactionID:     addressTransaction.TransactionID.String(),
+			AcceptingDAAScore: addressTransaction.AcceptingDAAScore,
+			IsCredit:          addressTransaction.IsCredit,
+			IsDebit:           addressTransaction.IsDebit,
+		}
+	}
+
+	return appmessage.NewGetAddressTransactionsResponseMessage(rpcAddressTransactions, nextPageToken), nil
+}
@@ -0,0 +1,29 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionid"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleGetBlockCandidateStatus handles the respectively named RPC command
+func HandleGetBlockCandidateStatus(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getBlockCandidateStatusRequest := request.(*appmessage.GetBlockCandidateStatusRequestMessage)
+
+	transactionID, err := transactionid.FromString(getBlockCandidateStatusRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.GetBlockCandidateStatusResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	status := context.Domain.MiningManager().GetBlockCandidateStatus(transactionID)
+
+	kind := ""
+	if status.IsInTransactionPool && !status.HasParentsInMempool {
+		kind = status.Kind.String()
+	}
+	return appmessage.NewGetBlockCandidateStatusResponseMessage(status.IsInTransactionPool, status.IsOrphan,
+		status.IsBlockCandidate, kind, status.Reason), nil
+}
@@ -22,6 +22,7 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 			AdvertisedProtocolVersion: peer.AdvertisedProtocolVersion(),
 			TimeConnected:             peer.TimeConnected().Milliseconds(),
 			IsIBDPeer:                 peer == ibdPeer,
+			BanScore:                  context.ConnectionManager.BanScore(peer.Connection()),
 		}
 		infos = append(infos, info)
 	}
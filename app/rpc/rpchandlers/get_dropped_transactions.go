@@ -0,0 +1,40 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionid"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleGetDroppedTransactions handles the respectively named RPC command
+func HandleGetDroppedTransactions(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getDroppedTransactionsRequest := request.(*appmessage.GetDroppedTransactionsRequestMessage)
+
+	if len(getDroppedTransactionsRequest.TransactionIDs) == 0 {
+		droppedTransactions := context.Domain.MiningManager().GetDroppedTransactions()
+		rpcDroppedTransactions := make([]*appmessage.RPCDroppedTransaction, len(droppedTransactions))
+		for i, droppedTransaction := range droppedTransactions {
+			rpcDroppedTransactions[i] = rpccontext.ConvertDroppedTransactionToRPCDroppedTransaction(droppedTransaction)
+		}
+		return appmessage.NewGetDroppedTransactionsResponseMessage(rpcDroppedTransactions), nil
+	}
+
+	rpcDroppedTransactions := make([]*appmessage.RPCDroppedTransaction, 0, len(getDroppedTransactionsRequest.TransactionIDs))
+	for _, transactionIDString := range getDroppedTransactionsRequest.TransactionIDs {
+		transactionID, err := transactionid.FromString(transactionIDString)
+		if err != nil {
+			errorMessage := &appmessage.GetDroppedTransactionsResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Transaction ID %s could not be parsed: %s", transactionIDString, err)
+			return errorMessage, nil
+		}
+
+		droppedTransaction, ok := context.Domain.MiningManager().GetDroppedTransaction(transactionID)
+		if !ok {
+			continue
+		}
+		rpcDroppedTransactions = append(rpcDroppedTransactions,
+			rpccontext.ConvertDroppedTransactionToRPCDroppedTransaction(droppedTransaction))
+	}
+	return appmessage.NewGetDroppedTransactionsResponseMessage(rpcDroppedTransactions), nil
+}
@@ -0,0 +1,34 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/emission"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleGetEmissionInfo handles the respectively named RPC command
+func HandleGetEmissionInfo(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	virtualDAAScore, err := context.Domain.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return nil, err
+	}
+
+	params := context.Config.ActiveNetParams
+	baseSubsidy := params.PreDeflationaryPhaseBaseSubsidy
+	halvingInterval := params.HalvingIntervalDaaScore
+
+	currentEra := emission.HalvingEra(virtualDAAScore, halvingInterval)
+	currentSubsidy := emission.BlockSubsidy(virtualDAAScore, baseSubsidy, halvingInterval)
+
+	var nextSubsidy, estimatedSecondsToNextHalving uint64
+	nextHalvingDAAScore, hasNextHalving := emission.NextHalvingDAAScore(virtualDAAScore, baseSubsidy, halvingInterval)
+	if hasNextHalving {
+		nextSubsidy = emission.BlockSubsidy(nextHalvingDAAScore, baseSubsidy, halvingInterval)
+		remainingDAAScore := nextHalvingDAAScore - virtualDAAScore
+		estimatedSecondsToNextHalving = uint64(float64(remainingDAAScore) * params.TargetTimePerBlock.Seconds())
+	}
+
+	return appmessage.NewGetEmissionInfoResponseMessage(virtualDAAScore, currentEra, currentSubsidy, halvingInterval,
+		nextHalvingDAAScore, nextSubsidy, estimatedSecondsToNextHalving), nil
+}
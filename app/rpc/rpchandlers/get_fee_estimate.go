@@ -0,0 +1,36 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleGetFeeEstimate handles the respectively named RPC command
+func HandleGetFeeEstimate(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	estimations := context.Domain.MiningManager().GetFeeEstimate()
+
+	response := appmessage.NewGetFeeEstimateResponseMessage()
+	response.Estimate = appmessage.RPCFeeEstimate{
+		PriorityBucket: feeRateBucketToRPC(estimations.PriorityBucket),
+		NormalBuckets:  feeRateBucketsToRPC(estimations.NormalBuckets),
+		LowBuckets:     feeRateBucketsToRPC(estimations.LowBuckets),
+	}
+	return response, nil
+}
+
+func feeRateBucketToRPC(bucket miningmanagermodel.FeeRateBucket) appmessage.RPCFeeRateBucket {
+	return appmessage.RPCFeeRateBucket{
+		Feerate:          bucket.FeeRate,
+		EstimatedSeconds: bucket.EstimatedSeconds,
+	}
+}
+
+func feeRateBucketsToRPC(buckets []miningmanagermodel.FeeRateBucket) []appmessage.RPCFeeRateBucket {
+	rpcBuckets := make([]appmessage.RPCFeeRateBucket, len(buckets))
+	for i, bucket := range buckets {
+		rpcBuckets[i] = feeRateBucketToRPC(bucket)
+	}
+	return rpcBuckets
+}
@@ -3,6 +3,7 @@ package rpchandlers
 import (
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
 )
 
@@ -25,6 +26,7 @@ func HandleGetMempoolEntries(context *rpccontext.Context, _ *router.Router, requ
 				Fee:         transaction.Fee,
 				Transaction: rpcTransaction,
 				IsOrphan:    false,
+				FeeDelta:    context.Domain.MiningManager().GetFeeDelta(consensushashing.TransactionID(transaction)),
 			})
 		}
 	}
@@ -39,6 +41,7 @@ func HandleGetMempoolEntries(context *rpccontext.Context, _ *router.Router, requ
 				Fee:         transaction.Fee,
 				Transaction: rpcTransaction,
 				IsOrphan:    true,
+				FeeDelta:    context.Domain.MiningManager().GetFeeDelta(consensushashing.TransactionID(transaction)),
 			})
 		}
 	}
@@ -3,6 +3,7 @@ package rpchandlers
 import (
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/txscript"
 
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
@@ -53,6 +54,7 @@ func HandleGetMempoolEntriesByAddresses(context *rpccontext.Context, _ *router.R
 					Fee:         transaction.Fee,
 					Transaction: rpcTransaction,
 					IsOrphan:    false,
+					FeeDelta:    context.Domain.MiningManager().GetFeeDelta(consensushashing.TransactionID(transaction)),
 				},
 				)
 			}
@@ -68,6 +70,7 @@ func HandleGetMempoolEntriesByAddresses(context *rpccontext.Context, _ *router.R
 					Fee:         transaction.Fee,
 					Transaction: rpcTransaction,
 					IsOrphan:    false,
+					FeeDelta:    context.Domain.MiningManager().GetFeeDelta(consensushashing.TransactionID(transaction)),
 				},
 				)
 			}
@@ -85,6 +88,7 @@ func HandleGetMempoolEntriesByAddresses(context *rpccontext.Context, _ *router.R
 					Fee:         transaction.Fee,
 					Transaction: rpcTransaction,
 					IsOrphan:    true,
+					FeeDelta:    context.Domain.MiningManager().GetFeeDelta(consensushashing.TransactionID(transaction)),
 				},
 				)
 			}
@@ -100,6 +104,7 @@ func HandleGetMempoolEntriesByAddresses(context *rpccontext.Context, _ *router.R
 					Fee:         transaction.Fee,
 					Transaction: rpcTransaction,
 					IsOrphan:    true,
+					FeeDelta:    context.Domain.MiningManager().GetFeeDelta(consensushashing.TransactionID(transaction)),
 				},
 				)
 			}
@@ -3,18 +3,12 @@ package rpchandlers
 import (
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
-	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionid"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
 )
 
 // HandleGetMempoolEntry handles the respectively named RPC command
 func HandleGetMempoolEntry(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
-
-	transaction := &externalapi.DomainTransaction{}
-	var found bool
-	var isOrphan bool
-
 	getMempoolEntryRequest := request.(*appmessage.GetMempoolEntryRequestMessage)
 
 	transactionID, err := transactionid.FromString(getMempoolEntryRequest.TxID)
@@ -28,7 +22,14 @@ func HandleGetMempoolEntry(context *rpccontext.Context, _ *router.Router, reques
 
 	if !found {
 		errorMessage := &appmessage.GetMempoolEntryResponseMessage{}
-		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found", transactionID)
+		droppedTransaction, wasDropped := context.Domain.MiningManager().GetDroppedTransaction(transactionID)
+		if !wasDropped {
+			errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found", transactionID)
+			return errorMessage, nil
+		}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found: it was %s from the mempool at %s",
+			transactionID, droppedTransaction.Reason, droppedTransaction.DroppedAt)
+		errorMessage.DroppedTransaction = rpccontext.ConvertDroppedTransactionToRPCDroppedTransaction(droppedTransaction)
 		return errorMessage, nil
 	}
 
@@ -37,5 +38,6 @@ func HandleGetMempoolEntry(context *rpccontext.Context, _ *router.Router, reques
 	if err != nil {
 		return nil, err
 	}
-	return appmessage.NewGetMempoolEntryResponseMessage(transaction.Fee, rpcTransaction, isOrphan), nil
+	feeDelta := context.Domain.MiningManager().GetFeeDelta(transactionID)
+	return appmessage.NewGetMempoolEntryResponseMessage(mempoolTransaction.Fee, rpcTransaction, isOrphan, feeDelta), nil
 }
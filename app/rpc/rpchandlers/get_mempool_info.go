@@ -0,0 +1,70 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// defaultMempoolFeeRateBucketBoundaries are the fee rates, in sompi/gram, at which the fee
+// rate histogram of GetMempoolInfo is split when the request doesn't specify any
+var defaultMempoolFeeRateBucketBoundaries = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+const maxMempoolFeeRateBucketBoundaries = 100
+
+// HandleGetMempoolInfo handles the respectively named RPC command
+func HandleGetMempoolInfo(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getMempoolInfoRequest := request.(*appmessage.GetMempoolInfoRequestMessage)
+
+	feeRateBucketBoundaries := getMempoolInfoRequest.FeeRateBucketBoundaries
+	if len(feeRateBucketBoundaries) == 0 {
+		feeRateBucketBoundaries = defaultMempoolFeeRateBucketBoundaries
+	}
+	if len(feeRateBucketBoundaries) > maxMempoolFeeRateBucketBoundaries {
+		errorMessage := appmessage.NewGetMempoolInfoResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Too many fee rate bucket boundaries: %d, the maximum is %d",
+			len(feeRateBucketBoundaries), maxMempoolFeeRateBucketBoundaries)
+		return errorMessage, nil
+	}
+	for i, boundary := range feeRateBucketBoundaries {
+		if !(boundary > 0) || (i > 0 && boundary <= feeRateBucketBoundaries[i-1]) {
+			errorMessage := appmessage.NewGetMempoolInfoResponseMessage()
+			errorMessage.Error = appmessage.RPCErrorf(
+				"Fee rate bucket boundaries must be positive and strictly increasing, but got %v", feeRateBucketBoundaries)
+			return errorMessage, nil
+		}
+	}
+
+	mempoolInfo := context.Domain.MiningManager().GetMempoolInfo(feeRateBucketBoundaries)
+
+	response := appmessage.NewGetMempoolInfoResponseMessage()
+	response.TransactionCount = uint64(mempoolInfo.TransactionCount)
+	response.OrphanCount = uint64(mempoolInfo.OrphanCount)
+	response.TotalMass = mempoolInfo.TotalMass
+	response.FeeRateHistogram = make([]*appmessage.RPCMempoolFeeRateBucket, len(mempoolInfo.FeeRateHistogram))
+	for i, bucket := range mempoolInfo.FeeRateHistogram {
+		response.FeeRateHistogram[i] = &appmessage.RPCMempoolFeeRateBucket{
+			MinimumFeerate:   bucket.MinimumFeeRate,
+			TransactionCount: uint64(bucket.Count),
+			TotalMass:        bucket.TotalMass,
+		}
+	}
+	response.MinimumFeerate = mempoolInfo.MinimumFeeRate
+	response.SpamTransactionCount = uint64(mempoolInfo.SpamTransactionCount)
+	response.FilteredTransactionCount = uint64(mempoolInfo.FilteredTransactionCount)
+
+	if mempoolInfo.TransactionCount > 0 {
+		virtualDAAScore, err := context.Domain.Consensus().GetVirtualDAAScore()
+		if err != nil {
+			return nil, err
+		}
+		response.OldestTransactionDAAScore = mempoolInfo.OldestTransactionDAAScore
+		if virtualDAAScore > mempoolInfo.OldestTransactionDAAScore {
+			ageDAAScore := virtualDAAScore - mempoolInfo.OldestTransactionDAAScore
+			response.EstimatedOldestTransactionAgeSeconds =
+				uint64(float64(ageDAAScore) * context.Config.ActiveNetParams.TargetTimePerBlock.Seconds())
+		}
+	}
+
+	return response, nil
+}
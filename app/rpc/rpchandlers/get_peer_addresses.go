@@ -1,9 +1,6 @@
 package rpchandlers
 
 import (
-	"net"
-	"strconv"
-
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
@@ -14,14 +11,14 @@ func HandleGetPeerAddresses(context *rpccontext.Context, _ *router.Router, _ app
 	netAddresses := context.AddressManager.Addresses()
 	addressMessages := make([]*appmessage.GetPeerAddressesKnownAddressMessage, len(netAddresses))
 	for i, netAddress := range netAddresses {
-		addressWithPort := net.JoinHostPort(netAddress.IP.String(), strconv.FormatUint(uint64(netAddress.Port), 10))
+		addressWithPort := netAddress.String()
 		addressMessages[i] = &appmessage.GetPeerAddressesKnownAddressMessage{Addr: addressWithPort}
 	}
 
 	bannedAddresses := context.AddressManager.BannedAddresses()
 	bannedAddressMessages := make([]*appmessage.GetPeerAddressesKnownAddressMessage, len(bannedAddresses))
 	for i, netAddress := range bannedAddresses {
-		addressWithPort := net.JoinHostPort(netAddress.IP.String(), strconv.FormatUint(uint64(netAddress.Port), 10))
+		addressWithPort := netAddress.String()
 		bannedAddressMessages[i] = &appmessage.GetPeerAddressesKnownAddressMessage{Addr: addressWithPort}
 	}
 
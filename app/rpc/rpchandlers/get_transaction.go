@@ -0,0 +1,74 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionid"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleGetTransaction handles the respectively named RPC command
+func HandleGetTransaction(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.TXIndex {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --txindex")
+		return errorMessage, nil
+	}
+
+	getTransactionRequest := request.(*appmessage.GetTransactionRequestMessage)
+	transactionID, err := transactionid.FromString(getTransactionRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	acceptance, found, err := context.TXIndex.TXAcceptance(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not accepted by the selected chain", transactionID)
+		return errorMessage, nil
+	}
+
+	block, found, err := context.Domain.Consensus().GetBlock(acceptance.IncludingBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("The body of block %s, which contains transaction %s, "+
+			"is not available", acceptance.IncludingBlockHash, transactionID)
+		return errorMessage, nil
+	}
+
+	var rpcTransaction *appmessage.RPCTransaction
+	for _, transaction := range block.Transactions {
+		if consensushashing.TransactionID(transaction).Equal(transactionID) {
+			rpcTransaction = appmessage.DomainTransactionToRPCTransaction(transaction)
+			break
+		}
+	}
+	if rpcTransaction == nil {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found in block %s",
+			transactionID, acceptance.IncludingBlockHash)
+		return errorMessage, nil
+	}
+	err = context.PopulateTransactionWithVerboseData(rpcTransaction, block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualSelectedParentBlueScore, err := context.VirtualSelectedParentBlueScore()
+	if err != nil {
+		return nil, err
+	}
+
+	return appmessage.NewGetTransactionResponseMessage(rpcTransaction,
+		rpccontext.ConvertTXAcceptanceToRPCTransactionAcceptance(transactionID, acceptance,
+			virtualSelectedParentBlueScore)), nil
+}
@@ -0,0 +1,47 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionid"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleGetTransactionAcceptance handles the respectively named RPC command
+func HandleGetTransactionAcceptance(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.TXIndex {
+		errorMessage := &appmessage.GetTransactionAcceptanceResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --txindex")
+		return errorMessage, nil
+	}
+
+	getTransactionAcceptanceRequest := request.(*appmessage.GetTransactionAcceptanceRequestMessage)
+
+	virtualSelectedParentBlueScore, err := context.VirtualSelectedParentBlueScore()
+	if err != nil {
+		return nil, err
+	}
+
+	transactionAcceptances := make([]*appmessage.TransactionAcceptance, len(getTransactionAcceptanceRequest.TransactionIDs))
+	for i, transactionIDString := range getTransactionAcceptanceRequest.TransactionIDs {
+		transactionID, err := transactionid.FromString(transactionIDString)
+		if err != nil {
+			errorMessage := &appmessage.GetTransactionAcceptanceResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Transaction ID %s could not be parsed: %s",
+				transactionIDString, err)
+			return errorMessage, nil
+		}
+
+		acceptance, found, err := context.TXIndex.TXAcceptance(transactionID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			acceptance = nil
+		}
+		transactionAcceptances[i] = rpccontext.ConvertTXAcceptanceToRPCTransactionAcceptance(transactionID,
+			acceptance, virtualSelectedParentBlueScore)
+	}
+
+	return appmessage.NewGetTransactionAcceptanceResponseMessage(transactionAcceptances), nil
+}
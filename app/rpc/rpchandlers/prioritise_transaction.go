@@ -0,0 +1,39 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionid"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandlePrioritiseTransaction handles the respectively named RPC command
+func HandlePrioritiseTransaction(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("PrioritiseTransaction RPC command called while node in safe RPC mode -- ignoring.")
+		response := &appmessage.PrioritiseTransactionResponseMessage{}
+		response.Error =
+			appmessage.RPCErrorf("PrioritiseTransaction RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	prioritiseTransactionRequest := request.(*appmessage.PrioritiseTransactionRequestMessage)
+
+	transactionID, err := transactionid.FromString(prioritiseTransactionRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.PrioritiseTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	feeDelta, err := context.Domain.MiningManager().PrioritiseTransaction(transactionID, prioritiseTransactionRequest.FeeDelta)
+	if err != nil {
+		errorMessage := &appmessage.PrioritiseTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not prioritise transaction %s: %s", transactionID, err)
+		return errorMessage, nil
+	}
+	log.Infof("Transaction %s was prioritised by %d sompi, and its fee delta is now %d sompi",
+		transactionID, prioritiseTransactionRequest.FeeDelta, feeDelta)
+
+	return appmessage.NewPrioritiseTransactionResponseMessage(feeDelta), nil
+}
@@ -0,0 +1,44 @@
+package rpchandlers
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleSubmitTransactionPackage handles the respectively named RPC command
+func HandleSubmitTransactionPackage(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	submitTransactionPackageRequest := request.(*appmessage.SubmitTransactionPackageRequestMessage)
+
+	domainTransactions := make([]*externalapi.DomainTransaction, len(submitTransactionPackageRequest.Transactions))
+	transactionIDs := make([]string, len(submitTransactionPackageRequest.Transactions))
+	for i, transaction := range submitTransactionPackageRequest.Transactions {
+		domainTransaction, err := appmessage.RPCTransactionToDomainTransaction(transaction)
+		if err != nil {
+			errorMessage := &appmessage.SubmitTransactionPackageResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not parse transaction #%d: %s", i, err)
+			return errorMessage, nil
+		}
+		domainTransactions[i] = domainTransaction
+		transactionIDs[i] = consensushashing.TransactionID(domainTransaction).String()
+	}
+
+	err := context.ProtocolManager.AddTransactionPackage(domainTransactions)
+	if err != nil {
+		if !errors.As(err, &mempool.RuleError{}) {
+			return nil, err
+		}
+
+		log.Debugf("Rejected transaction package %s: %s", transactionIDs, err)
+		// Return the IDs also in the case of error, so that clients can match the response to the correct request
+		errorMessage := appmessage.NewSubmitTransactionPackageResponseMessage(transactionIDs)
+		errorMessage.Error = appmessage.RPCErrorf("Rejected transaction package: %s", err)
+		return errorMessage, nil
+	}
+
+	return appmessage.NewSubmitTransactionPackageResponseMessage(transactionIDs), nil
+}
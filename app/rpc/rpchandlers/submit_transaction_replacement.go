@@ -0,0 +1,40 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// HandleSubmitTransactionReplacement handles the respectively named RPC command
+func HandleSubmitTransactionReplacement(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	submitTransactionReplacementRequest := request.(*appmessage.SubmitTransactionReplacementRequestMessage)
+
+	domainTransaction, err := appmessage.RPCTransactionToDomainTransaction(submitTransactionReplacementRequest.Transaction)
+	if err != nil {
+		errorMessage := &appmessage.SubmitTransactionReplacementResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse transaction: %s", err)
+		return errorMessage, nil
+	}
+
+	transactionID := consensushashing.TransactionID(domainTransaction)
+	replacedTransaction, err := context.ProtocolManager.AddTransactionReplacement(domainTransaction)
+	if err != nil {
+		if !errors.As(err, &mempool.RuleError{}) {
+			return nil, err
+		}
+
+		log.Debugf("Rejected transaction replacement %s: %s", transactionID, err)
+		// Return the ID also in the case of error, so that clients can match the response to the correct transaction submit request
+		errorMessage := appmessage.NewSubmitTransactionReplacementResponseMessage(transactionID.String())
+		errorMessage.Error = appmessage.RPCErrorf("Rejected transaction %s: %s", transactionID, err)
+		return errorMessage, nil
+	}
+
+	response := appmessage.NewSubmitTransactionReplacementResponseMessage(transactionID.String())
+	response.ReplacedTransaction = appmessage.DomainTransactionToRPCTransaction(replacedTransaction)
+	return response, nil
+}
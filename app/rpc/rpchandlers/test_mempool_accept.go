@@ -0,0 +1,44 @@
+package rpchandlers
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/rpc/rpccontext"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// HandleTestMempoolAccept handles the respectively named RPC command
+func HandleTestMempoolAccept(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	testMempoolAcceptRequest := request.(*appmessage.TestMempoolAcceptRequestMessage)
+
+	if len(testMempoolAcceptRequest.Transactions) > mempool.MaximumTestMempoolAcceptCount {
+		errorMessage := &appmessage.TestMempoolAcceptResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Can't check %d transactions at once, while the maximum is %d",
+			len(testMempoolAcceptRequest.Transactions), mempool.MaximumTestMempoolAcceptCount)
+		return errorMessage, nil
+	}
+
+	domainTransactions := make([]*externalapi.DomainTransaction, len(testMempoolAcceptRequest.Transactions))
+	for i, transaction := range testMempoolAcceptRequest.Transactions {
+		domainTransaction, err := appmessage.RPCTransactionToDomainTransaction(transaction)
+		if err != nil {
+			errorMessage := &appmessage.TestMempoolAcceptResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not parse transaction #%d: %s", i, err)
+			return errorMessage, nil
+		}
+		domainTransactions[i] = domainTransaction
+	}
+
+	// Transactions submitted through RPC are high priority, so they're checked as such
+	acceptances, err := context.Domain.MiningManager().TestMempoolAccept(domainTransactions, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcAcceptances := make([]*appmessage.RPCMempoolAcceptance, len(acceptances))
+	for i, acceptance := range acceptances {
+		rpcAcceptances[i] = rpccontext.ConvertMempoolAcceptanceToRPCMempoolAcceptance(acceptance)
+	}
+	return appmessage.NewTestMempoolAcceptResponseMessage(rpcAcceptances), nil
+}
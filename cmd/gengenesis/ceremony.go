@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/pow"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+	"github.com/stokesnetwork/stokes/util/difficulty"
+)
+
+// maxMessageLength keeps the length of the coinbase script, which is the message
+// prefixed by OP_FALSE, encodable as a single byte varint.
+const maxMessageLength = 0xfc - 1
+
+var networkNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// ceremony is the input of a genesis ceremony. Everything that goes into the
+// genesis block is in it, so anyone can re-run the ceremony and get the same block.
+type ceremony struct {
+	// Network is the short name of the network, such as "mainnet". It names the
+	// Go variables of the generated source.
+	Network string `json:"network"`
+
+	// Message is embedded in the coinbase script of the genesis block.
+	Message string `json:"message"`
+
+	TimeInMilliseconds int64  `json:"timeInMilliseconds"`
+	Bits               uint32 `json:"bits"`
+	Subsidy            uint64 `json:"subsidy"`
+
+	// ExternalEntropy is optional. It is meant to be something that can't be
+	// known before the ceremony, such as a recent Bitcoin block hash, and proves
+	// that the genesis block wasn't mined in advance. It's appended to the
+	// coinbase payload as extra data.
+	ExternalEntropy string `json:"externalEntropy,omitempty"`
+}
+
+func loadCeremony(path string) (*ceremony, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't open the ceremony file")
+	}
+	defer file.Close()
+
+	return parseCeremony(file)
+}
+
+func parseCeremony(reader io.Reader) (*ceremony, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+
+	c := &ceremony{}
+	err := decoder.Decode(c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't parse the ceremony file")
+	}
+
+	err = c.validate()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ceremony) validate() error {
+	if !networkNameRegexp.MatchString(c.Network) {
+		return errors.Errorf("the network name %q must be lowercase alphanumeric, such as \"mainnet\"", c.Network)
+	}
+	if c.Message == "" {
+		return errors.New("the message must not be empty")
+	}
+	if len(c.Message) > maxMessageLength {
+		return errors.Errorf("the message is %d bytes long, which is more than %d", len(c.Message), maxMessageLength)
+	}
+	if c.TimeInMilliseconds <= 0 {
+		return errors.New("the timestamp must be set")
+	}
+	if difficulty.CompactToBig(c.Bits).Sign() <= 0 {
+		return errors.Errorf("the bits 0x%08x encode a target that isn't positive", c.Bits)
+	}
+	if c.Subsidy > constants.MaxSompi {
+		return errors.Errorf("the subsidy %d is more than the maximal amount of %d sompi", c.Subsidy, constants.MaxSompi)
+	}
+	return nil
+}
+
+// coinbasePayload returns the payload of the genesis coinbase transaction, which has the
+// layout of a regular coinbase payload: blue score, subsidy, script version, script and
+// extra data. The script is the message prefixed by OP_FALSE, so that it can never be spent.
+func (c *ceremony) coinbasePayload() []byte {
+	script := append([]byte{0x00}, []byte(c.Message)...)
+
+	payload := make([]byte, 0, 8+8+2+1+len(script)+len(c.ExternalEntropy))
+	payload = binary.LittleEndian.AppendUint64(payload, 0)
+	payload = binary.LittleEndian.AppendUint64(payload, c.Subsidy)
+	payload = binary.LittleEndian.AppendUint16(payload, 0)
+	payload = append(payload, byte(len(script)))
+	payload = append(payload, script...)
+	payload = append(payload, []byte(c.ExternalEntropy)...)
+	return payload
+}
+
+// genesisBlock returns the genesis block of the ceremony with the given nonce
+func (c *ceremony) genesisBlock(nonce uint64) *externalapi.DomainBlock {
+	return dagconfig.NewGenesisBlock(0, c.coinbasePayload(), c.TimeInMilliseconds, c.Bits, nonce)
+}
+
+// mine searches for the lowest nonce that satisfies the proof of work of the genesis
+// block. Since the search always starts from zero, the result depends only on the
+// ceremony, which is what makes it reproducible. onProgress, if not nil, is called
+// every progressInterval nonces.
+func (c *ceremony) mine(maxNonce uint64, onProgress func(nonce uint64)) (*externalapi.DomainBlock, error) {
+	const progressInterval = 1 << 20
+
+	state := pow.NewState(c.genesisBlock(0).Header.ToMutable())
+	for {
+		if state.CheckProofOfWork() {
+			return c.genesisBlock(state.Nonce), nil
+		}
+		if state.Nonce == maxNonce {
+			return nil, errors.Errorf("no nonce up to %d satisfies the target of bits 0x%08x", maxNonce, c.Bits)
+		}
+		state.IncrementNonce()
+		if onProgress != nil && state.Nonce%progressInterval == 0 {
+			onProgress(state.Nonce)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+)
+
+const (
+	generateSubCmd = "generate"
+	verifySubCmd   = "verify"
+)
+
+type generateConfig struct {
+	CeremonyFile string `long:"ceremony" short:"c" description:"The JSON file with the inputs of the ceremony" required:"true"`
+	OutputDir    string `long:"output-dir" short:"o" description:"The directory to write the Go source and the JSON description of the genesis block to"`
+	MaxNonce     uint64 `long:"max-nonce" description:"Give up if no nonce up to this one satisfies the target"`
+}
+
+type verifyConfig struct {
+	Networks      []string `long:"net" description:"Verify only the genesis block of the given network, such as stokes-testnet. Can be given multiple times"`
+	NetParamsFile string   `long:"netparams" description:"Also verify the genesis block of the custom network in the given JSON file"`
+	CeremonyFile  string   `long:"ceremony" short:"c" description:"Also check that the given ceremony reproduces the genesis block of its network"`
+}
+
+func parseCommandLine() (subCommand string, config interface{}) {
+	parser := flags.NewParser(&struct{}{}, flags.PrintErrors|flags.HelpFlag)
+
+	generateConf := &generateConfig{OutputDir: ".", MaxNonce: math.MaxUint64}
+	parser.AddCommand(generateSubCmd, "Generate a genesis block from a ceremony file",
+		"Mine the genesis block described by a ceremony file, starting from nonce zero so that the result is "+
+			"reproducible, and write it both as Go source for domain/dagconfig/genesis.go and as a JSON description",
+		generateConf)
+
+	verifyConf := &verifyConfig{}
+	parser.AddCommand(verifySubCmd, "Verify the genesis blocks of the known networks",
+		"Recompute the hash, the merkle roots, the UTXO commitment and the proof of work of the genesis blocks "+
+			"in dagconfig, and optionally check that they are reproduced by a ceremony file", verifyConf)
+
+	_, err := parser.Parse()
+	if err != nil {
+		var flagsErr *flags.Error
+		if ok := errors.As(err, &flagsErr); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+		return "", nil
+	}
+
+	switch parser.Command.Active.Name {
+	case generateSubCmd:
+		config = generateConf
+	case verifySubCmd:
+		config = verifyConf
+	}
+	return parser.Command.Active.Name, config
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+)
+
+func generate(conf *generateConfig) error {
+	c, err := loadCeremony(conf.CeremonyFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Mining the %s genesis block with bits 0x%08x\n", c.Network, c.Bits)
+	genesis, err := c.mine(conf.MaxNonce, func(nonce uint64) {
+		fmt.Printf("Tried %d nonces\n", nonce)
+	})
+	if err != nil {
+		return err
+	}
+
+	source, err := goSource(c, genesis)
+	if err != nil {
+		return err
+	}
+	description, err := jsonDescription(c, genesis)
+	if err != nil {
+		return err
+	}
+
+	sourcePath := filepath.Join(conf.OutputDir, c.Network+"-genesis.go.txt")
+	err = os.WriteFile(sourcePath, source, 0644)
+	if err != nil {
+		return err
+	}
+	descriptionPath := filepath.Join(conf.OutputDir, c.Network+"-genesis.json")
+	err = os.WriteFile(descriptionPath, description, 0644)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Genesis hash: %s\n", consensushashing.BlockHash(genesis))
+	fmt.Printf("Nonce:        %d\n", genesis.Header.Nonce())
+	fmt.Printf("Wrote %s and %s\n", sourcePath, descriptionPath)
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+)
+
+func testCeremony() *ceremony {
+	return &ceremony{
+		Network:            "privnet",
+		Message:            "stokes-privnet",
+		TimeInMilliseconds: 1760309945665,
+		Bits:               0x207fffff,
+		Subsidy:            50 * 100_000_000,
+		ExternalEntropy:    "00000000000000000001a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7",
+	}
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	c := testCeremony()
+	genesis, err := c.mine(1000, nil)
+	if err != nil {
+		t.Fatalf("mine: %+v", err)
+	}
+	again, err := testCeremony().mine(1000, nil)
+	if err != nil {
+		t.Fatalf("mine: %+v", err)
+	}
+	if !consensushashing.BlockHash(genesis).Equal(consensushashing.BlockHash(again)) {
+		t.Fatalf("Expected the same ceremony to result in the same genesis block")
+	}
+
+	withoutEntropy := testCeremony()
+	withoutEntropy.ExternalEntropy = ""
+	genesisWithoutEntropy, err := withoutEntropy.mine(1000, nil)
+	if err != nil {
+		t.Fatalf("mine: %+v", err)
+	}
+	if consensushashing.BlockHash(genesis).Equal(consensushashing.BlockHash(genesisWithoutEntropy)) {
+		t.Fatalf("Expected the external entropy to be committed to by the genesis block")
+	}
+
+	impossible := testCeremony()
+	impossible.Bits = 0x03000001
+	_, err = impossible.mine(100, nil)
+	if err == nil {
+		t.Fatalf("Expected mining with a tiny target to give up at the maximal nonce")
+	}
+}
+
+func TestGeneratedGenesisVerifies(t *testing.T) {
+	c := testCeremony()
+	genesis, err := c.mine(1000, nil)
+	if err != nil {
+		t.Fatalf("mine: %+v", err)
+	}
+
+	source, err := goSource(c, genesis)
+	if err != nil {
+		t.Fatalf("goSource: %+v", err)
+	}
+	if !strings.Contains(string(source), "var privnetGenesisBlock = externalapi.DomainBlock{") {
+		t.Fatalf("Unexpected Go source:\n%s", source)
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), "genesis.go", append([]byte("package dagconfig\n"), source...), 0)
+	if err != nil {
+		t.Fatalf("The generated Go source doesn't parse: %+v", err)
+	}
+
+	// The genesis of the JSON description can be used as is in a --netparams file
+	serializedDescription, err := jsonDescription(c, genesis)
+	if err != nil {
+		t.Fatalf("jsonDescription: %+v", err)
+	}
+	description := map[string]json.RawMessage{}
+	err = json.Unmarshal(serializedDescription, &description)
+	if err != nil {
+		t.Fatalf("Unmarshal: %+v", err)
+	}
+	paramsFile, err := json.Marshal(map[string]interface{}{
+		"name":        "stokes-privnet",
+		"net":         0x5053544B,
+		"rpcPort":     "17710",
+		"defaultPort": "17711",
+		"prefix":      "stokessim",
+		"powMax":      dagconfig.SimnetParams.PowMax.Text(16),
+		"genesis":     description["genesis"],
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %+v", err)
+	}
+	params, err := dagconfig.ParseParams(bytes.NewReader(paramsFile))
+	if err != nil {
+		t.Fatalf("ParseParams: %+v", err)
+	}
+
+	for _, check := range verifyGenesis(params) {
+		if check.err != nil {
+			t.Fatalf("Check %s failed: %+v", check.name, check.err)
+		}
+	}
+	if !isNetworkOfCeremony(params, c) {
+		t.Fatalf("Expected %s to be the network of the ceremony", params.Name)
+	}
+	err = reproduce(params, c)
+	if err != nil {
+		t.Fatalf("reproduce: %+v", err)
+	}
+
+	otherMessage := testCeremony()
+	otherMessage.Message = "stokes-othernet"
+	err = reproduce(params, otherMessage)
+	if err == nil {
+		t.Fatalf("Expected a different ceremony not to reproduce the genesis block")
+	}
+}
+
+func TestVerifyGenesisDetectsMismatches(t *testing.T) {
+	params := dagconfig.SimnetParams
+	params.GenesisHash = dagconfig.TestnetParams.GenesisHash
+
+	failedChecks := map[string]bool{}
+	for _, check := range verifyGenesis(&params) {
+		if check.err != nil {
+			failedChecks[check.name] = true
+		}
+	}
+	if !failedChecks["hash"] {
+		t.Fatalf("Expected the hash check to fail")
+	}
+	if failedChecks["hash merkle root"] || failedChecks["UTXO commitment"] || failedChecks["structure"] {
+		t.Fatalf("Expected only the hash related checks to fail, but got %v", failedChecks)
+	}
+}
+
+func TestParseCeremonyInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		expectedError string
+	}{
+		{"unknown field", `{"network":"privnet","message":"m","timeInMilliseconds":1,"bits":545259519,"nonce":5}`,
+			"unknown field"},
+		{"bad network", `{"network":"Priv Net","message":"m","timeInMilliseconds":1,"bits":545259519}`,
+			"network name"},
+		{"no message", `{"network":"privnet","timeInMilliseconds":1,"bits":545259519}`, "message"},
+		{"no timestamp", `{"network":"privnet","message":"m","bits":545259519}`, "timestamp"},
+		{"zero target", `{"network":"privnet","message":"m","timeInMilliseconds":1,"bits":298590}`, "target"},
+	}
+
+	for _, test := range tests {
+		_, err := parseCeremony(strings.NewReader(test.file))
+		if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("%s: expected an error containing %q, but got %v", test.name, test.expectedError, err)
+		}
+	}
+}
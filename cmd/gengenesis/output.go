@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/util/difficulty"
+)
+
+var goSourceTemplate = template.Must(template.New("genesis").Funcs(template.FuncMap{
+	"bytes": formatBytes,
+}).Parse(`// ============================================================
+// {{.Title}} GENESIS - STOKES
+// ============================================================
+//
+// Generated by gengenesis from the following ceremony:
+//   Message:          {{.Ceremony.Message}}
+//   Timestamp:        {{.Ceremony.TimeInMilliseconds}}
+//   Bits:             0x{{printf "%08x" .Ceremony.Bits}}
+//   Subsidy:          {{.Ceremony.Subsidy}}
+{{- if .Ceremony.ExternalEntropy}}
+//   External entropy: {{.Ceremony.ExternalEntropy}}
+{{- end}}
+
+var {{.Prefix}}TxOuts = []*externalapi.DomainTransactionOutput{}
+
+var {{.Prefix}}TxPayload = []byte{
+{{bytes .Payload}}}
+
+// {{.Prefix}}CoinbaseTx is the coinbase transaction for the {{.Network}} genesis block.
+var {{.Prefix}}CoinbaseTx = transactionhelper.NewSubnetworkTransaction(0,
+	[]*externalapi.DomainTransactionInput{}, {{.Prefix}}TxOuts,
+	&subnetworks.SubnetworkIDCoinbase, 0, {{.Prefix}}TxPayload)
+
+// {{.Prefix}}Hash is the hash of the first block in the block DAG for {{.Network}}
+// (genesis block).
+var {{.Prefix}}Hash = externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{
+{{bytes .Hash}}})
+
+// {{.Prefix}}MerkleRoot is the hash of the first transaction in the genesis block
+// for {{.Network}}.
+var {{.Prefix}}MerkleRoot = externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{
+{{bytes .MerkleRoot}}})
+
+// {{.Prefix}}Block defines the genesis block of the block DAG which serves as the
+// public transaction ledger for {{.Network}}.
+var {{.Prefix}}Block = externalapi.DomainBlock{
+	Header: blockheader.NewImmutableBlockHeader(
+		{{.Header.Version}},
+		[]externalapi.BlockLevelParents{},
+		{{.Prefix}}MerkleRoot,
+		&externalapi.DomainHash{},
+		externalapi.NewDomainHashFromByteArray(muhash.EmptyMuHashHash.AsArray()),
+		{{.Header.TimeInMilliseconds}},
+		{{.Header.Bits}},
+		{{.Header.Nonce}},
+		0,
+		0,
+		big.NewInt(0),
+		&externalapi.DomainHash{},
+	),
+	Transactions: []*externalapi.DomainTransaction{ {{- .Prefix}}CoinbaseTx},
+}
+`))
+
+// goVariablePrefix returns the prefix of the Go variables of the genesis block of the
+// given network, following the naming in domain/dagconfig/genesis.go
+func goVariablePrefix(network string) string {
+	if network == "mainnet" {
+		return "genesis"
+	}
+	return network + "Genesis"
+}
+
+// goSource returns the section of domain/dagconfig/genesis.go that defines the genesis
+// block of the ceremony
+func goSource(c *ceremony, genesis *externalapi.DomainBlock) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	err := goSourceTemplate.Execute(buffer, map[string]interface{}{
+		"Title":      strings.ToUpper(c.Network),
+		"Network":    c.Network,
+		"Ceremony":   c,
+		"Prefix":     goVariablePrefix(c.Network),
+		"Payload":    genesis.Transactions[0].Payload,
+		"Hash":       consensushashing.BlockHash(genesis).ByteSlice(),
+		"MerkleRoot": genesis.Header.HashMerkleRoot().ByteSlice(),
+		"Header":     genesis.Header,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't format the generated source")
+	}
+	return source, nil
+}
+
+func formatBytes(data []byte) string {
+	const bytesPerLine = 8
+
+	builder := &strings.Builder{}
+	for i := 0; i < len(data); i += bytesPerLine {
+		builder.WriteString("\t")
+		end := i + bytesPerLine
+		if end > len(data) {
+			end = len(data)
+		}
+		for j := i; j < end; j++ {
+			fmt.Fprintf(builder, "0x%02x,", data[j])
+			if j != end-1 {
+				builder.WriteString(" ")
+			}
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// genesisDescription is the JSON description of a genesis block. Its genesis field
+// has the format of the genesis block in a --netparams file.
+type genesisDescription struct {
+	Ceremony             *ceremony               `json:"ceremony"`
+	Genesis              genesisDescriptionBlock `json:"genesis"`
+	HashMerkleRoot       string                  `json:"hashMerkleRoot"`
+	AcceptedIDMerkleRoot string                  `json:"acceptedIDMerkleRoot"`
+	UTXOCommitment       string                  `json:"utxoCommitment"`
+	Target               string                  `json:"target"`
+}
+
+type genesisDescriptionBlock struct {
+	Version            uint16 `json:"version"`
+	TimeInMilliseconds int64  `json:"timeInMilliseconds"`
+	Bits               uint32 `json:"bits"`
+	Nonce              uint64 `json:"nonce"`
+	CoinbasePayload    string `json:"coinbasePayload"`
+	Hash               string `json:"hash"`
+}
+
+func jsonDescription(c *ceremony, genesis *externalapi.DomainBlock) ([]byte, error) {
+	header := genesis.Header
+	description := &genesisDescription{
+		Ceremony: c,
+		Genesis: genesisDescriptionBlock{
+			Version:            header.Version(),
+			TimeInMilliseconds: header.TimeInMilliseconds(),
+			Bits:               header.Bits(),
+			Nonce:              header.Nonce(),
+			CoinbasePayload:    hex.EncodeToString(genesis.Transactions[0].Payload),
+			Hash:               consensushashing.BlockHash(genesis).String(),
+		},
+		HashMerkleRoot:       header.HashMerkleRoot().String(),
+		AcceptedIDMerkleRoot: header.AcceptedIDMerkleRoot().String(),
+		UTXOCommitment:       header.UTXOCommitment().String(),
+		Target:               fmt.Sprintf("%064x", difficulty.CompactToBig(header.Bits())),
+	}
+
+	data, err := json.MarshalIndent(description, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/go-muhash"
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/merkle"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/pow"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/subnetworks"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+	"github.com/stokesnetwork/stokes/util/difficulty"
+)
+
+// check is the result of a single check of a genesis block. err is nil if it passed.
+type check struct {
+	name string
+	err  error
+}
+
+func verify(conf *verifyConfig) error {
+	allParams, err := paramsToVerify(conf)
+	if err != nil {
+		return err
+	}
+
+	var ceremonyToReproduce *ceremony
+	if conf.CeremonyFile != "" {
+		ceremonyToReproduce, err = loadCeremony(conf.CeremonyFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	failed := false
+	reproduced := false
+	for _, params := range allParams {
+		fmt.Printf("%s\n", params.Name)
+		if params.GenesisBlock == nil || params.GenesisHash == nil {
+			fmt.Printf("  no genesis block is defined\n")
+			continue
+		}
+
+		checks := verifyGenesis(params)
+		if ceremonyToReproduce != nil && isNetworkOfCeremony(params, ceremonyToReproduce) {
+			checks = append(checks, check{"reproduced by the ceremony", reproduce(params, ceremonyToReproduce)})
+			reproduced = true
+		}
+
+		for _, check := range checks {
+			if check.err != nil {
+				failed = true
+				fmt.Printf("  %-28s FAILED: %s\n", check.name, check.err)
+			} else {
+				fmt.Printf("  %-28s ok\n", check.name)
+			}
+		}
+		fmt.Printf("  %-28s %s\n", "genesis hash", params.GenesisHash)
+	}
+
+	if ceremonyToReproduce != nil && !reproduced {
+		return errors.Errorf("none of the verified networks is the network %s of the ceremony", ceremonyToReproduce.Network)
+	}
+	if failed {
+		return errors.New("the verification failed")
+	}
+	return nil
+}
+
+func paramsToVerify(conf *verifyConfig) ([]*dagconfig.Params, error) {
+	allParams := []*dagconfig.Params{
+		&dagconfig.MainnetParams,
+		&dagconfig.TestnetParams,
+		&dagconfig.SimnetParams,
+		&dagconfig.DevnetParams,
+	}
+	if conf.NetParamsFile != "" {
+		params, err := dagconfig.LoadParamsFile(conf.NetParamsFile)
+		if err != nil {
+			return nil, err
+		}
+		allParams = append(allParams, params)
+	}
+
+	if len(conf.Networks) == 0 {
+		return allParams, nil
+	}
+
+	selectedParams := make([]*dagconfig.Params, 0, len(conf.Networks))
+	for _, network := range conf.Networks {
+		found := false
+		for _, params := range allParams {
+			if params.Name == network {
+				selectedParams = append(selectedParams, params)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown network %s", network)
+		}
+	}
+	return selectedParams, nil
+}
+
+// verifyGenesis recomputes everything in the genesis block of the given params that
+// can be derived from the rest of it
+func verifyGenesis(params *dagconfig.Params) []check {
+	genesis := params.GenesisBlock
+	header := genesis.Header
+
+	return []check{
+		{"structure", verifyStructure(genesis)},
+		{"hash merkle root", expectHash(header.HashMerkleRoot(), merkle.CalculateHashMerkleRoot(genesis.Transactions))},
+		{"accepted ID merkle root", expectHash(header.AcceptedIDMerkleRoot(), &externalapi.DomainHash{})},
+		{"UTXO commitment", expectHash(header.UTXOCommitment(),
+			externalapi.NewDomainHashFromByteArray(muhash.EmptyMuHashHash.AsArray()))},
+		{"hash", expectHash(params.GenesisHash, consensushashing.BlockHash(genesis))},
+		{"proof of work", verifyProofOfWork(params)},
+	}
+}
+
+func verifyStructure(genesis *externalapi.DomainBlock) error {
+	header := genesis.Header
+	if len(header.Parents()) != 0 {
+		return errors.New("the genesis block has parents")
+	}
+	if header.DAAScore() != 0 || header.BlueScore() != 0 || header.BlueWork().Sign() != 0 {
+		return errors.New("the DAA score, blue score and blue work of the genesis block must be zero")
+	}
+	if !header.PruningPoint().Equal(&externalapi.DomainHash{}) {
+		return errors.New("the pruning point of the genesis block must be zero")
+	}
+	if len(genesis.Transactions) != 1 {
+		return errors.Errorf("the genesis block has %d transactions instead of a single coinbase", len(genesis.Transactions))
+	}
+	coinbase := genesis.Transactions[0]
+	if !coinbase.SubnetworkID.Equal(&subnetworks.SubnetworkIDCoinbase) {
+		return errors.New("the transaction of the genesis block isn't a coinbase")
+	}
+	if len(coinbase.Inputs) != 0 || len(coinbase.Outputs) != 0 {
+		return errors.New("the genesis coinbase must have no inputs and no outputs")
+	}
+	return nil
+}
+
+func expectHash(actual, expected *externalapi.DomainHash) error {
+	if !actual.Equal(expected) {
+		return errors.Errorf("%s instead of %s", actual, expected)
+	}
+	return nil
+}
+
+func verifyProofOfWork(params *dagconfig.Params) error {
+	header := params.GenesisBlock.Header
+	target := difficulty.CompactToBig(header.Bits())
+	if target.Sign() <= 0 {
+		return errors.Errorf("the bits 0x%08x encode a target that isn't positive", header.Bits())
+	}
+	if target.Cmp(params.PowMax) > 0 {
+		return errors.Errorf("the target %064x is higher than the maximal target %064x", target, params.PowMax)
+	}
+	if !pow.CheckProofOfWorkByBits(header.ToMutable()) {
+		return errors.Errorf("nonce %d doesn't satisfy the target %064x", header.Nonce(), target)
+	}
+	return nil
+}
+
+func isNetworkOfCeremony(params *dagconfig.Params, c *ceremony) bool {
+	return params.Name == c.Network || params.Name == "stokes-"+c.Network
+}
+
+// reproduce re-runs the ceremony up to the nonce of the genesis block of the given params,
+// and checks that it results in the same block
+func reproduce(params *dagconfig.Params, c *ceremony) error {
+	genesis, err := c.mine(params.GenesisBlock.Header.Nonce(), nil)
+	if err != nil {
+		return err
+	}
+	return expectHash(params.GenesisHash, consensushashing.BlockHash(genesis))
+}
@@ -152,17 +152,31 @@ func stringToValue(parameterDesc *parameterDescription, valueStr string) (reflec
 
 	case reflect.Slice:
 		sliceType := parameterDesc.typeof.Elem()
-		if sliceType.Kind() != reflect.String {
+		switch sliceType.Kind() {
+		case reflect.String:
+			if valueStr == "" {
+				value = []string{}
+			} else {
+				value = strings.Split(valueStr, ",")
+			}
+		case reflect.Float64:
+			floats := []float64{}
+			if valueStr != "" {
+				for _, floatStr := range strings.Split(valueStr, ",") {
+					float, err := strconv.ParseFloat(floatStr, 64)
+					if err != nil {
+						return reflect.Value{}, errors.WithStack(err)
+					}
+					floats = append(floats, float)
+				}
+			}
+			value = floats
+		default:
 			return reflect.Value{},
 				errors.Errorf("Unsupported slice type '%s' for parameter '%s'",
 					sliceType,
 					parameterDesc.name)
 		}
-		if valueStr == "" {
-			value = []string{}
-		} else {
-			value = strings.Split(valueStr, ",")
-		}
 	// Int and uint are not supported because their size is platform-dependant
 	case reflect.Int,
 		reflect.Uint,
@@ -32,12 +32,23 @@ var commandTypes = []reflect.Type{
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntryRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntriesRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntriesByAddressesRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetMempoolInfoRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetBlockCandidateStatusRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetDroppedTransactionsRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_PrioritiseTransactionRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_TestMempoolAcceptRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetFeeEstimateRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_SubmitTransactionRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_SubmitTransactionPackageRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_GetUtxosByAddressesRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetBalanceByAddressRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetCoinSupplyRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetEmissionInfoRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionAcceptanceRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetAddressTransactionsRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_BanRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_UnbanRequest{}),
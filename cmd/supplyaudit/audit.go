@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/emission"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionhelper"
+)
+
+const (
+	utxoPageSize            = 10_000
+	acceptanceDataBatchSize = 100
+)
+
+type auditReport struct {
+	virtualDAAScore        uint64
+	utxoCount              uint64
+	utxoSupply             uint64
+	scheduledEmission      uint64
+	auditedChainBlockCount int
+	discrepancies          []string
+}
+
+// unissued returns the part of the scheduled emission that is missing from the UTXO set.
+// It is expected to be positive: blocks outside the DAA window are not rewarded, and the
+// rewards of the blocks merged by the virtual are not paid yet.
+func (r *auditReport) unissued() uint64 {
+	if r.utxoSupply > r.scheduledEmission {
+		return 0
+	}
+	return r.scheduledEmission - r.utxoSupply
+}
+
+func (r *auditReport) addDiscrepancy(format string, args ...interface{}) {
+	r.discrepancies = append(r.discrepancies, fmt.Sprintf(format, args...))
+}
+
+// audit compares the virtual UTXO set with the emission schedule, and checks that no coinbase
+// of a chain block above the pruning point paid more than the subsidies and fees of its merge set.
+// Coinbases below the pruning point are covered only by the total supply check, since their
+// blocks are pruned.
+func audit(consensusInstance externalapi.Consensus, consensusConfig *consensus.Config) (*auditReport, error) {
+	virtualInfo, err := consensusInstance.GetVirtualInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &auditReport{virtualDAAScore: virtualInfo.DAAScore}
+
+	err = sumVirtualUTXOs(consensusInstance, virtualInfo.ParentHashes, report)
+	if err != nil {
+		return nil, err
+	}
+
+	genesisSubsidy, err := consensusInstance.CalcBlockSubsidy(consensusConfig.GenesisHash)
+	if err != nil {
+		return nil, err
+	}
+	report.scheduledEmission = genesisSubsidy + scheduledEmission(virtualInfo.DAAScore,
+		consensusConfig.PreDeflationaryPhaseBaseSubsidy, consensusConfig.HalvingIntervalDaaScore)
+	if report.utxoSupply > report.scheduledEmission {
+		report.addDiscrepancy("The UTXO set holds %d sompi, which is %d sompi more than the emission scheduled "+
+			"up to DAA score %d", report.utxoSupply, report.utxoSupply-report.scheduledEmission, virtualInfo.DAAScore)
+	}
+
+	err = auditChainBlockCoinbases(consensusInstance, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func sumVirtualUTXOs(consensusInstance externalapi.Consensus, virtualParents []*externalapi.DomainHash,
+	report *auditReport) error {
+
+	var fromOutpoint *externalapi.DomainOutpoint
+	for {
+		outpointAndUTXOEntryPairs, err := consensusInstance.GetVirtualUTXOs(virtualParents, fromOutpoint, utxoPageSize)
+		if err != nil {
+			return err
+		}
+		for _, outpointAndUTXOEntryPair := range outpointAndUTXOEntryPairs {
+			report.utxoCount++
+			report.utxoSupply += outpointAndUTXOEntryPair.UTXOEntry.Amount()
+		}
+		if len(outpointAndUTXOEntryPairs) < utxoPageSize {
+			return nil
+		}
+		fromOutpoint = outpointAndUTXOEntryPairs[len(outpointAndUTXOEntryPairs)-1].Outpoint
+	}
+}
+
+// scheduledEmission integrates the block subsidy over the DAA scores 1 to daaScore, era by era
+func scheduledEmission(daaScore uint64, baseSubsidy uint64, halvingIntervalDaaScore uint64) uint64 {
+	total := uint64(0)
+	start := uint64(1)
+	for start <= daaScore {
+		subsidy := emission.BlockSubsidy(start, baseSubsidy, halvingIntervalDaaScore)
+		if subsidy == 0 {
+			break
+		}
+
+		end := daaScore
+		nextHalvingDAAScore, hasNextHalving := emission.NextHalvingDAAScore(start, baseSubsidy, halvingIntervalDaaScore)
+		if hasNextHalving && nextHalvingDAAScore-1 < end {
+			end = nextHalvingDAAScore - 1
+		}
+
+		total += (end - start + 1) * subsidy
+		start = end + 1
+	}
+	return total
+}
+
+// auditChainBlockCoinbases checks the coinbase of every chain block above the pruning point
+// against the subsidies of the blocks in its merge set, as returned by CalcBlockSubsidy, plus
+// the fees of the transactions they accepted. This is an upper bound: blocks outside the DAA
+// window are not rewarded at all.
+func auditChainBlockCoinbases(consensusInstance externalapi.Consensus, report *auditReport) error {
+	pruningPoint, err := consensusInstance.PruningPoint()
+	if err != nil {
+		return err
+	}
+	chainPath, err := consensusInstance.GetVirtualSelectedParentChainFromBlock(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(chainPath.Added); start += acceptanceDataBatchSize {
+		end := start + acceptanceDataBatchSize
+		if end > len(chainPath.Added) {
+			end = len(chainPath.Added)
+		}
+		chainBlockHashes := chainPath.Added[start:end]
+
+		blocksAcceptanceData, err := consensusInstance.GetBlocksAcceptanceData(chainBlockHashes)
+		if err != nil {
+			return err
+		}
+
+		for i, chainBlockHash := range chainBlockHashes {
+			err := auditChainBlockCoinbase(consensusInstance, chainBlockHash, blocksAcceptanceData[i], report)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func auditChainBlockCoinbase(consensusInstance externalapi.Consensus, chainBlockHash *externalapi.DomainHash,
+	acceptanceData externalapi.AcceptanceData, report *auditReport) error {
+
+	block, found, err := consensusInstance.GetBlock(chainBlockHash)
+	if err != nil {
+		return err
+	}
+	if !found {
+		report.addDiscrepancy("The body of chain block %s is missing", chainBlockHash)
+		return nil
+	}
+
+	maxReward := uint64(0)
+	for _, blockAcceptanceData := range acceptanceData {
+		subsidy, err := consensusInstance.CalcBlockSubsidy(blockAcceptanceData.BlockHash)
+		if err != nil {
+			return err
+		}
+		maxReward += subsidy
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if transactionAcceptanceData.IsAccepted {
+				maxReward += transactionAcceptanceData.Fee
+			}
+		}
+	}
+
+	paid := uint64(0)
+	for _, output := range block.Transactions[transactionhelper.CoinbaseTransactionIndex].Outputs {
+		paid += output.Value
+	}
+	if paid > maxReward {
+		report.addDiscrepancy("The coinbase of chain block %s pays %d sompi, but the subsidies and fees of its "+
+			"merge set add up to %d sompi", chainBlockHash, paid, maxReward)
+	}
+
+	report.auditedChainBlockCount++
+	return nil
+}
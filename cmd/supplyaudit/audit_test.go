@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionhelper"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+)
+
+func TestScheduledEmission(t *testing.T) {
+	tests := []struct {
+		daaScore uint64
+		expected uint64
+	}{
+		{daaScore: 0, expected: 0},
+		{daaScore: 9, expected: 9 * 8},
+		{daaScore: 10, expected: 9*8 + 4},
+		{daaScore: 25, expected: 9*8 + 10*4 + 6*2},
+		{daaScore: 1000, expected: 9*8 + 10*4 + 10*2 + 10*1},
+	}
+	for _, test := range tests {
+		result := scheduledEmission(test.daaScore, 8, 10)
+		if result != test.expected {
+			t.Errorf("DAA score %d: expected %d, got %d", test.daaScore, test.expected, result)
+		}
+	}
+}
+
+// overpayingConsensus inflates the coinbase of a single block, as a corrupted database would
+type overpayingConsensus struct {
+	externalapi.Consensus
+	overpayingBlockHash *externalapi.DomainHash
+}
+
+func (c *overpayingConsensus) GetBlock(blockHash *externalapi.DomainHash) (*externalapi.DomainBlock, bool, error) {
+	block, found, err := c.Consensus.GetBlock(blockHash)
+	if err != nil || !found || !blockHash.Equal(c.overpayingBlockHash) {
+		return block, found, err
+	}
+	block = block.Clone()
+	coinbase := block.Transactions[transactionhelper.CoinbaseTransactionIndex]
+	coinbase.Outputs = append(coinbase.Outputs, &externalapi.DomainTransactionOutput{
+		Value:           1,
+		ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+	})
+	return block, true, nil
+}
+
+func TestAudit(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+	tc, teardown, err := consensus.NewFactory().NewTestConsensus(consensusConfig, "TestAudit")
+	if err != nil {
+		t.Fatalf("Error setting up consensus: %+v", err)
+	}
+	defer teardown(false)
+
+	tipHash := consensusConfig.GenesisHash
+	var chain []*externalapi.DomainHash
+	for i := 0; i < 10; i++ {
+		tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		chain = append(chain, tipHash)
+	}
+
+	report, err := audit(tc, consensusConfig)
+	if err != nil {
+		t.Fatalf("audit: %+v", err)
+	}
+	if len(report.discrepancies) != 0 {
+		t.Fatalf("Expected no discrepancies, but got: %s", report.discrepancies)
+	}
+	if report.utxoSupply == 0 || report.utxoSupply > report.scheduledEmission {
+		t.Fatalf("Expected a UTXO set supply between 0 and %d, but got %d",
+			report.scheduledEmission, report.utxoSupply)
+	}
+	if report.auditedChainBlockCount != len(chain) {
+		t.Fatalf("Expected %d audited chain blocks, but got %d", len(chain), report.auditedChainBlockCount)
+	}
+
+	overpayingBlockHash := chain[5]
+	report, err = audit(&overpayingConsensus{Consensus: tc, overpayingBlockHash: overpayingBlockHash}, consensusConfig)
+	if err != nil {
+		t.Fatalf("audit: %+v", err)
+	}
+	if len(report.discrepancies) != 1 || !strings.Contains(report.discrepancies[0], overpayingBlockHash.String()) {
+		t.Fatalf("Expected a single discrepancy for the coinbase of %s, but got: %s",
+			overpayingBlockHash, report.discrepancies)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/jessevdk/go-flags"
+	"github.com/stokesnetwork/stokes/infrastructure/config"
+)
+
+type configFlags struct {
+	AppDir string `short:"b" long:"appdir" description:"The node's data directory"`
+	config.NetworkFlags
+}
+
+func parseConfig() (*configFlags, error) {
+	cfg := &configFlags{
+		AppDir: config.DefaultAppDir,
+	}
+	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
+	_, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.ResolveNetwork(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
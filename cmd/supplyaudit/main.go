@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/prefixmanager"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database/ldb"
+	"github.com/stokesnetwork/stokes/util"
+)
+
+const (
+	// defaultDataDirname is the name of the database directory inside the
+	// network's app directory, as created by the node
+	defaultDataDirname  = "datadir2"
+	leveldbCacheSizeMiB = 256
+)
+
+func main() {
+	cfg, err := parseConfig()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	report, err := run(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+		os.Exit(1)
+	}
+
+	printReport(report)
+	if len(report.discrepancies) > 0 {
+		os.Exit(2)
+	}
+}
+
+func run(cfg *configFlags) (*auditReport, error) {
+	dbPath := filepath.Join(cfg.AppDir, cfg.NetParams().Name, defaultDataDirname)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, errors.Wrapf(err, "could not find a database at %s", dbPath)
+	}
+
+	// Opening the database takes its lock, so the node must not be running
+	db, err := ldb.NewLevelDB(dbPath, leveldbCacheSizeMiB)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open the database at %s. Is the node still running?", dbPath)
+	}
+	defer db.Close()
+
+	activePrefix, exists, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("the database at %s does not contain a consensus", dbPath)
+	}
+
+	consensusConfig := &consensus.Config{Params: *cfg.NetParams()}
+	consensusInstance, shouldMigrate, err := consensus.NewFactory().NewConsensus(consensusConfig, db, activePrefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	if shouldMigrate {
+		return nil, errors.Errorf("the database at %s has to be migrated by the node before it can be audited", dbPath)
+	}
+
+	return audit(consensusInstance, consensusConfig)
+}
+
+func printReport(report *auditReport) {
+	fmt.Printf("Virtual DAA score:          %d\n", report.virtualDAAScore)
+	fmt.Printf("UTXOs:                      %d\n", report.utxoCount)
+	fmt.Printf("UTXO set supply:            %d sompi (%s)\n", report.utxoSupply, util.Amount(report.utxoSupply))
+	fmt.Printf("Scheduled emission:         %d sompi (%s)\n", report.scheduledEmission, util.Amount(report.scheduledEmission))
+	fmt.Printf("Not issued:                 %d sompi (%s)\n", report.unissued(), util.Amount(report.unissued()))
+	fmt.Printf("Audited chain blocks:       %d\n", report.auditedChainBlockCount)
+
+	if len(report.discrepancies) == 0 {
+		fmt.Println("No discrepancies found")
+		return
+	}
+	fmt.Printf("Found %d discrepancies:\n", len(report.discrepancies))
+	for _, discrepancy := range report.discrepancies {
+		fmt.Printf("  %s\n", discrepancy)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/infrastructure/config"
+)
+
+const (
+	exportSubCmd = "export"
+	importSubCmd = "import"
+	verifySubCmd = "verify"
+)
+
+var defaultAppDir = config.DefaultAppDir
+
+type exportConfig struct {
+	AppDir     string `long:"appdir" short:"b" description:"The node's data directory"`
+	OutputFile string `long:"output" short:"o" description:"The snapshot file to write" required:"true"`
+	config.NetworkFlags
+}
+
+type importConfig struct {
+	AppDir       string `long:"appdir" short:"b" description:"The node's data directory"`
+	InputFile    string `long:"input" short:"i" description:"The snapshot file to import" required:"true"`
+	ExpectedHash string `long:"hash" description:"Refuse to import the snapshot unless its hash is the given hex-encoded hash"`
+	config.NetworkFlags
+}
+
+type verifyConfig struct {
+	InputFile    string `long:"input" short:"i" description:"The snapshot file to verify" required:"true"`
+	ExpectedHash string `long:"hash" description:"Fail unless the snapshot hash is the given hex-encoded hash"`
+	config.NetworkFlags
+}
+
+func parseCommandLine() (subCommand string, config interface{}) {
+	parser := flags.NewParser(&struct{}{}, flags.PrintErrors|flags.HelpFlag)
+
+	exportConf := &exportConfig{AppDir: defaultAppDir}
+	parser.AddCommand(exportSubCmd, "Export the pruning point UTXO set of a stopped node",
+		"Export the pruning point UTXO set, together with the pruning point proof and headers, of a stopped "+
+			"node to a snapshot file", exportConf)
+
+	importConf := &importConfig{AppDir: defaultAppDir}
+	parser.AddCommand(importSubCmd, "Import a snapshot into a stopped node",
+		"Verify a snapshot file against the UTXO commitment of its pruning point and import it into a "+
+			"stopped node, which then syncs only the blocks above the pruning point from its peers", importConf)
+
+	verifyConf := &verifyConfig{}
+	parser.AddCommand(verifySubCmd, "Verify a snapshot file",
+		"Verify the hash of a snapshot file and that its UTXO set fits the UTXO commitment of its pruning point",
+		verifyConf)
+
+	_, err := parser.Parse()
+	if err != nil {
+		var flagsErr *flags.Error
+		if ok := errors.As(err, &flagsErr); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+		return "", nil
+	}
+
+	switch parser.Command.Active.Name {
+	case exportSubCmd:
+		err = exportConf.ResolveNetwork(parser)
+		config = exportConf
+	case importSubCmd:
+		err = importConf.ResolveNetwork(parser)
+		config = importConf
+	case verifySubCmd:
+		err = verifyConf.ResolveNetwork(parser)
+		config = verifyConf
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	return parser.Command.Active.Name, config
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database/ldb"
+)
+
+const (
+	// defaultDataDirname is the name of the database directory inside the
+	// network's app directory, as created by the node
+	defaultDataDirname  = "datadir2"
+	leveldbCacheSizeMiB = 256
+)
+
+func databasePath(appDir string, params *dagconfig.Params) string {
+	return filepath.Join(appDir, params.Name, defaultDataDirname)
+}
+
+// openDatabase opens the node's database. If mustExist is set, it fails when
+// there is no database in the given path instead of creating a new one.
+func openDatabase(dbPath string, mustExist bool) (database.Database, error) {
+	if mustExist {
+		if _, err := os.Stat(dbPath); err != nil {
+			return nil, errors.Wrapf(err, "could not find a database at %s", dbPath)
+		}
+	}
+
+	// Opening the database takes its lock, so the node must not be running
+	db, err := ldb.NewLevelDB(dbPath, leveldbCacheSizeMiB)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open the database at %s. Is the node still running?", dbPath)
+	}
+	return db, nil
+}
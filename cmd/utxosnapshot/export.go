@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/app/protocol/flows/v5/blockrelay"
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+	"github.com/stokesnetwork/stokes/domain/prefixmanager"
+)
+
+const (
+	// utxoSetChunkSize is the number of UTXOs in every chunk, the same as in IBD
+	utxoSetChunkSize = 1000
+
+	// maxHeadersPerMessage is the maximum number of headers in every headers message, the
+	// same as in IBD. It must be greater than the merge set size limit.
+	maxHeadersPerMessage = 1 << 10
+)
+
+func export(conf *exportConfig) error {
+	params := conf.NetParams()
+	dbPath := databasePath(conf.AppDir, params)
+	db, err := openDatabase(dbPath, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	activePrefix, exists, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("the database at %s does not contain a consensus", dbPath)
+	}
+	consensusInstance, shouldMigrate, err := consensus.NewFactory().NewConsensus(
+		&consensus.Config{Params: *params}, db, activePrefix, nil)
+	if err != nil {
+		return err
+	}
+	if shouldMigrate {
+		return errors.Errorf("the database at %s has to be migrated by the node before it can be exported", dbPath)
+	}
+
+	file, err := os.Create(conf.OutputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	bufferedWriter := bufio.NewWriter(file)
+
+	snapshotHash, err := exportSnapshot(consensusInstance, params, bufferedWriter)
+	if err != nil {
+		return err
+	}
+	err = bufferedWriter.Flush()
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported the snapshot to %s\n", conf.OutputFile)
+	fmt.Printf("Snapshot hash: %x\n", snapshotHash)
+	return nil
+}
+
+// exportSnapshot writes a snapshot of the current pruning point to the given writer and returns its hash
+func exportSnapshot(consensusInstance externalapi.Consensus, params *dagconfig.Params, writer io.Writer) ([]byte, error) {
+	pruningPoint, err := consensusInstance.PruningPoint()
+	if err != nil {
+		return nil, err
+	}
+	if pruningPoint.Equal(params.GenesisHash) {
+		return nil, errors.New("the pruning point is still the genesis, so there is nothing to export")
+	}
+
+	snapshotWriter, err := newSnapshotWriter(writer, &snapshotHeader{
+		networkName:  params.Name,
+		pruningPoint: pruningPoint,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pruningPointProof, err := consensusInstance.BuildPruningPointProof()
+	if err != nil {
+		return nil, err
+	}
+	err = snapshotWriter.writeMessage(appmessage.DomainPruningPointProofToMsgPruningPointProof(pruningPointProof))
+	if err != nil {
+		return nil, err
+	}
+
+	pruningPointHeaders, err := consensusInstance.PruningPointHeaders()
+	if err != nil {
+		return nil, err
+	}
+	msgPruningPointHeaders := make([]*appmessage.MsgBlockHeader, len(pruningPointHeaders))
+	for i, header := range pruningPointHeaders {
+		msgPruningPointHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(header)
+	}
+	err = snapshotWriter.writeMessage(appmessage.NewMsgPruningPoints(msgPruningPointHeaders))
+	if err != nil {
+		return nil, err
+	}
+
+	trustedData, err := blockrelay.CollectPruningPointTrustedData(consensusInstance, params)
+	if err != nil {
+		return nil, err
+	}
+	err = snapshotWriter.writeMessage(appmessage.DomainTrustedDataToTrustedData(trustedData.DAAWindow, trustedData.GHOSTDAGData))
+	if err != nil {
+		return nil, err
+	}
+	for _, blockHash := range trustedData.PruningPointAndItsAnticone {
+		block, found, err := consensusInstance.GetBlock(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, errors.Errorf("pruning point anticone block %s not found", blockHash)
+		}
+		err = snapshotWriter.writeMessage(appmessage.DomainBlockWithTrustedDataToBlockWithTrustedDataV4(block,
+			trustedData.DAAWindowIndices[*blockHash], trustedData.GHOSTDAGDataIndices[*blockHash]))
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = snapshotWriter.writeMessage(appmessage.NewMsgDoneBlocksWithTrustedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = writePruningPointFutureHeaders(consensusInstance, pruningPoint, snapshotWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromOutpoint *externalapi.DomainOutpoint
+	for {
+		pruningPointUTXOs, err := consensusInstance.GetPruningPointUTXOs(pruningPoint, fromOutpoint, utxoSetChunkSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(pruningPointUTXOs) > 0 {
+			err = snapshotWriter.writeMessage(appmessage.NewMsgPruningPointUTXOSetChunk(
+				appmessage.DomainOutpointAndUTXOEntryPairsToOutpointAndUTXOEntryPairs(pruningPointUTXOs)))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(pruningPointUTXOs) < utxoSetChunkSize {
+			break
+		}
+		fromOutpoint = pruningPointUTXOs[len(pruningPointUTXOs)-1].Outpoint
+	}
+	err = snapshotWriter.writeMessage(appmessage.NewMsgDonePruningPointUTXOSetChunks())
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshotWriter.finish()
+}
+
+// writePruningPointFutureHeaders writes the headers between the pruning point and the headers selected tip.
+// They are required for the importing node to consider the pruning point valid, since a pruning point has
+// to be at pruning depth below the headers selected tip.
+func writePruningPointFutureHeaders(consensusInstance externalapi.Consensus, pruningPoint *externalapi.DomainHash,
+	snapshotWriter *snapshotWriter) error {
+
+	headersSelectedTip, err := consensusInstance.GetHeadersSelectedTip()
+	if err != nil {
+		return err
+	}
+
+	lowHash := pruningPoint
+	for !lowHash.Equal(headersSelectedTip) {
+		blockHashes, _, err := consensusInstance.GetHashesBetween(lowHash, headersSelectedTip, maxHeadersPerMessage)
+		if err != nil {
+			return err
+		}
+		if len(blockHashes) == 0 {
+			return errors.Errorf("no headers found between %s and %s", lowHash, headersSelectedTip)
+		}
+
+		blockHeaders := make([]*appmessage.MsgBlockHeader, len(blockHashes))
+		for i, blockHash := range blockHashes {
+			blockHeader, err := consensusInstance.GetBlockHeader(blockHash)
+			if err != nil {
+				return err
+			}
+			blockHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(blockHeader)
+		}
+		err = snapshotWriter.writeMessage(appmessage.NewBlockHeadersMessage(blockHeaders))
+		if err != nil {
+			return err
+		}
+
+		lowHash = blockHashes[len(blockHashes)-1]
+	}
+
+	return snapshotWriter.writeMessage(appmessage.NewMsgDoneHeaders())
+}
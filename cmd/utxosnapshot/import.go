@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool"
+)
+
+func importSnapshotFile(conf *importConfig) error {
+	params := conf.NetParams()
+
+	// The snapshot is read twice: once to verify it before touching the database,
+	// and once to import it
+	summary, err := verifySnapshotFile(conf.InputFile, conf.ExpectedHash, params)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Verified the snapshot of pruning point %s with %d UTXOs\n",
+		summary.header.pruningPoint, summary.utxoCount)
+
+	db, err := openDatabase(databasePath(conf.AppDir, params), false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	consensusConfig := &consensus.Config{Params: *params}
+	domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(params), db)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(conf.InputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = importSnapshot(domainInstance, params, bufio.NewReader(file))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported the snapshot. The pruning point is now %s\n", summary.header.pruningPoint)
+	return nil
+}
+
+// importSnapshot imports the snapshot into a staging consensus, the same way IBD with a headers
+// proof does, and commits it only if the consensus accepts the snapshot pruning point and its
+// UTXO set.
+func importSnapshot(domainInstance domain.Domain, params *dagconfig.Params, reader io.Reader) error {
+	err := domainInstance.InitStagingConsensusWithoutGenesis()
+	if err != nil {
+		return err
+	}
+
+	err = importSnapshotToStagingConsensus(domainInstance, params, reader)
+	if err != nil {
+		deleteStagingConsensusErr := domainInstance.DeleteStagingConsensus()
+		if deleteStagingConsensusErr != nil {
+			return deleteStagingConsensusErr
+		}
+		return err
+	}
+
+	return domainInstance.CommitStagingConsensus()
+}
+
+func importSnapshotToStagingConsensus(domainInstance domain.Domain, params *dagconfig.Params, reader io.Reader) (err error) {
+	stagingConsensus := domainInstance.StagingConsensus()
+	defer func() {
+		clearErr := stagingConsensus.ClearImportedPruningPointData()
+		if err == nil {
+			err = clearErr
+		}
+	}()
+
+	var pruningPoint *externalapi.DomainHash
+	handlers := &snapshotHandlers{
+		onPruningPointProof: func(pruningPointProof *externalapi.PruningPointProof) error {
+			err := domainInstance.Consensus().ValidatePruningPointProof(pruningPointProof)
+			if err != nil {
+				return errors.Wrap(err, "pruning point proof validation failed")
+			}
+			return stagingConsensus.ApplyPruningPointProof(pruningPointProof)
+		},
+		onPruningPoints: func(pruningPointHeaders []externalapi.BlockHeader) error {
+			currentPruningPoint, err := domainInstance.Consensus().PruningPoint()
+			if err != nil {
+				return err
+			}
+			pruningPoint = consensushashing.HeaderHash(pruningPointHeaders[len(pruningPointHeaders)-1])
+			if currentPruningPoint.Equal(pruningPoint) {
+				return errors.Errorf("the node is already at pruning point %s", pruningPoint)
+			}
+
+			arePruningPointsViolatingFinality, err := domainInstance.Consensus().ArePruningPointsViolatingFinality(
+				pruningPointHeaders)
+			if err != nil {
+				return err
+			}
+			if arePruningPointsViolatingFinality {
+				return errors.New("the snapshot pruning points are violating finality")
+			}
+			return stagingConsensus.ImportPruningPoints(pruningPointHeaders)
+		},
+		onBlockWithTrustedData: func(blockWithTrustedData *externalapi.BlockWithTrustedData) error {
+			return stagingConsensus.ValidateAndInsertBlockWithTrustedData(blockWithTrustedData, false)
+		},
+		onHeader: func(header externalapi.BlockHeader) error {
+			block := &externalapi.DomainBlock{Header: header}
+			blockInfo, err := stagingConsensus.GetBlockInfo(consensushashing.BlockHash(block))
+			if err != nil {
+				return err
+			}
+			if blockInfo.Exists {
+				return nil
+			}
+			return stagingConsensus.ValidateAndInsertBlock(block, false)
+		},
+		onHeaders: func() error {
+			isValid, err := stagingConsensus.IsValidPruningPoint(pruningPoint)
+			if err != nil {
+				return err
+			}
+			if !isValid {
+				return errors.Errorf("invalid pruning point %s", pruningPoint)
+			}
+			return nil
+		},
+		onUTXOSetChunk: func(outpointAndUTXOEntryPairs []*externalapi.OutpointAndUTXOEntryPair) error {
+			return stagingConsensus.AppendImportedPruningPointUTXOs(outpointAndUTXOEntryPairs)
+		},
+	}
+
+	_, err = readSnapshot(reader, params, handlers)
+	if err != nil {
+		return err
+	}
+
+	// This validates the imported UTXO set against the UTXO commitment of the pruning point once again
+	return stagingConsensus.ValidateAndInsertImportedPruningPoint(pruningPoint)
+}
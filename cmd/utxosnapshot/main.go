@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	subCmd, config := parseCommandLine()
+	var err error
+	switch subCmd {
+	case exportSubCmd:
+		err = export(config.(*exportConfig))
+	case importSubCmd:
+		err = importSnapshotFile(config.(*importConfig))
+	case verifySubCmd:
+		err = verify(config.(*verifyConfig))
+	default:
+		err = errors.Errorf("Unknown sub-command '%s'\n", subCmd)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/multiset"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/utxo"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+)
+
+// snapshotHandlers are called with the content of a snapshot as it is read. Any of them may be nil.
+type snapshotHandlers struct {
+	onPruningPointProof     func(pruningPointProof *externalapi.PruningPointProof) error
+	onPruningPoints         func(pruningPointHeaders []externalapi.BlockHeader) error
+	onBlockWithTrustedData  func(blockWithTrustedData *externalapi.BlockWithTrustedData) error
+	onBlocksWithTrustedData func() error
+	onHeader                func(header externalapi.BlockHeader) error
+	onHeaders               func() error
+	onUTXOSetChunk          func(outpointAndUTXOEntryPairs []*externalapi.OutpointAndUTXOEntryPair) error
+}
+
+type snapshotSummary struct {
+	header      *snapshotHeader
+	hash        []byte
+	blockCount  int
+	headerCount int
+	utxoCount   int
+}
+
+// readSnapshot reads the snapshot and passes its content to the given handlers. It fails if the
+// snapshot does not belong to the given network, if it is malformed, if its UTXO set does not fit
+// the UTXO commitment of its pruning point, or if its hash does not match its content.
+func readSnapshot(reader io.Reader, params *dagconfig.Params, handlers *snapshotHandlers) (*snapshotSummary, error) {
+	snapshotReader, err := newSnapshotReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	header := snapshotReader.header
+	if header.networkName != params.Name {
+		return nil, errors.Errorf("the snapshot belongs to %s, not to %s", header.networkName, params.Name)
+	}
+	summary := &snapshotSummary{header: header}
+
+	message, err := snapshotReader.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	pruningPointProofMessage, ok := message.(*appmessage.MsgPruningPointProof)
+	if !ok {
+		return nil, errors.Errorf("unexpected snapshot message %s. Expected: %s", message.Command(), appmessage.CmdPruningPointProof)
+	}
+	pruningPointProof := appmessage.MsgPruningPointProofToDomainPruningPointProof(pruningPointProofMessage)
+	if len(pruningPointProof.Headers) == 0 || len(pruningPointProof.Headers[0]) == 0 {
+		return nil, errors.New("the snapshot contains an empty pruning point proof")
+	}
+	proofPruningPoint := consensushashing.HeaderHash(pruningPointProof.Headers[0][len(pruningPointProof.Headers[0])-1])
+	if !proofPruningPoint.Equal(header.pruningPoint) {
+		return nil, errors.Errorf("the pruning point proof leads to %s instead of %s", proofPruningPoint, header.pruningPoint)
+	}
+	if handlers.onPruningPointProof != nil {
+		err := handlers.onPruningPointProof(pruningPointProof)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	message, err = snapshotReader.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	pruningPointsMessage, ok := message.(*appmessage.MsgPruningPoints)
+	if !ok {
+		return nil, errors.Errorf("unexpected snapshot message %s. Expected: %s", message.Command(), appmessage.CmdPruningPoints)
+	}
+	pruningPointHeaders := make([]externalapi.BlockHeader, len(pruningPointsMessage.Headers))
+	for i, header := range pruningPointsMessage.Headers {
+		pruningPointHeaders[i] = appmessage.BlockHeaderToDomainBlockHeader(header)
+	}
+	if len(pruningPointHeaders) == 0 ||
+		!consensushashing.HeaderHash(pruningPointHeaders[len(pruningPointHeaders)-1]).Equal(header.pruningPoint) {
+
+		return nil, errors.Errorf("the last of the past pruning points is not %s", header.pruningPoint)
+	}
+	if handlers.onPruningPoints != nil {
+		err := handlers.onPruningPoints(pruningPointHeaders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	message, err = snapshotReader.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	trustedData, ok := message.(*appmessage.MsgTrustedData)
+	if !ok {
+		return nil, errors.Errorf("unexpected snapshot message %s. Expected: %s", message.Command(), appmessage.CmdTrustedData)
+	}
+
+	var utxoCommitment *externalapi.DomainHash
+	for {
+		message, err := snapshotReader.readMessage()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := message.(*appmessage.MsgDoneBlocksWithTrustedData); ok {
+			break
+		}
+		blockMessage, ok := message.(*appmessage.MsgBlockWithTrustedDataV4)
+		if !ok {
+			return nil, errors.Errorf("unexpected snapshot message %s", message.Command())
+		}
+		blockWithTrustedData, err := toBlockWithTrustedData(blockMessage, trustedData)
+		if err != nil {
+			return nil, err
+		}
+
+		// The pruning point comes first, as it does in IBD
+		if summary.blockCount == 0 {
+			blockHash := consensushashing.BlockHash(blockWithTrustedData.Block)
+			if !blockHash.Equal(header.pruningPoint) {
+				return nil, errors.Errorf("the first block in the snapshot is %s instead of the pruning point %s",
+					blockHash, header.pruningPoint)
+			}
+			utxoCommitment = blockWithTrustedData.Block.Header.UTXOCommitment()
+		}
+		summary.blockCount++
+
+		if handlers.onBlockWithTrustedData != nil {
+			err := handlers.onBlockWithTrustedData(blockWithTrustedData)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if summary.blockCount == 0 {
+		return nil, errors.New("the snapshot does not contain the pruning point block")
+	}
+	if handlers.onBlocksWithTrustedData != nil {
+		err := handlers.onBlocksWithTrustedData()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		message, err := snapshotReader.readMessage()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := message.(*appmessage.MsgDoneHeaders); ok {
+			break
+		}
+		blockHeadersMessage, ok := message.(*appmessage.BlockHeadersMessage)
+		if !ok {
+			return nil, errors.Errorf("unexpected snapshot message %s", message.Command())
+		}
+		for _, blockHeader := range blockHeadersMessage.BlockHeaders {
+			summary.headerCount++
+			if handlers.onHeader != nil {
+				err := handlers.onHeader(appmessage.BlockHeaderToDomainBlockHeader(blockHeader))
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if handlers.onHeaders != nil {
+		err := handlers.onHeaders()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	utxoSetMultiset := multiset.New()
+	for {
+		message, err := snapshotReader.readMessage()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := message.(*appmessage.MsgDonePruningPointUTXOSetChunks); ok {
+			break
+		}
+		chunk, ok := message.(*appmessage.MsgPruningPointUTXOSetChunk)
+		if !ok {
+			return nil, errors.Errorf("unexpected snapshot message %s", message.Command())
+		}
+
+		outpointAndUTXOEntryPairs :=
+			appmessage.OutpointAndUTXOEntryPairsToDomainOutpointAndUTXOEntryPairs(chunk.OutpointAndUTXOEntryPairs)
+		for _, outpointAndUTXOEntryPair := range outpointAndUTXOEntryPairs {
+			serializedUTXO, err := utxo.SerializeUTXO(outpointAndUTXOEntryPair.UTXOEntry, outpointAndUTXOEntryPair.Outpoint)
+			if err != nil {
+				return nil, err
+			}
+			utxoSetMultiset.Add(serializedUTXO)
+		}
+		summary.utxoCount += len(outpointAndUTXOEntryPairs)
+
+		if handlers.onUTXOSetChunk != nil {
+			err := handlers.onUTXOSetChunk(outpointAndUTXOEntryPairs)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	utxoSetHash := utxoSetMultiset.Hash()
+	if !utxoSetHash.Equal(utxoCommitment) {
+		return nil, errors.Errorf("the snapshot UTXO set hashes to %s, but the UTXO commitment of the "+
+			"pruning point %s is %s", utxoSetHash, header.pruningPoint, utxoCommitment)
+	}
+
+	summary.hash, err = snapshotReader.finish()
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func toBlockWithTrustedData(block *appmessage.MsgBlockWithTrustedDataV4, trustedData *appmessage.MsgTrustedData) (
+	*externalapi.BlockWithTrustedData, error) {
+
+	blockWithTrustedData := &externalapi.BlockWithTrustedData{
+		Block:        appmessage.MsgBlockToDomainBlock(block.Block),
+		DAAWindow:    make([]*externalapi.TrustedDataDataDAAHeader, 0, len(block.DAAWindowIndices)),
+		GHOSTDAGData: make([]*externalapi.BlockGHOSTDAGDataHashPair, 0, len(block.GHOSTDAGDataIndices)),
+	}
+
+	for _, index := range block.DAAWindowIndices {
+		if index >= uint64(len(trustedData.DAAWindow)) {
+			return nil, errors.Errorf("DAA window index %d is out of range", index)
+		}
+		blockWithTrustedData.DAAWindow = append(blockWithTrustedData.DAAWindow,
+			appmessage.TrustedDataDataDAABlockV4ToTrustedDataDataDAAHeader(trustedData.DAAWindow[index]))
+	}
+
+	for _, index := range block.GHOSTDAGDataIndices {
+		if index >= uint64(len(trustedData.GHOSTDAGData)) {
+			return nil, errors.Errorf("GHOSTDAG data index %d is out of range", index)
+		}
+		blockWithTrustedData.GHOSTDAGData = append(blockWithTrustedData.GHOSTDAGData,
+			appmessage.GHOSTDAGHashPairToDomainGHOSTDAGHashPair(trustedData.GHOSTDAGData[index]))
+	}
+
+	return blockWithTrustedData, nil
+}
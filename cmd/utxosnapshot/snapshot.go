@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// A snapshot file consists of:
+//  1. A header: the magic, the format version, the network name and the pruning point hash
+//  2. A sequence of length-prefixed p2p messages, in the same order a syncer sends them
+//     during IBD with a headers proof:
+//     - MsgPruningPointProof
+//     - MsgPruningPoints
+//     - MsgTrustedData
+//     - A MsgBlockWithTrustedDataV4 for the pruning point and for every block in its anticone
+//     - MsgDoneBlocksWithTrustedData
+//     - Any number of BlockHeadersMessage, with the headers between the pruning point and the headers selected tip
+//     - MsgDoneHeaders
+//     - Any number of MsgPruningPointUTXOSetChunk
+//     - MsgDonePruningPointUTXOSetChunks
+//  3. The SHA-256 hash of everything that precedes it
+var snapshotMagic = []byte("STKSSNAP")
+
+const (
+	snapshotVersion = 1
+
+	// maxMessageSize is the maximum payload of a p2p message. It's checked before a message is
+	// allocated, so that a corrupted length prefix can't make the reader allocate more than that.
+	maxMessageSize = appmessage.MaxMessagePayload
+
+	// maxNetworkNameLength is the longest network name whose length fits in the single byte
+	// it's prefixed with
+	maxNetworkNameLength = math.MaxUint8
+)
+
+type snapshotHeader struct {
+	networkName  string
+	pruningPoint *externalapi.DomainHash
+}
+
+type snapshotWriter struct {
+	writer io.Writer
+	hasher hash.Hash
+	hashed io.Writer
+}
+
+func newSnapshotWriter(writer io.Writer, header *snapshotHeader) (*snapshotWriter, error) {
+	if len(header.networkName) > maxNetworkNameLength {
+		return nil, errors.Errorf("the network name %s is %d bytes long, while the maximum is %d bytes",
+			header.networkName, len(header.networkName), maxNetworkNameLength)
+	}
+
+	hasher := sha256.New()
+	snapshotWriter := &snapshotWriter{
+		writer: writer,
+		hasher: hasher,
+		hashed: io.MultiWriter(writer, hasher),
+	}
+
+	serializedHeader := &bytes.Buffer{}
+	serializedHeader.Write(snapshotMagic)
+	err := binary.Write(serializedHeader, binary.LittleEndian, uint32(snapshotVersion))
+	if err != nil {
+		return nil, err
+	}
+	serializedHeader.WriteByte(byte(len(header.networkName)))
+	serializedHeader.WriteString(header.networkName)
+	serializedHeader.Write(header.pruningPoint.ByteSlice())
+
+	_, err = snapshotWriter.hashed.Write(serializedHeader.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return snapshotWriter, nil
+}
+
+func (sw *snapshotWriter) writeMessage(message appmessage.Message) error {
+	protoMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return err
+	}
+	serializedMessage, err := proto.Marshal(protoMessage)
+	if err != nil {
+		return err
+	}
+	if len(serializedMessage) > maxMessageSize {
+		return errors.Errorf("%s of %d bytes exceeds the maximum snapshot message size of %d bytes",
+			message.Command(), len(serializedMessage), maxMessageSize)
+	}
+
+	err = binary.Write(sw.hashed, binary.LittleEndian, uint32(len(serializedMessage)))
+	if err != nil {
+		return err
+	}
+	_, err = sw.hashed.Write(serializedMessage)
+	return err
+}
+
+// finish writes the hash of the snapshot to its end and returns it
+func (sw *snapshotWriter) finish() ([]byte, error) {
+	snapshotHash := sw.hasher.Sum(nil)
+	_, err := sw.writer.Write(snapshotHash)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotHash, nil
+}
+
+type snapshotReader struct {
+	reader io.Reader
+	hasher hash.Hash
+	hashed io.Reader
+	header *snapshotHeader
+}
+
+func newSnapshotReader(reader io.Reader) (*snapshotReader, error) {
+	hasher := sha256.New()
+	snapshotReader := &snapshotReader{
+		reader: reader,
+		hasher: hasher,
+		hashed: io.TeeReader(reader, hasher),
+	}
+
+	magic := make([]byte, len(snapshotMagic))
+	_, err := io.ReadFull(snapshotReader.hashed, magic)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading the snapshot magic")
+	}
+	if !bytes.Equal(magic, snapshotMagic) {
+		return nil, errors.New("the file is not a UTXO set snapshot")
+	}
+
+	var version uint32
+	err = binary.Read(snapshotReader.hashed, binary.LittleEndian, &version)
+	if err != nil {
+		return nil, err
+	}
+	if version != snapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d. Expected version: %d", version, snapshotVersion)
+	}
+
+	var networkNameLength uint8
+	err = binary.Read(snapshotReader.hashed, binary.LittleEndian, &networkNameLength)
+	if err != nil {
+		return nil, err
+	}
+	networkName := make([]byte, networkNameLength)
+	_, err = io.ReadFull(snapshotReader.hashed, networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedPruningPoint := make([]byte, externalapi.DomainHashSize)
+	_, err = io.ReadFull(snapshotReader.hashed, serializedPruningPoint)
+	if err != nil {
+		return nil, err
+	}
+	pruningPoint, err := externalapi.NewDomainHashFromByteSlice(serializedPruningPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotReader.header = &snapshotHeader{
+		networkName:  string(networkName),
+		pruningPoint: pruningPoint,
+	}
+	return snapshotReader, nil
+}
+
+func (sr *snapshotReader) readMessage() (appmessage.Message, error) {
+	var length uint32
+	err := binary.Read(sr.hashed, binary.LittleEndian, &length)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading the snapshot")
+	}
+	if length > maxMessageSize {
+		return nil, errors.Errorf("snapshot message of %d bytes exceeds the maximum of %d bytes",
+			length, maxMessageSize)
+	}
+
+	serializedMessage := make([]byte, length)
+	_, err = io.ReadFull(sr.hashed, serializedMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading the snapshot")
+	}
+
+	protoMessage := &protowire.KaspadMessage{}
+	err = proto.Unmarshal(serializedMessage, protoMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed deserializing a snapshot message")
+	}
+	return protoMessage.ToAppMessage()
+}
+
+// finish makes sure the snapshot ends with the hash of everything read so far, and returns it
+func (sr *snapshotReader) finish() ([]byte, error) {
+	expectedHash := sr.hasher.Sum(nil)
+	snapshotHash := make([]byte, len(expectedHash))
+	_, err := io.ReadFull(sr.reader, snapshotHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading the snapshot hash")
+	}
+	if !bytes.Equal(snapshotHash, expectedHash) {
+		return nil, errors.Errorf("the snapshot is corrupted: its hash is %x, but its content hashes to %x",
+			snapshotHash, expectedHash)
+	}
+
+	_, err = io.ReadFull(sr.reader, make([]byte, 1))
+	if !errors.Is(err, io.EOF) {
+		return nil, errors.New("the snapshot has unexpected data after its hash")
+	}
+	return snapshotHash, nil
+}
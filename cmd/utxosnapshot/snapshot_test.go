@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database/ldb"
+)
+
+func newTestDomain(t *testing.T, consensusConfig *consensus.Config) domain.Domain {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+	if err != nil {
+		t.Fatalf("domain.New: %+v", err)
+	}
+	return domainInstance
+}
+
+func TestSnapshotExportAndImport(t *testing.T) {
+	params := dagconfig.SimnetParams
+	// This is done to make a pruning depth of 6 blocks, as in the IBD with headers proof tests
+	params.TargetTimePerBlock = time.Minute
+	params.FinalityDuration = 2 * params.TargetTimePerBlock
+	params.K = 0
+	params.PruningProofM = 20
+	consensusConfig := &consensus.Config{Params: params}
+	consensusConfig.SkipProofOfWork = true
+
+	source := newTestDomain(t, consensusConfig)
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{Script: []byte{1}, Version: 0},
+	}
+	var blocks []*externalapi.DomainBlock
+	for i := 0; i < 30; i++ {
+		block, err := source.Consensus().BuildBlock(coinbaseData, nil)
+		if err != nil {
+			t.Fatalf("BuildBlock: %+v", err)
+		}
+		err = source.Consensus().ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	pruningPoint, err := source.Consensus().PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if pruningPoint.Equal(params.GenesisHash) {
+		t.Fatalf("Expected the pruning point to move")
+	}
+
+	snapshot := &bytes.Buffer{}
+	snapshotHash, err := exportSnapshot(source.Consensus(), &params, snapshot)
+	if err != nil {
+		t.Fatalf("exportSnapshot: %+v", err)
+	}
+
+	summary, err := readSnapshot(bytes.NewReader(snapshot.Bytes()), &params, &snapshotHandlers{})
+	if err != nil {
+		t.Fatalf("readSnapshot: %+v", err)
+	}
+	if !bytes.Equal(summary.hash, snapshotHash) || !summary.header.pruningPoint.Equal(pruningPoint) {
+		t.Fatalf("Unexpected snapshot summary: %+v", summary)
+	}
+
+	_, err = readSnapshot(bytes.NewReader(snapshot.Bytes()), &dagconfig.TestnetParams, &snapshotHandlers{})
+	if err == nil {
+		t.Fatalf("Expected a snapshot of another network to be rejected")
+	}
+
+	target := newTestDomain(t, consensusConfig)
+	err = importSnapshot(target, &params, bytes.NewReader(snapshot.Bytes()))
+	if err != nil {
+		t.Fatalf("importSnapshot: %+v", err)
+	}
+	targetPruningPoint, err := target.Consensus().PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if !targetPruningPoint.Equal(pruningPoint) {
+		t.Fatalf("Expected the pruning point after the import to be %s, but got %s", pruningPoint, targetPruningPoint)
+	}
+
+	// The imported node has to be able to sync the blocks above the pruning point
+	pruningPointIndex := -1
+	for i, block := range blocks {
+		if consensushashing.BlockHash(block).Equal(pruningPoint) {
+			pruningPointIndex = i
+		}
+	}
+	for _, block := range blocks[pruningPointIndex+1:] {
+		err := target.Consensus().ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+	sourceTip, err := source.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	targetTip, err := target.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	if !targetTip.Equal(sourceTip) {
+		t.Fatalf("Expected the selected tip after the sync to be %s, but got %s", sourceTip, targetTip)
+	}
+}
+
+func TestSnapshotTampering(t *testing.T) {
+	params := dagconfig.SimnetParams
+	params.TargetTimePerBlock = time.Minute
+	params.FinalityDuration = 2 * params.TargetTimePerBlock
+	params.K = 0
+	params.PruningProofM = 20
+	consensusConfig := &consensus.Config{Params: params}
+	consensusConfig.SkipProofOfWork = true
+
+	source := newTestDomain(t, consensusConfig)
+	for i := 0; i < 30; i++ {
+		block, err := source.Consensus().BuildBlock(&externalapi.DomainCoinbaseData{
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: []byte{1}, Version: 0},
+		}, nil)
+		if err != nil {
+			t.Fatalf("BuildBlock: %+v", err)
+		}
+		err = source.Consensus().ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+
+	snapshot := &bytes.Buffer{}
+	_, err := exportSnapshot(source.Consensus(), &params, snapshot)
+	if err != nil {
+		t.Fatalf("exportSnapshot: %+v", err)
+	}
+
+	// A corrupted byte is caught by the snapshot hash, if not earlier
+	corrupted := append([]byte{}, snapshot.Bytes()...)
+	corrupted[len(corrupted)/2] ^= 0xff
+	_, err = readSnapshot(bytes.NewReader(corrupted), &params, &snapshotHandlers{})
+	if err == nil {
+		t.Fatalf("Expected a corrupted snapshot to be rejected")
+	}
+
+	// A snapshot with a different UTXO set, even with a valid hash, is caught by the UTXO commitment
+	snapshotReader, err := newSnapshotReader(bytes.NewReader(snapshot.Bytes()))
+	if err != nil {
+		t.Fatalf("newSnapshotReader: %+v", err)
+	}
+	tampered := &bytes.Buffer{}
+	snapshotWriter, err := newSnapshotWriter(tampered, snapshotReader.header)
+	if err != nil {
+		t.Fatalf("newSnapshotWriter: %+v", err)
+	}
+	for {
+		message, err := snapshotReader.readMessage()
+		if err != nil {
+			t.Fatalf("readMessage: %+v", err)
+		}
+		if chunk, ok := message.(*appmessage.MsgPruningPointUTXOSetChunk); ok {
+			chunk.OutpointAndUTXOEntryPairs[0].UTXOEntry.Amount++
+		}
+		err = snapshotWriter.writeMessage(message)
+		if err != nil {
+			t.Fatalf("writeMessage: %+v", err)
+		}
+		if _, ok := message.(*appmessage.MsgDonePruningPointUTXOSetChunks); ok {
+			break
+		}
+	}
+	_, err = snapshotWriter.finish()
+	if err != nil {
+		t.Fatalf("finish: %+v", err)
+	}
+
+	_, err = readSnapshot(bytes.NewReader(tampered.Bytes()), &params, &snapshotHandlers{})
+	if err == nil || !strings.Contains(err.Error(), "UTXO commitment") {
+		t.Fatalf("Expected the tampered UTXO set to be rejected by the UTXO commitment, but got: %v", err)
+	}
+
+	target := newTestDomain(t, consensusConfig)
+	err = importSnapshot(target, &params, bytes.NewReader(tampered.Bytes()))
+	if err == nil {
+		t.Fatalf("Expected the import of a tampered snapshot to fail")
+	}
+	targetPruningPoint, err := target.Consensus().PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if !targetPruningPoint.Equal(params.GenesisHash) {
+		t.Fatalf("Expected a failed import to leave the consensus untouched")
+	}
+}
+
+func TestSnapshotLimits(t *testing.T) {
+	// A network name whose length doesn't fit in a byte can't be written
+	for _, networkNameLength := range []int{maxNetworkNameLength, maxNetworkNameLength + 1} {
+		_, err := newSnapshotWriter(&bytes.Buffer{}, &snapshotHeader{
+			networkName:  strings.Repeat("a", networkNameLength),
+			pruningPoint: externalapi.NewZeroHash(),
+		})
+		if (err == nil) != (networkNameLength <= maxNetworkNameLength) {
+			t.Fatalf("Unexpected result for a network name of %d bytes: %v", networkNameLength, err)
+		}
+	}
+
+	// A length prefix above the maximum message size is rejected before anything is allocated for it
+	snapshot := &bytes.Buffer{}
+	_, err := newSnapshotWriter(snapshot, &snapshotHeader{
+		networkName:  dagconfig.SimnetParams.Name,
+		pruningPoint: externalapi.NewZeroHash(),
+	})
+	if err != nil {
+		t.Fatalf("newSnapshotWriter: %+v", err)
+	}
+	err = binary.Write(snapshot, binary.LittleEndian, uint32(maxMessageSize+1))
+	if err != nil {
+		t.Fatalf("binary.Write: %+v", err)
+	}
+	snapshotReader, err := newSnapshotReader(bytes.NewReader(snapshot.Bytes()))
+	if err != nil {
+		t.Fatalf("newSnapshotReader: %+v", err)
+	}
+	if snapshotReader.header.networkName != dagconfig.SimnetParams.Name {
+		t.Fatalf("Expected the network name to be %s, but got %s", dagconfig.SimnetParams.Name,
+			snapshotReader.header.networkName)
+	}
+	_, err = snapshotReader.readMessage()
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Fatalf("Expected an oversized message to be rejected, but got: %v", err)
+	}
+}
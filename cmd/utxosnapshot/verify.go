@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
+)
+
+func verify(conf *verifyConfig) error {
+	summary, err := verifySnapshotFile(conf.InputFile, conf.ExpectedHash, conf.NetParams())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The snapshot of pruning point %s is valid\n", summary.header.pruningPoint)
+	fmt.Printf("Blocks with trusted data: %d\n", summary.blockCount)
+	fmt.Printf("Headers above the pruning point: %d\n", summary.headerCount)
+	fmt.Printf("UTXOs: %d\n", summary.utxoCount)
+	fmt.Printf("Snapshot hash: %x\n", summary.hash)
+	return nil
+}
+
+// verifySnapshotFile reads the whole snapshot file without importing it. If expectedHash
+// is not empty, the snapshot hash must match it.
+func verifySnapshotFile(path string, expectedHash string, params *dagconfig.Params) (*snapshotSummary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	summary, err := readSnapshot(bufio.NewReader(file), params, &snapshotHandlers{})
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedHash != "" {
+		if hex.EncodeToString(summary.hash) != expectedHash {
+			return nil, errors.Errorf("the snapshot hash is %x instead of the expected %s", summary.hash, expectedHash)
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,302 @@
+package addresshistoryindex
+
+import (
+	"sync"
+
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database"
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+)
+
+// acceptanceDataBatchSize is the maximum number of chain blocks whose acceptance data
+// is requested from the consensus at once
+const acceptanceDataBatchSize = 100
+
+// AddressHistoryIndex maintains an index between scriptPublicKeys and
+// the accepted transactions that credited or debited them
+type AddressHistoryIndex struct {
+	domain domain.Domain
+	store  *addressHistoryIndexStore
+
+	mutex sync.Mutex
+}
+
+// addressTransactionEntry is a single (scriptPublicKey, transaction) pair of the index
+type addressTransactionEntry struct {
+	scriptPublicKey   *externalapi.ScriptPublicKey
+	transactionID     *externalapi.DomainTransactionID
+	acceptingDAAScore uint64
+	isCredit          bool
+	isDebit           bool
+}
+
+// New creates a new address history index.
+//
+// NOTE: While this is called no new blocks can be added to the consensus.
+func New(domain domain.Domain, database database.Database) (*AddressHistoryIndex, error) {
+	addressHistoryIndex := &AddressHistoryIndex{
+		domain: domain,
+		store:  newAddressHistoryIndexStore(database),
+	}
+	isSynced, err := addressHistoryIndex.isSynced()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSynced {
+		err := addressHistoryIndex.Reset()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return addressHistoryIndex, nil
+}
+
+// Reset deletes the whole address history index and resyncs it from the virtual
+// selected parent chain above the pruning point. The history of transactions that
+// were accepted below the pruning point is no longer available in the consensus.
+func (ahi *AddressHistoryIndex) Reset() error {
+	ahi.mutex.Lock()
+	defer ahi.mutex.Unlock()
+
+	log.Infof("Starting address history index reset")
+
+	err := ahi.store.deleteAll()
+	if err != nil {
+		return err
+	}
+
+	virtualInfo, err := ahi.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return err
+	}
+
+	pruningPoint, err := ahi.domain.Consensus().PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	chainPath, err := ahi.domain.Consensus().GetVirtualSelectedParentChainFromBlock(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(chainPath.Added); start += acceptanceDataBatchSize {
+		end := start + acceptanceDataBatchSize
+		if end > len(chainPath.Added) {
+			end = len(chainPath.Added)
+		}
+
+		err := ahi.addAndCommitChainBlocks(chainPath.Added[start:end])
+		if err != nil {
+			return err
+		}
+		log.Debugf("Indexed the address history of %d out of %d chain blocks", end, len(chainPath.Added))
+	}
+
+	// This has to be done last to mark that the reset went smoothly and no reset has to be called next time.
+	err = ahi.store.putVirtualParents(ahi.store.database, virtualInfo.ParentHashes)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Finished address history index reset")
+	return nil
+}
+
+func (ahi *AddressHistoryIndex) isSynced() (bool, error) {
+	addressHistoryIndexVirtualParents, err := ahi.store.getVirtualParents()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	virtualInfo, err := ahi.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return false, err
+	}
+
+	return externalapi.HashesEqual(virtualInfo.ParentHashes, addressHistoryIndexVirtualParents), nil
+}
+
+// Update updates the address history index with the given DAG selected parent chain changes.
+// The history added by chain blocks that were removed from the selected parent chain is rolled back.
+func (ahi *AddressHistoryIndex) Update(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressHistoryIndex.Update")
+	defer onEnd()
+
+	ahi.mutex.Lock()
+	defer ahi.mutex.Unlock()
+
+	var removed, added []*externalapi.DomainHash
+	if virtualChangeSet.VirtualSelectedParentChainChanges != nil {
+		removed = virtualChangeSet.VirtualSelectedParentChainChanges.Removed
+		added = virtualChangeSet.VirtualSelectedParentChainChanges.Added
+	}
+	log.Tracef("Updating address history index with %d removed and %d added chain blocks",
+		len(removed), len(added))
+
+	dbTransaction, err := ahi.store.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	// Removals have to be staged before additions, since the same entry might be
+	// re-added by a new chain block, and the last write wins
+	removedEntries, err := ahi.chainBlocksEntries(removed)
+	if err != nil {
+		return err
+	}
+	for _, entry := range removedEntries {
+		err := ahi.store.remove(dbTransaction, entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ahi.addChainBlocks(dbTransaction, added)
+	if err != nil {
+		return err
+	}
+
+	err = ahi.store.putVirtualParents(dbTransaction, virtualChangeSet.VirtualParents)
+	if err != nil {
+		return err
+	}
+
+	return dbTransaction.Commit()
+}
+
+func (ahi *AddressHistoryIndex) addAndCommitChainBlocks(added []*externalapi.DomainHash) error {
+	dbTransaction, err := ahi.store.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = ahi.addChainBlocks(dbTransaction, added)
+	if err != nil {
+		return err
+	}
+
+	return dbTransaction.Commit()
+}
+
+func (ahi *AddressHistoryIndex) addChainBlocks(dbTransaction database.Transaction, added []*externalapi.DomainHash) error {
+	addedEntries, err := ahi.chainBlocksEntries(added)
+	if err != nil {
+		return err
+	}
+	for _, entry := range addedEntries {
+		err := ahi.store.add(dbTransaction, entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chainBlocksEntries returns the address history entries of all the transactions accepted by the given
+// chain blocks. The entries are derived from the acceptance data, so that the history of a chain block
+// can be rolled back the same way it was added.
+func (ahi *AddressHistoryIndex) chainBlocksEntries(chainBlockHashes []*externalapi.DomainHash) (
+	[]*addressTransactionEntry, error) {
+
+	if len(chainBlockHashes) == 0 {
+		return nil, nil
+	}
+
+	blocksAcceptanceData, err := ahi.domain.Consensus().GetBlocksAcceptanceData(chainBlockHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*addressTransactionEntry
+	for i, blockHash := range chainBlockHashes {
+		header, err := ahi.domain.Consensus().GetBlockHeader(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		acceptingDAAScore := header.DAAScore()
+
+		for _, blockAcceptanceData := range blocksAcceptanceData[i] {
+			for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+				if !transactionAcceptanceData.IsAccepted {
+					continue
+				}
+				entries = append(entries, transactionEntries(transactionAcceptanceData, acceptingDAAScore)...)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// transactionEntries returns an entry for every scriptPublicKey that the given accepted transaction
+// credits or debits
+func transactionEntries(transactionAcceptanceData *externalapi.TransactionAcceptanceData,
+	acceptingDAAScore uint64) []*addressTransactionEntry {
+
+	transaction := transactionAcceptanceData.Transaction
+	transactionID := consensushashing.TransactionID(transaction)
+
+	var entries []*addressTransactionEntry
+	entriesByScriptPublicKey := make(map[string]*addressTransactionEntry)
+	entryOf := func(scriptPublicKey *externalapi.ScriptPublicKey) *addressTransactionEntry {
+		key := scriptPublicKey.String()
+		entry, ok := entriesByScriptPublicKey[key]
+		if !ok {
+			entry = &addressTransactionEntry{
+				scriptPublicKey:   scriptPublicKey,
+				transactionID:     transactionID,
+				acceptingDAAScore: acceptingDAAScore,
+			}
+			entriesByScriptPublicKey[key] = entry
+			entries = append(entries, entry)
+		}
+		return entry
+	}
+
+	for _, output := range transaction.Outputs {
+		entryOf(output.ScriptPublicKey).isCredit = true
+	}
+	for _, utxoEntry := range transactionAcceptanceData.TransactionInputUTXOEntries {
+		entryOf(utxoEntry.ScriptPublicKey()).isDebit = true
+	}
+	return entries
+}
+
+// AddressTransactions returns up to limit transactions that credited or debited the given
+// scriptPublicKey, ordered by their accepting DAA score. An empty pageToken returns the
+// first page. The returned nextPageToken is empty if there are no more transactions.
+func (ahi *AddressHistoryIndex) AddressTransactions(scriptPublicKey *externalapi.ScriptPublicKey,
+	pageToken string, limit int) (addressTransactions []*AddressTransaction, nextPageToken string, err error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressHistoryIndex.AddressTransactions")
+	defer onEnd()
+
+	var startKeySuffix []byte
+	if pageToken != "" {
+		startKeySuffix, err = decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	ahi.mutex.Lock()
+	defer ahi.mutex.Unlock()
+
+	addressTransactions, nextKeySuffix, err := ahi.store.getAddressTransactions(scriptPublicKey, startKeySuffix, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if nextKeySuffix != nil {
+		nextPageToken = encodePageToken(nextKeySuffix)
+	}
+	return addressTransactions, nextPageToken, nil
+}
@@ -0,0 +1,118 @@
+package addresshistoryindex
+
+import (
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/testutils"
+)
+
+type fakeDomain struct {
+	domain.Domain
+	consensus externalapi.Consensus
+}
+
+func (d *fakeDomain) Consensus() externalapi.Consensus {
+	return d.consensus
+}
+
+func TestAddressHistoryIndex(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestAddressHistoryIndex")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		addressHistoryIndex, err := New(&fakeDomain{consensus: tc}, tc.Database())
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+
+		// addChain adds a chain of blocks on top of genesis, all of which pay to the given
+		// scriptPublicKey, and updates the index with every resulting virtual change
+		addChain := func(length int, scriptPublicKey *externalapi.ScriptPublicKey) {
+			coinbaseData := &externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey}
+			parent := consensusConfig.GenesisHash
+			for i := 0; i < length; i++ {
+				blockHash, virtualChangeSet, err := tc.AddBlock([]*externalapi.DomainHash{parent}, coinbaseData, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				err = addressHistoryIndex.Update(virtualChangeSet)
+				if err != nil {
+					t.Fatalf("Update: %+v", err)
+				}
+				parent = blockHash
+			}
+		}
+		allAddressTransactions := func(scriptPublicKey *externalapi.ScriptPublicKey, limit int) []*AddressTransaction {
+			var addressTransactions []*AddressTransaction
+			pageToken := ""
+			for {
+				page, nextPageToken, err := addressHistoryIndex.AddressTransactions(scriptPublicKey, pageToken, limit)
+				if err != nil {
+					t.Fatalf("AddressTransactions: %+v", err)
+				}
+				if len(page) > limit {
+					t.Fatalf("Expected at most %d transactions in a page, but got %d", limit, len(page))
+				}
+				addressTransactions = append(addressTransactions, page...)
+				if nextPageToken == "" {
+					return addressTransactions
+				}
+				pageToken = nextPageToken
+			}
+		}
+
+		scriptPublicKeyA := &externalapi.ScriptPublicKey{Script: []byte{0xaa}, Version: 0}
+		scriptPublicKeyB := &externalapi.ScriptPublicKey{Script: []byte{0xbb}, Version: 0}
+
+		addChain(3, scriptPublicKeyA)
+		historyA := allAddressTransactions(scriptPublicKeyA, 100)
+		if len(historyA) == 0 {
+			t.Fatalf("Expected the coinbase transactions of chain A to be indexed")
+		}
+		for _, addressTransaction := range historyA {
+			if !addressTransaction.IsCredit || addressTransaction.IsDebit {
+				t.Fatalf("Expected coinbase transaction %s to only credit the coinbase scriptPublicKey",
+					addressTransaction.TransactionID)
+			}
+		}
+
+		// A longer chain that does not merge chain A reorgs the virtual selected parent chain
+		addChain(6, scriptPublicKeyB)
+		historyA = allAddressTransactions(scriptPublicKeyA, 100)
+		if len(historyA) != 0 {
+			t.Fatalf("Expected the history of chain A to be rolled back, but got %d transactions", len(historyA))
+		}
+
+		historyB := allAddressTransactions(scriptPublicKeyB, 100)
+		if len(historyB) < 2 {
+			t.Fatalf("Expected the coinbase transactions of chain B to be indexed, but got %d", len(historyB))
+		}
+		for i := 1; i < len(historyB); i++ {
+			if historyB[i].AcceptingDAAScore < historyB[i-1].AcceptingDAAScore {
+				t.Fatalf("Expected transactions to be ordered by accepting DAA score")
+			}
+		}
+
+		pagedHistoryB := allAddressTransactions(scriptPublicKeyB, 1)
+		if len(pagedHistoryB) != len(historyB) {
+			t.Fatalf("Expected %d transactions when paging, but got %d", len(historyB), len(pagedHistoryB))
+		}
+		for i := range historyB {
+			if !pagedHistoryB[i].TransactionID.Equal(historyB[i].TransactionID) {
+				t.Fatalf("Expected paging to return the same transactions in the same order")
+			}
+		}
+
+		_, _, err = addressHistoryIndex.AddressTransactions(scriptPublicKeyB, "not a page token", 1)
+		if err == nil {
+			t.Fatalf("Expected an invalid page token to be rejected")
+		}
+	})
+}
@@ -0,0 +1,7 @@
+package addresshistoryindex
+
+import (
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("AHIN")
@@ -0,0 +1,24 @@
+package addresshistoryindex
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+// ErrInvalidPageToken is returned when a page token that was not returned by
+// AddressTransactions is passed to it
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// AddressTransaction is a transaction that credited or debited a scriptPublicKey
+type AddressTransaction struct {
+	TransactionID *externalapi.DomainTransactionID
+
+	// AcceptingDAAScore is the DAA score of the selected chain block that accepted the transaction
+	AcceptingDAAScore uint64
+
+	// IsCredit is set if any of the transaction outputs pays to the scriptPublicKey
+	IsCredit bool
+
+	// IsDebit is set if any of the transaction inputs spends an output of the scriptPublicKey
+	IsDebit bool
+}
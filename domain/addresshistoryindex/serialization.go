@@ -0,0 +1,116 @@
+package addresshistoryindex
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+const (
+	lengthSize    = 8
+	daaScoreSize  = 8
+	entryKeySize  = daaScoreSize + externalapi.DomainHashSize
+	directionSize = 1
+)
+
+const (
+	creditFlag = byte(1 << 0)
+	debitFlag  = byte(1 << 1)
+)
+
+// entryKeySuffix returns the key suffix of an address transaction within the bucket of its
+// scriptPublicKey. The DAA score is serialized in big-endian so that cursors iterate the
+// history of a scriptPublicKey by ascending accepting DAA score
+func entryKeySuffix(acceptingDAAScore uint64, transactionID *externalapi.DomainTransactionID) []byte {
+	suffix := make([]byte, entryKeySize)
+	binary.BigEndian.PutUint64(suffix[:daaScoreSize], acceptingDAAScore)
+	copy(suffix[daaScoreSize:], transactionID.ByteSlice())
+	return suffix
+}
+
+func deserializeAddressTransaction(keySuffix []byte, serializedDirection []byte) (*AddressTransaction, error) {
+	if len(keySuffix) != entryKeySize {
+		return nil, errors.Errorf("unexpected address transaction key length %d", len(keySuffix))
+	}
+	if len(serializedDirection) != directionSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected length %d while deserializing "+
+			"address transaction direction", len(serializedDirection))
+	}
+
+	transactionID, err := externalapi.NewDomainTransactionIDFromByteSlice(keySuffix[daaScoreSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &AddressTransaction{
+		TransactionID:     transactionID,
+		AcceptingDAAScore: binary.BigEndian.Uint64(keySuffix[:daaScoreSize]),
+		IsCredit:          serializedDirection[0]&creditFlag != 0,
+		IsDebit:           serializedDirection[0]&debitFlag != 0,
+	}, nil
+}
+
+func serializeDirection(isCredit, isDebit bool) []byte {
+	direction := byte(0)
+	if isCredit {
+		direction |= creditFlag
+	}
+	if isDebit {
+		direction |= debitFlag
+	}
+	return []byte{direction}
+}
+
+// encodePageToken returns an opaque token that points to the first entry of the next page
+func encodePageToken(keySuffix []byte) string {
+	return hex.EncodeToString(keySuffix)
+}
+
+func decodePageToken(pageToken string) ([]byte, error) {
+	keySuffix, err := hex.DecodeString(pageToken)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidPageToken, "%s", err)
+	}
+	if len(keySuffix) != entryKeySize {
+		return nil, errors.Wrapf(ErrInvalidPageToken, "unexpected length %d", len(keySuffix))
+	}
+	return keySuffix, nil
+}
+
+func serializeHashes(hashes []*externalapi.DomainHash) []byte {
+	serializedHashes := make([]byte, lengthSize+externalapi.DomainHashSize*len(hashes))
+	binary.LittleEndian.PutUint64(serializedHashes[:lengthSize], uint64(len(hashes)))
+	for i, hash := range hashes {
+		start := lengthSize + externalapi.DomainHashSize*i
+		end := start + externalapi.DomainHashSize
+		copy(serializedHashes[start:end], hash.ByteSlice())
+	}
+	return serializedHashes
+}
+
+func deserializeHashes(serializedHashes []byte) ([]*externalapi.DomainHash, error) {
+	if len(serializedHashes) < lengthSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing hashes")
+	}
+
+	length := binary.LittleEndian.Uint64(serializedHashes[:lengthSize])
+	hashes := make([]*externalapi.DomainHash, 0, length)
+	for i := uint64(0); i < length; i++ {
+		start := lengthSize + externalapi.DomainHashSize*i
+		end := start + externalapi.DomainHashSize
+
+		if end > uint64(len(serializedHashes)) {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing hashes")
+		}
+
+		hash, err := externalapi.NewDomainHashFromByteSlice(serializedHashes[start:end])
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,47 @@
+package addresshistoryindex
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+func Test_serializeAddressTransaction(t *testing.T) {
+	transactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1, 2, 3})
+	for _, test := range []struct{ isCredit, isDebit bool }{{true, false}, {false, true}, {true, true}} {
+		result, err := deserializeAddressTransaction(entryKeySuffix(1234, transactionID),
+			serializeDirection(test.isCredit, test.isDebit))
+		if err != nil {
+			t.Fatalf("Failed deserializing address transaction: %v", err)
+		}
+		if !result.TransactionID.Equal(transactionID) || result.AcceptingDAAScore != 1234 ||
+			result.IsCredit != test.isCredit || result.IsDebit != test.isDebit {
+			t.Fatalf("Unexpected address transaction %+v", result)
+		}
+	}
+}
+
+func Test_pageToken(t *testing.T) {
+	transactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	keySuffix := entryKeySuffix(0x100, transactionID)
+	result, err := decodePageToken(encodePageToken(keySuffix))
+	if err != nil {
+		t.Fatalf("Failed decoding page token: %v", err)
+	}
+	if string(result) != string(keySuffix) {
+		t.Fatalf("Expected %x, got %x", keySuffix, result)
+	}
+
+	for _, pageToken := range []string{"zz", encodePageToken(keySuffix[1:])} {
+		_, err := decodePageToken(pageToken)
+		if !errors.Is(err, ErrInvalidPageToken) {
+			t.Fatalf("Expected ErrInvalidPageToken for page token %s, but got: %v", pageToken, err)
+		}
+	}
+
+	lower := entryKeySuffix(0xff, externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{0xff}))
+	if string(lower) >= string(keySuffix) {
+		t.Fatalf("Expected entry keys to be ordered by accepting DAA score")
+	}
+}
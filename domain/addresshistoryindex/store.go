@@ -0,0 +1,137 @@
+package addresshistoryindex
+
+import (
+	"encoding/binary"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database"
+)
+
+var addressHistoryIndexBucket = database.MakeBucket([]byte("address-history-index"))
+var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("address-history-index-virtual-parents"))
+
+type addressHistoryIndexStore struct {
+	database database.Database
+}
+
+func newAddressHistoryIndexStore(database database.Database) *addressHistoryIndexStore {
+	return &addressHistoryIndexStore{
+		database: database,
+	}
+}
+
+func (ahis *addressHistoryIndexStore) bucketForScriptPublicKey(scriptPublicKey *externalapi.ScriptPublicKey) *database.Bucket {
+	var scriptPublicKeyBytes = make([]byte, 2+len(scriptPublicKey.Script)) // uint16
+	binary.LittleEndian.PutUint16(scriptPublicKeyBytes[:2], scriptPublicKey.Version)
+	copy(scriptPublicKeyBytes[2:], scriptPublicKey.Script)
+	return addressHistoryIndexBucket.Bucket(scriptPublicKeyBytes)
+}
+
+func (ahis *addressHistoryIndexStore) entryKey(entry *addressTransactionEntry) *database.Key {
+	return ahis.bucketForScriptPublicKey(entry.scriptPublicKey).Key(
+		entryKeySuffix(entry.acceptingDAAScore, entry.transactionID))
+}
+
+func (ahis *addressHistoryIndexStore) add(dbTransaction database.Transaction, entry *addressTransactionEntry) error {
+	return dbTransaction.Put(ahis.entryKey(entry), serializeDirection(entry.isCredit, entry.isDebit))
+}
+
+func (ahis *addressHistoryIndexStore) remove(dbTransaction database.Transaction, entry *addressTransactionEntry) error {
+	return dbTransaction.Delete(ahis.entryKey(entry))
+}
+
+// getAddressTransactions returns up to limit transactions of the given scriptPublicKey, starting
+// at the given key suffix (or at the beginning if it's nil). It also returns the key suffix of
+// the entry following the returned ones, or nil if there are none.
+func (ahis *addressHistoryIndexStore) getAddressTransactions(scriptPublicKey *externalapi.ScriptPublicKey,
+	startKeySuffix []byte, limit int) ([]*AddressTransaction, []byte, error) {
+
+	bucket := ahis.bucketForScriptPublicKey(scriptPublicKey)
+	cursor, err := ahis.database.Cursor(bucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close()
+
+	// Seek positions the cursor at the first key that is greater than or equal to the start key. It
+	// returns ErrNotFound if the start key itself doesn't exist, for example if it was removed by a
+	// reorg, but the cursor would still be positioned at the following key, if any.
+	hasCurrent := cursor.First()
+	if startKeySuffix != nil {
+		err := cursor.Seek(bucket.Key(startKeySuffix))
+		if err != nil && !database.IsNotFoundError(err) {
+			return nil, nil, err
+		}
+		_, err = cursor.Key()
+		hasCurrent = err == nil
+	}
+
+	var addressTransactions []*AddressTransaction
+	for ; hasCurrent; hasCurrent = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, nil, err
+		}
+		// The bucket of a scriptPublicKey might prefix the bucket of a longer one
+		if len(key.Suffix()) != entryKeySize {
+			continue
+		}
+		if len(addressTransactions) == limit {
+			return addressTransactions, key.Suffix(), nil
+		}
+
+		serializedDirection, err := cursor.Value()
+		if err != nil {
+			return nil, nil, err
+		}
+		addressTransaction, err := deserializeAddressTransaction(key.Suffix(), serializedDirection)
+		if err != nil {
+			return nil, nil, err
+		}
+		addressTransactions = append(addressTransactions, addressTransaction)
+	}
+
+	return addressTransactions, nil, nil
+}
+
+func (ahis *addressHistoryIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
+	serializedVirtualParents, err := ahis.database.Get(virtualParentsKey)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeHashes(serializedVirtualParents)
+}
+
+func (ahis *addressHistoryIndexStore) putVirtualParents(dataAccessor database.DataAccessor,
+	virtualParents []*externalapi.DomainHash) error {
+
+	return dataAccessor.Put(virtualParentsKey, serializeHashes(virtualParents))
+}
+
+func (ahis *addressHistoryIndexStore) deleteAll() error {
+	// First we delete the virtual parents, so if anything goes wrong, the address history index will be
+	// marked as "not synced" and will be reset.
+	err := ahis.database.Delete(virtualParentsKey)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := ahis.database.Cursor(addressHistoryIndexBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+
+		err = ahis.database.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -741,6 +741,20 @@ func (s *consensus) GetVirtualDAAScore() (uint64, error) {
 	return s.daaBlocksStore.DAAScore(s.databaseContext, stagingArea, model.VirtualBlockHash)
 }
 
+func (s *consensus) CalcBlockSubsidy(blockHash *externalapi.DomainHash) (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stagingArea := model.NewStagingArea()
+
+	err := s.validateBlockHashExists(stagingArea, blockHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.coinbaseManager.CalcBlockSubsidy(stagingArea, blockHash)
+}
+
 func (s *consensus) CreateBlockLocatorFromPruningPoint(highHash *externalapi.DomainHash, limit uint32) (externalapi.BlockLocator, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
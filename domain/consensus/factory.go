@@ -351,6 +351,7 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		config.TimestampDeviationTolerance,
 		config.TargetTimePerBlock,
 		config.MaxBlockLevel,
+		config.Checkpoints,
 
 		dbManager,
 		difficultyManager,
@@ -471,6 +472,7 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		config.K,
 		config.PruningProofM,
 		config.MaxBlockLevel,
+		config.Checkpoints,
 	)
 
 	c := &consensus{
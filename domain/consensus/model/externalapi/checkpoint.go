@@ -0,0 +1,7 @@
+package externalapi
+
+// Checkpoint is a known-good selected chain block at a given DAA score
+type Checkpoint struct {
+	DAAScore uint64
+	Hash     *DomainHash
+}
@@ -40,6 +40,7 @@ type Consensus interface {
 	Tips() ([]*DomainHash, error)
 	GetVirtualInfo() (*VirtualInfo, error)
 	GetVirtualDAAScore() (uint64, error)
+	CalcBlockSubsidy(blockHash *DomainHash) (uint64, error)
 	IsValidPruningPoint(blockHash *DomainHash) (bool, error)
 	ArePruningPointsViolatingFinality(pruningPoints []BlockHeader) (bool, error)
 	GetVirtualSelectedParentChainFromBlock(blockHash *DomainHash) (*SelectedChainPath, error)
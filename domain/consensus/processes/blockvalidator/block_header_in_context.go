@@ -85,6 +85,11 @@ func (v *blockValidator) ValidateHeaderInContext(stagingArea *model.StagingArea,
 		return err
 	}
 
+	err = v.checkCheckpoints(stagingArea, blockHash, isBlockWithTrustedData)
+	if err != nil {
+		return err
+	}
+
 	err = v.checkBlueWork(stagingArea, blockHash, header)
 	if err != nil {
 		return err
@@ -25,6 +25,7 @@ type blockValidator struct {
 	timestampDeviationTolerance int
 	targetTimePerBlock          time.Duration
 	maxBlockLevel               int
+	checkpoints                 []externalapi.Checkpoint
 
 	databaseContext       model.DBReader
 	difficultyManager     model.DifficultyManager
@@ -64,6 +65,7 @@ func New(powMax *big.Int,
 	timestampDeviationTolerance int,
 	targetTimePerBlock time.Duration,
 	maxBlockLevel int,
+	checkpoints []externalapi.Checkpoint,
 
 	databaseContext model.DBReader,
 
@@ -103,6 +105,7 @@ func New(powMax *big.Int,
 		mergeSetSizeLimit:          mergeSetSizeLimit,
 		maxBlockParents:            maxBlockParents,
 		maxBlockLevel:              maxBlockLevel,
+		checkpoints:                checkpoints,
 
 		timestampDeviationTolerance: timestampDeviationTolerance,
 		targetTimePerBlock:          targetTimePerBlock,
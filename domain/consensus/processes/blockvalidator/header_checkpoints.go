@@ -0,0 +1,82 @@
+package blockvalidator
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/ruleerrors"
+)
+
+// checkCheckpoints validates that every checkpoint that is below the finality point of the
+// block is in the block's selected parent chain.
+//
+// The checkpoints are checked against the finality point rather than the block itself, since
+// blocks in the anticone of a checkpoint are valid, and so are the blocks that merge them. A
+// block that is more than the finality depth above a checkpoint, however, has to agree with it.
+func (v *blockValidator) checkCheckpoints(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash,
+	isBlockWithTrustedData bool) error {
+
+	if len(v.checkpoints) == 0 || isBlockWithTrustedData {
+		return nil
+	}
+
+	finalityPoint, err := v.finalityManager.FinalityPoint(stagingArea, blockHash, isBlockWithTrustedData)
+	if err != nil {
+		return err
+	}
+	// The finality point is the virtual genesis when it's below the pruning point, in which case
+	// the checkpoints below it were already checked as part of the pruning point proof
+	if finalityPoint.Equal(model.VirtualGenesisBlockHash) {
+		return nil
+	}
+
+	finalityPointHeader, err := v.blockHeaderStore.BlockHeader(v.databaseContext, stagingArea, finalityPoint)
+	if err != nil {
+		return err
+	}
+
+	for _, checkpoint := range v.checkpoints {
+		if checkpoint.DAAScore > finalityPointHeader.DAAScore() {
+			break
+		}
+
+		isInSelectedParentChain, err := v.isCheckpointInSelectedParentChainOf(stagingArea, checkpoint, finalityPoint)
+		if err != nil {
+			return err
+		}
+		if !isInSelectedParentChain {
+			return errors.Wrapf(ruleerrors.ErrCheckpointMismatch, "block %s is not in the future of "+
+				"checkpoint %s at DAA score %d", blockHash, checkpoint.Hash, checkpoint.DAAScore)
+		}
+	}
+
+	return nil
+}
+
+func (v *blockValidator) isCheckpointInSelectedParentChainOf(stagingArea *model.StagingArea,
+	checkpoint externalapi.Checkpoint, blockHash *externalapi.DomainHash) (bool, error) {
+
+	if checkpoint.Hash.Equal(blockHash) {
+		return true, nil
+	}
+
+	hasReachabilityData, err := v.reachabilityStore.HasReachabilityData(v.databaseContext, stagingArea, checkpoint.Hash)
+	if err != nil {
+		return false, err
+	}
+	if hasReachabilityData {
+		return v.dagTopologyManagers[0].IsInSelectedParentChainOf(stagingArea, checkpoint.Hash, blockHash)
+	}
+
+	// A node that was synced from a pruning point above the checkpoint might not know the
+	// checkpoint block. In this case the checkpoint was checked against the pruning point proof.
+	pruningPoint, err := v.pruningStore.PruningPoint(v.databaseContext, stagingArea)
+	if err != nil {
+		return false, err
+	}
+	pruningPointHeader, err := v.blockHeaderStore.BlockHeader(v.databaseContext, stagingArea, pruningPoint)
+	if err != nil {
+		return false, err
+	}
+	return checkpoint.DAAScore <= pruningPointHeader.DAAScore(), nil
+}
@@ -0,0 +1,116 @@
+package blockvalidator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/testapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/ruleerrors"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/testutils"
+)
+
+func TestCheckCheckpoints(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.FinalityDuration = 10 * consensusConfig.TargetTimePerBlock
+		finalityDepth := consensusConfig.FinalityDepth()
+
+		factory := consensus.NewFactory()
+		tcSource, teardownSource, err := factory.NewTestConsensus(consensusConfig, "TestCheckCheckpointsSource")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSource(false)
+
+		// addChain adds a chain of blocks on top of the given parent, and returns the blocks
+		addChain := func(parent *externalapi.DomainHash, length int, extraData byte) []*externalapi.DomainBlock {
+			coinbaseData := &externalapi.DomainCoinbaseData{
+				ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+				ExtraData:       []byte{extraData},
+			}
+			chain := make([]*externalapi.DomainBlock, length)
+			for i := range chain {
+				blockHash, _, err := tcSource.AddBlock([]*externalapi.DomainHash{parent}, coinbaseData, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				block, found, err := tcSource.GetBlock(blockHash)
+				if err != nil {
+					t.Fatalf("GetBlock: %+v", err)
+				}
+				if !found {
+					t.Fatalf("Block %s was not found", blockHash)
+				}
+				chain[i] = block
+				parent = blockHash
+			}
+			return chain
+		}
+
+		chainLength := int(finalityDepth) + 10
+		chainA := addChain(consensusConfig.GenesisHash, chainLength, 'a')
+		checkpointBlock := chainA[2]
+		checkpointHash := consensushashing.BlockHash(checkpointBlock)
+
+		// A block in the anticone of the checkpoint
+		siblingOfCheckpoint := addChain(consensushashing.BlockHash(chainA[1]), 1, 's')[0]
+		chainB := addChain(consensusConfig.GenesisHash, chainLength, 'b')
+
+		checkedConfig := *consensusConfig
+		checkedConfig.Checkpoints = []externalapi.Checkpoint{
+			{DAAScore: checkpointBlock.Header.DAAScore(), Hash: checkpointHash},
+		}
+		tc, teardown, err := factory.NewTestConsensus(&checkedConfig, "TestCheckCheckpoints")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		insertBlocks(t, tc, chainA)
+
+		// Blocks in the anticone of the checkpoint don't contradict it
+		insertBlocks(t, tc, []*externalapi.DomainBlock{siblingOfCheckpoint})
+
+		// A chain that doesn't go through the checkpoint is valid until its finality point passes the checkpoint
+		var rejectedBlock *externalapi.DomainBlock
+		for i, block := range chainB {
+			err := tc.ValidateAndInsertBlock(block, true)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, ruleerrors.ErrCheckpointMismatch) {
+				t.Fatalf("Expected block %d of the competing chain to fail with ErrCheckpointMismatch, but "+
+					"got: %+v", i, err)
+			}
+			if uint64(i) < finalityDepth {
+				t.Fatalf("Block %d of the competing chain was rejected before its finality point passed "+
+					"the checkpoint", i)
+			}
+			rejectedBlock = block
+			break
+		}
+		if rejectedBlock == nil {
+			t.Fatalf("Expected the competing chain to be rejected")
+		}
+
+		// Without the checkpoint, the competing chain is valid
+		tcWithoutCheckpoints, teardownWithoutCheckpoints, err := factory.NewTestConsensus(consensusConfig,
+			"TestCheckCheckpointsWithoutCheckpoints")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownWithoutCheckpoints(false)
+		insertBlocks(t, tcWithoutCheckpoints, chainB)
+	})
+}
+
+func insertBlocks(t *testing.T, tc testapi.TestConsensus, blocks []*externalapi.DomainBlock) {
+	for _, block := range blocks {
+		err := tc.ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+}
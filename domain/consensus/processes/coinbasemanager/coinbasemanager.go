@@ -4,6 +4,7 @@ import (
 	"github.com/stokesnetwork/stokes/domain/consensus/model"
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/emission"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/hashset"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/subnetworks"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionhelper"
@@ -199,27 +200,10 @@ func (c *coinbaseManager) CalcBlockSubsidy(stagingArea *model.StagingArea, block
 }
 
 // STOKES: Bitcoin-style halving calculation
-// This function calculates the block subsidy based on the number of halvings that have occurred.
 // The subsidy starts at preDeflationaryPhaseBaseSubsidy and halves every halvingIntervalDaaScore blocks.
+// The schedule itself lives in the emission package so that it can be reported over RPC as well.
 func (c *coinbaseManager) calcHalvingBlockSubsidy(blockDaaScore uint64) uint64 {
-	// Calculate how many halving intervals have passed
-	// Each interval is approximately 4 years (126,230,400 seconds at 1 block/second)
-	halvings := blockDaaScore / c.halvingIntervalDaaScore
-	
-	// After 64 halvings, the subsidy becomes 0 (since we're using uint64)
-	// This is similar to Bitcoin's behavior
-	if halvings >= 64 {
-		return 0
-	}
-	
-	// Start with the initial subsidy (50 STKS in sompi)
-	subsidy := c.preDeflationaryPhaseBaseSubsidy
-	
-	// Right shift is equivalent to dividing by 2^halvings
-	// This implements the halving: subsidy = initialSubsidy / (2^halvings)
-	subsidy >>= halvings
-	
-	return subsidy
+	return emission.BlockSubsidy(blockDaaScore, c.preDeflationaryPhaseBaseSubsidy, c.halvingIntervalDaaScore)
 }
 
 // STOKES: Removed Kaspa's smooth decay table and float calculation
@@ -45,6 +45,7 @@ type pruningProofManager struct {
 	k             externalapi.KType
 	pruningProofM uint64
 	maxBlockLevel int
+	checkpoints   []externalapi.Checkpoint
 
 	cachedPruningPoint *externalapi.DomainHash
 	cachedProof        *externalapi.PruningPointProof
@@ -74,6 +75,7 @@ func New(
 	k externalapi.KType,
 	pruningProofM uint64,
 	maxBlockLevel int,
+	checkpoints []externalapi.Checkpoint,
 ) model.PruningProofManager {
 
 	return &pruningProofManager{
@@ -98,6 +100,7 @@ func New(
 		k:             k,
 		pruningProofM: pruningProofM,
 		maxBlockLevel: maxBlockLevel,
+		checkpoints:   checkpoints,
 	}
 }
 
@@ -456,6 +459,11 @@ func (ppm *pruningProofManager) ValidatePruningPointProof(pruningPointProof *ext
 		selectedTipByLevel[blockLevel] = selectedTip
 	}
 
+	err = ppm.validateProofCheckpoints(stagingArea, blockHeaderStore, ghostdagDataStores[0], pruningPoint)
+	if err != nil {
+		return err
+	}
+
 	currentDAGPruningPoint, err := ppm.pruningStore.PruningPoint(ppm.databaseContext, model.NewStagingArea())
 	if err != nil {
 		return err
@@ -568,6 +576,56 @@ func (ppm *pruningProofManager) ValidatePruningPointProof(pruningPointProof *ext
 		"shared blocks with the known DAGs, but doesn't have enough headers from levels higher than the existing block levels.")
 }
 
+// validateProofCheckpoints validates that the selected chain of the pruning point in level 0 of the proof
+// doesn't pass the DAA score of any checkpoint without going through it. Checkpoints below the lowest block
+// of that chain can't be checked by the proof.
+func (ppm *pruningProofManager) validateProofCheckpoints(stagingArea *model.StagingArea,
+	blockHeaderStore model.BlockHeaderStore, ghostdagDataStore model.GHOSTDAGDataStore,
+	pruningPoint *externalapi.DomainHash) error {
+
+	pruningPointHeader, err := blockHeaderStore.BlockHeader(ppm.databaseContext, stagingArea, pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	// The checkpoints are ordered by DAA score, so they're matched from the highest one
+	// that isn't above the pruning point as the selected chain is traversed downwards
+	checkpointIndex := len(ppm.checkpoints) - 1
+	for checkpointIndex >= 0 && ppm.checkpoints[checkpointIndex].DAAScore > pruningPointHeader.DAAScore() {
+		checkpointIndex--
+	}
+
+	current := pruningPoint
+	for checkpointIndex >= 0 && !current.Equal(model.VirtualGenesisBlockHash) {
+		header, err := blockHeaderStore.BlockHeader(ppm.databaseContext, stagingArea, current)
+		if err != nil {
+			return err
+		}
+
+		for checkpointIndex >= 0 {
+			checkpoint := ppm.checkpoints[checkpointIndex]
+			if checkpoint.Hash.Equal(current) {
+				checkpointIndex--
+				continue
+			}
+			if checkpoint.DAAScore < header.DAAScore() {
+				break
+			}
+			return errors.Wrapf(ruleerrors.ErrPruningProofContradictsCheckpoint, "the selected chain of "+
+				"the proof passes the DAA score %d of checkpoint %s at block %s without going through it",
+				checkpoint.DAAScore, checkpoint.Hash, current)
+		}
+
+		ghostdagData, err := ghostdagDataStore.Get(ppm.databaseContext, stagingArea, current, false)
+		if err != nil {
+			return err
+		}
+		current = ghostdagData.SelectedParent()
+	}
+
+	return nil
+}
+
 func (ppm *pruningProofManager) dagStores(maxLevel int) (model.BlockHeaderStore, []model.BlockRelationStore, []model.ReachabilityDataStore, []model.GHOSTDAGDataStore, error) {
 	blockRelationStores := make([]model.BlockRelationStore, maxLevel+1)
 	reachabilityDataStores := make([]model.ReachabilityDataStore, maxLevel+1)
@@ -0,0 +1,104 @@
+package pruningproofmanager_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/ruleerrors"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/testutils"
+)
+
+func TestValidatePruningPointProofCheckpoints(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		// This is done to reduce the pruning depth to 6 blocks
+		consensusConfig.FinalityDuration = 2 * consensusConfig.TargetTimePerBlock
+		consensusConfig.K = 0
+
+		factory := consensus.NewFactory()
+		tcSource, teardownSource, err := factory.NewTestConsensus(consensusConfig,
+			"TestValidatePruningPointProofCheckpointsSource")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSource(false)
+
+		tip := consensusConfig.GenesisHash
+		for i := 0; i < 30; i++ {
+			tip, _, err = tcSource.AddBlock([]*externalapi.DomainHash{tip}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+		}
+
+		pruningPoint, err := tcSource.PruningPoint()
+		if err != nil {
+			t.Fatalf("PruningPoint: %+v", err)
+		}
+		if pruningPoint.Equal(consensusConfig.GenesisHash) {
+			t.Fatalf("Expected the pruning point to move")
+		}
+
+		pruningPointProof, err := tcSource.BuildPruningPointProof()
+		if err != nil {
+			t.Fatalf("BuildPruningPointProof: %+v", err)
+		}
+		level0Headers := pruningPointProof.Headers[0]
+		pruningPointHeader := level0Headers[len(level0Headers)-1]
+		checkpointHeader := level0Headers[len(level0Headers)/2]
+		unknownHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{0xff})
+
+		tests := []struct {
+			name        string
+			checkpoints []externalapi.Checkpoint
+			expectedErr error
+		}{
+			{
+				name: "checkpoint in the proof selected chain",
+				checkpoints: []externalapi.Checkpoint{
+					{DAAScore: checkpointHeader.DAAScore(), Hash: consensushashing.HeaderHash(checkpointHeader)},
+				},
+				expectedErr: nil,
+			},
+			{
+				name: "checkpoint contradicting the proof selected chain",
+				checkpoints: []externalapi.Checkpoint{
+					{DAAScore: checkpointHeader.DAAScore(), Hash: unknownHash},
+				},
+				expectedErr: ruleerrors.ErrPruningProofContradictsCheckpoint,
+			},
+			{
+				name: "checkpoint at the pruning point",
+				checkpoints: []externalapi.Checkpoint{
+					{DAAScore: checkpointHeader.DAAScore(), Hash: consensushashing.HeaderHash(checkpointHeader)},
+					{DAAScore: pruningPointHeader.DAAScore(), Hash: consensushashing.HeaderHash(pruningPointHeader)},
+				},
+				expectedErr: nil,
+			},
+			{
+				name: "checkpoint above the pruning point",
+				checkpoints: []externalapi.Checkpoint{
+					{DAAScore: pruningPointHeader.DAAScore() + 1, Hash: unknownHash},
+				},
+				expectedErr: nil,
+			},
+		}
+
+		for _, test := range tests {
+			checkedConfig := *consensusConfig
+			checkedConfig.Checkpoints = test.checkpoints
+			tc, teardown, err := factory.NewTestConsensus(&checkedConfig, "TestValidatePruningPointProofCheckpoints")
+			if err != nil {
+				t.Fatalf("Error setting up consensus: %+v", err)
+			}
+
+			err = tc.ValidatePruningPointProof(pruningPointProof)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("%s: expected error %v, but got: %+v", test.name, test.expectedErr, err)
+			}
+			teardown(false)
+		}
+	})
+}
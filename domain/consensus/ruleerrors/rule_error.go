@@ -230,6 +230,10 @@ var (
 	// the expected value.
 	ErrUnexpectedHeaderPruningPoint = newRuleError("ErrUnexpectedHeaderPruningPoint")
 
+	// ErrCheckpointMismatch indicates that the selected chain of a block passes the DAA
+	// score of a checkpoint without going through the checkpoint block.
+	ErrCheckpointMismatch = newRuleError("ErrCheckpointMismatch")
+
 	ErrPruningProofHeaderWithNoKnownParents           = newRuleError("ErrPruningProofHeaderWithNoKnownParents")
 	ErrPruningProofMissingBlockLevels                 = newRuleError("ErrPruningProofMissingBlockLevels")
 	ErrPruningProofWrongBlockLevel                    = newRuleError("ErrPruningProofWrongBlockLevel")
@@ -239,6 +243,7 @@ var (
 	ErrPruningProofMissingBlockAtDepthMFromNextLevel  = newRuleError("ErrPruningProofMissingBlockAtDepthMFromNextLevel")
 	ErrPruningProofMissesBlocksBelowPruningPoint      = newRuleError("ErrPruningProofMissesBlocksBelowPruningPoint")
 	ErrPruningProofEmpty                              = newRuleError("ErrPruningProofEmpty")
+	ErrPruningProofContradictsCheckpoint              = newRuleError("ErrPruningProofContradictsCheckpoint")
 	ErrWrongCoinbaseSubsidy                           = newRuleError("ErrWrongCoinbaseSubsidy")
 	ErrWrongBlockVersion                              = newRuleError("ErrWrongBlockVersion")
 	ErrCoinbaseWithInputs                             = newRuleError("ErrCoinbaseWithInputs")
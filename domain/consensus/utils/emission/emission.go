@@ -0,0 +1,33 @@
+package emission
+
+// maxHalvings is the number of halvings after which the subsidy is
+// shifted out of a uint64 entirely
+const maxHalvings = 64
+
+// HalvingEra returns the number of halvings that occurred up to the given DAA score.
+// A halving interval of zero means the subsidy never halves.
+func HalvingEra(daaScore uint64, halvingIntervalDaaScore uint64) uint64 {
+	if halvingIntervalDaaScore == 0 {
+		return 0
+	}
+	return daaScore / halvingIntervalDaaScore
+}
+
+// BlockSubsidy returns the subsidy of a block with the given DAA score. The subsidy
+// starts at baseSubsidy and halves every halvingIntervalDaaScore, Bitcoin-style.
+func BlockSubsidy(daaScore uint64, baseSubsidy uint64, halvingIntervalDaaScore uint64) uint64 {
+	era := HalvingEra(daaScore, halvingIntervalDaaScore)
+	if era >= maxHalvings {
+		return 0
+	}
+	return baseSubsidy >> era
+}
+
+// NextHalvingDAAScore returns the DAA score at which the halving following the given
+// DAA score occurs, and false if the subsidy never changes again after it.
+func NextHalvingDAAScore(daaScore uint64, baseSubsidy uint64, halvingIntervalDaaScore uint64) (uint64, bool) {
+	if BlockSubsidy(daaScore, baseSubsidy, halvingIntervalDaaScore) == 0 || halvingIntervalDaaScore == 0 {
+		return 0, false
+	}
+	return (HalvingEra(daaScore, halvingIntervalDaaScore) + 1) * halvingIntervalDaaScore, true
+}
@@ -0,0 +1,33 @@
+package emission
+
+import "testing"
+
+func TestNextHalvingDAAScore(t *testing.T) {
+	const halvingInterval = 1000
+	const baseSubsidy = 8
+
+	tests := []struct {
+		daaScore               uint64
+		expectedSubsidy        uint64
+		expectedNextHalving    uint64
+		expectedHasNextHalving bool
+	}{
+		{daaScore: 0, expectedSubsidy: 8, expectedNextHalving: 1000, expectedHasNextHalving: true},
+		{daaScore: 999, expectedSubsidy: 8, expectedNextHalving: 1000, expectedHasNextHalving: true},
+		{daaScore: 1000, expectedSubsidy: 4, expectedNextHalving: 2000, expectedHasNextHalving: true},
+		{daaScore: 3500, expectedSubsidy: 1, expectedNextHalving: 4000, expectedHasNextHalving: true},
+		{daaScore: 4000, expectedSubsidy: 0, expectedNextHalving: 0, expectedHasNextHalving: false},
+	}
+
+	for _, test := range tests {
+		subsidy := BlockSubsidy(test.daaScore, baseSubsidy, halvingInterval)
+		if subsidy != test.expectedSubsidy {
+			t.Errorf("DAA score %d: expected subsidy %d, got %d", test.daaScore, test.expectedSubsidy, subsidy)
+		}
+		nextHalving, hasNextHalving := NextHalvingDAAScore(test.daaScore, baseSubsidy, halvingInterval)
+		if nextHalving != test.expectedNextHalving || hasNextHalving != test.expectedHasNextHalving {
+			t.Errorf("DAA score %d: expected next halving (%d, %t), got (%d, %t)", test.daaScore,
+				test.expectedNextHalving, test.expectedHasNextHalving, nextHalving, hasNextHalving)
+		}
+	}
+}
@@ -56,3 +56,18 @@ func TestDevnetGenesisBlock(t *testing.T) {
 			DevnetParams.GenesisHash)
 	}
 }
+
+// TestNewGenesisBlock tests that NewGenesisBlock builds the genesis blocks of the
+// public networks from their header fields and coinbase payloads.
+func TestNewGenesisBlock(t *testing.T) {
+	for _, params := range []*Params{&TestnetParams, &SimnetParams, &DevnetParams} {
+		header := params.GenesisBlock.Header
+		genesis := NewGenesisBlock(header.Version(), params.GenesisBlock.Transactions[0].Payload,
+			header.TimeInMilliseconds(), header.Bits(), header.Nonce())
+		hash := consensushashing.BlockHash(genesis)
+		if !params.GenesisHash.Equal(hash) {
+			t.Fatalf("TestNewGenesisBlock: the genesis block of %s was built "+
+				"with the hash %v, want %v", params.Name, hash, params.GenesisHash)
+		}
+	}
+}
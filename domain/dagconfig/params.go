@@ -192,6 +192,10 @@ type Params struct {
 	MaxBlockLevel int
 
 	MergeDepth uint64
+
+	// Checkpoints are known-good selected chain blocks, ordered by DAA score. A chain that
+	// reaches past the DAA score of a checkpoint without going through it is rejected.
+	Checkpoints []externalapi.Checkpoint
 }
 
 // NormalizeRPCServerAddress returns addr with the current network default
@@ -0,0 +1,401 @@
+package dagconfig
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kaspanet/go-muhash"
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/blockheader"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/merkle"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/subnetworks"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionhelper"
+	"github.com/stokesnetwork/stokes/util"
+	"github.com/stokesnetwork/stokes/util/difficulty"
+)
+
+// paramsFile is the JSON representation of the params of a custom network.
+// Durations are in milliseconds, PowMax is in hex, and any omitted optional
+// field gets the same default as the public networks.
+type paramsFile struct {
+	Name        string   `json:"name"`
+	Net         uint32   `json:"net"`
+	RPCPort     string   `json:"rpcPort"`
+	DefaultPort string   `json:"defaultPort"`
+	DNSSeeds    []string `json:"dnsSeeds"`
+	GRPCSeeds   []string `json:"grpcSeeds"`
+	Prefix      string   `json:"prefix"`
+	PowMax      string   `json:"powMax"`
+
+	Genesis *genesisFile `json:"genesis"`
+
+	K                                       *externalapi.KType `json:"k"`
+	BlockCoinbaseMaturity                   *uint64            `json:"blockCoinbaseMaturity"`
+	SubsidyGenesisReward                    *uint64            `json:"subsidyGenesisReward"`
+	PreDeflationaryPhaseBaseSubsidy         *uint64            `json:"preDeflationaryPhaseBaseSubsidy"`
+	DeflationaryPhaseBaseSubsidy            *uint64            `json:"deflationaryPhaseBaseSubsidy"`
+	TargetTimePerBlockInMilliSeconds        *int64             `json:"targetTimePerBlockInMilliSeconds"`
+	FinalityDurationInMilliSeconds          *int64             `json:"finalityDurationInMilliSeconds"`
+	TimestampDeviationTolerance             *int               `json:"timestampDeviationTolerance"`
+	DifficultyAdjustmentWindowSize          *int               `json:"difficultyAdjustmentWindowSize"`
+	RelayNonStdTxs                          *bool              `json:"relayNonStdTxs"`
+	AcceptUnroutable                        *bool              `json:"acceptUnroutable"`
+	PrivateKeyID                            *byte              `json:"privateKeyID"`
+	EnableNonNativeSubnetworks              *bool              `json:"enableNonNativeSubnetworks"`
+	DisableDifficultyAdjustment             *bool              `json:"disableDifficultyAdjustment"`
+	SkipProofOfWork                         *bool              `json:"skipProofOfWork"`
+	MaxCoinbasePayloadLength                *uint64            `json:"maxCoinbasePayloadLength"`
+	MaxBlockMass                            *uint64            `json:"maxBlockMass"`
+	MaxBlockParents                         *externalapi.KType `json:"maxBlockParents"`
+	MassPerTxByte                           *uint64            `json:"massPerTxByte"`
+	MassPerScriptPubKeyByte                 *uint64            `json:"massPerScriptPubKeyByte"`
+	MassPerSigOp                            *uint64            `json:"massPerSigOp"`
+	MergeSetSizeLimit                       *uint64            `json:"mergeSetSizeLimit"`
+	CoinbasePayloadScriptPublicKeyMaxLength *uint8             `json:"coinbasePayloadScriptPublicKeyMaxLength"`
+	PruningProofM                           *uint64            `json:"pruningProofM"`
+	DeflationaryPhaseDaaScore               *uint64            `json:"deflationaryPhaseDaaScore"`
+	HalvingIntervalDaaScore                 *uint64            `json:"halvingIntervalDaaScore"`
+	DisallowDirectBlocksOnTopOfGenesis      *bool              `json:"disallowDirectBlocksOnTopOfGenesis"`
+	MaxBlockLevel                           *int               `json:"maxBlockLevel"`
+	MergeDepth                              *uint64            `json:"mergeDepth"`
+	Checkpoints                             []checkpointFile   `json:"checkpoints"`
+}
+
+// genesisFile is the JSON representation of a genesis block. The genesis block
+// has a single coinbase transaction with the given payload and no outputs.
+type genesisFile struct {
+	Version            uint16 `json:"version"`
+	TimeInMilliseconds int64  `json:"timeInMilliseconds"`
+	Bits               uint32 `json:"bits"`
+	Nonce              uint64 `json:"nonce"`
+	CoinbasePayload    string `json:"coinbasePayload"`
+
+	// Hash is optional. If it's set, the hash of the genesis block is checked against it.
+	Hash string `json:"hash"`
+}
+
+type checkpointFile struct {
+	DAAScore uint64 `json:"daaScore"`
+	Hash     string `json:"hash"`
+}
+
+// LoadParamsFile loads the params of a custom network from the given JSON file and validates them
+func LoadParamsFile(path string) (*Params, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	params, err := ParseParams(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the network params from %s", path)
+	}
+	return params, nil
+}
+
+// ParseParams parses the params of a custom network from JSON and validates them
+func ParseParams(reader io.Reader) (*Params, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	file := &paramsFile{}
+	err := decoder.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := file.toParams()
+	if err != nil {
+		return nil, err
+	}
+
+	err = params.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (file *paramsFile) toParams() (*Params, error) {
+	if file.Genesis == nil {
+		return nil, errors.New("the genesis block is missing")
+	}
+	if file.PowMax == "" {
+		return nil, errors.New("powMax is missing")
+	}
+	powMax, ok := big.NewInt(0).SetString(file.PowMax, 16)
+	if !ok {
+		return nil, errors.Errorf("couldn't convert powMax %s to big int", file.PowMax)
+	}
+	prefix, err := util.ParsePrefix(file.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	genesisBlock, err := file.Genesis.toBlock()
+	if err != nil {
+		return nil, err
+	}
+	genesisHash := consensushashing.BlockHash(genesisBlock)
+	if file.Genesis.Hash != "" && file.Genesis.Hash != genesisHash.String() {
+		return nil, errors.Errorf("the genesis block hashes to %s instead of %s", genesisHash, file.Genesis.Hash)
+	}
+
+	params := &Params{
+		K:                                       defaultGHOSTDAGK,
+		Name:                                    file.Name,
+		Net:                                     appmessage.KaspaNet(file.Net),
+		RPCPort:                                 file.RPCPort,
+		DefaultPort:                             file.DefaultPort,
+		DNSSeeds:                                file.DNSSeeds,
+		GRPCSeeds:                               file.GRPCSeeds,
+		GenesisBlock:                            genesisBlock,
+		GenesisHash:                             genesisHash,
+		PowMax:                                  powMax,
+		BlockCoinbaseMaturity:                   100,
+		SubsidyGenesisReward:                    defaultSubsidyGenesisReward,
+		PreDeflationaryPhaseBaseSubsidy:         defaultPreDeflationaryPhaseBaseSubsidy,
+		DeflationaryPhaseBaseSubsidy:            defaultDeflationaryPhaseBaseSubsidy,
+		TargetTimePerBlock:                      defaultTargetTimePerBlock,
+		FinalityDuration:                        defaultFinalityDuration,
+		TimestampDeviationTolerance:             defaultTimestampDeviationTolerance,
+		DifficultyAdjustmentWindowSize:          defaultDifficultyAdjustmentWindowSize,
+		Prefix:                                  prefix,
+		PrivateKeyID:                            0xef,
+		MaxCoinbasePayloadLength:                defaultMaxCoinbasePayloadLength,
+		MaxBlockMass:                            defaultMaxBlockMass,
+		MaxBlockParents:                         defaultMaxBlockParents,
+		MassPerTxByte:                           defaultMassPerTxByte,
+		MassPerScriptPubKeyByte:                 defaultMassPerScriptPubKeyByte,
+		MassPerSigOp:                            defaultMassPerSigOp,
+		MergeSetSizeLimit:                       defaultMergeSetSizeLimit,
+		CoinbasePayloadScriptPublicKeyMaxLength: defaultCoinbasePayloadScriptPublicKeyMaxLength,
+		PruningProofM:                           defaultPruningProofM,
+		DeflationaryPhaseDaaScore:               defaultDeflationaryPhaseDaaScore,
+		HalvingIntervalDaaScore:                 defaultHalvingIntervalDaaScore,
+		MaxBlockLevel:                           250,
+		MergeDepth:                              defaultMergeDepth,
+	}
+
+	if file.K != nil {
+		params.K = *file.K
+	}
+	if file.BlockCoinbaseMaturity != nil {
+		params.BlockCoinbaseMaturity = *file.BlockCoinbaseMaturity
+	}
+	if file.SubsidyGenesisReward != nil {
+		params.SubsidyGenesisReward = *file.SubsidyGenesisReward
+	}
+	if file.PreDeflationaryPhaseBaseSubsidy != nil {
+		params.PreDeflationaryPhaseBaseSubsidy = *file.PreDeflationaryPhaseBaseSubsidy
+	}
+	if file.DeflationaryPhaseBaseSubsidy != nil {
+		params.DeflationaryPhaseBaseSubsidy = *file.DeflationaryPhaseBaseSubsidy
+	}
+	if file.TargetTimePerBlockInMilliSeconds != nil {
+		params.TargetTimePerBlock = time.Duration(*file.TargetTimePerBlockInMilliSeconds) * time.Millisecond
+	}
+	if file.FinalityDurationInMilliSeconds != nil {
+		params.FinalityDuration = time.Duration(*file.FinalityDurationInMilliSeconds) * time.Millisecond
+	}
+	if file.TimestampDeviationTolerance != nil {
+		params.TimestampDeviationTolerance = *file.TimestampDeviationTolerance
+	}
+	if file.DifficultyAdjustmentWindowSize != nil {
+		params.DifficultyAdjustmentWindowSize = *file.DifficultyAdjustmentWindowSize
+	}
+	if file.RelayNonStdTxs != nil {
+		params.RelayNonStdTxs = *file.RelayNonStdTxs
+	}
+	if file.AcceptUnroutable != nil {
+		params.AcceptUnroutable = *file.AcceptUnroutable
+	}
+	if file.PrivateKeyID != nil {
+		params.PrivateKeyID = *file.PrivateKeyID
+	}
+	if file.EnableNonNativeSubnetworks != nil {
+		params.EnableNonNativeSubnetworks = *file.EnableNonNativeSubnetworks
+	}
+	if file.DisableDifficultyAdjustment != nil {
+		params.DisableDifficultyAdjustment = *file.DisableDifficultyAdjustment
+	}
+	if file.SkipProofOfWork != nil {
+		params.SkipProofOfWork = *file.SkipProofOfWork
+	}
+	if file.MaxCoinbasePayloadLength != nil {
+		params.MaxCoinbasePayloadLength = *file.MaxCoinbasePayloadLength
+	}
+	if file.MaxBlockMass != nil {
+		params.MaxBlockMass = *file.MaxBlockMass
+	}
+	if file.MaxBlockParents != nil {
+		params.MaxBlockParents = *file.MaxBlockParents
+	}
+	if file.MassPerTxByte != nil {
+		params.MassPerTxByte = *file.MassPerTxByte
+	}
+	if file.MassPerScriptPubKeyByte != nil {
+		params.MassPerScriptPubKeyByte = *file.MassPerScriptPubKeyByte
+	}
+	if file.MassPerSigOp != nil {
+		params.MassPerSigOp = *file.MassPerSigOp
+	}
+	if file.MergeSetSizeLimit != nil {
+		params.MergeSetSizeLimit = *file.MergeSetSizeLimit
+	}
+	if file.CoinbasePayloadScriptPublicKeyMaxLength != nil {
+		params.CoinbasePayloadScriptPublicKeyMaxLength = *file.CoinbasePayloadScriptPublicKeyMaxLength
+	}
+	if file.PruningProofM != nil {
+		params.PruningProofM = *file.PruningProofM
+	}
+	if file.DeflationaryPhaseDaaScore != nil {
+		params.DeflationaryPhaseDaaScore = *file.DeflationaryPhaseDaaScore
+	}
+	if file.HalvingIntervalDaaScore != nil {
+		params.HalvingIntervalDaaScore = *file.HalvingIntervalDaaScore
+	}
+	if file.DisallowDirectBlocksOnTopOfGenesis != nil {
+		params.DisallowDirectBlocksOnTopOfGenesis = *file.DisallowDirectBlocksOnTopOfGenesis
+	}
+	if file.MaxBlockLevel != nil {
+		params.MaxBlockLevel = *file.MaxBlockLevel
+	}
+	if file.MergeDepth != nil {
+		params.MergeDepth = *file.MergeDepth
+	}
+
+	for _, checkpoint := range file.Checkpoints {
+		hash, err := externalapi.NewDomainHashFromString(checkpoint.Hash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hash of the checkpoint at DAA score %d", checkpoint.DAAScore)
+		}
+		params.Checkpoints = append(params.Checkpoints, externalapi.Checkpoint{DAAScore: checkpoint.DAAScore, Hash: hash})
+	}
+
+	return params, nil
+}
+
+func (genesis *genesisFile) toBlock() (*externalapi.DomainBlock, error) {
+	coinbasePayload, err := hex.DecodeString(genesis.CoinbasePayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid genesis coinbase payload")
+	}
+	return NewGenesisBlock(genesis.Version, coinbasePayload, genesis.TimeInMilliseconds, genesis.Bits, genesis.Nonce), nil
+}
+
+// NewGenesisBlock returns a genesis block with the given header fields, whose only transaction is
+// a coinbase with the given payload and no outputs. Everything else in the block is derived from these.
+func NewGenesisBlock(version uint16, coinbasePayload []byte, timeInMilliseconds int64, bits uint32,
+	nonce uint64) *externalapi.DomainBlock {
+
+	coinbaseTransaction := transactionhelper.NewSubnetworkTransaction(0,
+		[]*externalapi.DomainTransactionInput{}, []*externalapi.DomainTransactionOutput{},
+		&subnetworks.SubnetworkIDCoinbase, 0, coinbasePayload)
+	transactions := []*externalapi.DomainTransaction{coinbaseTransaction}
+
+	return &externalapi.DomainBlock{
+		Header: blockheader.NewImmutableBlockHeader(
+			version,
+			[]externalapi.BlockLevelParents{},
+			merkle.CalculateHashMerkleRoot(transactions),
+			&externalapi.DomainHash{},
+			externalapi.NewDomainHashFromByteArray(muhash.EmptyMuHashHash.AsArray()),
+			timeInMilliseconds,
+			bits,
+			nonce,
+			0,
+			0,
+			big.NewInt(0),
+			&externalapi.DomainHash{},
+		),
+		Transactions: transactions,
+	}
+}
+
+// Validate checks that the params are consistent with each other, and that they
+// can't be confused with the params of one of the public networks
+func (p *Params) Validate() error {
+	for _, publicNetParams := range []*Params{&MainnetParams, &TestnetParams, &SimnetParams, &DevnetParams} {
+		if p.Name == publicNetParams.Name {
+			return errors.Errorf("the network name %s is the name of a public network", p.Name)
+		}
+		if p.Net == publicNetParams.Net {
+			return errors.Errorf("the network magic %d is the magic of %s", uint32(p.Net), publicNetParams.Name)
+		}
+	}
+	if p.Name == "" || strings.ContainsAny(p.Name, `/\.`) {
+		return errors.Errorf("the network name %q must be non-empty and must not contain path characters", p.Name)
+	}
+	if p.RPCPort == "" || p.DefaultPort == "" {
+		return errors.New("the RPC port and the default P2P port must be set")
+	}
+
+	if p.GenesisBlock == nil || p.GenesisHash == nil {
+		return errors.New("the genesis block is missing")
+	}
+	if !consensushashing.BlockHash(p.GenesisBlock).Equal(p.GenesisHash) {
+		return errors.Errorf("the genesis hash %s doesn't match the genesis block", p.GenesisHash)
+	}
+	if p.PowMax == nil || p.PowMax.Sign() <= 0 {
+		return errors.New("powMax must be positive")
+	}
+	genesisTarget := difficulty.CompactToBig(p.GenesisBlock.Header.Bits())
+	if genesisTarget.Sign() <= 0 || genesisTarget.Cmp(p.PowMax) > 0 {
+		return errors.Errorf("the genesis target %s must be positive and no higher than powMax %s",
+			genesisTarget.Text(16), p.PowMax.Text(16))
+	}
+
+	if p.TargetTimePerBlock <= 0 {
+		return errors.New("the target time per block must be positive")
+	}
+	if p.FinalityDuration < p.TargetTimePerBlock {
+		return errors.Errorf("the finality duration %s must be at least the target time per block %s",
+			p.FinalityDuration, p.TargetTimePerBlock)
+	}
+	if p.MergeSetSizeLimit <= uint64(p.K) {
+		return errors.Errorf("the merge set size limit %d must be greater than K %d, since a block "+
+			"can merge up to K blue blocks along with its selected parent", p.MergeSetSizeLimit, p.K)
+	}
+	if p.MergeSetSizeLimit >= p.FinalityDepth() {
+		return errors.Errorf("the merge set size limit %d must be smaller than the finality depth %d",
+			p.MergeSetSizeLimit, p.FinalityDepth())
+	}
+	if p.MergeDepth == 0 || p.MergeDepth > p.FinalityDepth() {
+		return errors.Errorf("the merge depth %d must be positive and no greater than the finality depth %d",
+			p.MergeDepth, p.FinalityDepth())
+	}
+	if p.MaxBlockParents == 0 || uint64(p.MaxBlockParents) > p.MergeSetSizeLimit {
+		return errors.Errorf("the maximum number of block parents %d must be positive and no greater "+
+			"than the merge set size limit %d", p.MaxBlockParents, p.MergeSetSizeLimit)
+	}
+	if p.DifficultyAdjustmentWindowSize <= 0 {
+		return errors.New("the difficulty adjustment window size must be positive")
+	}
+	if p.TimestampDeviationTolerance <= 0 {
+		return errors.New("the timestamp deviation tolerance must be positive")
+	}
+	if p.PruningProofM == 0 {
+		return errors.New("the pruning proof m must be positive")
+	}
+	if p.MaxBlockLevel <= 0 || p.MaxBlockLevel > 255 {
+		return errors.Errorf("the maximum block level %d must be between 1 and 255", p.MaxBlockLevel)
+	}
+	if p.MaxBlockMass == 0 {
+		return errors.New("the maximum block mass must be positive")
+	}
+	for i := 1; i < len(p.Checkpoints); i++ {
+		if p.Checkpoints[i].DAAScore <= p.Checkpoints[i-1].DAAScore {
+			return errors.New("the checkpoints must be ordered by ascending DAA score")
+		}
+	}
+
+	return nil
+}
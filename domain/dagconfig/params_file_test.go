@@ -0,0 +1,159 @@
+package dagconfig
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func validParamsFile() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        "stokes-privnet",
+		"net":         0x5053544B,
+		"rpcPort":     "17710",
+		"defaultPort": "17711",
+		"prefix":      "stokessim",
+		"powMax":      simnetPowMax.Text(16),
+		// The simnet genesis, so its hash is known
+		"genesis": map[string]interface{}{
+			"version":            0,
+			"timeInMilliseconds": 1760309945665,
+			"bits":               545259519,
+			"nonce":              0,
+			"coinbasePayload":    hex.EncodeToString(simnetGenesisTxPayload),
+			"hash":               simnetGenesisHash.String(),
+		},
+		"k":                                18,
+		"mergeSetSizeLimit":                180,
+		"targetTimePerBlockInMilliSeconds": 1000,
+		"finalityDurationInMilliSeconds":   600_000,
+		"mergeDepth":                       300,
+		"halvingIntervalDaaScore":          1000,
+		"checkpoints": []map[string]interface{}{
+			{"daaScore": 0, "hash": simnetGenesisHash.String()},
+		},
+	}
+}
+
+func parseParamsFile(t *testing.T, file map[string]interface{}) (*Params, error) {
+	serializedFile, err := json.Marshal(file)
+	if err != nil {
+		t.Fatalf("Marshal: %+v", err)
+	}
+	return ParseParams(bytes.NewReader(serializedFile))
+}
+
+func TestParseParams(t *testing.T) {
+	params, err := parseParamsFile(t, validParamsFile())
+	if err != nil {
+		t.Fatalf("ParseParams: %+v", err)
+	}
+
+	if !params.GenesisHash.Equal(simnetGenesisHash) {
+		t.Fatalf("Expected the genesis hash to be %s, but got %s", simnetGenesisHash, params.GenesisHash)
+	}
+	if params.Name != "stokes-privnet" || params.Net != appmessage.KaspaNet(0x5053544B) {
+		t.Fatalf("Unexpected network identity %s %s", params.Name, params.Net)
+	}
+	if params.K != 18 || params.TargetTimePerBlock != time.Second || params.FinalityDepth() != 600 ||
+		params.HalvingIntervalDaaScore != 1000 || params.MergeDepth != 300 {
+		t.Fatalf("The params were not loaded as expected: %+v", params)
+	}
+	if len(params.Checkpoints) != 1 || !params.Checkpoints[0].Hash.Equal(simnetGenesisHash) {
+		t.Fatalf("Unexpected checkpoints %+v", params.Checkpoints)
+	}
+	// Omitted params get the defaults of the public networks
+	if params.MaxBlockMass != defaultMaxBlockMass || params.PruningProofM != defaultPruningProofM {
+		t.Fatalf("Expected the omitted params to get their defaults")
+	}
+}
+
+func TestParseParamsInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(file map[string]interface{})
+		expectedError string
+	}{
+		{
+			name:          "public network name",
+			modify:        func(file map[string]interface{}) { file["name"] = TestnetParams.Name },
+			expectedError: "name of a public network",
+		},
+		{
+			name:          "public network magic",
+			modify:        func(file map[string]interface{}) { file["net"] = uint32(appmessage.Mainnet) },
+			expectedError: "magic",
+		},
+		{
+			name:          "path in network name",
+			modify:        func(file map[string]interface{}) { file["name"] = "../stokes-privnet" },
+			expectedError: "path characters",
+		},
+		{
+			name:          "merge set size limit not above K",
+			modify:        func(file map[string]interface{}) { file["mergeSetSizeLimit"] = 18 },
+			expectedError: "greater than K",
+		},
+		{
+			name: "merge set size limit above the finality depth",
+			modify: func(file map[string]interface{}) {
+				file["finalityDurationInMilliSeconds"] = 100_000
+				file["mergeDepth"] = 50
+			},
+			expectedError: "smaller than the finality depth",
+		},
+		{
+			name:          "merge depth above the finality depth",
+			modify:        func(file map[string]interface{}) { file["mergeDepth"] = 1000 },
+			expectedError: "merge depth",
+		},
+		{
+			name: "wrong genesis hash",
+			modify: func(file map[string]interface{}) {
+				file["genesis"].(map[string]interface{})["nonce"] = 1
+			},
+			expectedError: "the genesis block hashes to",
+		},
+		{
+			name: "genesis target above powMax",
+			modify: func(file map[string]interface{}) {
+				file["powMax"] = "1"
+			},
+			expectedError: "genesis target",
+		},
+		{
+			name:          "missing genesis",
+			modify:        func(file map[string]interface{}) { delete(file, "genesis") },
+			expectedError: "genesis block is missing",
+		},
+		{
+			name:          "unknown field",
+			modify:        func(file map[string]interface{}) { file["kk"] = 1 },
+			expectedError: "unknown field",
+		},
+		{
+			name: "unordered checkpoints",
+			modify: func(file map[string]interface{}) {
+				file["checkpoints"] = []map[string]interface{}{
+					{"daaScore": 10, "hash": simnetGenesisHash.String()},
+					{"daaScore": 5, "hash": simnetGenesisHash.String()},
+				}
+			},
+			expectedError: "ascending DAA score",
+		},
+	}
+
+	for _, test := range tests {
+		file := validParamsFile()
+		test.modify(file)
+		_, err := parseParamsFile(t, file)
+		if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Fatalf("%s: expected an error containing %q, but got: %v", test.name, test.expectedError, err)
+		}
+	}
+}
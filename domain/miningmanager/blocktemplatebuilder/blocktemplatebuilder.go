@@ -6,30 +6,16 @@ import (
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionhelper"
 	"github.com/stokesnetwork/stokes/domain/consensusreference"
 	"github.com/stokesnetwork/stokes/util/mstime"
-	"math"
-	"sort"
+	"time"
 
 	"github.com/stokesnetwork/stokes/util/difficulty"
 
 	consensusexternalapi "github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/ruleerrors"
-	"github.com/stokesnetwork/stokes/domain/consensus/utils/subnetworks"
 	miningmanagerapi "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 	"github.com/pkg/errors"
 )
 
-type candidateTx struct {
-	*consensusexternalapi.DomainTransaction
-	txValue  float64
-	gasLimit uint64
-
-	p     float64
-	start float64
-	end   float64
-
-	isMarkedForDeletion bool
-}
-
 // blockTemplateBuilder creates block templates for a miner to consume
 type blockTemplateBuilder struct {
 	consensusReference consensusreference.ConsensusReference
@@ -41,11 +27,16 @@ type blockTemplateBuilder struct {
 
 // New creates a new blockTemplateBuilder
 func New(consensusReference consensusreference.ConsensusReference, mempool miningmanagerapi.Mempool,
-	blockMaxMass uint64, coinbasePayloadScriptPublicKeyMaxLength uint8) miningmanagerapi.BlockTemplateBuilder {
+	blockMaxMass uint64, targetTimePerBlock time.Duration, minimumFeeRate float64,
+	coinbasePayloadScriptPublicKeyMaxLength uint8) miningmanagerapi.BlockTemplateBuilder {
 	return &blockTemplateBuilder{
 		consensusReference: consensusReference,
 		mempool:            mempool,
-		policy:             policy{BlockMaxMass: blockMaxMass},
+		policy: policy{
+			BlockMaxMass:       blockMaxMass,
+			TargetTimePerBlock: targetTimePerBlock,
+			MinimumFeeRate:     minimumFeeRate,
+		},
 
 		coinbasePayloadScriptPublicKeyMaxLength: coinbasePayloadScriptPublicKeyMaxLength,
 	}
@@ -118,30 +109,7 @@ func New(consensusReference consensusreference.ConsensusReference, mempool minin
 func (btb *blockTemplateBuilder) BuildBlockTemplate(
 	coinbaseData *consensusexternalapi.DomainCoinbaseData) (*consensusexternalapi.DomainBlockTemplate, error) {
 
-	mempoolTransactions := btb.mempool.BlockCandidateTransactions()
-	candidateTxs := make([]*candidateTx, 0, len(mempoolTransactions))
-	for _, tx := range mempoolTransactions {
-		// Calculate the tx value
-		gasLimit := uint64(0)
-		if !subnetworks.IsBuiltInOrNative(tx.SubnetworkID) {
-			panic("We currently don't support non native subnetworks")
-		}
-		candidateTxs = append(candidateTxs, &candidateTx{
-			DomainTransaction: tx,
-			txValue:           btb.calcTxValue(tx),
-			gasLimit:          gasLimit,
-		})
-	}
-
-	// Sort the candidate txs by subnetworkID.
-	sort.Slice(candidateTxs, func(i, j int) bool {
-		return subnetworks.Less(candidateTxs[i].SubnetworkID, candidateTxs[j].SubnetworkID)
-	})
-
-	log.Debugf("Considering %d transactions for inclusion to new block",
-		len(candidateTxs))
-
-	blockTxs := btb.selectTransactions(candidateTxs)
+	blockTxs := btb.selectTransactions()
 	blockTemplate, err := btb.consensusReference.Consensus().BuildBlockTemplate(coinbaseData, blockTxs.selectedTxs)
 
 	invalidTxsErr := ruleerrors.ErrInvalidTransactionsInNewBlock{}
@@ -200,19 +168,3 @@ func (btb *blockTemplateBuilder) ModifyBlockTemplate(newCoinbaseData *consensuse
 
 	return blockTemplateToModify, nil
 }
-
-// calcTxValue calculates a value to be used in transaction selection.
-// The higher the number the more likely it is that the transaction will be
-// included in the block.
-func (btb *blockTemplateBuilder) calcTxValue(tx *consensusexternalapi.DomainTransaction) float64 {
-	massLimit := btb.policy.BlockMaxMass
-
-	mass := tx.Mass
-	fee := tx.Fee
-	if subnetworks.IsBuiltInOrNative(tx.SubnetworkID) {
-		return float64(fee) / (float64(mass) / float64(massLimit))
-	}
-	// TODO: Replace with real gas once implemented
-	gasLimit := uint64(math.MaxUint64)
-	return float64(fee) / (float64(mass)/float64(massLimit) + float64(tx.Gas)/float64(gasLimit))
-}
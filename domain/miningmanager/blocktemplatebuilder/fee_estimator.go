@@ -0,0 +1,135 @@
+package blocktemplatebuilder
+
+import (
+	"math"
+	"time"
+
+	consensusexternalapi "github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	miningmanagerapi "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+const (
+	// priorityTargetBlocks is the number of blocks within which a transaction
+	// paying the priority fee rate is expected to be included
+	priorityTargetBlocks = 2
+
+	normalTargetSeconds          = 60
+	normalSecondaryTargetSeconds = 600
+	lowTargetSeconds             = 3600
+)
+
+// feeRateEstimator models the inclusion time of a transaction with a given fee
+// rate according to the way selectTransactions picks transactions.
+//
+// Every draw in selectTransactions picks a candidate with probability
+// proportional to feeRate^alpha. Hence, given the total weight W of all
+// current candidates, a new transaction with fee rate f is expected to be
+// selected after W / f^alpha draws. Each draw consumes the block space of an
+// average candidate transaction, which the network processes in
+// inclusionInterval seconds, so the expected time until inclusion is:
+//
+//	blockInterval + inclusionInterval * W / f^alpha
+//
+// When all candidates fit into a single block there is no competition, W is
+// zero and any transaction paying the minimum fee rate is expected to be
+// included in the next block.
+type feeRateEstimator struct {
+	blockInterval     float64
+	inclusionInterval float64
+	totalWeight       float64
+	minimumFeeRate    float64
+}
+
+func newFeeRateEstimator(candidateTxs []*consensusexternalapi.DomainTransaction, blockMaxMass uint64,
+	targetTimePerBlock time.Duration, minimumFeeRate float64) *feeRateEstimator {
+
+	summary := &miningmanagerapi.BlockCandidatesSummary{}
+	for _, tx := range candidateTxs {
+		if tx.Mass == 0 {
+			continue
+		}
+		summary.TotalMass += tx.Mass
+		summary.TotalWeight += math.Pow(float64(tx.Fee)/float64(tx.Mass), alpha)
+		summary.Count++
+	}
+
+	return newFeeRateEstimatorFromSummary(summary, blockMaxMass, targetTimePerBlock, minimumFeeRate)
+}
+
+func newFeeRateEstimatorFromSummary(summary *miningmanagerapi.BlockCandidatesSummary, blockMaxMass uint64,
+	targetTimePerBlock time.Duration, minimumFeeRate float64) *feeRateEstimator {
+
+	estimator := &feeRateEstimator{
+		blockInterval:  targetTimePerBlock.Seconds(),
+		minimumFeeRate: minimumFeeRate,
+	}
+	if summary.Count == 0 || summary.TotalMass <= blockMaxMass {
+		return estimator
+	}
+
+	averageMass := float64(summary.TotalMass) / float64(summary.Count)
+	massPerSecond := float64(blockMaxMass) / estimator.blockInterval
+	estimator.inclusionInterval = averageMass / massPerSecond
+	estimator.totalWeight = summary.TotalWeight
+
+	return estimator
+}
+
+// feeRateToTime returns the expected number of seconds until a transaction
+// paying the given fee rate is included in a block
+func (fre *feeRateEstimator) feeRateToTime(feeRate float64) float64 {
+	if fre.totalWeight == 0 {
+		return fre.blockInterval
+	}
+	return fre.blockInterval + fre.inclusionInterval*fre.totalWeight/math.Pow(feeRate, alpha)
+}
+
+// timeToFeeRate is the inverse of feeRateToTime. It returns the fee rate
+// required for a transaction to be included within the given number of
+// seconds, but never less than the minimum fee rate
+func (fre *feeRateEstimator) timeToFeeRate(seconds float64) float64 {
+	if fre.totalWeight == 0 {
+		return fre.minimumFeeRate
+	}
+	if seconds <= fre.blockInterval {
+		return math.Inf(1)
+	}
+	feeRate := math.Pow(fre.inclusionInterval*fre.totalWeight/(seconds-fre.blockInterval), 1.0/alpha)
+	return math.Max(feeRate, fre.minimumFeeRate)
+}
+
+func (fre *feeRateEstimator) bucket(feeRate float64) miningmanagerapi.FeeRateBucket {
+	return miningmanagerapi.FeeRateBucket{
+		FeeRate:          feeRate,
+		EstimatedSeconds: fre.feeRateToTime(feeRate),
+	}
+}
+
+func (fre *feeRateEstimator) estimations() *miningmanagerapi.FeeRateEstimations {
+	priorityFeeRate := fre.timeToFeeRate(priorityTargetBlocks * fre.blockInterval)
+	// Make sure the targets are always ordered from the fastest to the slowest,
+	// even for networks with very long block intervals
+	normalFeeRate := math.Min(fre.timeToFeeRate(normalTargetSeconds), priorityFeeRate)
+	normalSecondaryFeeRate := math.Min(fre.timeToFeeRate(normalSecondaryTargetSeconds), normalFeeRate)
+	lowFeeRate := math.Min(fre.timeToFeeRate(lowTargetSeconds), normalSecondaryFeeRate)
+
+	return &miningmanagerapi.FeeRateEstimations{
+		PriorityBucket: fre.bucket(priorityFeeRate),
+		NormalBuckets: []miningmanagerapi.FeeRateBucket{
+			fre.bucket(normalFeeRate),
+			fre.bucket(normalSecondaryFeeRate),
+		},
+		LowBuckets: []miningmanagerapi.FeeRateBucket{
+			fre.bucket(lowFeeRate),
+		},
+	}
+}
+
+// EstimateFeeRates returns fee rate recommendations based on the current
+// block candidate transactions in the mempool
+func (btb *blockTemplateBuilder) EstimateFeeRates() *miningmanagerapi.FeeRateEstimations {
+	estimator := newFeeRateEstimatorFromSummary(btb.mempool.BlockCandidatesSummary(), btb.policy.BlockMaxMass,
+		btb.policy.TargetTimePerBlock, btb.policy.MinimumFeeRate)
+
+	return estimator.estimations()
+}
@@ -0,0 +1,150 @@
+package blocktemplatebuilder
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	consensusexternalapi "github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	miningmanagerapi "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+const (
+	testBlockMaxMass       = 500_000
+	testTargetTimePerBlock = time.Second
+	testMinimumFeeRate     = 1.0
+)
+
+// syntheticMempool returns count candidate transactions of the given mass,
+// with fee rates spread uniformly between minFeeRate and maxFeeRate
+func syntheticMempool(count int, mass uint64, minFeeRate, maxFeeRate float64) []*consensusexternalapi.DomainTransaction {
+	txs := make([]*consensusexternalapi.DomainTransaction, count)
+	for i := range txs {
+		feeRate := minFeeRate
+		if count > 1 {
+			feeRate += (maxFeeRate - minFeeRate) * float64(i) / float64(count-1)
+		}
+		txs[i] = &consensusexternalapi.DomainTransaction{
+			Mass: mass,
+			Fee:  uint64(feeRate * float64(mass)),
+		}
+	}
+	return txs
+}
+
+func allBuckets(estimations *miningmanagerapi.FeeRateEstimations) []miningmanagerapi.FeeRateBucket {
+	buckets := []miningmanagerapi.FeeRateBucket{estimations.PriorityBucket}
+	buckets = append(buckets, estimations.NormalBuckets...)
+	return append(buckets, estimations.LowBuckets...)
+}
+
+func TestFeeRateEstimatorNoCompetition(t *testing.T) {
+	tests := []struct {
+		name string
+		txs  []*consensusexternalapi.DomainTransaction
+	}{
+		{
+			name: "empty mempool",
+			txs:  nil,
+		},
+		{
+			name: "mempool fits into a single block",
+			txs:  syntheticMempool(100, 2000, 1, 100),
+		},
+	}
+
+	for _, test := range tests {
+		estimations := newFeeRateEstimator(test.txs, testBlockMaxMass, testTargetTimePerBlock,
+			testMinimumFeeRate).estimations()
+
+		if len(estimations.NormalBuckets) == 0 || len(estimations.LowBuckets) == 0 {
+			t.Fatalf("%s: expected at least one normal and one low bucket", test.name)
+		}
+		for _, bucket := range allBuckets(estimations) {
+			if bucket.FeeRate != testMinimumFeeRate {
+				t.Errorf("%s: expected fee rate %f, got %f", test.name, testMinimumFeeRate, bucket.FeeRate)
+			}
+			if bucket.EstimatedSeconds != testTargetTimePerBlock.Seconds() {
+				t.Errorf("%s: expected estimated seconds %f, got %f",
+					test.name, testTargetTimePerBlock.Seconds(), bucket.EstimatedSeconds)
+			}
+		}
+	}
+}
+
+func TestFeeRateEstimatorCongestedMempool(t *testing.T) {
+	// 100,000 transactions of 2000 grams each are worth 400 full blocks
+	txs := syntheticMempool(100_000, 2000, 1, 100)
+	estimator := newFeeRateEstimator(txs, testBlockMaxMass, testTargetTimePerBlock, testMinimumFeeRate)
+	estimations := estimator.estimations()
+
+	buckets := allBuckets(estimations)
+	for i, bucket := range buckets {
+		if bucket.FeeRate < testMinimumFeeRate {
+			t.Errorf("bucket %d: fee rate %f is below the minimum fee rate", i, bucket.FeeRate)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := buckets[i-1]
+		if bucket.FeeRate > previous.FeeRate {
+			t.Errorf("bucket %d: fee rate %f is higher than that of the previous bucket (%f)",
+				i, bucket.FeeRate, previous.FeeRate)
+		}
+		if bucket.EstimatedSeconds < previous.EstimatedSeconds {
+			t.Errorf("bucket %d: estimated seconds %f are lower than those of the previous bucket (%f)",
+				i, bucket.EstimatedSeconds, previous.EstimatedSeconds)
+		}
+	}
+
+	expectedPrioritySeconds := priorityTargetBlocks * testTargetTimePerBlock.Seconds()
+	if math.Abs(estimations.PriorityBucket.EstimatedSeconds-expectedPrioritySeconds) > 1e-6 {
+		t.Errorf("expected the priority bucket to be estimated at %f seconds, got %f",
+			expectedPrioritySeconds, estimations.PriorityBucket.EstimatedSeconds)
+	}
+	if estimations.NormalBuckets[0].EstimatedSeconds > normalTargetSeconds+1e-6 {
+		t.Errorf("expected the first normal bucket to be estimated at no more than %d seconds, got %f",
+			normalTargetSeconds, estimations.NormalBuckets[0].EstimatedSeconds)
+	}
+
+	// A transaction paying the highest fee rate in the mempool should be
+	// estimated to be included before the whole mempool (400 blocks) drains
+	drainSeconds := 400 * testTargetTimePerBlock.Seconds()
+	if estimator.feeRateToTime(100) >= drainSeconds {
+		t.Errorf("expected the highest fee rate to be included within %f seconds, got %f",
+			drainSeconds, estimator.feeRateToTime(100))
+	}
+}
+
+func TestFeeRateEstimatorRoundTrip(t *testing.T) {
+	txs := syntheticMempool(10_000, 5000, 1, 1000)
+	estimator := newFeeRateEstimator(txs, testBlockMaxMass, testTargetTimePerBlock, 0)
+
+	for _, seconds := range []float64{1.5, 2, 10, 60, 3600} {
+		feeRate := estimator.timeToFeeRate(seconds)
+		roundTrip := estimator.feeRateToTime(feeRate)
+		if math.Abs(roundTrip-seconds)/seconds > 1e-9 {
+			t.Errorf("timeToFeeRate(%f) = %f, but feeRateToTime(%f) = %f", seconds, feeRate, feeRate, roundTrip)
+		}
+	}
+
+	if !math.IsInf(estimator.timeToFeeRate(testTargetTimePerBlock.Seconds()), 1) {
+		t.Errorf("expected no fee rate to guarantee inclusion faster than a single block")
+	}
+}
+
+func TestFeeRateEstimatorGrowsWithLoad(t *testing.T) {
+	lightLoad := newFeeRateEstimator(syntheticMempool(1_000, 2000, 1, 100),
+		testBlockMaxMass, testTargetTimePerBlock, testMinimumFeeRate).estimations()
+	heavyLoad := newFeeRateEstimator(syntheticMempool(100_000, 2000, 1, 100),
+		testBlockMaxMass, testTargetTimePerBlock, testMinimumFeeRate).estimations()
+
+	if heavyLoad.PriorityBucket.FeeRate <= lightLoad.PriorityBucket.FeeRate {
+		t.Errorf("expected priority fee rate under heavy load (%f) to be higher than under light load (%f)",
+			heavyLoad.PriorityBucket.FeeRate, lightLoad.PriorityBucket.FeeRate)
+	}
+	if heavyLoad.NormalBuckets[0].FeeRate <= lightLoad.NormalBuckets[0].FeeRate {
+		t.Errorf("expected normal fee rate under heavy load (%f) to be higher than under light load (%f)",
+			heavyLoad.NormalBuckets[0].FeeRate, lightLoad.NormalBuckets[0].FeeRate)
+	}
+}
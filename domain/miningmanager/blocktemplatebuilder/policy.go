@@ -4,6 +4,8 @@
 
 package blocktemplatebuilder
 
+import "time"
+
 // policy houses the policy (configuration parameters) which is used to control
 // the generation of block templates. See the documentation for
 // NewBlockTemplate for more details on each of these parameters are used.
@@ -11,4 +13,12 @@ type policy struct {
 	// BlockMaxMass is the maximum block mass to be used when generating a
 	// block template.
 	BlockMaxMass uint64
+
+	// TargetTimePerBlock is the expected time between blocks. It is used
+	// when estimating the time until a transaction is included in a block.
+	TargetTimePerBlock time.Duration
+
+	// MinimumFeeRate is the minimum fee rate, in sompi per gram, required for
+	// a transaction to be accepted to the mempool.
+	MinimumFeeRate float64
 }
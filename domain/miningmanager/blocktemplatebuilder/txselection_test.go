@@ -0,0 +1,218 @@
+package blocktemplatebuilder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	consensusexternalapi "github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/subnetworks"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagerapi "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+const benchmarkBlockMaxMass = 500_000
+
+// frontierMempool is a mempool that holds its block candidates in a model.Frontier, like the real mempool does
+type frontierMempool struct {
+	miningmanagerapi.Mempool
+	frontier *model.Frontier
+}
+
+func (fm *frontierMempool) SampleBlockCandidateTransactions(blockMaxMass uint64) []*consensusexternalapi.DomainTransaction {
+	sample := fm.frontier.Sample(blockMaxMass)
+	result := make([]*consensusexternalapi.DomainTransaction, len(sample))
+	for i, transaction := range sample {
+		result[i] = transaction.Transaction().Clone()
+	}
+	return result
+}
+
+func benchmarkMempoolTransactions(count int) map[consensusexternalapi.DomainTransactionID]*model.MempoolTransaction {
+	random := rand.New(rand.NewSource(0))
+	transactions := make(map[consensusexternalapi.DomainTransactionID]*model.MempoolTransaction, count)
+	for i := 0; i < count; i++ {
+		var id [consensusexternalapi.DomainHashSize]byte
+		binary.LittleEndian.PutUint64(id[:], uint64(i))
+		transactionID := consensusexternalapi.NewDomainTransactionIDFromByteArray(&id)
+		mass := uint64(1000 + random.Intn(2000))
+		scriptPublicKey := &consensusexternalapi.ScriptPublicKey{Script: make([]byte, 34), Version: 0}
+		transaction := &consensusexternalapi.DomainTransaction{
+			Inputs: []*consensusexternalapi.DomainTransactionInput{{
+				PreviousOutpoint: consensusexternalapi.DomainOutpoint{TransactionID: *transactionID, Index: 0},
+				SignatureScript:  make([]byte, 66),
+			}},
+			Outputs: []*consensusexternalapi.DomainTransactionOutput{
+				{Value: 1, ScriptPublicKey: scriptPublicKey},
+				{Value: 2, ScriptPublicKey: scriptPublicKey},
+			},
+			SubnetworkID: subnetworks.SubnetworkIDNative,
+			Mass:         mass,
+			Fee:          mass * uint64(1+random.Intn(100)),
+			ID:           transactionID,
+		}
+		transactions[*transactionID] = model.NewMempoolTransaction(transaction, model.IDToTransactionMap{}, false, 0)
+	}
+	return transactions
+}
+
+// BenchmarkSelectTransactions compares selecting the transactions of a block template from
+// the frontier to the selection that preceded it, which went over the whole mempool to
+// collect the candidates and then drew from them with rebalancing.
+func BenchmarkSelectTransactions(b *testing.B) {
+	for _, count := range []int{10_000, 100_000, 1_000_000} {
+		transactions := benchmarkMempoolTransactions(count)
+
+		b.Run(fmt.Sprintf("rescan/%d", count), func(b *testing.B) {
+			btb := &blockTemplateBuilder{policy: policy{BlockMaxMass: benchmarkBlockMaxMass}}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				legacySelectTransactions(btb, transactions)
+			}
+		})
+
+		frontier := model.NewFrontier(alpha)
+		for _, transaction := range transactions {
+			frontier.Push(transaction)
+		}
+		b.Run(fmt.Sprintf("frontier/%d", count), func(b *testing.B) {
+			btb := &blockTemplateBuilder{
+				mempool: &frontierMempool{frontier: frontier},
+				policy:  policy{BlockMaxMass: benchmarkBlockMaxMass},
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				btb.selectTransactions()
+			}
+		})
+	}
+}
+
+func TestSelectTransactionsFillsBlock(t *testing.T) {
+	frontier := model.NewFrontier(alpha)
+	for _, transaction := range benchmarkMempoolTransactions(10_000) {
+		frontier.Push(transaction)
+	}
+	btb := &blockTemplateBuilder{
+		mempool: &frontierMempool{frontier: frontier},
+		policy:  policy{BlockMaxMass: benchmarkBlockMaxMass},
+	}
+
+	selected := btb.selectTransactions()
+	if selected.totalMass > benchmarkBlockMaxMass {
+		t.Fatalf("The selected transactions have a mass of %d, which is more than the block max mass %d",
+			selected.totalMass, benchmarkBlockMaxMass)
+	}
+	// Selection stops at the first transaction that doesn't fit, and no transaction is heavier than 3000
+	if selected.totalMass <= benchmarkBlockMaxMass-3000 {
+		t.Fatalf("Expected the selected transactions to fill the block, but they only have a mass of %d",
+			selected.totalMass)
+	}
+	if len(selected.selectedTxs) != len(selected.txMasses) || len(selected.selectedTxs) != len(selected.txFees) {
+		t.Fatalf("Expected a mass and a fee for every selected transaction")
+	}
+}
+
+// legacyCandidateTx, legacySelectTransactions and the functions below are the transaction
+// selection from before the frontier, kept for BenchmarkSelectTransactions
+type legacyCandidateTx struct {
+	*consensusexternalapi.DomainTransaction
+	txValue float64
+
+	p     float64
+	start float64
+	end   float64
+
+	isMarkedForDeletion bool
+}
+
+const legacyRebalanceThreshold = 0.95
+
+func legacySelectTransactions(btb *blockTemplateBuilder,
+	transactions map[consensusexternalapi.DomainTransactionID]*model.MempoolTransaction) []*consensusexternalapi.DomainTransaction {
+
+	candidateTxs := make([]*legacyCandidateTx, 0, len(transactions))
+	for _, transaction := range transactions {
+		if len(transaction.ParentTransactionsInPool()) != 0 {
+			continue
+		}
+		tx := transaction.Transaction().Clone()
+		candidateTxs = append(candidateTxs, &legacyCandidateTx{
+			DomainTransaction: tx,
+			txValue:           float64(tx.Fee) / (float64(tx.Mass) / float64(btb.policy.BlockMaxMass)),
+		})
+	}
+	sort.Slice(candidateTxs, func(i, j int) bool {
+		return subnetworks.Less(candidateTxs[i].SubnetworkID, candidateTxs[j].SubnetworkID)
+	})
+
+	usedCount, usedP := 0, 0.0
+	candidateTxs, totalP := legacyRebalanceCandidates(candidateTxs, true)
+	totalMass := uint64(0)
+	selectedTxs := make([]*consensusexternalapi.DomainTransaction, 0)
+	for len(candidateTxs)-usedCount > 0 {
+		if usedP >= legacyRebalanceThreshold*totalP {
+			candidateTxs, totalP = legacyRebalanceCandidates(candidateTxs, false)
+			usedCount, usedP = 0, 0.0
+			if len(candidateTxs) == 0 {
+				break
+			}
+		}
+
+		selectedTx := legacyFindTx(candidateTxs, rand.Float64()*totalP)
+		if selectedTx.isMarkedForDeletion {
+			continue
+		}
+		if totalMass+selectedTx.Mass > btb.policy.BlockMaxMass {
+			break
+		}
+		selectedTxs = append(selectedTxs, selectedTx.DomainTransaction)
+		totalMass += selectedTx.Mass
+		selectedTx.isMarkedForDeletion = true
+		usedCount++
+		usedP += selectedTx.p
+	}
+	return selectedTxs
+}
+
+func legacyRebalanceCandidates(oldCandidateTxs []*legacyCandidateTx, isFirstRun bool) (
+	candidateTxs []*legacyCandidateTx, totalP float64) {
+
+	candidateTxs = make([]*legacyCandidateTx, 0, len(oldCandidateTxs))
+	for _, candidateTx := range oldCandidateTxs {
+		if candidateTx.isMarkedForDeletion {
+			continue
+		}
+		candidateTxs = append(candidateTxs, candidateTx)
+	}
+
+	for _, candidateTx := range candidateTxs {
+		if isFirstRun {
+			candidateTx.p = math.Pow(candidateTx.txValue, alpha)
+		}
+		candidateTx.start = totalP
+		candidateTx.end = totalP + candidateTx.p
+		totalP += candidateTx.p
+	}
+	return candidateTxs, totalP
+}
+
+func legacyFindTx(candidateTxs []*legacyCandidateTx, r float64) *legacyCandidateTx {
+	min := 0
+	max := len(candidateTxs) - 1
+	for {
+		i := (min + max) / 2
+		candidateTx := candidateTxs[i]
+		if candidateTx.end < r {
+			min = i + 1
+			continue
+		} else if candidateTx.start > r {
+			max = i - 1
+			continue
+		}
+		return candidateTx
+	}
+}
@@ -0,0 +1,82 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// BlockCandidatePolicy decides how block templates treat the transactions that have no parents
+// in the mempool
+type BlockCandidatePolicy interface {
+	// Classify returns the kind of block candidate the given transaction is, along with a
+	// human-readable reason if it isn't a regular one. The UTXO entries of the inputs of the
+	// transaction are populated, and so is its fee.
+	Classify(transaction *externalapi.DomainTransaction) (kind miningmanagermodel.BlockCandidateKind, reason string)
+}
+
+const (
+	defaultSpamFeePerExtraOutput         = constants.SompiPerKaspa
+	defaultMaximumUnpaidSpamExtraOutputs = 2
+)
+
+// ExtraOutputsPolicy is the default BlockCandidatePolicy. It holds back transactions that create
+// more outputs than they spend without paying for the extra outputs, since they grow the UTXO set
+// for free:
+// 1. A transaction that pays more than FeePerExtraOutput per extra output is a regular block
+// candidate, and so is one that spends a coinbase output if ExemptCoinbaseSpends is set
+// 2. Otherwise, a transaction with more than MaximumUnpaidExtraOutputs extra outputs that pays
+// less than that is filtered
+// 3. Any other transaction with extra outputs is spam
+//
+// A FeePerExtraOutput of 0 makes every transaction a regular block candidate.
+type ExtraOutputsPolicy struct {
+	FeePerExtraOutput         uint64
+	MaximumUnpaidExtraOutputs int
+	ExemptCoinbaseSpends      bool
+}
+
+// DefaultBlockCandidatePolicy returns the default BlockCandidatePolicy
+func DefaultBlockCandidatePolicy() *ExtraOutputsPolicy {
+	return &ExtraOutputsPolicy{
+		FeePerExtraOutput:         defaultSpamFeePerExtraOutput,
+		MaximumUnpaidExtraOutputs: defaultMaximumUnpaidSpamExtraOutputs,
+		ExemptCoinbaseSpends:      true,
+	}
+}
+
+// Classify implements BlockCandidatePolicy
+func (policy *ExtraOutputsPolicy) Classify(transaction *externalapi.DomainTransaction) (
+	kind miningmanagermodel.BlockCandidateKind, reason string) {
+
+	if policy.FeePerExtraOutput == 0 || len(transaction.Outputs) <= len(transaction.Inputs) {
+		return miningmanagermodel.BlockCandidateRegular, ""
+	}
+
+	if policy.ExemptCoinbaseSpends {
+		for _, input := range transaction.Inputs {
+			if input.UTXOEntry.IsCoinbase() {
+				return miningmanagermodel.BlockCandidateRegular, ""
+			}
+		}
+	}
+
+	numExtraOutputs := len(transaction.Outputs) - len(transaction.Inputs)
+	requiredFee := uint64(numExtraOutputs) * policy.FeePerExtraOutput
+	if transaction.Fee > requiredFee {
+		return miningmanagermodel.BlockCandidateRegular, ""
+	}
+
+	if numExtraOutputs > policy.MaximumUnpaidExtraOutputs && transaction.Fee < requiredFee {
+		return miningmanagermodel.BlockCandidateFiltered, fmt.Sprintf("the transaction creates %d more outputs "+
+			"than it spends, more than the %d allowed without paying for them, and its fee of %d sompi is less "+
+			"than the %d sompi they require", numExtraOutputs, policy.MaximumUnpaidExtraOutputs, transaction.Fee,
+			requiredFee)
+	}
+
+	return miningmanagermodel.BlockCandidateSpam, fmt.Sprintf("the transaction creates %d more outputs than it "+
+		"spends, and its fee of %d sompi isn't more than the %d sompi they require", numExtraOutputs,
+		transaction.Fee, requiredFee)
+}
@@ -0,0 +1,110 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/utxo"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+func TestExtraOutputsPolicy(t *testing.T) {
+	createTransaction := func(numInputs int, numOutputs int, spendsCoinbase bool, fee uint64) *externalapi.DomainTransaction {
+		scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{0x51}, Version: 0}
+		transaction := &externalapi.DomainTransaction{Fee: fee}
+		for i := 0; i < numInputs; i++ {
+			transaction.Inputs = append(transaction.Inputs, &externalapi.DomainTransactionInput{
+				UTXOEntry: utxo.NewUTXOEntry(100*constants.SompiPerKaspa, scriptPublicKey, spendsCoinbase && i == 0, 0),
+			})
+		}
+		for i := 0; i < numOutputs; i++ {
+			transaction.Outputs = append(transaction.Outputs, &externalapi.DomainTransactionOutput{
+				Value:           constants.SompiPerKaspa,
+				ScriptPublicKey: scriptPublicKey,
+			})
+		}
+		return transaction
+	}
+
+	defaultPolicy := DefaultBlockCandidatePolicy()
+	noCoinbaseExemptionPolicy := DefaultBlockCandidatePolicy()
+	noCoinbaseExemptionPolicy.ExemptCoinbaseSpends = false
+	disabledPolicy := DefaultBlockCandidatePolicy()
+	disabledPolicy.FeePerExtraOutput = 0
+	lowFeePolicy := DefaultBlockCandidatePolicy()
+	lowFeePolicy.FeePerExtraOutput = 1000
+
+	tests := []struct {
+		name         string
+		policy       *ExtraOutputsPolicy
+		transaction  *externalapi.DomainTransaction
+		expectedKind miningmanagermodel.BlockCandidateKind
+	}{
+		{
+			name:         "no extra outputs",
+			policy:       defaultPolicy,
+			transaction:  createTransaction(2, 2, false, 0),
+			expectedKind: miningmanagermodel.BlockCandidateRegular,
+		},
+		{
+			name:         "extra outputs paid for",
+			policy:       defaultPolicy,
+			transaction:  createTransaction(1, 4, false, 3*constants.SompiPerKaspa+1),
+			expectedKind: miningmanagermodel.BlockCandidateRegular,
+		},
+		{
+			name:         "extra outputs spending a coinbase output",
+			policy:       defaultPolicy,
+			transaction:  createTransaction(1, 4, true, 0),
+			expectedKind: miningmanagermodel.BlockCandidateRegular,
+		},
+		{
+			name:         "extra outputs spending a coinbase output without the exemption",
+			policy:       noCoinbaseExemptionPolicy,
+			transaction:  createTransaction(1, 4, true, 0),
+			expectedKind: miningmanagermodel.BlockCandidateFiltered,
+		},
+		{
+			name:         "few unpaid extra outputs",
+			policy:       defaultPolicy,
+			transaction:  createTransaction(1, 3, false, constants.SompiPerKaspa),
+			expectedKind: miningmanagermodel.BlockCandidateSpam,
+		},
+		{
+			name:         "many unpaid extra outputs",
+			policy:       defaultPolicy,
+			transaction:  createTransaction(1, 4, false, 3*constants.SompiPerKaspa-1),
+			expectedKind: miningmanagermodel.BlockCandidateFiltered,
+		},
+		{
+			name:         "many extra outputs paying exactly the required fee",
+			policy:       defaultPolicy,
+			transaction:  createTransaction(1, 4, false, 3*constants.SompiPerKaspa),
+			expectedKind: miningmanagermodel.BlockCandidateSpam,
+		},
+		{
+			name:         "many extra outputs paid for at a lower fee per extra output",
+			policy:       lowFeePolicy,
+			transaction:  createTransaction(1, 101, false, 100_001),
+			expectedKind: miningmanagermodel.BlockCandidateRegular,
+		},
+		{
+			name:         "many unpaid extra outputs with the policy disabled",
+			policy:       disabledPolicy,
+			transaction:  createTransaction(1, 101, false, 0),
+			expectedKind: miningmanagermodel.BlockCandidateRegular,
+		},
+	}
+
+	for _, test := range tests {
+		kind, reason := test.policy.Classify(test.transaction)
+		if kind != test.expectedKind {
+			t.Errorf("%s: expected %s, but got %s", test.name, test.expectedKind, kind)
+		}
+		if (kind == miningmanagermodel.BlockCandidateRegular) != (reason == "") {
+			t.Errorf("%s: expected a reason only for non-regular transactions, but got %s with reason %q",
+				test.name, kind, reason)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// blockCandidateKindOf classifies a transaction that has no parents in the mempool according to
+// the configured BlockCandidatePolicy. Prioritised transactions are always regular block candidates.
+func (mp *mempool) blockCandidateKindOf(transaction *model.MempoolTransaction) (
+	kind miningmanagermodel.BlockCandidateKind, reason string) {
+
+	if mp.config.BlockCandidatePolicy == nil || transaction.IsPrioritised() {
+		return miningmanagermodel.BlockCandidateRegular, ""
+	}
+	return mp.config.BlockCandidatePolicy.Classify(transaction.Transaction())
+}
+
+func newestUTXODAAScore(transaction *externalapi.DomainTransaction) uint64 {
+	newest := transaction.Inputs[0].UTXOEntry.BlockDAAScore()
+	for _, input := range transaction.Inputs {
+		if input.UTXOEntry.BlockDAAScore() > newest {
+			newest = input.UTXOEntry.BlockDAAScore()
+		}
+	}
+	return newest
+}
+
+// addBlockCandidate is called for every transaction in the pool once it has no parents in the
+// mempool, and keeps the block candidates up to date incrementally
+func (tp *transactionsPool) addBlockCandidate(transaction *model.MempoolTransaction) error {
+	kind, reason := tp.mempool.blockCandidateKindOf(transaction)
+	switch kind {
+	case miningmanagermodel.BlockCandidateRegular:
+		err := tp.updatePackage(transaction)
+		if err != nil {
+			return err
+		}
+		tp.blockCandidates.Push(transaction)
+	case miningmanagermodel.BlockCandidateSpam:
+		tp.spamBlockCandidates[*transaction.TransactionID()] = transaction
+	case miningmanagermodel.BlockCandidateFiltered:
+		log.Debugf("Filtered spam tx %s: %s", transaction.TransactionID(), reason)
+	}
+	return nil
+}
+
+func (tp *transactionsPool) removeBlockCandidate(transaction *model.MempoolTransaction) {
+	tp.blockCandidates.Remove(transaction.TransactionID())
+	delete(tp.spamBlockCandidates, *transaction.TransactionID())
+}
+
+// spamBlockCandidate returns the spam transaction that spends the oldest UTXOs, or nil if
+// there are none
+func (tp *transactionsPool) spamBlockCandidate() *model.MempoolTransaction {
+	var spamTransaction *model.MempoolTransaction
+	var spamTransactionNewestUTXODAAScore uint64
+	for _, transaction := range tp.spamBlockCandidates {
+		transactionNewestUTXODAAScore := newestUTXODAAScore(transaction.Transaction())
+		if spamTransaction == nil || transactionNewestUTXODAAScore < spamTransactionNewestUTXODAAScore {
+			spamTransaction = transaction
+			spamTransactionNewestUTXODAAScore = transactionNewestUTXODAAScore
+		}
+	}
+	return spamTransaction
+}
+
+func (tp *transactionsPool) allBlockCandidates() []*externalapi.DomainTransaction {
+	result := make([]*externalapi.DomainTransaction, 0, tp.blockCandidates.Len()+1)
+	tp.blockCandidates.ForEach(func(transaction *model.MempoolTransaction) bool {
+		result = append(result, transaction.Transaction().Clone()) //this pointer leaves the mempool, hence we clone.
+		return true
+	})
+
+	spamTransaction := tp.spamBlockCandidate()
+	if spamTransaction != nil {
+		log.Debugf("Adding spam tx candidate %s", spamTransaction.TransactionID())
+		result = append(result, spamTransaction.Transaction().Clone())
+	}
+	return result
+}
+
+// sampleBlockCandidates draws the transactions of a block template from the block candidates.
+// The spam transaction, if any, competes with the regular candidates for the duration of the
+// draw only.
+func (tp *transactionsPool) sampleBlockCandidates(blockMaxMass uint64) []*externalapi.DomainTransaction {
+	spamTransaction := tp.spamBlockCandidate()
+	if spamTransaction != nil {
+		tp.blockCandidates.Push(spamTransaction)
+		defer tp.blockCandidates.Remove(spamTransaction.TransactionID())
+	}
+
+	sample := tp.blockCandidates.Sample(blockMaxMass)
+	result := make([]*externalapi.DomainTransaction, len(sample))
+	for i, transaction := range sample {
+		result[i] = transaction.Transaction().Clone() //this pointer leaves the mempool, hence we clone.
+	}
+	return result
+}
+
+// blockCandidateStatus explains whether the given transaction is a block candidate, and why not
+// if it isn't
+func (mp *mempool) blockCandidateStatus(transactionID *externalapi.DomainTransactionID) *miningmanagermodel.BlockCandidateStatus {
+	if _, ok := mp.orphansPool.allOrphans[*transactionID]; ok {
+		return &miningmanagermodel.BlockCandidateStatus{
+			IsOrphan: true,
+			Reason:   "the transaction is an orphan: some of the outputs it spends are neither in the mempool nor in the DAG",
+		}
+	}
+
+	transaction, ok := mp.transactionsPool.allTransactions[*transactionID]
+	if !ok {
+		return &miningmanagermodel.BlockCandidateStatus{
+			Reason: "the transaction is not in the mempool",
+		}
+	}
+
+	status := &miningmanagermodel.BlockCandidateStatus{IsInTransactionPool: true}
+	if len(transaction.ParentTransactionsInPool()) != 0 {
+		status.HasParentsInMempool = true
+		status.Reason = fmt.Sprintf("the transaction spends outputs of %d transactions that are still in the "+
+			"mempool, and becomes a block candidate once they're included in a block",
+			len(transaction.ParentTransactionsInPool()))
+		return status
+	}
+
+	kind, reason := mp.blockCandidateKindOf(transaction)
+	status.Kind = kind
+	switch kind {
+	case miningmanagermodel.BlockCandidateRegular:
+		status.IsBlockCandidate = true
+		if transaction.IsPrioritised() {
+			status.Reason = "the transaction is a prioritised block candidate, and is selected into block " +
+				"templates ahead of the transactions that aren't prioritised"
+			break
+		}
+		status.Reason = "the transaction is a block candidate, and is drawn into block templates with a " +
+			"probability that grows with its fee rate"
+	case miningmanagermodel.BlockCandidateSpam:
+		spamTransaction := mp.transactionsPool.spamBlockCandidate()
+		if spamTransaction == transaction {
+			status.IsBlockCandidate = true
+			status.Reason = reason + ". It spends the oldest UTXOs among such transactions, so it's the one " +
+				"of them that is a block candidate"
+		} else {
+			status.Reason = fmt.Sprintf("%s. Only one such transaction is a block candidate at any given "+
+				"time, and currently it's %s, which spends older UTXOs", reason, spamTransaction.TransactionID())
+		}
+	case miningmanagermodel.BlockCandidateFiltered:
+		status.Reason = reason + ", so it's never a block candidate"
+	}
+	return status
+}
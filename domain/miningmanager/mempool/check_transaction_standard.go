@@ -42,6 +42,14 @@ const (
 )
 
 func (mp *mempool) checkTransactionStandardInIsolation(transaction *externalapi.DomainTransaction) error {
+	return firstError(mp.transactionStandardInIsolationErrors(transaction))
+}
+
+// transactionStandardInIsolationErrors returns every reason the transaction isn't standard for, on
+// its own, in the order checkTransactionStandardInIsolation checks them in
+func (mp *mempool) transactionStandardInIsolationErrors(transaction *externalapi.DomainTransaction) []error {
+	var errs []error
+
 	// The transaction must be a currently supported version.
 	//
 	// This check is currently mirrored in consensus.
@@ -51,7 +59,7 @@ func (mp *mempool) checkTransactionStandardInIsolation(transaction *externalapi.
 		transaction.Version < mp.config.MinimumStandardTransactionVersion {
 		str := fmt.Sprintf("transaction version %d is not in the valid range of %d-%d", transaction.Version,
 			mp.config.MinimumStandardTransactionVersion, mp.config.MaximumStandardTransactionVersion)
-		return transactionRuleError(RejectNonstandard, str)
+		errs = append(errs, transactionRuleError(RejectNonstandard, str))
 	}
 
 	// Since extremely large transactions with a lot of inputs can cost
@@ -61,7 +69,7 @@ func (mp *mempool) checkTransactionStandardInIsolation(transaction *externalapi.
 	if transaction.Mass > MaximumStandardTransactionMass {
 		str := fmt.Sprintf("transaction mass of %d is larger than max allowed size of %d",
 			transaction.Mass, MaximumStandardTransactionMass)
-		return transactionRuleError(RejectNonstandard, str)
+		errs = append(errs, transactionRuleError(RejectNonstandard, str))
 	}
 
 	for i, input := range transaction.Inputs {
@@ -72,29 +80,30 @@ func (mp *mempool) checkTransactionStandardInIsolation(transaction *externalapi.
 		if signatureScriptLen > maximumStandardSignatureScriptSize {
 			str := fmt.Sprintf("transaction input %d: signature script size of %d bytes is larger than the "+
 				"maximum allowed size of %d bytes", i, signatureScriptLen, maximumStandardSignatureScriptSize)
-			return transactionRuleError(RejectNonstandard, str)
+			errs = append(errs, transactionRuleError(RejectNonstandard, str))
 		}
 	}
 
 	// None of the output public key scripts can be a non-standard script or be "dust".
 	for i, output := range transaction.Outputs {
 		if output.ScriptPublicKey.Version > constants.MaxScriptPublicKeyVersion {
-			return transactionRuleError(RejectNonstandard, "The version of the scriptPublicKey is higher than the known version.")
+			errs = append(errs, transactionRuleError(RejectNonstandard, "The version of the scriptPublicKey is higher than the known version."))
+			continue
 		}
 		scriptClass := txscript.GetScriptClass(output.ScriptPublicKey.Script)
 		if scriptClass == txscript.NonStandardTy {
 			str := fmt.Sprintf("transaction output %d: non-standard script form", i)
-			return transactionRuleError(RejectNonstandard, str)
+			errs = append(errs, transactionRuleError(RejectNonstandard, str))
 		}
 
 		if mp.IsTransactionOutputDust(output) {
 			str := fmt.Sprintf("transaction output %d: payment "+
 				"of %d is dust", i, output.Value)
-			return transactionRuleError(RejectDust, str)
+			errs = append(errs, transactionRuleError(RejectDust, str))
 		}
 	}
 
-	return nil
+	return errs
 }
 
 // IsTransactionOutputDust returns whether or not the passed transaction output amount
@@ -152,6 +161,14 @@ func (mp *mempool) IsTransactionOutputDust(output *externalapi.DomainTransaction
 // In addition, makes sure that the transaction's fee is above the minimum for acceptance
 // into the mempool and relay
 func (mp *mempool) checkTransactionStandardInContext(transaction *externalapi.DomainTransaction) error {
+	return firstError(mp.transactionStandardInContextErrors(transaction))
+}
+
+// transactionStandardInContextErrors returns every reason the inputs or the fee of the transaction
+// aren't standard for, in the order checkTransactionStandardInContext checks them in
+func (mp *mempool) transactionStandardInContextErrors(transaction *externalapi.DomainTransaction) []error {
+	var errs []error
+
 	for i, input := range transaction.Inputs {
 		// It is safe to elide existence and index checks here since
 		// they have already been checked prior to calling this
@@ -165,12 +182,12 @@ func (mp *mempool) checkTransactionStandardInContext(transaction *externalapi.Do
 			if numSigOps > maxStandardP2SHSigOps {
 				str := fmt.Sprintf("transaction input #%d has %d signature operations which is more "+
 					"than the allowed max amount of %d", i, numSigOps, maxStandardP2SHSigOps)
-				return transactionRuleError(RejectNonstandard, str)
+				errs = append(errs, transactionRuleError(RejectNonstandard, str))
 			}
 
 		case txscript.NonStandardTy:
 			str := fmt.Sprintf("transaction input #%d has a non-standard script form", i)
-			return transactionRuleError(RejectNonstandard, str)
+			errs = append(errs, transactionRuleError(RejectNonstandard, str))
 		}
 	}
 
@@ -178,10 +195,17 @@ func (mp *mempool) checkTransactionStandardInContext(transaction *externalapi.Do
 	if transaction.Fee < minimumFee {
 		str := fmt.Sprintf("transaction %s has %d fees which is under the required amount of %d",
 			consensushashing.TransactionID(transaction), transaction.Fee, minimumFee)
-		return transactionRuleError(RejectInsufficientFee, str)
+		errs = append(errs, transactionRuleError(RejectInsufficientFee, str))
 	}
 
-	return nil
+	return errs
+}
+
+func firstError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
 }
 
 // minimumRequiredTransactionRelayFee returns the minimum transaction fee required for a
@@ -13,6 +13,14 @@ import (
 const (
 	defaultMaximumTransactionCount = 1_000_000
 
+	// defaultMaximumTotalTransactionMassInBlocks bounds the total mass of the transaction pool,
+	// in units of the maximum block mass
+	defaultMaximumTotalTransactionMassInBlocks = 10_000
+
+	// defaultDynamicMinimumFeeRateHalfLifeSeconds is how long it takes the minimum fee rate that
+	// is set by evictions from a full mempool to decay by half
+	defaultDynamicMinimumFeeRateHalfLifeSeconds uint64 = 10 * 60
+
 	defaultTransactionExpireIntervalSeconds     uint64 = 60
 	defaultTransactionExpireScanIntervalSeconds uint64 = 10
 	defaultOrphanExpireIntervalSeconds          uint64 = 60
@@ -23,6 +31,21 @@ const (
 	// removeOrphans when removeRedeemers = true
 	defaultMaximumOrphanTransactionCount = 50
 
+	// defaultMaximumReplacementEvictionCount bounds the number of transactions (the replaced
+	// transaction along with its redeemers) a single transaction replacement may evict
+	defaultMaximumReplacementEvictionCount = 100
+
+	// defaultMaximumDroppedTransactionCount and defaultDroppedTransactionExpireIntervalSeconds
+	// bound how many expired and evicted transactions are remembered, and for how long
+	defaultMaximumDroppedTransactionCount                 = 10_000
+	defaultDroppedTransactionExpireIntervalSeconds uint64 = 60 * 60
+
+	// defaultMaximumFeeDeltaCount and defaultFeeDeltaExpireIntervalSeconds bound how many fee
+	// deltas are kept, and for how long the fee delta of a transaction that isn't in the mempool
+	// is kept since it was last changed
+	defaultMaximumFeeDeltaCount                 = 10_000
+	defaultFeeDeltaExpireIntervalSeconds uint64 = 60 * 60
+
 	// defaultMinimumRelayTransactionFee specifies the minimum transaction fee for a transaction to be accepted to
 	// the mempool and relayed. It is specified in sompi per 1kg (or 1000 grams) of transaction mass.
 	defaultMinimumRelayTransactionFee = util.Amount(1000)
@@ -37,38 +60,63 @@ const (
 
 // Config represents a mempool configuration
 type Config struct {
-	MaximumTransactionCount               uint64
-	TransactionExpireIntervalDAAScore     uint64
-	TransactionExpireScanIntervalDAAScore uint64
-	TransactionExpireScanIntervalSeconds  uint64
-	OrphanExpireIntervalDAAScore          uint64
-	OrphanExpireScanIntervalDAAScore      uint64
-	MaximumOrphanTransactionMass          uint64
-	MaximumOrphanTransactionCount         uint64
-	AcceptNonStandard                     bool
-	MaximumMassPerBlock                   uint64
-	MinimumRelayTransactionFee            util.Amount
-	MinimumStandardTransactionVersion     uint16
-	MaximumStandardTransactionVersion     uint16
+	MaximumTransactionCount                 uint64
+	MaximumTotalTransactionMass             uint64
+	DynamicMinimumFeeRateHalfLifeSeconds    uint64
+	TransactionExpireIntervalDAAScore       uint64
+	TransactionExpireScanIntervalDAAScore   uint64
+	TransactionExpireScanIntervalSeconds    uint64
+	OrphanExpireIntervalDAAScore            uint64
+	OrphanExpireScanIntervalDAAScore        uint64
+	MaximumOrphanTransactionMass            uint64
+	MaximumOrphanTransactionCount           uint64
+	MaximumReplacementEvictionCount         uint64
+	MaximumDroppedTransactionCount          uint64
+	DroppedTransactionExpireIntervalSeconds uint64
+	MaximumFeeDeltaCount                    uint64
+	FeeDeltaExpireIntervalDAAScore          uint64
+	AcceptNonStandard                       bool
+	MaximumMassPerBlock                     uint64
+	MinimumRelayTransactionFee              util.Amount
+	MinimumStandardTransactionVersion       uint16
+	MaximumStandardTransactionVersion       uint16
+
+	// BlockCandidatePolicy decides which of the transactions without parents in the mempool are
+	// block candidates. Transactions it filters out are rejected from the mempool, unless they're
+	// prioritised. A nil policy makes all of them regular block candidates.
+	BlockCandidatePolicy BlockCandidatePolicy
 }
 
 // DefaultConfig returns the default mempool configuration
 func DefaultConfig(dagParams *dagconfig.Params) *Config {
-	targetBlocksPerSecond := time.Second.Seconds() / dagParams.TargetTimePerBlock.Seconds()
-
 	return &Config{
-		MaximumTransactionCount:               defaultMaximumTransactionCount,
-		TransactionExpireIntervalDAAScore:     uint64(float64(defaultTransactionExpireIntervalSeconds) / targetBlocksPerSecond),
-		TransactionExpireScanIntervalDAAScore: uint64(float64(defaultTransactionExpireScanIntervalSeconds) / targetBlocksPerSecond),
-		TransactionExpireScanIntervalSeconds:  defaultTransactionExpireScanIntervalSeconds,
-		OrphanExpireIntervalDAAScore:          uint64(float64(defaultOrphanExpireIntervalSeconds) / targetBlocksPerSecond),
-		OrphanExpireScanIntervalDAAScore:      uint64(float64(defaultOrphanExpireScanIntervalSeconds) / targetBlocksPerSecond),
-		MaximumOrphanTransactionMass:          defaultMaximumOrphanTransactionMass,
-		MaximumOrphanTransactionCount:         defaultMaximumOrphanTransactionCount,
-		AcceptNonStandard:                     dagParams.RelayNonStdTxs,
-		MaximumMassPerBlock:                   dagParams.MaxBlockMass,
-		MinimumRelayTransactionFee:            defaultMinimumRelayTransactionFee,
-		MinimumStandardTransactionVersion:     defaultMinimumStandardTransactionVersion,
-		MaximumStandardTransactionVersion:     defaultMaximumStandardTransactionVersion,
+		MaximumTransactionCount:                 defaultMaximumTransactionCount,
+		MaximumTotalTransactionMass:             defaultMaximumTotalTransactionMassInBlocks * dagParams.MaxBlockMass,
+		DynamicMinimumFeeRateHalfLifeSeconds:    defaultDynamicMinimumFeeRateHalfLifeSeconds,
+		TransactionExpireIntervalDAAScore:       SecondsToDAAScore(dagParams, defaultTransactionExpireIntervalSeconds),
+		TransactionExpireScanIntervalDAAScore:   SecondsToDAAScore(dagParams, defaultTransactionExpireScanIntervalSeconds),
+		TransactionExpireScanIntervalSeconds:    defaultTransactionExpireScanIntervalSeconds,
+		OrphanExpireIntervalDAAScore:            SecondsToDAAScore(dagParams, defaultOrphanExpireIntervalSeconds),
+		OrphanExpireScanIntervalDAAScore:        SecondsToDAAScore(dagParams, defaultOrphanExpireScanIntervalSeconds),
+		MaximumOrphanTransactionMass:            defaultMaximumOrphanTransactionMass,
+		MaximumOrphanTransactionCount:           defaultMaximumOrphanTransactionCount,
+		MaximumReplacementEvictionCount:         defaultMaximumReplacementEvictionCount,
+		MaximumDroppedTransactionCount:          defaultMaximumDroppedTransactionCount,
+		DroppedTransactionExpireIntervalSeconds: defaultDroppedTransactionExpireIntervalSeconds,
+		MaximumFeeDeltaCount:                    defaultMaximumFeeDeltaCount,
+		FeeDeltaExpireIntervalDAAScore:          SecondsToDAAScore(dagParams, defaultFeeDeltaExpireIntervalSeconds),
+		AcceptNonStandard:                       dagParams.RelayNonStdTxs,
+		MaximumMassPerBlock:                     dagParams.MaxBlockMass,
+		MinimumRelayTransactionFee:              defaultMinimumRelayTransactionFee,
+		MinimumStandardTransactionVersion:       defaultMinimumStandardTransactionVersion,
+		MaximumStandardTransactionVersion:       defaultMaximumStandardTransactionVersion,
+		BlockCandidatePolicy:                    DefaultBlockCandidatePolicy(),
 	}
 }
+
+// SecondsToDAAScore returns the DAA score that accumulates over the given number of seconds, for
+// the intervals of the configuration that are measured in DAA score
+func SecondsToDAAScore(dagParams *dagconfig.Params, seconds uint64) uint64 {
+	targetBlocksPerSecond := time.Second.Seconds() / dagParams.TargetTimePerBlock.Seconds()
+	return uint64(float64(seconds) / targetBlocksPerSecond)
+}
@@ -0,0 +1,119 @@
+package mempool
+
+import (
+	"time"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// droppedTransactions remembers the transactions that recently expired or were evicted from the
+// mempool. It holds at most MaximumDroppedTransactionCount transactions, each for at most
+// DroppedTransactionExpireIntervalSeconds.
+type droppedTransactions struct {
+	mempool *mempool
+
+	byID map[externalapi.DomainTransactionID]*miningmanagermodel.DroppedTransaction
+
+	// inOrder holds the dropped transactions from the oldest to the most recent. It may also hold
+	// transactions that were forgotten or dropped again since, which are skipped.
+	inOrder []*miningmanagermodel.DroppedTransaction
+}
+
+func newDroppedTransactions(mp *mempool) *droppedTransactions {
+	return &droppedTransactions{
+		mempool: mp,
+		byID:    make(map[externalapi.DomainTransactionID]*miningmanagermodel.DroppedTransaction),
+	}
+}
+
+func isDropReason(reason miningmanagermodel.MempoolChangeReason) bool {
+	return reason == miningmanagermodel.MempoolChangeExpired || reason == miningmanagermodel.MempoolChangeEvicted
+}
+
+func (dt *droppedTransactions) add(transaction model.Transaction, reason miningmanagermodel.MempoolChangeReason,
+	isOrphan bool, virtualDAAScore uint64) {
+
+	if dt.mempool.config.MaximumDroppedTransactionCount == 0 {
+		return
+	}
+
+	droppedTransaction := &miningmanagermodel.DroppedTransaction{
+		TransactionID:     transaction.TransactionID(),
+		Reason:            reason,
+		IsOrphan:          isOrphan,
+		Fee:               transaction.Transaction().Fee,
+		Mass:              transaction.Transaction().Mass,
+		DroppedAt:         time.Now(),
+		DroppedAtDAAScore: virtualDAAScore,
+	}
+	dt.byID[*droppedTransaction.TransactionID] = droppedTransaction
+	dt.inOrder = append(dt.inOrder, droppedTransaction)
+
+	dt.limitSize()
+}
+
+// forget is called once a dropped transaction is back in the mempool or included in a block
+func (dt *droppedTransactions) forget(transactionID *externalapi.DomainTransactionID) {
+	delete(dt.byID, *transactionID)
+}
+
+func (dt *droppedTransactions) get(transactionID *externalapi.DomainTransactionID) (
+	*miningmanagermodel.DroppedTransaction, bool) {
+
+	droppedTransaction, ok := dt.byID[*transactionID]
+	if !ok || dt.isExpired(droppedTransaction) {
+		return nil, false
+	}
+	return droppedTransaction, true
+}
+
+// all returns the dropped transactions from the oldest to the most recent
+func (dt *droppedTransactions) all() []*miningmanagermodel.DroppedTransaction {
+	all := make([]*miningmanagermodel.DroppedTransaction, 0, len(dt.byID))
+	for _, droppedTransaction := range dt.inOrder {
+		if dt.isCurrent(droppedTransaction) && !dt.isExpired(droppedTransaction) {
+			all = append(all, droppedTransaction)
+		}
+	}
+	return all
+}
+
+func (dt *droppedTransactions) isCurrent(droppedTransaction *miningmanagermodel.DroppedTransaction) bool {
+	return dt.byID[*droppedTransaction.TransactionID] == droppedTransaction
+}
+
+func (dt *droppedTransactions) isExpired(droppedTransaction *miningmanagermodel.DroppedTransaction) bool {
+	expireInterval := time.Duration(dt.mempool.config.DroppedTransactionExpireIntervalSeconds) * time.Second
+	return time.Since(droppedTransaction.DroppedAt) > expireInterval
+}
+
+// limitSize removes the oldest dropped transactions until at most MaximumDroppedTransactionCount
+// remain, along with the expired ones
+func (dt *droppedTransactions) limitSize() {
+	removedCount := 0
+	for _, droppedTransaction := range dt.inOrder {
+		if dt.isCurrent(droppedTransaction) {
+			if uint64(len(dt.byID)) <= dt.mempool.config.MaximumDroppedTransactionCount &&
+				!dt.isExpired(droppedTransaction) {
+				break
+			}
+			delete(dt.byID, *droppedTransaction.TransactionID)
+		}
+		removedCount++
+	}
+	dt.inOrder = dt.inOrder[removedCount:]
+
+	// Forgotten transactions are only skipped once they're the oldest, so compact the rest
+	// before they pile up
+	if uint64(len(dt.inOrder)) > 2*dt.mempool.config.MaximumDroppedTransactionCount {
+		current := make([]*miningmanagermodel.DroppedTransaction, 0, len(dt.byID))
+		for _, droppedTransaction := range dt.inOrder {
+			if dt.isCurrent(droppedTransaction) {
+				current = append(current, droppedTransaction)
+			}
+		}
+		dt.inOrder = current
+	}
+}
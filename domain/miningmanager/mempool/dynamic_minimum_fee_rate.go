@@ -0,0 +1,77 @@
+package mempool
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+)
+
+// transactionFeeRate returns the fee rate of the transaction in sompi per gram of mass
+func transactionFeeRate(transaction *externalapi.DomainTransaction) float64 {
+	if transaction.Mass == 0 {
+		return 0
+	}
+	return float64(transaction.Fee) / float64(transaction.Mass)
+}
+
+func (tp *transactionsPool) raiseEvictedFeeRate(feeRate float64) {
+	// A transaction that takes the place of the evicted one has to pay for being relayed on top
+	// of the evicted fee rate, same as it would in an empty mempool
+	feeRate += float64(tp.mempool.config.MinimumRelayTransactionFee) / 1000
+	if feeRate <= tp.dynamicMinimumFeeRate() {
+		return
+	}
+	tp.evictedFeeRate = feeRate
+	tp.evictedFeeRateTime = time.Now()
+}
+
+// dynamicMinimumFeeRate returns the minimum fee rate, in sompi per gram, for a transaction to enter
+// the pool, on top of the static MinimumRelayTransactionFee.
+//
+// When the pool is full, transactions with the lowest fee rates are evicted, and there's no point
+// in accepting a transaction that would be the next to go. So the minimum is raised to the fee rate
+// of every evicted transaction plus the static minimum. It then halves every
+// DynamicMinimumFeeRateHalfLifeSeconds, so that transactions with lower fee rates are accepted again
+// once blocks are taking transactions out of the pool, and it's dropped altogether once the pool is
+// less than half full.
+//
+// The static minimum is checked separately, and only for standard transactions.
+func (tp *transactionsPool) dynamicMinimumFeeRate() float64 {
+	if tp.evictedFeeRate == 0 {
+		return 0
+	}
+
+	if uint64(len(tp.allTransactions)) < tp.mempool.config.MaximumTransactionCount/2 &&
+		tp.totalMass < tp.mempool.config.MaximumTotalTransactionMass/2 {
+		tp.evictedFeeRate = 0
+		return 0
+	}
+
+	halfLife := float64(tp.mempool.config.DynamicMinimumFeeRateHalfLifeSeconds)
+	if halfLife == 0 {
+		return tp.evictedFeeRate
+	}
+	return tp.evictedFeeRate * math.Pow(0.5, time.Since(tp.evictedFeeRateTime).Seconds()/halfLife)
+}
+
+// checkDynamicMinimumFeeRate rejects transactions whose fee rate is below the minimum that was set by
+// evictions from the full pool, see dynamicMinimumFeeRate. High priority transactions are never
+// evicted, so they aren't subject to it either.
+func (mp *mempool) checkDynamicMinimumFeeRate(transaction *externalapi.DomainTransaction) error {
+	minimumFeeRate := mp.transactionsPool.dynamicMinimumFeeRate()
+	if minimumFeeRate == 0 {
+		return nil
+	}
+
+	feeRate := transactionFeeRate(transaction)
+	if feeRate < minimumFeeRate {
+		str := fmt.Sprintf("transaction %s has a fee rate of %f sompi/gram, while the mempool is full "+
+			"and only accepts fee rates of at least %f sompi/gram",
+			consensushashing.TransactionID(transaction), feeRate, minimumFeeRate)
+		return transactionRuleError(RejectInsufficientFee, str)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package mempool
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+)
+
+// feeDeltaEntry is the fee delta of a transaction, along with the virtual DAA score it was last changed at
+type feeDeltaEntry struct {
+	amount            int64
+	changedAtDAAScore uint64
+}
+
+// prioritiseTransaction adds feeDelta to the fee delta of the given transaction, and returns the
+// resulting fee delta. A fee delta is kept until its transaction is included in a block, so it
+// applies to a transaction that enters the pool only later as well, unless the transaction stays
+// out of the pool for FeeDeltaExpireIntervalDAAScore. At most MaximumFeeDeltaCount fee deltas are kept.
+func (mp *mempool) prioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (int64, error) {
+	const maximumFeeDelta = int64(constants.MaxSompi)
+	if feeDelta > maximumFeeDelta || feeDelta < -maximumFeeDelta {
+		return 0, errors.Errorf("the fee delta %d is out of the range [-%d, %d]", feeDelta, maximumFeeDelta,
+			maximumFeeDelta)
+	}
+	totalFeeDelta := mp.feeDeltaOf(transactionID) + feeDelta
+	if totalFeeDelta > maximumFeeDelta || totalFeeDelta < -maximumFeeDelta {
+		return 0, errors.Errorf("the fee delta of transaction %s would be %d, which is out of the range "+
+			"[-%d, %d]", transactionID, totalFeeDelta, maximumFeeDelta, maximumFeeDelta)
+	}
+	_, hasFeeDelta := mp.feeDeltas[*transactionID]
+	if !hasFeeDelta && totalFeeDelta != 0 && uint64(len(mp.feeDeltas)) >= mp.config.MaximumFeeDeltaCount {
+		return 0, errors.Errorf("the fee deltas of %d transactions are already kept, which is the maximum",
+			len(mp.feeDeltas))
+	}
+
+	if totalFeeDelta == 0 {
+		delete(mp.feeDeltas, *transactionID)
+	} else {
+		virtualDAAScore, err := mp.consensusReference.Consensus().GetVirtualDAAScore()
+		if err != nil {
+			return 0, err
+		}
+		mp.feeDeltas[*transactionID] = &feeDeltaEntry{amount: totalFeeDelta, changedAtDAAScore: virtualDAAScore}
+	}
+
+	if transaction, ok := mp.transactionsPool.allTransactions[*transactionID]; ok {
+		err := mp.transactionsPool.setFeeDelta(transaction, totalFeeDelta)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return totalFeeDelta, nil
+}
+
+// feeDeltaOf returns the fee delta of the given transaction, or 0 if it wasn't prioritised
+func (mp *mempool) feeDeltaOf(transactionID *externalapi.DomainTransactionID) int64 {
+	feeDelta, ok := mp.feeDeltas[*transactionID]
+	if !ok {
+		return 0
+	}
+	return feeDelta.amount
+}
+
+// expireFeeDeltas forgets the fee deltas of transactions that aren't in the mempool, and whose fee
+// delta wasn't changed for more than FeeDeltaExpireIntervalDAAScore
+func (mp *mempool) expireFeeDeltas() error {
+	if len(mp.feeDeltas) == 0 {
+		return nil
+	}
+	virtualDAAScore, err := mp.consensusReference.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return err
+	}
+
+	for transactionID, feeDelta := range mp.feeDeltas {
+		if _, ok := mp.transactionsPool.allTransactions[transactionID]; ok {
+			continue
+		}
+		if _, ok := mp.orphansPool.allOrphans[transactionID]; ok {
+			continue
+		}
+		if virtualDAAScore-feeDelta.changedAtDAAScore > mp.config.FeeDeltaExpireIntervalDAAScore {
+			log.Debugf("Forgetting the fee delta of transaction %s, because it expired", transactionID)
+			delete(mp.feeDeltas, transactionID)
+		}
+	}
+	return nil
+}
+
+// setFeeDelta changes the fee delta of a transaction in the pool, and moves it to its new place
+// among the transactions ordered by fee rate and among the block candidates
+func (tp *transactionsPool) setFeeDelta(transaction *model.MempoolTransaction, feeDelta int64) error {
+	err := tp.transactionsOrderedByFeeRate.Remove(transaction)
+	if err != nil {
+		return err
+	}
+	isBlockCandidate := len(transaction.ParentTransactionsInPool()) == 0
+	if isBlockCandidate {
+		tp.removeBlockCandidate(transaction)
+	}
+
+	transaction.SetFeeDelta(feeDelta)
+
+	err = tp.transactionsOrderedByFeeRate.Push(transaction)
+	if err != nil {
+		return err
+	}
+	if isBlockCandidate {
+		err = tp.addBlockCandidate(transaction)
+		if err != nil {
+			return err
+		}
+	}
+	return tp.updatePackages(tp.transactionsAffectedByChangeOf(transaction))
+}
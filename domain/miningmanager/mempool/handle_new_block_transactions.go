@@ -4,6 +4,7 @@ import (
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/transactionhelper"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 )
 
 func (mp *mempool) handleNewBlockTransactions(blockTransactions []*externalapi.DomainTransaction) (
@@ -15,7 +16,9 @@ func (mp *mempool) handleNewBlockTransactions(blockTransactions []*externalapi.D
 	acceptedOrphans := []*externalapi.DomainTransaction{}
 	for _, transaction := range blockTransactions {
 		transactionID := consensushashing.TransactionID(transaction)
-		err := mp.removeTransaction(transactionID, false)
+		mp.droppedTransactions.forget(transactionID)
+		delete(mp.feeDeltas, *transactionID)
+		err := mp.removeTransaction(transactionID, false, miningmanagermodel.MempoolChangeAccepted)
 		if err != nil {
 			return nil, err
 		}
@@ -45,6 +48,10 @@ func (mp *mempool) handleNewBlockTransactions(blockTransactions []*externalapi.D
 	if err != nil {
 		return nil, err
 	}
+	err = mp.expireFeeDeltas()
+	if err != nil {
+		return nil, err
+	}
 
 	return acceptedOrphans, nil
 }
@@ -52,7 +59,7 @@ func (mp *mempool) handleNewBlockTransactions(blockTransactions []*externalapi.D
 func (mp *mempool) removeDoubleSpends(transaction *externalapi.DomainTransaction) error {
 	for _, input := range transaction.Inputs {
 		if redeemer, ok := mp.mempoolUTXOSet.transactionByPreviousOutpoint[input.PreviousOutpoint]; ok {
-			err := mp.removeTransaction(redeemer.TransactionID(), true)
+			err := mp.removeTransaction(redeemer.TransactionID(), true, miningmanagermodel.MempoolChangeDoubleSpent)
 			if err != nil {
 				return err
 			}
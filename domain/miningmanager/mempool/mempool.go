@@ -5,7 +5,6 @@ import (
 
 	"github.com/stokesnetwork/stokes/domain/consensus/ruleerrors"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
-	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
 	"github.com/pkg/errors"
 
 	"github.com/stokesnetwork/stokes/domain/consensusreference"
@@ -23,6 +22,13 @@ type mempool struct {
 	mempoolUTXOSet   *mempoolUTXOSet
 	transactionsPool *transactionsPool
 	orphansPool      *orphansPool
+
+	droppedTransactions *droppedTransactions
+	feeDeltas           map[externalapi.DomainTransactionID]*feeDeltaEntry
+
+	notifyMtx               sync.Mutex
+	onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler
+	pendingChanges          []*miningmanagermodel.MempoolChange
 }
 
 // New constructs a new mempool
@@ -30,11 +36,13 @@ func New(config *Config, consensusReference consensusreference.ConsensusReferenc
 	mp := &mempool{
 		config:             config,
 		consensusReference: consensusReference,
+		feeDeltas:          make(map[externalapi.DomainTransactionID]*feeDeltaEntry),
 	}
 
 	mp.mempoolUTXOSet = newMempoolUTXOSet(mp)
 	mp.transactionsPool = newTransactionsPool(mp)
 	mp.orphansPool = newOrphansPool(mp)
+	mp.droppedTransactions = newDroppedTransactions(mp)
 
 	return mp
 }
@@ -42,12 +50,33 @@ func New(config *Config, consensusReference consensusreference.ConsensusReferenc
 func (mp *mempool) ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 	acceptedTransactions []*externalapi.DomainTransaction, err error) {
 
+	defer mp.notifyChanges()
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
 	return mp.validateAndInsertTransaction(transaction, isHighPriority, allowOrphan)
 }
 
+func (mp *mempool) ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+	acceptedTransactions []*externalapi.DomainTransaction, replacedTransaction *externalapi.DomainTransaction, err error) {
+
+	defer mp.notifyChanges()
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.validateAndReplaceTransaction(transaction, isHighPriority)
+}
+
+func (mp *mempool) ValidateAndInsertTransactionPackage(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+	acceptedTransactions []*externalapi.DomainTransaction, err error) {
+
+	defer mp.notifyChanges()
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.validateAndInsertTransactionPackage(transactions, isHighPriority)
+}
+
 func (mp *mempool) GetTransaction(transactionID *externalapi.DomainTransactionID,
 	includeTransactionPool bool,
 	includeOrphanPool bool) (
@@ -136,6 +165,7 @@ func (mp *mempool) TransactionCount(includeTransactionPool bool, includeOrphanPo
 func (mp *mempool) HandleNewBlockTransactions(transactions []*externalapi.DomainTransaction) (
 	acceptedOrphans []*externalapi.DomainTransaction, err error) {
 
+	defer mp.notifyChanges()
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
@@ -146,60 +176,105 @@ func (mp *mempool) BlockCandidateTransactions() []*externalapi.DomainTransaction
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
 
-	readyTxs := mp.transactionsPool.allReadyTransactions()
-	var candidateTxs []*externalapi.DomainTransaction
-	var spamTx *externalapi.DomainTransaction
-	var spamTxNewestUTXODaaScore uint64
-	for _, tx := range readyTxs {
-		if len(tx.Outputs) > len(tx.Inputs) {
-			hasCoinbaseInput := false
-			for _, input := range tx.Inputs {
-				if input.UTXOEntry.IsCoinbase() {
-					hasCoinbaseInput = true
-					break
-				}
-			}
-
-			numExtraOuts := len(tx.Outputs) - len(tx.Inputs)
-			if !hasCoinbaseInput && numExtraOuts > 2 && tx.Fee < uint64(numExtraOuts)*constants.SompiPerKaspa {
-				log.Debugf("Filtered spam tx %s", consensushashing.TransactionID(tx))
-				continue
-			}
-
-			if hasCoinbaseInput || tx.Fee > uint64(numExtraOuts)*constants.SompiPerKaspa {
-				candidateTxs = append(candidateTxs, tx)
-			} else {
-				txNewestUTXODaaScore := tx.Inputs[0].UTXOEntry.BlockDAAScore()
-				for _, input := range tx.Inputs {
-					if input.UTXOEntry.BlockDAAScore() > txNewestUTXODaaScore {
-						txNewestUTXODaaScore = input.UTXOEntry.BlockDAAScore()
-					}
-				}
-
-				if spamTx != nil {
-					if txNewestUTXODaaScore < spamTxNewestUTXODaaScore {
-						spamTx = tx
-						spamTxNewestUTXODaaScore = txNewestUTXODaaScore
-					}
-				} else {
-					spamTx = tx
-					spamTxNewestUTXODaaScore = txNewestUTXODaaScore
-				}
-			}
-		} else {
-			candidateTxs = append(candidateTxs, tx)
-		}
-	}
+	return mp.transactionsPool.allBlockCandidates()
+}
+
+// SampleBlockCandidateTransactions draws the transactions for a block template of at most
+// blockMaxMass from the block candidates, see model.Frontier.Sample
+func (mp *mempool) SampleBlockCandidateTransactions(blockMaxMass uint64) []*externalapi.DomainTransaction {
+	// The candidates are modified while they're sampled, so a read lock isn't enough
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.transactionsPool.sampleBlockCandidates(blockMaxMass)
+}
 
-	if spamTx != nil {
-		log.Debugf("Adding spam tx candidate %s", consensushashing.TransactionID(spamTx))
-		candidateTxs = append(candidateTxs, spamTx)
+func (mp *mempool) BlockCandidatesSummary() *miningmanagermodel.BlockCandidatesSummary {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return &miningmanagermodel.BlockCandidatesSummary{
+		Count:       mp.transactionsPool.blockCandidates.Len(),
+		TotalMass:   mp.transactionsPool.blockCandidates.TotalMass(),
+		TotalWeight: mp.transactionsPool.blockCandidates.TotalWeight(),
 	}
+}
+
+// MempoolInfo describes the mempool, with the fee rate histogram split at the given fee rates.
+// feeRateBucketBoundaries must be positive and strictly increasing.
+func (mp *mempool) MempoolInfo(feeRateBucketBoundaries []float64) *miningmanagermodel.MempoolInfo {
+	// The dynamic minimum fee rate is reset once it's read while the pool is less than half
+	// full, so a read lock isn't enough
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.mempoolInfo(feeRateBucketBoundaries)
+}
+
+// BlockCandidateStatus explains whether the given transaction is a block candidate, and why not
+// if it isn't
+func (mp *mempool) BlockCandidateStatus(transactionID *externalapi.DomainTransactionID) *miningmanagermodel.BlockCandidateStatus {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.blockCandidateStatus(transactionID)
+}
+
+// DroppedTransaction returns the given transaction if it recently expired or was evicted from
+// the mempool
+func (mp *mempool) DroppedTransaction(transactionID *externalapi.DomainTransactionID) (
+	*miningmanagermodel.DroppedTransaction, bool) {
+
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.droppedTransactions.get(transactionID)
+}
+
+// DroppedTransactions returns the transactions that recently expired or were evicted from the
+// mempool, from the oldest to the most recent
+func (mp *mempool) DroppedTransactions() []*miningmanagermodel.DroppedTransaction {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.droppedTransactions.all()
+}
+
+// PrioritiseTransaction adds feeDelta to the fee delta of the given transaction, and returns the
+// resulting fee delta. The transaction is selected into block templates and evicted from the pool
+// as if its fee were higher by its fee delta, but it's still validated by its actual fee. A
+// prioritised transaction is always a regular block candidate, so the block candidate policy
+// doesn't filter it out.
+func (mp *mempool) PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (int64, error) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.prioritiseTransaction(transactionID, feeDelta)
+}
+
+// FeeDelta returns the fee delta of the given transaction, or 0 if it wasn't prioritised
+func (mp *mempool) FeeDelta(transactionID *externalapi.DomainTransactionID) int64 {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.feeDeltaOf(transactionID)
+}
+
+// TestMempoolAccept checks whether each of the given transactions would be accepted into the
+// transaction pool, without inserting any of them. Transactions may spend the outputs of the
+// accepted transactions before them.
+func (mp *mempool) TestMempoolAccept(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+	[]*miningmanagermodel.MempoolAcceptance, error) {
+
+	// The dynamic minimum fee rate may be reset while it's checked, so a read lock isn't enough
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
 
-	return candidateTxs
+	return mp.testMempoolAccept(transactions, isHighPriority)
 }
 
 func (mp *mempool) RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error) {
+	defer mp.notifyChanges()
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
@@ -207,12 +282,14 @@ func (mp *mempool) RevalidateHighPriorityTransactions() (validTransactions []*ex
 }
 
 func (mp *mempool) RemoveInvalidTransactions(err *ruleerrors.ErrInvalidTransactionsInNewBlock) error {
+	defer mp.notifyChanges()
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
 	for _, tx := range err.InvalidTransactions {
 		removeRedeemers := !errors.As(tx.Error, &ruleerrors.ErrMissingTxOut{})
-		err := mp.removeTransaction(consensushashing.TransactionID(tx.Transaction), removeRedeemers)
+		err := mp.removeTransaction(consensushashing.TransactionID(tx.Transaction), removeRedeemers,
+			miningmanagermodel.MempoolChangeInvalid)
 		if err != nil {
 			return err
 		}
@@ -222,8 +299,9 @@ func (mp *mempool) RemoveInvalidTransactions(err *ruleerrors.ErrInvalidTransacti
 }
 
 func (mp *mempool) RemoveTransaction(transactionID *externalapi.DomainTransactionID, removeRedeemers bool) error {
+	defer mp.notifyChanges()
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
-	return mp.removeTransaction(transactionID, removeRedeemers)
+	return mp.removeTransaction(transactionID, removeRedeemers, miningmanagermodel.MempoolChangeInvalid)
 }
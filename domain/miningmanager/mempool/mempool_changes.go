@@ -0,0 +1,50 @@
+package mempool
+
+import (
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+func (mp *mempool) SetOnMempoolChangedHandler(onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	mp.onMempoolChangedHandler = onMempoolChangedHandler
+}
+
+// recordChange queues a change to the transaction pool until notifyChanges is called.
+// Changes aren't recorded while there's no handler to notify.
+func (mp *mempool) recordChange(transaction *model.MempoolTransaction, reason miningmanagermodel.MempoolChangeReason) {
+	if mp.onMempoolChangedHandler == nil {
+		return
+	}
+	mp.pendingChanges = append(mp.pendingChanges, &miningmanagermodel.MempoolChange{
+		Transaction: transaction.Transaction().Clone(), //this pointer leaves the mempool, hence we clone.
+		Reason:      reason,
+	})
+}
+
+// discardChangesSince drops the changes recorded after the given number of changes were
+// pending, for operations that undo their own changes
+func (mp *mempool) discardChangesSince(pendingChangeCount int) {
+	mp.pendingChanges = mp.pendingChanges[:pendingChangeCount]
+}
+
+// notifyChanges hands the pending changes to the handler. It must be called without holding
+// mp.mtx, which is why the public methods that change the pool defer it before they lock.
+// notifyMtx makes sure the changes of consecutive operations are handled in order.
+func (mp *mempool) notifyChanges() {
+	mp.notifyMtx.Lock()
+	defer mp.notifyMtx.Unlock()
+
+	mp.mtx.Lock()
+	changes := mp.pendingChanges
+	mp.pendingChanges = nil
+	onMempoolChangedHandler := mp.onMempoolChangedHandler
+	mp.mtx.Unlock()
+
+	if len(changes) == 0 || onMempoolChangedHandler == nil {
+		return
+	}
+	onMempoolChangedHandler(changes)
+}
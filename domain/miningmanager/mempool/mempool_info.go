@@ -0,0 +1,64 @@
+package mempool
+
+import (
+	"sort"
+
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// mempoolInfo describes the mempool, with the fee rate histogram split at the given fee rates.
+// feeRateBucketBoundaries must be positive and strictly increasing.
+func (mp *mempool) mempoolInfo(feeRateBucketBoundaries []float64) *miningmanagermodel.MempoolInfo {
+	tp := mp.transactionsPool
+
+	histogram := make([]miningmanagermodel.FeeRateHistogramBucket, len(feeRateBucketBoundaries)+1)
+	for i, boundary := range feeRateBucketBoundaries {
+		histogram[i+1].MinimumFeeRate = boundary
+	}
+
+	info := &miningmanagermodel.MempoolInfo{
+		TransactionCount: len(tp.allTransactions),
+		OrphanCount:      len(mp.orphansPool.allOrphans),
+		TotalMass:        tp.totalMass,
+		FeeRateHistogram: histogram,
+		MinimumFeeRate:   mp.minimumFeeRate(),
+	}
+	for _, transaction := range tp.allTransactions {
+		feeRate := transaction.FeeRate()
+		bucketIndex := sort.Search(len(feeRateBucketBoundaries), func(i int) bool {
+			return feeRateBucketBoundaries[i] > feeRate
+		})
+		histogram[bucketIndex].Count++
+		histogram[bucketIndex].TotalMass += transaction.Transaction().Mass
+
+		if info.OldestTransactionDAAScore == 0 || transaction.AddedAtDAAScore() < info.OldestTransactionDAAScore {
+			info.OldestTransactionDAAScore = transaction.AddedAtDAAScore()
+		}
+
+		if len(transaction.ParentTransactionsInPool()) != 0 {
+			continue
+		}
+		kind, _ := mp.blockCandidateKindOf(transaction)
+		switch kind {
+		case miningmanagermodel.BlockCandidateSpam:
+			info.SpamTransactionCount++
+		case miningmanagermodel.BlockCandidateFiltered:
+			info.FilteredTransactionCount++
+		}
+	}
+	return info
+}
+
+// minimumFeeRate returns the lowest fee rate, in sompi per gram, at which a transaction that isn't
+// high priority is currently accepted
+func (mp *mempool) minimumFeeRate() float64 {
+	minimumFeeRate := mp.transactionsPool.dynamicMinimumFeeRate()
+	if !mp.config.AcceptNonStandard {
+		// MinimumRelayTransactionFee is in sompi/kg
+		staticMinimumFeeRate := float64(mp.config.MinimumRelayTransactionFee) / 1000
+		if staticMinimumFeeRate > minimumFeeRate {
+			minimumFeeRate = staticMinimumFeeRate
+		}
+	}
+	return minimumFeeRate
+}
@@ -0,0 +1,364 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+// Frontier is the set of block candidate transactions, ordered by their package fee rate
+// (see MempoolTransaction.PackageFeeRate).
+//
+// It's a treap (a randomized balanced search tree) where every node also keeps the total
+// mass and the total weight of its subtree. The weight of a transaction is feeRate^alpha,
+// so selecting a transaction with probability proportional to its weight is a walk from
+// the root that takes O(log n), regardless of the number of transactions in the mempool.
+//
+// Prioritised transactions (see MempoolTransaction.IsPrioritised) are kept in a separate
+// treap, since they're selected ahead of the rest rather than drawn at random.
+type Frontier struct {
+	alpha           float64
+	root            *frontierNode
+	prioritisedRoot *frontierNode
+	nodes           map[externalapi.DomainTransactionID]*frontierNode
+}
+
+type frontierNode struct {
+	transaction   *MempoolTransaction
+	feeRate       float64
+	weight        float64
+	priority      uint64
+	isPrioritised bool
+
+	left  *frontierNode
+	right *frontierNode
+
+	subtreeWeight float64
+	subtreeMass   uint64
+}
+
+// NewFrontier creates a new empty Frontier, in which the weight of every transaction is
+// its fee rate to the power of alpha
+func NewFrontier(alpha float64) *Frontier {
+	return &Frontier{
+		alpha: alpha,
+		nodes: make(map[externalapi.DomainTransactionID]*frontierNode),
+	}
+}
+
+// Len returns the number of transactions in the frontier
+func (f *Frontier) Len() int {
+	return len(f.nodes)
+}
+
+// TotalMass returns the sum of the masses of all the transactions in the frontier
+func (f *Frontier) TotalMass() uint64 {
+	return f.root.totalMass() + f.prioritisedRoot.totalMass()
+}
+
+// TotalWeight returns the sum of the weights of the transactions in the frontier that are
+// drawn at random, that is, of all of them but the prioritised ones
+func (f *Frontier) TotalWeight() float64 {
+	return f.root.totalWeight()
+}
+
+// Contains returns whether the given transaction is in the frontier
+func (f *Frontier) Contains(transactionID *externalapi.DomainTransactionID) bool {
+	_, ok := f.nodes[*transactionID]
+	return ok
+}
+
+// Push inserts a transaction into the frontier. Pushing a transaction that is already
+// in the frontier does nothing. The package fee rate and the fee delta of the transaction
+// must not change while it's in the frontier.
+func (f *Frontier) Push(transaction *MempoolTransaction) {
+	transactionID := *transaction.TransactionID()
+	if _, ok := f.nodes[transactionID]; ok {
+		return
+	}
+
+	node := f.newNode(transaction)
+	f.nodes[transactionID] = node
+	f.insertNode(node)
+}
+
+// Remove removes a transaction from the frontier, and returns whether it was found
+func (f *Frontier) Remove(transactionID *externalapi.DomainTransactionID) bool {
+	node, ok := f.nodes[*transactionID]
+	if !ok {
+		return false
+	}
+
+	delete(f.nodes, *transactionID)
+	f.removeNode(node)
+	return true
+}
+
+// ForEach calls the given function on every transaction in the frontier, from the highest
+// fee rate to the lowest, the prioritised ones first, until it returns false
+func (f *Frontier) ForEach(callback func(transaction *MempoolTransaction) bool) {
+	if !forEachFrontierNode(f.prioritisedRoot, callback) {
+		return
+	}
+	forEachFrontierNode(f.root, callback)
+}
+
+// forEachFrontierNode calls the given function on the transactions in the subtree in order,
+// and returns false if the function stopped the iteration
+func forEachFrontierNode(root *frontierNode, callback func(transaction *MempoolTransaction) bool) bool {
+	stack := []*frontierNode{}
+	current := root
+	for current != nil || len(stack) > 0 {
+		for current != nil {
+			stack = append(stack, current)
+			current = current.left
+		}
+		last := len(stack) - 1
+		current, stack = stack[last], stack[:last]
+		if !callback(current.transaction) {
+			return false
+		}
+		current = current.right
+	}
+	return true
+}
+
+// Sample selects transactions for a block of at most maxMass.
+//
+// If the whole frontier fits in a block it's returned as is, from the highest fee rate to
+// the lowest. Otherwise, the prioritised transactions are selected first, from the highest
+// fee rate to the lowest, skipping the ones that don't fit in the block. Then the rest are
+// drawn at random one after the other, each with probability proportional to its weight
+// among the transactions that weren't drawn yet, until the next drawn transaction doesn't
+// fit in the block. The randomization makes sure that transactions with a lower fee rate
+// have a chance to be included as well, and that the templates of different miners differ
+// from each other.
+//
+// A selected transaction is selected together with the rest of its package roots that are
+// in the frontier, since its package fee rate is realized only if they're all mined.
+//
+// The frontier is left unchanged once Sample returns.
+func (f *Frontier) Sample(maxMass uint64) []*MempoolTransaction {
+	if f.TotalMass() <= maxMass {
+		selected := make([]*MempoolTransaction, 0, f.Len())
+		f.ForEach(func(transaction *MempoolTransaction) bool {
+			selected = append(selected, transaction)
+			return true
+		})
+		return selected
+	}
+
+	// The drawn transactions are removed from the trees, so that they aren't drawn again,
+	// and are put back once the block is full
+	drawn := []*frontierNode{}
+	defer func() {
+		for _, node := range drawn {
+			f.insertNode(node)
+		}
+	}()
+	isDrawn := make(map[externalapi.DomainTransactionID]struct{})
+
+	selected := []*MempoolTransaction{}
+	mass := uint64(0)
+	// selectPackage selects the given node along with the rest of its package roots, and
+	// returns false if they don't fit in the block
+	selectPackage := func(node *frontierNode) bool {
+		packageNodes := []*frontierNode{node}
+		packageMass := node.transaction.Transaction().Mass
+		for _, root := range node.transaction.PackageRoots() {
+			rootNode, ok := f.nodes[*root.TransactionID()]
+			if !ok || rootNode == node {
+				continue
+			}
+			if _, ok := isDrawn[*root.TransactionID()]; ok {
+				continue
+			}
+			packageNodes = append(packageNodes, rootNode)
+			packageMass += rootNode.transaction.Transaction().Mass
+		}
+		if mass+packageMass < mass || mass+packageMass > maxMass {
+			return false
+		}
+		mass += packageMass
+
+		for _, packageNode := range packageNodes {
+			selected = append(selected, packageNode.transaction)
+			f.removeNode(packageNode)
+			drawn = append(drawn, packageNode)
+			isDrawn[*packageNode.transaction.TransactionID()] = struct{}{}
+		}
+		return true
+	}
+
+	prioritisedNodes := []*frontierNode{}
+	forEachFrontierNode(f.prioritisedRoot, func(transaction *MempoolTransaction) bool {
+		prioritisedNodes = append(prioritisedNodes, f.nodes[*transaction.TransactionID()])
+		return true
+	})
+	for _, node := range prioritisedNodes {
+		if _, ok := isDrawn[*node.transaction.TransactionID()]; ok {
+			continue
+		}
+		selectPackage(node)
+	}
+
+	for f.root != nil {
+		node := f.root.find(rand.Float64() * f.root.subtreeWeight)
+		if !selectPackage(node) {
+			break
+		}
+	}
+	return selected
+}
+
+// insertNode inserts the node into the tree it belongs to
+func (f *Frontier) insertNode(node *frontierNode) {
+	if node.isPrioritised {
+		f.prioritisedRoot = insertFrontierNode(f.prioritisedRoot, node)
+		return
+	}
+	f.root = insertFrontierNode(f.root, node)
+}
+
+// removeNode removes the node from the tree it belongs to
+func (f *Frontier) removeNode(node *frontierNode) {
+	if node.isPrioritised {
+		f.prioritisedRoot = removeFrontierNode(f.prioritisedRoot, node)
+		return
+	}
+	f.root = removeFrontierNode(f.root, node)
+}
+
+func (f *Frontier) newNode(transaction *MempoolTransaction) *frontierNode {
+	feeRate := transaction.PackageFeeRate()
+	node := &frontierNode{
+		transaction:   transaction,
+		feeRate:       feeRate,
+		weight:        math.Pow(feeRate, f.alpha),
+		priority:      rand.Uint64(),
+		isPrioritised: transaction.IsPrioritised(),
+	}
+	node.update()
+	return node
+}
+
+func (node *frontierNode) totalMass() uint64 {
+	if node == nil {
+		return 0
+	}
+	return node.subtreeMass
+}
+
+func (node *frontierNode) totalWeight() float64 {
+	if node == nil {
+		return 0
+	}
+	return node.subtreeWeight
+}
+
+// update recalculates the totals of the node's subtree from its children. The totals are
+// always recalculated rather than adjusted, so that floating point errors don't pile up.
+func (node *frontierNode) update() {
+	node.subtreeWeight = node.left.totalWeight() + node.weight + node.right.totalWeight()
+	node.subtreeMass = node.left.totalMass() + node.transaction.Transaction().Mass + node.right.totalMass()
+}
+
+// less returns whether the node comes before the other node: nodes are ordered from the
+// highest fee rate to the lowest, and by transaction ID between equal fee rates
+func (node *frontierNode) less(other *frontierNode) bool {
+	if node.feeRate != other.feeRate {
+		return node.feeRate > other.feeRate
+	}
+	return node.transaction.TransactionID().Less(other.transaction.TransactionID())
+}
+
+// find returns the node in whose weight range r falls, where the ranges of the nodes in the
+// subtree are laid one after the other in order
+func (node *frontierNode) find(r float64) *frontierNode {
+	current := node
+	for {
+		leftWeight := current.left.totalWeight()
+		if r < leftWeight && current.left != nil {
+			current = current.left
+			continue
+		}
+		r -= leftWeight
+		if r < current.weight || current.right == nil {
+			return current
+		}
+		r -= current.weight
+		current = current.right
+	}
+}
+
+func insertFrontierNode(root *frontierNode, node *frontierNode) *frontierNode {
+	if root == nil {
+		node.left, node.right = nil, nil
+		node.update()
+		return node
+	}
+	if node.priority > root.priority {
+		node.left, node.right = splitFrontier(root, node)
+		node.update()
+		return node
+	}
+	if node.less(root) {
+		root.left = insertFrontierNode(root.left, node)
+	} else {
+		root.right = insertFrontierNode(root.right, node)
+	}
+	root.update()
+	return root
+}
+
+func removeFrontierNode(root *frontierNode, node *frontierNode) *frontierNode {
+	if root == nil {
+		return nil
+	}
+	if root == node {
+		return mergeFrontiers(root.left, root.right)
+	}
+	if node.less(root) {
+		root.left = removeFrontierNode(root.left, node)
+	} else {
+		root.right = removeFrontierNode(root.right, node)
+	}
+	root.update()
+	return root
+}
+
+// splitFrontier splits the subtree into the nodes that come before the given node and the
+// ones that come after it
+func splitFrontier(root *frontierNode, node *frontierNode) (before *frontierNode, after *frontierNode) {
+	if root == nil {
+		return nil, nil
+	}
+	if root.less(node) {
+		root.right, after = splitFrontier(root.right, node)
+		root.update()
+		return root, after
+	}
+	before, root.left = splitFrontier(root.left, node)
+	root.update()
+	return before, root
+}
+
+// mergeFrontiers merges two subtrees where all the nodes of the first come before all the
+// nodes of the second
+func mergeFrontiers(first *frontierNode, second *frontierNode) *frontierNode {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	if first.priority > second.priority {
+		first.right = mergeFrontiers(first.right, second)
+		first.update()
+		return first
+	}
+	second.left = mergeFrontiers(first, second.left)
+	second.update()
+	return second
+}
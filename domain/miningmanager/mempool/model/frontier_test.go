@@ -0,0 +1,219 @@
+package model
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+func newTestMempoolTransaction(index uint64, fee uint64, mass uint64) *MempoolTransaction {
+	var id [externalapi.DomainHashSize]byte
+	binary.LittleEndian.PutUint64(id[:], index)
+	transaction := &externalapi.DomainTransaction{
+		Fee:  fee,
+		Mass: mass,
+		ID:   externalapi.NewDomainTransactionIDFromByteArray(&id),
+	}
+	return NewMempoolTransaction(transaction, IDToTransactionMap{}, false, 0)
+}
+
+func frontierFeeRates(frontier *Frontier) []float64 {
+	feeRates := []float64{}
+	frontier.ForEach(func(transaction *MempoolTransaction) bool {
+		feeRates = append(feeRates, float64(transaction.Transaction().Fee)/float64(transaction.Transaction().Mass))
+		return true
+	})
+	return feeRates
+}
+
+func TestFrontierOrderAndTotals(t *testing.T) {
+	frontier := NewFrontier(3)
+	transactions := make([]*MempoolTransaction, 100)
+	for i := range transactions {
+		// Fee rates between 1 and 10, in a scrambled order and with duplicates
+		transactions[i] = newTestMempoolTransaction(uint64(i), uint64((i*37)%10+1)*1000, 1000)
+		frontier.Push(transactions[i])
+	}
+	// Pushing a transaction twice doesn't add it again
+	frontier.Push(transactions[0])
+
+	for i := 0; i < len(transactions); i += 2 {
+		if !frontier.Remove(transactions[i].TransactionID()) {
+			t.Fatalf("Transaction %d was not found in the frontier", i)
+		}
+	}
+	if frontier.Remove(transactions[0].TransactionID()) {
+		t.Fatalf("Expected a removed transaction not to be found")
+	}
+
+	if frontier.Len() != len(transactions)/2 {
+		t.Fatalf("Expected %d transactions, but got %d", len(transactions)/2, frontier.Len())
+	}
+	expectedWeight := 0.0
+	for i := 1; i < len(transactions); i += 2 {
+		if !frontier.Contains(transactions[i].TransactionID()) {
+			t.Fatalf("Transaction %d is missing from the frontier", i)
+		}
+		expectedWeight += math.Pow(float64(transactions[i].Transaction().Fee)/1000, 3)
+	}
+	if frontier.TotalMass() != uint64(len(transactions)/2)*1000 {
+		t.Fatalf("Unexpected total mass %d", frontier.TotalMass())
+	}
+	if math.Abs(frontier.TotalWeight()-expectedWeight) > 1e-9 {
+		t.Fatalf("Expected a total weight of %f, but got %f", expectedWeight, frontier.TotalWeight())
+	}
+
+	feeRates := frontierFeeRates(frontier)
+	if len(feeRates) != frontier.Len() {
+		t.Fatalf("ForEach went over %d transactions instead of %d", len(feeRates), frontier.Len())
+	}
+	for i := 1; i < len(feeRates); i++ {
+		if feeRates[i] > feeRates[i-1] {
+			t.Fatalf("The frontier is not ordered by fee rate: %v", feeRates)
+		}
+	}
+}
+
+func TestFrontierSample(t *testing.T) {
+	frontier := NewFrontier(3)
+	for i := uint64(0); i < 1000; i++ {
+		frontier.Push(newTestMempoolTransaction(i, (i%100+1)*1000, 1000))
+	}
+	totalWeight := frontier.TotalWeight()
+
+	// Everything fits
+	sample := frontier.Sample(frontier.TotalMass())
+	if len(sample) != frontier.Len() {
+		t.Fatalf("Expected the whole frontier to be sampled, but got %d transactions", len(sample))
+	}
+
+	const blockMaxMass = 50 * 1000
+	const rounds = 200
+	highFeeRateCount, lowFeeRateCount := 0, 0
+	for round := 0; round < rounds; round++ {
+		sample = frontier.Sample(blockMaxMass)
+		if len(sample) != blockMaxMass/1000 {
+			t.Fatalf("Expected the sample to fill the block, but got %d transactions", len(sample))
+		}
+		seen := make(map[externalapi.DomainTransactionID]struct{})
+		for _, transaction := range sample {
+			if _, ok := seen[*transaction.TransactionID()]; ok {
+				t.Fatalf("Transaction %s was sampled twice", transaction.TransactionID())
+			}
+			seen[*transaction.TransactionID()] = struct{}{}
+
+			feeRate := transaction.Transaction().Fee / transaction.Transaction().Mass
+			if feeRate > 90 {
+				highFeeRateCount++
+			}
+			if feeRate <= 10 {
+				lowFeeRateCount++
+			}
+		}
+	}
+
+	if frontier.Len() != 1000 || math.Abs(frontier.TotalWeight()-totalWeight) > 1e-6*totalWeight {
+		t.Fatalf("Expected sampling to leave the frontier unchanged")
+	}
+	// The top 10% of the fee rates hold about a third of the weight, while the bottom 10% hold
+	// about 0.01% of it
+	if highFeeRateCount < rounds*blockMaxMass/1000/10 {
+		t.Fatalf("Expected high fee rate transactions to be sampled often, but got %d", highFeeRateCount)
+	}
+	if lowFeeRateCount >= highFeeRateCount/10 {
+		t.Fatalf("Expected low fee rate transactions to be sampled rarely, but got %d against %d",
+			lowFeeRateCount, highFeeRateCount)
+	}
+}
+
+func TestFrontierSamplePackages(t *testing.T) {
+	frontier := NewFrontier(3)
+	for i := uint64(0); i < 100; i++ {
+		frontier.Push(newTestMempoolTransaction(i, 1000, 1000))
+	}
+
+	// Two parents with the lowest fee rate, whose child pays for both of them
+	firstParent := newTestMempoolTransaction(100, 1, 1000)
+	secondParent := newTestMempoolTransaction(101, 1, 1000)
+	packageRoots := []*MempoolTransaction{firstParent, secondParent}
+	firstParent.SetPackage(50, packageRoots)
+	secondParent.SetPackage(50, packageRoots)
+	frontier.Push(firstParent)
+	frontier.Push(secondParent)
+
+	const blockMaxMass = 10 * 1000
+	const rounds = 100
+	packageCount := 0
+	for round := 0; round < rounds; round++ {
+		sample := frontier.Sample(blockMaxMass)
+		hasFirstParent, hasSecondParent := false, false
+		for _, transaction := range sample {
+			hasFirstParent = hasFirstParent || transaction == firstParent
+			hasSecondParent = hasSecondParent || transaction == secondParent
+		}
+		if hasFirstParent != hasSecondParent {
+			t.Fatalf("Expected the parents to be sampled together")
+		}
+		if hasFirstParent {
+			packageCount++
+		}
+	}
+
+	// The weight of each parent is 50^3, against a total of 100 for the rest of the frontier
+	if packageCount < rounds*9/10 {
+		t.Fatalf("Expected the package to be sampled in almost every round, but it was sampled in %d of %d",
+			packageCount, rounds)
+	}
+}
+
+func TestFrontierSamplePrioritised(t *testing.T) {
+	frontier := NewFrontier(3)
+	for i := uint64(0); i < 100; i++ {
+		frontier.Push(newTestMempoolTransaction(i, 100_000, 1000))
+	}
+
+	// A prioritised transaction with the lowest fee rate, and one too heavy to fit any block
+	prioritised := newTestMempoolTransaction(100, 1, 1000)
+	prioritised.SetFeeDelta(1)
+	frontier.Push(prioritised)
+	tooHeavy := newTestMempoolTransaction(101, 1, 100*1000)
+	tooHeavy.SetFeeDelta(1)
+	frontier.Push(tooHeavy)
+
+	var firstTransaction *MempoolTransaction
+	frontier.ForEach(func(transaction *MempoolTransaction) bool {
+		firstTransaction = transaction
+		return false
+	})
+	if firstTransaction != prioritised && firstTransaction != tooHeavy {
+		t.Fatalf("Expected ForEach to go over the prioritised transactions first")
+	}
+
+	const blockMaxMass = 10 * 1000
+	for round := 0; round < 100; round++ {
+		sample := frontier.Sample(blockMaxMass)
+		if len(sample) != blockMaxMass/1000 {
+			t.Fatalf("Expected the sample to fill the block, but got %d transactions", len(sample))
+		}
+		if sample[0] != prioritised {
+			t.Fatalf("Expected the prioritised transaction to be sampled first in every round")
+		}
+		for _, transaction := range sample {
+			if transaction == tooHeavy {
+				t.Fatalf("Expected the transaction that doesn't fit not to be sampled")
+			}
+		}
+	}
+
+	// Removing the prioritisation returns the transaction to the random lane
+	frontier.Remove(prioritised.TransactionID())
+	prioritised.SetFeeDelta(0)
+	frontier.Push(prioritised)
+	for round := 0; round < 100; round++ {
+		if frontier.Sample(blockMaxMass)[0] == prioritised {
+			t.Fatalf("Expected the transaction not to be sampled first once it's no longer prioritised")
+		}
+	}
+}
@@ -11,6 +11,10 @@ type MempoolTransaction struct {
 	parentTransactionsInPool IDToTransactionMap
 	isHighPriority           bool
 	addedAtDAAScore          uint64
+	feeDelta                 int64
+
+	packageFeeRate float64
+	packageRoots   []*MempoolTransaction
 }
 
 // NewMempoolTransaction constructs a new MempoolTransaction
@@ -57,3 +61,79 @@ func (mt *MempoolTransaction) IsHighPriority() bool {
 func (mt *MempoolTransaction) AddedAtDAAScore() uint64 {
 	return mt.addedAtDAAScore
 }
+
+// FeeRate returns the fee rate of the transaction itself, in sompi per gram of mass
+func (mt *MempoolTransaction) FeeRate() float64 {
+	if mt.transaction.Mass == 0 {
+		return 0
+	}
+	return float64(mt.transaction.Fee) / float64(mt.transaction.Mass)
+}
+
+// FeeDelta returns the amount of sompi the transaction was prioritised by, see ModifiedFee
+func (mt *MempoolTransaction) FeeDelta() int64 {
+	return mt.feeDelta
+}
+
+// SetFeeDelta sets the amount of sompi the transaction is prioritised by. It must not change while
+// the transaction is ordered by its fee rate or is in the frontier.
+func (mt *MempoolTransaction) SetFeeDelta(feeDelta int64) {
+	mt.feeDelta = feeDelta
+}
+
+// IsPrioritised returns whether the transaction was prioritised by a positive fee delta
+func (mt *MempoolTransaction) IsPrioritised() bool {
+	return mt.feeDelta > 0
+}
+
+// ModifiedFee returns the fee of the transaction plus its fee delta, or 0 if the delta takes more
+// than the whole fee. It's the fee the transaction is selected into block templates and evicted
+// by, while the actual fee is what it's validated by.
+func (mt *MempoolTransaction) ModifiedFee() uint64 {
+	fee := mt.transaction.Fee
+	if mt.feeDelta >= 0 {
+		return fee + uint64(mt.feeDelta)
+	}
+	if uint64(-mt.feeDelta) > fee {
+		return 0
+	}
+	return fee - uint64(-mt.feeDelta)
+}
+
+// ModifiedFeeRate returns the modified fee rate of the transaction itself, in sompi per gram of
+// mass, see ModifiedFee
+func (mt *MempoolTransaction) ModifiedFeeRate() float64 {
+	if mt.transaction.Mass == 0 {
+		return 0
+	}
+	return float64(mt.ModifiedFee()) / float64(mt.transaction.Mass)
+}
+
+// PackageFeeRate returns the fee rate at which this transaction should be selected into block
+// templates, and evicted from a full mempool. It's higher than ModifiedFeeRate when the transaction
+// is the ancestor of a child that pays for its parents, in which case it's the modified fee rate of
+// the child and all its ancestors together.
+func (mt *MempoolTransaction) PackageFeeRate() float64 {
+	if mt.packageRoots == nil {
+		return mt.ModifiedFeeRate()
+	}
+	return mt.packageFeeRate
+}
+
+// PackageRoots returns the transactions without parents in the mempool that have to be selected
+// into a block template together with this transaction for PackageFeeRate to be realized,
+// including this transaction itself
+func (mt *MempoolTransaction) PackageRoots() []*MempoolTransaction {
+	if mt.packageRoots == nil {
+		return []*MempoolTransaction{mt}
+	}
+	return mt.packageRoots
+}
+
+// SetPackage sets the package fee rate and package roots of this transaction. A nil packageRoots
+// means the transaction isn't part of any package that pays more than itself. It must not change
+// while the transaction is ordered by its fee rate or is in the frontier.
+func (mt *MempoolTransaction) SetPackage(packageFeeRate float64, packageRoots []*MempoolTransaction) {
+	mt.packageFeeRate = packageFeeRate
+	mt.packageRoots = packageRoots
+}
@@ -6,11 +6,17 @@ import (
 	"github.com/pkg/errors"
 )
 
-// TransactionsOrderedByFeeRate represents a set of MempoolTransactions ordered by their fee / mass rate
+// TransactionsOrderedByFeeRate represents a set of MempoolTransactions ordered by their package fee rate,
+// see MempoolTransaction.PackageFeeRate
 type TransactionsOrderedByFeeRate struct {
 	slice []*MempoolTransaction
 }
 
+// Len returns the number of transactions in the set
+func (tobf *TransactionsOrderedByFeeRate) Len() int {
+	return len(tobf.slice)
+}
+
 // GetByIndex returns the transaction in the given index
 func (tobf *TransactionsOrderedByFeeRate) GetByIndex(index int) *MempoolTransaction {
 	return tobf.slice[index]
@@ -68,11 +74,11 @@ func (tobf *TransactionsOrderedByFeeRate) findTransactionIndex(transaction *Memp
 			"populated fee and mass")
 	}
 	txID := transaction.TransactionID()
-	txFeeRate := float64(transaction.Transaction().Fee) / float64(transaction.Transaction().Mass)
+	txFeeRate := transaction.PackageFeeRate()
 
 	index = sort.Search(len(tobf.slice), func(i int) bool {
 		iElement := tobf.slice[i]
-		elementFeeRate := float64(iElement.Transaction().Fee) / float64(iElement.Transaction().Mass)
+		elementFeeRate := iElement.PackageFeeRate()
 		if elementFeeRate > txFeeRate {
 			return true
 		}
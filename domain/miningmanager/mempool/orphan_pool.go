@@ -13,6 +13,7 @@ import (
 
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 	"github.com/pkg/errors"
 )
 
@@ -68,6 +69,14 @@ func (op *orphansPool) maybeAddOrphan(transaction *externalapi.DomainTransaction
 }
 
 func (op *orphansPool) limitOrphanPoolSize() error {
+	if uint64(len(op.allOrphans)) <= op.mempool.config.MaximumOrphanTransactionCount {
+		return nil
+	}
+	virtualDAAScore, err := op.mempool.consensusReference.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return err
+	}
+
 	for uint64(len(op.allOrphans)) > op.mempool.config.MaximumOrphanTransactionCount {
 		orphanToRemove := op.randomNonHighPriorityOrphan()
 		if orphanToRemove == nil { // this means all orphans are HighPriority
@@ -84,6 +93,7 @@ func (op *orphansPool) limitOrphanPoolSize() error {
 		if err != nil {
 			return err
 		}
+		op.mempool.droppedTransactions.add(orphanToRemove, miningmanagermodel.MempoolChangeEvicted, true, virtualDAAScore)
 	}
 	return nil
 }
@@ -127,6 +137,7 @@ func (op *orphansPool) addOrphan(transaction *externalapi.DomainTransaction, isH
 	orphanTransaction := model.NewOrphanTransaction(transaction, isHighPriority, virtualDAAScore)
 
 	op.allOrphans[*orphanTransaction.TransactionID()] = orphanTransaction
+	op.mempool.droppedTransactions.forget(orphanTransaction.TransactionID())
 	for _, input := range transaction.Inputs {
 		op.orphansByPreviousOutpoint[input.PreviousOutpoint] = orphanTransaction
 	}
@@ -209,6 +220,13 @@ func (op *orphansPool) unorphanTransaction(transaction *model.OrphanTransaction)
 		return err
 	}
 
+	if !transaction.IsHighPriority() {
+		err = op.mempool.checkDynamicMinimumFeeRate(transaction.Transaction())
+		if err != nil {
+			return err
+		}
+	}
+
 	virtualDAAScore, err := op.mempool.consensusReference.Consensus().GetVirtualDAAScore()
 	if err != nil {
 		return err
@@ -290,6 +308,8 @@ func (op *orphansPool) expireOrphanTransactions() error {
 			if err != nil {
 				return err
 			}
+			op.mempool.droppedTransactions.add(orphanTransaction, miningmanagermodel.MempoolChangeExpired, true,
+				virtualDAAScore)
 		}
 	}
 
@@ -0,0 +1,192 @@
+package mempool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/database/serialization"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+)
+
+// persistenceVersion is the version of the format in which SaveTransactions writes the
+// mempool. It's written at the start of the stream, and LoadTransactions refuses any
+// other version.
+//
+// Following the version, every transaction is written as a flags byte, the length of the
+// serialized transaction as a little-endian uint32, and the serialized DbTransaction.
+const persistenceVersion uint32 = 1
+
+const persistedTransactionIsHighPriority byte = 1 << 0
+
+// maxPersistedTransactionSize protects LoadTransactions from allocating a huge buffer for
+// a corrupted length. No valid transaction comes close to it.
+const maxPersistedTransactionSize = 10 * 1024 * 1024
+
+type persistedTransaction struct {
+	transaction    *externalapi.DomainTransaction
+	isHighPriority bool
+}
+
+// SaveTransactions writes all the transactions in the transaction pool and in the orphan
+// pool to the given writer, so that they can be given to LoadTransactions after a restart.
+// Transactions in the transaction pool are written after their parents in the pool.
+func (mp *mempool) SaveTransactions(writer io.Writer) error {
+	// The transactions themselves are never modified once they're in the mempool, so
+	// only collecting them requires the lock, and not writing them
+	mp.mtx.RLock()
+	transactions := mp.transactionsPool.persistedTransactions()
+	transactions = append(transactions, mp.orphansPool.persistedTransactions()...)
+	mp.mtx.RUnlock()
+
+	bufferedWriter := bufio.NewWriter(writer)
+	err := binary.Write(bufferedWriter, binary.LittleEndian, persistenceVersion)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, transaction := range transactions {
+		err := writePersistedTransaction(bufferedWriter, transaction)
+		if err != nil {
+			return err
+		}
+	}
+	return errors.WithStack(bufferedWriter.Flush())
+}
+
+// LoadTransactions reads transactions written by SaveTransactions, and inserts each of
+// them through ValidateAndInsertTransaction, so that they're checked against the current
+// virtual. Transactions that are rejected by the mempool rules are skipped and counted.
+func (mp *mempool) LoadTransactions(reader io.Reader) (loadedCount int, rejectedCount int, err error) {
+	bufferedReader := bufio.NewReader(reader)
+	var version uint32
+	err = binary.Read(bufferedReader, binary.LittleEndian, &version)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read the persisted mempool version")
+	}
+	if version != persistenceVersion {
+		return 0, 0, errors.Errorf("unsupported persisted mempool version %d", version)
+	}
+
+	for {
+		transaction, err := readPersistedTransaction(bufferedReader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return loadedCount, rejectedCount, nil
+			}
+			return loadedCount, rejectedCount, err
+		}
+
+		_, err = mp.ValidateAndInsertTransaction(transaction.transaction, transaction.isHighPriority, true)
+		if err != nil {
+			if !errors.As(err, &RuleError{}) {
+				return loadedCount, rejectedCount, err
+			}
+			log.Debugf("Persisted transaction %s was rejected: %s",
+				consensushashing.TransactionID(transaction.transaction), err)
+			rejectedCount++
+			continue
+		}
+		loadedCount++
+	}
+}
+
+func writePersistedTransaction(writer io.Writer, transaction *persistedTransaction) error {
+	serializedTransaction, err := proto.Marshal(serialization.DomainTransactionToDbTransaction(transaction.transaction))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var header [5]byte
+	if transaction.isHighPriority {
+		header[0] |= persistedTransactionIsHighPriority
+	}
+	binary.LittleEndian.PutUint32(header[1:], uint32(len(serializedTransaction)))
+	_, err = writer.Write(header[:])
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = writer.Write(serializedTransaction)
+	return errors.WithStack(err)
+}
+
+// readPersistedTransaction returns io.EOF only if the reader ended cleanly between two
+// transactions
+func readPersistedTransaction(reader io.Reader) (*persistedTransaction, error) {
+	var header [5]byte
+	_, err := io.ReadFull(reader, header[:])
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, errors.Wrap(err, "failed to read a persisted transaction")
+	}
+
+	length := binary.LittleEndian.Uint32(header[1:])
+	if length > maxPersistedTransactionSize {
+		return nil, errors.Errorf("persisted transaction of %d bytes is too large", length)
+	}
+	serializedTransaction := make([]byte, length)
+	_, err = io.ReadFull(reader, serializedTransaction)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read a persisted transaction")
+	}
+
+	dbTransaction := &serialization.DbTransaction{}
+	err = proto.Unmarshal(serializedTransaction, dbTransaction)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize a persisted transaction")
+	}
+	transaction, err := serialization.DbTransactionToDomainTransaction(dbTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &persistedTransaction{
+		transaction:    transaction,
+		isHighPriority: header[0]&persistedTransactionIsHighPriority != 0,
+	}, nil
+}
+
+// persistedTransactions returns the transactions of the pool such that every transaction
+// comes after its parents in the pool, which is the order in which they can be inserted
+// back into the mempool
+func (tp *transactionsPool) persistedTransactions() []*persistedTransaction {
+	result := make([]*persistedTransaction, 0, len(tp.allTransactions))
+	visited := make(map[externalapi.DomainTransactionID]struct{}, len(tp.allTransactions))
+
+	var visit func(transaction *model.MempoolTransaction)
+	visit = func(transaction *model.MempoolTransaction) {
+		if _, ok := visited[*transaction.TransactionID()]; ok {
+			return
+		}
+		visited[*transaction.TransactionID()] = struct{}{}
+		for _, parent := range transaction.ParentTransactionsInPool() {
+			visit(parent)
+		}
+		result = append(result, &persistedTransaction{
+			transaction:    transaction.Transaction(),
+			isHighPriority: transaction.IsHighPriority(),
+		})
+	}
+
+	for _, transaction := range tp.allTransactions {
+		visit(transaction)
+	}
+	return result
+}
+
+func (op *orphansPool) persistedTransactions() []*persistedTransaction {
+	result := make([]*persistedTransaction, 0, len(op.allOrphans))
+	for _, orphan := range op.allOrphans {
+		result = append(result, &persistedTransaction{
+			transaction:    orphan.Transaction(),
+			isHighPriority: orphan.IsHighPriority(),
+		})
+	}
+	return result
+}
@@ -3,9 +3,15 @@ package mempool
 import (
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 )
 
-func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactionID, removeRedeemers bool) error {
+// removeTransaction removes the given transaction from the mempool, together with its redeemers if
+// removeRedeemers is true. Every transaction that leaves the transaction pool is recorded as a change
+// with the given reason.
+func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactionID, removeRedeemers bool,
+	reason miningmanagermodel.MempoolChangeReason) error {
+
 	if _, ok := mp.orphansPool.allOrphans[*transactionID]; ok {
 		return mp.orphansPool.removeOrphan(transactionID, true)
 	}
@@ -15,6 +21,10 @@ func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactio
 		return nil
 	}
 
+	// The packages of the remaining ancestors and descendants of the removed transactions are
+	// updated once they're removed
+	affectedTransactions := mp.transactionsPool.transactionsAffectedByChangeOf(mempoolTransaction)
+
 	transactionsToRemove := []*model.MempoolTransaction{mempoolTransaction}
 	redeemers := mp.transactionsPool.getRedeemers(mempoolTransaction)
 	if removeRedeemers {
@@ -22,6 +32,21 @@ func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactio
 	} else {
 		for _, redeemer := range redeemers {
 			redeemer.RemoveParentTransactionInPool(transactionID)
+			if len(redeemer.ParentTransactionsInPool()) == 0 {
+				err := mp.transactionsPool.addBlockCandidate(redeemer)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var virtualDAAScore uint64
+	if isDropReason(reason) {
+		var err error
+		virtualDAAScore, err = mp.consensusReference.Consensus().GetVirtualDAAScore()
+		if err != nil {
+			return err
 		}
 	}
 
@@ -30,6 +55,10 @@ func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactio
 		if err != nil {
 			return err
 		}
+		mp.recordChange(transactionToRemove, reason)
+		if isDropReason(reason) {
+			mp.droppedTransactions.add(transactionToRemove, reason, false, virtualDAAScore)
+		}
 	}
 
 	if removeRedeemers {
@@ -39,7 +68,7 @@ func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactio
 		}
 	}
 
-	return nil
+	return mp.transactionsPool.updatePackages(affectedTransactions)
 }
 
 func (mp *mempool) removeTransactionFromSets(mempoolTransaction *model.MempoolTransaction, removeRedeemers bool) error {
@@ -0,0 +1,234 @@
+package mempool
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+)
+
+// validateAndReplaceTransaction inserts the given transaction into the mempool in place of the single
+// mempool transaction it double-spends. The replaced transaction is evicted along with all of its redeemers.
+//
+// A replacement is accepted only if:
+//  1. It double-spends the inputs of exactly one transaction in the transaction pool
+//  2. It does not spend any output of the transactions it evicts
+//  3. It is not an orphan
+//  4. Its fee rate is strictly higher than the fee rate of the replaced transaction
+//  5. Its fee is at least the total fee of all the transactions it evicts, plus the minimum relay fee
+//     of its own mass, so that every replacement pays for the bandwidth it takes to relay it
+//  6. It does not evict more than MaximumReplacementEvictionCount transactions
+//  7. The mempool, if full, would not evict it right away
+func (mp *mempool) validateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+	acceptedTransactions []*externalapi.DomainTransaction, replacedTransaction *externalapi.DomainTransaction, err error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log,
+		fmt.Sprintf("validateAndReplaceTransaction %s", consensushashing.TransactionID(transaction)))
+	defer onEnd()
+
+	// Populate mass in the beginning, it will be used in multiple places throughout the validation and insertion.
+	mp.consensusReference.Consensus().PopulateMass(transaction)
+
+	err = mp.validateTransactionInIsolation(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionToReplace, err := mp.getTransactionToReplace(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evictedTransactions := mp.getReplacementEvictions(transactionToReplace)
+	err = mp.checkReplacementEvictions(transaction, evictedTransactions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentsInPool, missingOutpoints, err := mp.fillInputsAndGetMissingParents(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(missingOutpoints) > 0 {
+		str := fmt.Sprintf("Transaction replacement %s is an orphan", consensushashing.TransactionID(transaction))
+		return nil, nil, transactionRuleError(RejectBadOrphan, str)
+	}
+
+	err = mp.validateTransactionInContext(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isHighPriority {
+		err = mp.checkDynamicMinimumFeeRate(transaction)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = mp.checkReplacementFee(transaction, transactionToReplace, evictedTransactions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isHighPriority {
+		err = mp.checkEvictedRightAway(transaction, nil, evictedTransactions)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Everything that may fail is done before the replaced transaction is removed, so that a rejected
+	// replacement leaves the mempool as it was. The replacement can't be added before that, since it
+	// spends the same outpoints as the transaction it replaces.
+	virtualDAAScore, err := mp.consensusReference.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return nil, nil, err
+	}
+	mempoolTransaction := model.NewMempoolTransaction(transaction, parentsInPool, isHighPriority, virtualDAAScore)
+
+	replacedTransaction = transactionToReplace.Transaction().Clone() //this pointer leaves the mempool, hence we clone.
+	log.Debugf("Replacing transaction %s with %s, evicting %d transactions",
+		transactionToReplace.TransactionID(), consensushashing.TransactionID(transaction), len(evictedTransactions))
+	err = mp.removeTransaction(transactionToReplace.TransactionID(), true, miningmanagermodel.MempoolChangeReplaced)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = mp.transactionsPool.addMempoolTransaction(mempoolTransaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acceptedOrphans, err := mp.orphansPool.processOrphansAfterAcceptedTransaction(mempoolTransaction.Transaction())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acceptedTransactions = append([]*externalapi.DomainTransaction{transaction.Clone()}, acceptedOrphans...) //these pointer leave the mempool, hence we clone.
+
+	err = mp.transactionsPool.limitTransactionsPoolSize()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := mp.transactionsPool.allTransactions[*mempoolTransaction.TransactionID()]; !ok {
+		str := fmt.Sprintf("transaction %s was evicted right away, since the mempool is full and its fee "+
+			"rate is among the lowest in it", mempoolTransaction.TransactionID())
+		return nil, nil, transactionRuleError(RejectInsufficientFee, str)
+	}
+
+	return acceptedTransactions, replacedTransaction, nil
+}
+
+// getTransactionToReplace returns the single transaction in the transaction pool whose inputs are
+// double-spent by the given transaction
+func (mp *mempool) getTransactionToReplace(transaction *externalapi.DomainTransaction) (*model.MempoolTransaction, error) {
+	transactionID := consensushashing.TransactionID(transaction)
+
+	var transactionToReplace *model.MempoolTransaction
+	for _, input := range transaction.Inputs {
+		conflictingTransaction, ok := mp.mempoolUTXOSet.transactionByPreviousOutpoint[input.PreviousOutpoint]
+		if !ok {
+			continue
+		}
+		if transactionToReplace == nil {
+			transactionToReplace = conflictingTransaction
+			continue
+		}
+		if !transactionToReplace.TransactionID().Equal(conflictingTransaction.TransactionID()) {
+			str := fmt.Sprintf("transaction replacement %s double-spends more than one transaction in the "+
+				"memory pool (%s and %s)", transactionID, transactionToReplace.TransactionID(),
+				conflictingTransaction.TransactionID())
+			return nil, transactionRuleError(RejectReplacement, str)
+		}
+	}
+
+	if transactionToReplace == nil {
+		str := fmt.Sprintf("transaction replacement %s does not double-spend any transaction in the memory pool",
+			transactionID)
+		return nil, transactionRuleError(RejectReplacement, str)
+	}
+
+	return transactionToReplace, nil
+}
+
+// getReplacementEvictions returns the given transaction along with all of its redeemers, without duplicates
+func (mp *mempool) getReplacementEvictions(transactionToReplace *model.MempoolTransaction) []*model.MempoolTransaction {
+	evictedTransactions := []*model.MempoolTransaction{transactionToReplace}
+	visited := map[externalapi.DomainTransactionID]struct{}{*transactionToReplace.TransactionID(): {}}
+	for _, redeemer := range mp.transactionsPool.getRedeemers(transactionToReplace) {
+		if _, ok := visited[*redeemer.TransactionID()]; ok {
+			continue
+		}
+		visited[*redeemer.TransactionID()] = struct{}{}
+		evictedTransactions = append(evictedTransactions, redeemer)
+	}
+	return evictedTransactions
+}
+
+func (mp *mempool) checkReplacementEvictions(transaction *externalapi.DomainTransaction,
+	evictedTransactions []*model.MempoolTransaction) error {
+
+	transactionID := consensushashing.TransactionID(transaction)
+	if uint64(len(evictedTransactions)) > mp.config.MaximumReplacementEvictionCount {
+		str := fmt.Sprintf("transaction replacement %s would evict %d transactions from the memory pool, "+
+			"which is more than the maximum allowed (%d)", transactionID, len(evictedTransactions),
+			mp.config.MaximumReplacementEvictionCount)
+		return transactionRuleError(RejectReplacement, str)
+	}
+
+	evictedTransactionIDs := make(map[externalapi.DomainTransactionID]struct{}, len(evictedTransactions))
+	for _, evictedTransaction := range evictedTransactions {
+		evictedTransactionIDs[*evictedTransaction.TransactionID()] = struct{}{}
+	}
+	for _, input := range transaction.Inputs {
+		if _, ok := evictedTransactionIDs[input.PreviousOutpoint.TransactionID]; ok {
+			str := fmt.Sprintf("transaction replacement %s spends output %s of a transaction it evicts",
+				transactionID, input.PreviousOutpoint)
+			return transactionRuleError(RejectReplacement, str)
+		}
+	}
+
+	return nil
+}
+
+func (mp *mempool) checkReplacementFee(transaction *externalapi.DomainTransaction,
+	transactionToReplace *model.MempoolTransaction, evictedTransactions []*model.MempoolTransaction) error {
+
+	transactionID := consensushashing.TransactionID(transaction)
+
+	replaced := transactionToReplace.Transaction()
+	if !hasHigherFeeRate(transaction, replaced) {
+		str := fmt.Sprintf("transaction replacement %s has a fee rate of %f sompi/gram, which is not higher "+
+			"than the fee rate of the replaced transaction %s (%f sompi/gram)", transactionID,
+			float64(transaction.Fee)/float64(transaction.Mass), transactionToReplace.TransactionID(),
+			float64(replaced.Fee)/float64(replaced.Mass))
+		return transactionRuleError(RejectInsufficientFee, str)
+	}
+
+	evictedFees := uint64(0)
+	for _, evictedTransaction := range evictedTransactions {
+		evictedFees += evictedTransaction.Transaction().Fee
+	}
+	incrementalFee := mp.minimumRequiredTransactionRelayFee(transaction.Mass)
+	if transaction.Fee < evictedFees || transaction.Fee-evictedFees < incrementalFee {
+		str := fmt.Sprintf("transaction replacement %s pays a fee of %d sompi, which is lower than the %d "+
+			"sompi paid by the %d transactions it evicts plus the minimum relay fee of %d sompi",
+			transactionID, transaction.Fee, evictedFees, len(evictedTransactions), incrementalFee)
+		return transactionRuleError(RejectInsufficientFee, str)
+	}
+
+	return nil
+}
+
+// hasHigherFeeRate returns whether the fee rate of a is strictly higher than the fee rate of b
+func hasHigherFeeRate(a, b *externalapi.DomainTransaction) bool {
+	// Compare a.Fee/a.Mass with b.Fee/b.Mass by cross-multiplication, in order to avoid floating point imprecision
+	aHigh, aLow := bits.Mul64(a.Fee, b.Mass)
+	bHigh, bLow := bits.Mul64(b.Fee, a.Mass)
+	return aHigh > bHigh || (aHigh == bHigh && aLow > bLow)
+}
@@ -3,6 +3,7 @@ package mempool
 import (
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 	"github.com/stokesnetwork/stokes/infrastructure/logger"
 )
 
@@ -100,7 +101,7 @@ func (mp *mempool) revalidateTransaction(transaction *model.MempoolTransaction)
 	}
 	if len(missingParents) > 0 {
 		log.Debugf("Removing transaction %s, it failed revalidation", transaction.TransactionID())
-		err := mp.removeTransaction(transaction.TransactionID(), false)
+		err := mp.removeTransaction(transaction.TransactionID(), false, miningmanagermodel.MempoolChangeInvalid)
 		if err != nil {
 			return false, err
 		}
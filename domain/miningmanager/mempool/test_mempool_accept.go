@@ -0,0 +1,223 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/utxo"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+)
+
+// MaximumTestMempoolAcceptCount is the most transactions testMempoolAccept checks at once
+const MaximumTestMempoolAcceptCount = maximumPackageSize
+
+// testMempoolAccept checks whether each of the given transactions would be accepted into the
+// transaction pool, without inserting any of them or changing the mempool in any other way.
+//
+// The transactions are checked in order, as if each of the accepted ones were inserted before the
+// next is checked, so a transaction may spend the outputs of accepted transactions before it.
+// Each transaction is checked by its own fee, same as ValidateAndInsertTransaction does, and the
+// checks go on after a failure wherever they don't depend on it, so that all the reasons
+// a transaction is rejected for are reported together. Nothing is logged along the way.
+func (mp *mempool) testMempoolAccept(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+	[]*miningmanagermodel.MempoolAcceptance, error) {
+
+	if len(transactions) > MaximumTestMempoolAcceptCount {
+		return nil, errors.Errorf("can't check %d transactions at once, while the maximum is %d",
+			len(transactions), MaximumTestMempoolAcceptCount)
+	}
+
+	acceptedTransactions := make(map[externalapi.DomainTransactionID]*externalapi.DomainTransaction)
+	rejectedTransactionIDs := make(map[externalapi.DomainTransactionID]struct{})
+	spentOutpoints := make(map[externalapi.DomainOutpoint]*externalapi.DomainTransactionID)
+
+	acceptances := make([]*miningmanagermodel.MempoolAcceptance, len(transactions))
+	for i, transaction := range transactions {
+		// The transaction is populated with consensus data along the way, so a copy of it is checked
+		transaction = transaction.Clone()
+		transactionID := consensushashing.TransactionID(transaction)
+		acceptance := &miningmanagermodel.MempoolAcceptance{TransactionID: transactionID}
+		acceptances[i] = acceptance
+
+		reject := func(err error) {
+			rejectCode, _ := extractRejectCode(err)
+			acceptance.Rejections = append(acceptance.Rejections, &miningmanagermodel.MempoolRejection{
+				RejectCode: rejectCode.String(),
+				Reason:     err.Error(),
+			})
+		}
+
+		if _, ok := mp.transactionsPool.allTransactions[*transactionID]; ok {
+			reject(transactionRuleError(RejectDuplicate,
+				fmt.Sprintf("transaction %s is already in the mempool", transactionID)))
+			continue
+		}
+
+		mp.consensusReference.Consensus().PopulateMass(transaction)
+
+		for _, err := range mp.nonStandardErrorsInIsolation(transaction) {
+			reject(err)
+		}
+		err := mp.mempoolUTXOSet.checkDoubleSpends(transaction)
+		if err != nil {
+			reject(err)
+		}
+		for _, input := range transaction.Inputs {
+			if spendingTransactionID, ok := spentOutpoints[input.PreviousOutpoint]; ok {
+				reject(transactionRuleError(RejectDuplicate, fmt.Sprintf("output %s already spent by "+
+					"transaction %s earlier in the batch", input.PreviousOutpoint, spendingTransactionID)))
+			}
+		}
+
+		isPopulated, err := mp.populateTestMempoolAcceptTransaction(transaction, acceptedTransactions,
+			rejectedTransactionIDs, reject)
+		if err != nil {
+			return nil, err
+		}
+		if isPopulated {
+			acceptance.Mass = transaction.Mass
+			acceptance.Fee = transaction.Fee
+			acceptance.FeeRate = transactionFeeRate(transaction)
+
+			for _, err := range mp.nonStandardErrorsInContext(transaction) {
+				reject(err)
+			}
+			err = mp.checkBlockCandidatePolicy(transaction)
+			if err != nil {
+				reject(err)
+			}
+			if !isHighPriority {
+				err = mp.checkDynamicMinimumFeeRate(transaction)
+				if err != nil {
+					reject(err)
+				}
+				err = mp.checkEvictedRightAway(transaction, acceptedTransactions, nil)
+				if err != nil {
+					reject(err)
+				}
+			}
+		}
+
+		acceptance.IsAccepted = len(acceptance.Rejections) == 0
+		if !acceptance.IsAccepted {
+			rejectedTransactionIDs[*transactionID] = struct{}{}
+			continue
+		}
+		acceptedTransactions[*transactionID] = transaction
+		for _, input := range transaction.Inputs {
+			spentOutpoints[input.PreviousOutpoint] = transactionID
+		}
+	}
+
+	return acceptances, nil
+}
+
+// populateTestMempoolAcceptTransaction fills the inputs of a transaction that spend the outputs of
+// transactions in the pool or of accepted transactions earlier in the batch, and populates it with
+// consensus data. It returns false if the transaction was rejected on the way.
+func (mp *mempool) populateTestMempoolAcceptTransaction(transaction *externalapi.DomainTransaction,
+	acceptedTransactions map[externalapi.DomainTransactionID]*externalapi.DomainTransaction,
+	rejectedTransactionIDs map[externalapi.DomainTransactionID]struct{}, reject func(err error)) (bool, error) {
+
+	for _, input := range transaction.Inputs {
+		parent, ok := acceptedTransactions[input.PreviousOutpoint.TransactionID]
+		if !ok {
+			continue
+		}
+		if input.PreviousOutpoint.Index >= uint32(len(parent.Outputs)) {
+			reject(transactionRuleError(RejectInvalid, fmt.Sprintf("output %s doesn't exist",
+				input.PreviousOutpoint)))
+			return false, nil
+		}
+		relevantOutput := parent.Outputs[input.PreviousOutpoint.Index]
+		input.UTXOEntry = utxo.NewUTXOEntry(relevantOutput.Value, relevantOutput.ScriptPublicKey,
+			false, constants.UnacceptedDAAScore)
+	}
+
+	_, missingOutpoints, err := mp.fillInputsAndGetMissingParents(transaction)
+	if err != nil {
+		if !errors.As(err, &RuleError{}) {
+			return false, err
+		}
+		reject(err)
+		return false, nil
+	}
+	for _, outpoint := range missingOutpoints {
+		if _, ok := rejectedTransactionIDs[outpoint.TransactionID]; ok {
+			reject(transactionRuleError(RejectBadOrphan, fmt.Sprintf("output %s belongs to transaction %s, "+
+				"which was rejected earlier in the batch", outpoint, outpoint.TransactionID)))
+			continue
+		}
+		reject(transactionRuleError(RejectBadOrphan, fmt.Sprintf("output %s is neither in the UTXO set nor "+
+			"created by a transaction in the mempool or earlier in the batch", outpoint)))
+	}
+	return len(missingOutpoints) == 0, nil
+}
+
+// checkEvictedRightAway rejects a transaction that limitTransactionsPoolSize would evict right after
+// inserting it, since the pool would be over its limits and the transaction's fee rate among the
+// lowest in it. The transactions accepted earlier in the batch count towards the limits, but are
+// taken to be kept, and the transaction is placed by its own fee rate rather than by its package's.
+// replacedTransactions are the pool transactions the given transaction replaces, and are taken to be
+// gone already.
+func (mp *mempool) checkEvictedRightAway(transaction *externalapi.DomainTransaction,
+	acceptedTransactions map[externalapi.DomainTransactionID]*externalapi.DomainTransaction,
+	replacedTransactions []*model.MempoolTransaction) error {
+
+	tp := mp.transactionsPool
+	transactionCount := uint64(len(tp.allTransactions)+len(acceptedTransactions)) + 1
+	totalMass := tp.totalMass + transaction.Mass
+	for _, acceptedTransaction := range acceptedTransactions {
+		totalMass += acceptedTransaction.Mass
+	}
+	isOverLimits := func() bool {
+		return transactionCount > mp.config.MaximumTransactionCount ||
+			totalMass > mp.config.MaximumTotalTransactionMass
+	}
+
+	transactionID := consensushashing.TransactionID(transaction)
+	feeRate := transactionFeeRate(transaction)
+	evicted := make(map[externalapi.DomainTransactionID]struct{})
+	for _, replacedTransaction := range replacedTransactions {
+		evicted[*replacedTransaction.TransactionID()] = struct{}{}
+		transactionCount--
+		totalMass -= replacedTransaction.Transaction().Mass
+	}
+	for index := 0; isOverLimits(); index++ {
+		if index >= tp.transactionsOrderedByFeeRate.Len() {
+			return evictedRightAwayError(transactionID)
+		}
+		transactionToEvict := tp.transactionsOrderedByFeeRate.GetByIndex(index)
+		if _, ok := evicted[*transactionToEvict.TransactionID()]; ok || transactionToEvict.IsHighPriority() {
+			continue
+		}
+		// The transaction is placed among the ones in the pool the same way TransactionsOrderedByFeeRate places it
+		evictedFeeRate := transactionToEvict.PackageFeeRate()
+		if evictedFeeRate > feeRate ||
+			(evictedFeeRate == feeRate && transactionID.LessOrEqual(transactionToEvict.TransactionID())) {
+			return evictedRightAwayError(transactionID)
+		}
+
+		for _, removed := range append([]*model.MempoolTransaction{transactionToEvict},
+			tp.getRedeemers(transactionToEvict)...) {
+
+			if _, ok := evicted[*removed.TransactionID()]; ok {
+				continue
+			}
+			evicted[*removed.TransactionID()] = struct{}{}
+			transactionCount--
+			totalMass -= removed.Transaction().Mass
+		}
+	}
+	return nil
+}
+
+func evictedRightAwayError(transactionID *externalapi.DomainTransactionID) error {
+	str := fmt.Sprintf("transaction %s would be evicted right away, since the mempool is full and its fee "+
+		"rate is among the lowest in it", transactionID)
+	return transactionRuleError(RejectInsufficientFee, str)
+}
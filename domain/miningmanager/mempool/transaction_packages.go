@@ -0,0 +1,159 @@
+package mempool
+
+import (
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+)
+
+// maximumPackageSize bounds the number of transactions in a package, that is, a transaction
+// together with all its ancestors in the mempool. Larger packages are not considered when
+// calculating package fee rates, which keeps the work done on every insertion and removal
+// bounded regardless of how deep chains of transactions in the mempool get.
+const maximumPackageSize = 25
+
+// transactionPackage returns the given transaction together with all its ancestors in the
+// pool, or false if there are more than maximumPackageSize of them
+func transactionPackage(transaction *model.MempoolTransaction) ([]*model.MempoolTransaction, bool) {
+	transactionPackage := []*model.MempoolTransaction{transaction}
+	visited := map[externalapi.DomainTransactionID]struct{}{*transaction.TransactionID(): {}}
+	for i := 0; i < len(transactionPackage); i++ {
+		for parentID, parent := range transactionPackage[i].ParentTransactionsInPool() {
+			if _, ok := visited[parentID]; ok {
+				continue
+			}
+			if len(transactionPackage) == maximumPackageSize {
+				return nil, false
+			}
+			visited[parentID] = struct{}{}
+			transactionPackage = append(transactionPackage, parent)
+		}
+	}
+	return transactionPackage, true
+}
+
+func packageFeeRate(transactionPackage []*model.MempoolTransaction) float64 {
+	fee, mass := uint64(0), uint64(0)
+	for _, transaction := range transactionPackage {
+		fee += transaction.ModifiedFee()
+		mass += transaction.Transaction().Mass
+	}
+	if mass == 0 {
+		return 0
+	}
+	return float64(fee) / float64(mass)
+}
+
+func packageRoots(transactionPackage []*model.MempoolTransaction) []*model.MempoolTransaction {
+	roots := []*model.MempoolTransaction{}
+	for _, transaction := range transactionPackage {
+		if len(transaction.ParentTransactionsInPool()) == 0 {
+			roots = append(roots, transaction)
+		}
+	}
+	return roots
+}
+
+// boundedDescendants returns up to maximumPackageSize of the descendants of the given
+// transaction in the pool, closest first
+func (tp *transactionsPool) boundedDescendants(transaction *model.MempoolTransaction) []*model.MempoolTransaction {
+	descendants := []*model.MempoolTransaction{}
+	visited := map[externalapi.DomainTransactionID]struct{}{}
+	queue := []*model.MempoolTransaction{transaction}
+	for len(queue) > 0 {
+		var current *model.MempoolTransaction
+		current, queue = queue[0], queue[1:]
+		for _, redeemer := range tp.chainedTransactionsByParentID[*current.TransactionID()] {
+			if _, ok := visited[*redeemer.TransactionID()]; ok {
+				continue
+			}
+			if len(descendants) == maximumPackageSize {
+				return descendants
+			}
+			visited[*redeemer.TransactionID()] = struct{}{}
+			descendants = append(descendants, redeemer)
+			queue = append(queue, redeemer)
+		}
+	}
+	return descendants
+}
+
+// updatePackage sets the package of a transaction to the package with the highest fee rate among the
+// packages of its descendants, if it's higher than the fee rate of the transaction itself. This is what
+// lets a child pay for its parents: the parents are selected into block templates, and are evicted from
+// a full pool, at the fee rate of the child and its ancestors together.
+//
+// Only transactions without parents in the pool are block candidates. The child itself can't be mined
+// in the same block as its parents, but once they're mined it becomes a block candidate on its own.
+func (tp *transactionsPool) updatePackage(transaction *model.MempoolTransaction) error {
+	bestFeeRate := transaction.ModifiedFeeRate()
+	var bestRoots []*model.MempoolTransaction
+	for _, descendant := range tp.boundedDescendants(transaction) {
+		descendantPackage, ok := transactionPackage(descendant)
+		if !ok {
+			continue
+		}
+		feeRate := packageFeeRate(descendantPackage)
+		if feeRate > bestFeeRate {
+			bestFeeRate = feeRate
+			bestRoots = packageRoots(descendantPackage)
+		}
+	}
+
+	// The position of the transaction in the frontier and among the transactions ordered by fee rate
+	// depends on its package fee rate, so it's taken out of both while it's updated
+	isInFrontier := tp.blockCandidates.Remove(transaction.TransactionID())
+	err := tp.transactionsOrderedByFeeRate.Remove(transaction)
+	if err != nil {
+		return err
+	}
+	transaction.SetPackage(bestFeeRate, bestRoots)
+	err = tp.transactionsOrderedByFeeRate.Push(transaction)
+	if err != nil {
+		return err
+	}
+	if isInFrontier {
+		tp.blockCandidates.Push(transaction)
+	}
+	return nil
+}
+
+// updatePackages calls updatePackage on the given transactions that are still in the pool
+func (tp *transactionsPool) updatePackages(transactions []*model.MempoolTransaction) error {
+	for _, transaction := range transactions {
+		if _, ok := tp.allTransactions[*transaction.TransactionID()]; !ok {
+			continue
+		}
+		err := tp.updatePackage(transaction)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transactionsAffectedByChangeOf returns the transactions whose package may change once the given
+// transaction is added to or removed from the pool: the transactions in its own package and in the
+// packages of its descendants
+func (tp *transactionsPool) transactionsAffectedByChangeOf(transaction *model.MempoolTransaction) []*model.MempoolTransaction {
+	affected := []*model.MempoolTransaction{}
+	visited := map[externalapi.DomainTransactionID]struct{}{}
+	addPackageOf := func(transaction *model.MempoolTransaction) {
+		transactionPackage, ok := transactionPackage(transaction)
+		if !ok {
+			return
+		}
+		for _, packageTransaction := range transactionPackage {
+			if _, ok := visited[*packageTransaction.TransactionID()]; ok {
+				continue
+			}
+			visited[*packageTransaction.TransactionID()] = struct{}{}
+			affected = append(affected, packageTransaction)
+		}
+	}
+
+	addPackageOf(transaction)
+	for _, descendant := range tp.boundedDescendants(transaction) {
+		addPackageOf(descendant)
+	}
+	return affected
+}
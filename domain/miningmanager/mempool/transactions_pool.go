@@ -8,6 +8,7 @@ import (
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
 	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 )
 
 type transactionsPool struct {
@@ -16,8 +17,17 @@ type transactionsPool struct {
 	highPriorityTransactions      model.IDToTransactionMap
 	chainedTransactionsByParentID model.IDToTransactionsSliceMap
 	transactionsOrderedByFeeRate  model.TransactionsOrderedByFeeRate
+	blockCandidates               *model.Frontier
+	spamBlockCandidates           model.IDToTransactionMap
+	totalMass                     uint64
 	lastExpireScanDAAScore        uint64
 	lastExpireScanTime            time.Time
+
+	// evictedFeeRate is the highest fee rate of a transaction that was evicted since the
+	// pool was last less than half full, and evictedFeeRateTime is when it was set. See
+	// dynamicMinimumFeeRate.
+	evictedFeeRate     float64
+	evictedFeeRateTime time.Time
 }
 
 func newTransactionsPool(mp *mempool) *transactionsPool {
@@ -27,6 +37,8 @@ func newTransactionsPool(mp *mempool) *transactionsPool {
 		highPriorityTransactions:      model.IDToTransactionMap{},
 		chainedTransactionsByParentID: model.IDToTransactionsSliceMap{},
 		transactionsOrderedByFeeRate:  model.TransactionsOrderedByFeeRate{},
+		blockCandidates:               model.NewFrontier(miningmanagermodel.TransactionSelectionAlpha),
+		spamBlockCandidates:           model.IDToTransactionMap{},
 		lastExpireScanDAAScore:        0,
 		lastExpireScanTime:            time.Now(),
 	}
@@ -52,7 +64,10 @@ func (tp *transactionsPool) addTransaction(transaction *externalapi.DomainTransa
 }
 
 func (tp *transactionsPool) addMempoolTransaction(transaction *model.MempoolTransaction) error {
+	transaction.SetFeeDelta(tp.mempool.feeDeltaOf(transaction.TransactionID()))
 	tp.allTransactions[*transaction.TransactionID()] = transaction
+	tp.totalMass += transaction.Transaction().Mass
+	tp.mempool.droppedTransactions.forget(transaction.TransactionID())
 
 	for _, parentTransactionInPool := range transaction.ParentTransactionsInPool() {
 		parentTransactionID := *parentTransactionInPool.TransactionID()
@@ -74,11 +89,23 @@ func (tp *transactionsPool) addMempoolTransaction(transaction *model.MempoolTran
 		tp.highPriorityTransactions[*transaction.TransactionID()] = transaction
 	}
 
+	if len(transaction.ParentTransactionsInPool()) == 0 {
+		err = tp.addBlockCandidate(transaction)
+	} else {
+		err = tp.updatePackages(tp.transactionsAffectedByChangeOf(transaction))
+	}
+	if err != nil {
+		return err
+	}
+
+	tp.mempool.recordChange(transaction, miningmanagermodel.MempoolChangeAdded)
+
 	return nil
 }
 
 func (tp *transactionsPool) removeTransaction(transaction *model.MempoolTransaction) error {
 	delete(tp.allTransactions, *transaction.TransactionID())
+	tp.totalMass -= transaction.Transaction().Mass
 
 	err := tp.transactionsOrderedByFeeRate.Remove(transaction)
 	if err != nil {
@@ -92,6 +119,14 @@ func (tp *transactionsPool) removeTransaction(transaction *model.MempoolTransact
 
 	delete(tp.highPriorityTransactions, *transaction.TransactionID())
 
+	tp.removeBlockCandidate(transaction)
+
+	// Parents that remain in the pool must not find the transaction among their redeemers,
+	// or it would be removed, and reported as removed, once again along with them
+	for parentID := range transaction.ParentTransactionsInPool() {
+		tp.removeChainedTransaction(parentID, transaction)
+	}
+
 	delete(tp.chainedTransactionsByParentID, *transaction.TransactionID())
 
 	return nil
@@ -119,7 +154,8 @@ func (tp *transactionsPool) expireOldTransactions() error {
 		if daaScoreSinceAdded > tp.mempool.config.TransactionExpireIntervalDAAScore {
 			log.Debugf("Removing transaction %s, because it expired. DAAScore moved by %d, expire interval: %d",
 				mempoolTransaction.TransactionID(), daaScoreSinceAdded, tp.mempool.config.TransactionExpireIntervalDAAScore)
-			err = tp.mempool.removeTransaction(mempoolTransaction.TransactionID(), true)
+			err = tp.mempool.removeTransaction(mempoolTransaction.TransactionID(), true,
+				miningmanagermodel.MempoolChangeExpired)
 			if err != nil {
 				return err
 			}
@@ -131,18 +167,6 @@ func (tp *transactionsPool) expireOldTransactions() error {
 	return nil
 }
 
-func (tp *transactionsPool) allReadyTransactions() []*externalapi.DomainTransaction {
-	result := []*externalapi.DomainTransaction{}
-
-	for _, mempoolTransaction := range tp.allTransactions {
-		if len(mempoolTransaction.ParentTransactionsInPool()) == 0 {
-			result = append(result, mempoolTransaction.Transaction().Clone()) //this pointer leaves the mempool, and gets its utxo set to nil, hence we clone.
-		}
-	}
-
-	return result
-}
-
 func (tp *transactionsPool) getParentTransactionsInPool(
 	transaction *externalapi.DomainTransaction) model.IDToTransactionMap {
 
@@ -157,15 +181,33 @@ func (tp *transactionsPool) getParentTransactionsInPool(
 	return parentsTransactionsInPool
 }
 
+func (tp *transactionsPool) removeChainedTransaction(parentID externalapi.DomainTransactionID,
+	transaction *model.MempoolTransaction) {
+
+	chainedTransactions := tp.chainedTransactionsByParentID[parentID]
+	for i, chainedTransaction := range chainedTransactions {
+		if chainedTransaction == transaction {
+			tp.chainedTransactionsByParentID[parentID] = append(chainedTransactions[:i], chainedTransactions[i+1:]...)
+			return
+		}
+	}
+}
+
 func (tp *transactionsPool) getRedeemers(transaction *model.MempoolTransaction) []*model.MempoolTransaction {
 	stack := []*model.MempoolTransaction{transaction}
 	redeemers := []*model.MempoolTransaction{}
+	// A redeemer that spends more than one of the transactions is found more than once
+	visited := map[externalapi.DomainTransactionID]struct{}{}
 	for len(stack) > 0 {
 		var current *model.MempoolTransaction
 		last := len(stack) - 1
 		current, stack = stack[last], stack[:last]
 
 		for _, redeemerTransaction := range tp.chainedTransactionsByParentID[*current.TransactionID()] {
+			if _, ok := visited[*redeemerTransaction.TransactionID()]; ok {
+				continue
+			}
+			visited[*redeemerTransaction.TransactionID()] = struct{}{}
 			stack = append(stack, redeemerTransaction)
 			redeemers = append(redeemers, redeemerTransaction)
 		}
@@ -173,34 +215,45 @@ func (tp *transactionsPool) getRedeemers(transaction *model.MempoolTransaction)
 	return redeemers
 }
 
-func (tp *transactionsPool) limitTransactionCount() error {
+func (tp *transactionsPool) isOverLimits() bool {
+	return uint64(len(tp.allTransactions)) > tp.mempool.config.MaximumTransactionCount ||
+		tp.totalMass > tp.mempool.config.MaximumTotalTransactionMass
+}
+
+// limitTransactionsPoolSize evicts the non-high-priority transactions with the lowest package fee
+// rates, along with their redeemers, until the pool is within both its transaction count and its
+// total mass limits. A parent that a child pays for is evicted at the fee rate of their package, so
+// it outlasts the transactions that pay less than the package. Every eviction raises the dynamic
+// minimum fee rate to the evicted fee rate.
+func (tp *transactionsPool) limitTransactionsPoolSize() error {
 	currentIndex := 0
 
-	for uint64(len(tp.allTransactions)) > tp.mempool.config.MaximumTransactionCount {
-		var transactionToRemove *model.MempoolTransaction
-		for {
-			transactionToRemove = tp.transactionsOrderedByFeeRate.GetByIndex(currentIndex)
-			if !transactionToRemove.IsHighPriority() {
-				break
-			}
+	for tp.isOverLimits() {
+		if currentIndex >= tp.transactionsOrderedByFeeRate.Len() {
+			log.Warnf(
+				"High-priority transactions in mempool (%d transactions with a total mass of %d) exceed "+
+					"the maximum allowed (%d transactions with a total mass of %d)",
+				len(tp.allTransactions), tp.totalMass,
+				tp.mempool.config.MaximumTransactionCount, tp.mempool.config.MaximumTotalTransactionMass)
+			return nil
+		}
+		transactionToRemove := tp.transactionsOrderedByFeeRate.GetByIndex(currentIndex)
+		if transactionToRemove.IsHighPriority() {
 			currentIndex++
-			if currentIndex >= len(tp.allTransactions) {
-				log.Warnf(
-					"Number of high-priority transactions in mempool (%d) is higher than maximum allowed (%d)",
-					len(tp.allTransactions), tp.mempool.config.MaximumTransactionCount)
-				return nil
-			}
+			continue
 		}
 
-		log.Debugf("Removing transaction %s, because mempoolTransaction count (%d) exceeded the limit (%d)",
-			transactionToRemove.TransactionID(), len(tp.allTransactions), tp.mempool.config.MaximumTransactionCount)
-		err := tp.mempool.removeTransaction(transactionToRemove.TransactionID(), true)
+		feeRate := transactionToRemove.PackageFeeRate()
+		log.Debugf("Removing transaction %s with a fee rate of %f, because the mempool (%d transactions "+
+			"with a total mass of %d) exceeded its limits (%d transactions with a total mass of %d)",
+			transactionToRemove.TransactionID(), feeRate, len(tp.allTransactions), tp.totalMass,
+			tp.mempool.config.MaximumTransactionCount, tp.mempool.config.MaximumTotalTransactionMass)
+		err := tp.mempool.removeTransaction(transactionToRemove.TransactionID(), true,
+			miningmanagermodel.MempoolChangeEvicted)
 		if err != nil {
 			return err
 		}
-		if currentIndex >= len(tp.allTransactions) {
-			break
-		}
+		tp.raiseEvictedFeeRate(feeRate)
 	}
 	return nil
 }
@@ -43,6 +43,13 @@ func (mp *mempool) validateAndInsertTransaction(transaction *externalapi.DomainT
 		return nil, err
 	}
 
+	if !isHighPriority {
+		err = mp.checkDynamicMinimumFeeRate(transaction)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	mempoolTransaction, err := mp.transactionsPool.addTransaction(transaction, parentsInPool, isHighPriority)
 	if err != nil {
 		return nil, err
@@ -55,10 +62,15 @@ func (mp *mempool) validateAndInsertTransaction(transaction *externalapi.DomainT
 
 	acceptedTransactions = append([]*externalapi.DomainTransaction{transaction.Clone()}, acceptedOrphans...) //these pointer leave the mempool, hence we clone.
 
-	err = mp.transactionsPool.limitTransactionCount()
+	err = mp.transactionsPool.limitTransactionsPoolSize()
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := mp.transactionsPool.allTransactions[*mempoolTransaction.TransactionID()]; !ok {
+		str := fmt.Sprintf("transaction %s was evicted right away, since the mempool is full and its fee "+
+			"rate is among the lowest in it", mempoolTransaction.TransactionID())
+		return nil, transactionRuleError(RejectInsufficientFee, str)
+	}
 
 	return acceptedTransactions, nil
 }
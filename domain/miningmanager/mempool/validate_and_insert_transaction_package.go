@@ -0,0 +1,211 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+)
+
+// validateAndInsertTransactionPackage inserts a child transaction together with its parents, all or none
+// of them. The transactions are given parents first, with the child last.
+//
+// A package is accepted only if:
+// 1. It has between 2 and maximumPackageSize transactions
+// 2. The child spends an output of every other transaction in the package
+// 3. None of its transactions is an orphan once the transactions before it are in the mempool
+// 4. Every transaction passes the mempool rules on its own, except that the parents may pay less than the
+// minimum relay fee
+// 5. The package as a whole pays at least the minimum relay fee for its total mass, and its fee rate is
+// at least the dynamic minimum fee rate
+//
+// Parents that are already in the transaction pool are skipped, and don't count towards the package fee.
+func (mp *mempool) validateAndInsertTransactionPackage(transactions []*externalapi.DomainTransaction,
+	isHighPriority bool) (acceptedTransactions []*externalapi.DomainTransaction, err error) {
+
+	if len(transactions) == 0 {
+		return nil, transactionRuleError(RejectInvalid, "transaction package is empty")
+	}
+	child := transactions[len(transactions)-1]
+	childID := consensushashing.TransactionID(child)
+
+	onEnd := logger.LogAndMeasureExecutionTime(log,
+		fmt.Sprintf("validateAndInsertTransactionPackage %s", childID))
+	defer onEnd()
+
+	err = checkTransactionPackageStructure(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The dynamic minimum fee rate is taken before any of the package is inserted, since inserting it
+	// may evict transactions and raise it
+	dynamicMinimumFeeRate := mp.transactionsPool.dynamicMinimumFeeRate()
+
+	// A package that's rolled back never entered the transaction pool as far as the mempool change
+	// handler is concerned, so the changes it recorded are discarded along with it
+	pendingChangeCount := len(mp.pendingChanges)
+	insertedTransactions := make([]*model.MempoolTransaction, 0, len(transactions))
+	rollback := func() error {
+		for i := len(insertedTransactions) - 1; i >= 0; i-- {
+			err := mp.removeTransaction(insertedTransactions[i].TransactionID(), false,
+				miningmanagermodel.MempoolChangeInvalid)
+			if err != nil {
+				return err
+			}
+		}
+		mp.discardChangesSince(pendingChangeCount)
+		return nil
+	}
+
+	packageFee, packageMass := uint64(0), uint64(0)
+	for _, transaction := range transactions {
+		transactionID := consensushashing.TransactionID(transaction)
+		isChild := transaction == child
+		if _, ok := mp.transactionsPool.allTransactions[*transactionID]; ok && !isChild {
+			continue
+		}
+
+		mempoolTransaction, err := mp.validateAndInsertPackageTransaction(transaction, isChild, isHighPriority)
+		if err != nil {
+			rollbackErr := rollback()
+			if rollbackErr != nil {
+				return nil, rollbackErr
+			}
+			return nil, err
+		}
+		insertedTransactions = append(insertedTransactions, mempoolTransaction)
+		packageFee += transaction.Fee
+		packageMass += transaction.Mass
+	}
+
+	err = mp.checkTransactionPackageFee(childID, packageFee, packageMass, isHighPriority, dynamicMinimumFeeRate)
+	if err != nil {
+		rollbackErr := rollback()
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+		return nil, err
+	}
+
+	for _, mempoolTransaction := range insertedTransactions {
+		acceptedTransactions = append(acceptedTransactions, mempoolTransaction.Transaction().Clone()) //this pointer leaves the mempool, hence we clone.
+	}
+	for _, mempoolTransaction := range insertedTransactions {
+		acceptedOrphans, err := mp.orphansPool.processOrphansAfterAcceptedTransaction(mempoolTransaction.Transaction())
+		if err != nil {
+			return nil, err
+		}
+		acceptedTransactions = append(acceptedTransactions, acceptedOrphans...)
+	}
+
+	err = mp.transactionsPool.limitTransactionsPoolSize()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := mp.transactionsPool.allTransactions[*childID]; !ok {
+		str := fmt.Sprintf("transaction package of %s was evicted right away, since the mempool is full and its "+
+			"fee rate is among the lowest in it", childID)
+		return nil, transactionRuleError(RejectInsufficientFee, str)
+	}
+
+	return acceptedTransactions, nil
+}
+
+func checkTransactionPackageStructure(transactions []*externalapi.DomainTransaction) error {
+	child := transactions[len(transactions)-1]
+	childID := consensushashing.TransactionID(child)
+	if len(transactions) < 2 || len(transactions) > maximumPackageSize {
+		str := fmt.Sprintf("transaction package of %s has %d transactions, while a package must have between "+
+			"2 and %d transactions", childID, len(transactions), maximumPackageSize)
+		return transactionRuleError(RejectInvalid, str)
+	}
+
+	spentTransactionIDs := make(map[externalapi.DomainTransactionID]struct{}, len(child.Inputs))
+	for _, input := range child.Inputs {
+		spentTransactionIDs[input.PreviousOutpoint.TransactionID] = struct{}{}
+	}
+
+	packageTransactionIDs := make(map[externalapi.DomainTransactionID]struct{}, len(transactions))
+	for _, transaction := range transactions {
+		transactionID := consensushashing.TransactionID(transaction)
+		if _, ok := packageTransactionIDs[*transactionID]; ok {
+			str := fmt.Sprintf("transaction package of %s has transaction %s more than once", childID, transactionID)
+			return transactionRuleError(RejectInvalid, str)
+		}
+		packageTransactionIDs[*transactionID] = struct{}{}
+
+		if transaction == child {
+			continue
+		}
+		if _, ok := spentTransactionIDs[*transactionID]; !ok {
+			str := fmt.Sprintf("transaction package of %s has transaction %s, which is not a parent of %s",
+				childID, transactionID, childID)
+			return transactionRuleError(RejectInvalid, str)
+		}
+	}
+
+	return nil
+}
+
+// validateAndInsertPackageTransaction validates a single transaction of a package and inserts it into the
+// transaction pool, without processing the orphans that spend it
+func (mp *mempool) validateAndInsertPackageTransaction(transaction *externalapi.DomainTransaction,
+	isChild bool, isHighPriority bool) (*model.MempoolTransaction, error) {
+
+	// Populate mass in the beginning, it will be used in multiple places throughout the validation and insertion.
+	mp.consensusReference.Consensus().PopulateMass(transaction)
+
+	err := mp.validateTransactionPreUTXOEntry(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	parentsInPool, missingOutpoints, err := mp.fillInputsAndGetMissingParents(transaction)
+	if err != nil {
+		return nil, err
+	}
+	if len(missingOutpoints) > 0 {
+		str := fmt.Sprintf("transaction %s of the package is an orphan", consensushashing.TransactionID(transaction))
+		return nil, transactionRuleError(RejectBadOrphan, str)
+	}
+
+	err = mp.validateTransactionInContext(transaction)
+	if err != nil {
+		// The relay fee is the last of the checks in context, so a parent that fails only on it
+		// passed all the others. The fee of the package is checked as a whole later on.
+		rejectCode, _ := extractRejectCode(err)
+		if isChild || rejectCode != RejectInsufficientFee {
+			return nil, err
+		}
+	}
+
+	return mp.transactionsPool.addTransaction(transaction, parentsInPool, isHighPriority)
+}
+
+func (mp *mempool) checkTransactionPackageFee(childID *externalapi.DomainTransactionID, packageFee uint64,
+	packageMass uint64, isHighPriority bool, dynamicMinimumFeeRate float64) error {
+
+	if !mp.config.AcceptNonStandard {
+		minimumFee := mp.minimumRequiredTransactionRelayFee(packageMass)
+		if packageFee < minimumFee {
+			str := fmt.Sprintf("transaction package of %s has %d fees which is under the required amount of %d",
+				childID, packageFee, minimumFee)
+			return transactionRuleError(RejectInsufficientFee, str)
+		}
+	}
+
+	if !isHighPriority && dynamicMinimumFeeRate > 0 && packageMass > 0 {
+		feeRate := float64(packageFee) / float64(packageMass)
+		if feeRate < dynamicMinimumFeeRate {
+			str := fmt.Sprintf("transaction package of %s has a fee rate of %f sompi/gram, while the mempool "+
+				"is full and only accepts fee rates of at least %f sompi/gram", childID, feeRate, dynamicMinimumFeeRate)
+			return transactionRuleError(RejectInsufficientFee, str)
+		}
+	}
+
+	return nil
+}
@@ -2,10 +2,10 @@ package mempool
 
 import (
 	"fmt"
-	"github.com/stokesnetwork/stokes/domain/consensus/utils/constants"
 
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	miningmanagermodel "github.com/stokesnetwork/stokes/domain/miningmanager/model"
 )
 
 func (mp *mempool) validateTransactionPreUTXOEntry(transaction *externalapi.DomainTransaction) error {
@@ -27,53 +27,64 @@ func (mp *mempool) validateTransactionInIsolation(transaction *externalapi.Domai
 			fmt.Sprintf("transaction %s is already in the mempool", transactionID))
 	}
 
-	if !mp.config.AcceptNonStandard {
-		if err := mp.checkTransactionStandardInIsolation(transaction); err != nil {
-			// Attempt to extract a reject code from the error so
-			// it can be retained. When not possible, fall back to
-			// a non standard error.
-			rejectCode, found := extractRejectCode(err)
-			if !found {
-				rejectCode = RejectNonstandard
-			}
-			str := fmt.Sprintf("transaction %s is not standard: %s", transactionID, err)
-			return transactionRuleError(rejectCode, str)
-		}
-	}
+	return firstError(mp.nonStandardErrorsInIsolation(transaction))
+}
 
-	return nil
+// nonStandardErrorsInIsolation returns every reason the transaction isn't standard for, on its own,
+// or nothing if non-standard transactions are accepted
+func (mp *mempool) nonStandardErrorsInIsolation(transaction *externalapi.DomainTransaction) []error {
+	if mp.config.AcceptNonStandard {
+		return nil
+	}
+	return nonStandardErrors(mp.transactionStandardInIsolationErrors(transaction),
+		"transaction %s is not standard: %s", consensushashing.TransactionID(transaction))
 }
 
+// validateTransactionInContext validates the transaction against the UTXO entries of its inputs
 func (mp *mempool) validateTransactionInContext(transaction *externalapi.DomainTransaction) error {
-	hasCoinbaseInput := false
-	for _, input := range transaction.Inputs {
-		if input.UTXOEntry.IsCoinbase() {
-			hasCoinbaseInput = true
-			break
-		}
+	err := firstError(mp.nonStandardErrorsInContext(transaction))
+	if err != nil {
+		return err
+	}
+	return mp.checkBlockCandidatePolicy(transaction)
+}
+
+// checkBlockCandidatePolicy rejects a transaction that the BlockCandidatePolicy filters out of block
+// templates, since such a transaction would only take up space in the mempool until it expires.
+// A transaction prioritised by a positive fee delta is never filtered out, so it's never rejected.
+func (mp *mempool) checkBlockCandidatePolicy(transaction *externalapi.DomainTransaction) error {
+	transactionID := consensushashing.TransactionID(transaction)
+	if mp.config.BlockCandidatePolicy == nil || mp.feeDeltaOf(transactionID) > 0 {
+		return nil
 	}
+	kind, reason := mp.config.BlockCandidatePolicy.Classify(transaction)
+	if kind == miningmanagermodel.BlockCandidateFiltered {
+		return transactionRuleError(RejectSpamTx,
+			fmt.Sprintf("transaction %s would never be included in a block template: %s", transactionID, reason))
+	}
+	return nil
+}
 
-	numExtraOuts := len(transaction.Outputs) - len(transaction.Inputs)
-	if !hasCoinbaseInput && numExtraOuts > 2 && transaction.Fee < uint64(numExtraOuts)*constants.SompiPerKaspa {
-		log.Warnf("Rejected spam tx %s from mempool (%d outputs)", consensushashing.TransactionID(transaction), len(transaction.Outputs))
-		return transactionRuleError(RejectSpamTx, fmt.Sprintf("Rejected spam tx %s from mempool", consensushashing.TransactionID(transaction)))
+// nonStandardErrorsInContext returns every reason the inputs or the fee of the transaction aren't
+// standard for, or nothing if non-standard transactions are accepted
+func (mp *mempool) nonStandardErrorsInContext(transaction *externalapi.DomainTransaction) []error {
+	if mp.config.AcceptNonStandard {
+		return nil
 	}
+	return nonStandardErrors(mp.transactionStandardInContextErrors(transaction),
+		"transaction inputs %s are not standard: %s", consensushashing.TransactionID(transaction))
+}
 
-	if !mp.config.AcceptNonStandard {
-		err := mp.checkTransactionStandardInContext(transaction)
-		if err != nil {
-			// Attempt to extract a reject code from the error so
-			// it can be retained. When not possible, fall back to
-			// a non standard error.
-			rejectCode, found := extractRejectCode(err)
-			if !found {
-				rejectCode = RejectNonstandard
-			}
-			str := fmt.Sprintf("transaction inputs %s are not standard: %s",
-				consensushashing.TransactionID(transaction), err)
-			return transactionRuleError(rejectCode, str)
+// nonStandardErrors wraps each of errs in a transaction rule error with the given format, keeping
+// its reject code where one can be extracted, and falling back to a non standard error otherwise
+func nonStandardErrors(errs []error, format string, transactionID *externalapi.DomainTransactionID) []error {
+	ruleErrors := make([]error, len(errs))
+	for i, err := range errs {
+		rejectCode, found := extractRejectCode(err)
+		if !found {
+			rejectCode = RejectNonstandard
 		}
+		ruleErrors[i] = transactionRuleError(rejectCode, fmt.Sprintf(format, transactionID, err))
 	}
-
-	return nil
+	return ruleErrors
 }
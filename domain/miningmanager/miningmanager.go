@@ -32,7 +32,23 @@ type MiningManager interface {
 	HandleNewBlockTransactions(txs []*externalapi.DomainTransaction) ([]*externalapi.DomainTransaction, error)
 	ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 		acceptedTransactions []*externalapi.DomainTransaction, err error)
+	ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+		acceptedTransactions []*externalapi.DomainTransaction, replacedTransaction *externalapi.DomainTransaction, err error)
+	ValidateAndInsertTransactionPackage(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+		acceptedTransactions []*externalapi.DomainTransaction, err error)
 	RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error)
+	GetFeeEstimate() *miningmanagermodel.FeeRateEstimations
+	GetMempoolInfo(feeRateBucketBoundaries []float64) *miningmanagermodel.MempoolInfo
+	GetBlockCandidateStatus(transactionID *externalapi.DomainTransactionID) *miningmanagermodel.BlockCandidateStatus
+	GetDroppedTransaction(transactionID *externalapi.DomainTransactionID) (*miningmanagermodel.DroppedTransaction, bool)
+	GetDroppedTransactions() []*miningmanagermodel.DroppedTransaction
+	PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (int64, error)
+	GetFeeDelta(transactionID *externalapi.DomainTransactionID) int64
+	TestMempoolAccept(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+		[]*miningmanagermodel.MempoolAcceptance, error)
+	SaveMempool(path string) error
+	LoadMempool(path string) (loadedCount int, rejectedCount int, err error)
+	SetOnMempoolChangedHandler(onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler)
 }
 
 type miningManager struct {
@@ -117,6 +133,25 @@ func (mm *miningManager) ValidateAndInsertTransaction(transaction *externalapi.D
 	return mm.mempool.ValidateAndInsertTransaction(transaction, isHighPriority, allowOrphan)
 }
 
+// ValidateAndReplaceTransaction validates the given transaction, and
+// adds it to the set of known transactions in place of the transaction
+// it double-spends
+func (mm *miningManager) ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction,
+	isHighPriority bool) (acceptedTransactions []*externalapi.DomainTransaction,
+	replacedTransaction *externalapi.DomainTransaction, err error) {
+
+	return mm.mempool.ValidateAndReplaceTransaction(transaction, isHighPriority)
+}
+
+// ValidateAndInsertTransactionPackage validates the given child transaction
+// together with its parents, and adds all of them to the set of known
+// transactions that have not yet been added to any block, or none of them
+func (mm *miningManager) ValidateAndInsertTransactionPackage(transactions []*externalapi.DomainTransaction,
+	isHighPriority bool) (acceptedTransactions []*externalapi.DomainTransaction, err error) {
+
+	return mm.mempool.ValidateAndInsertTransactionPackage(transactions, isHighPriority)
+}
+
 func (mm *miningManager) GetTransaction(
 	transactionID *externalapi.DomainTransactionID,
 	includeTransactionPool bool,
@@ -154,3 +189,70 @@ func (mm *miningManager) RevalidateHighPriorityTransactions() (
 
 	return mm.mempool.RevalidateHighPriorityTransactions()
 }
+
+// GetFeeEstimate returns fee rate recommendations based on the current state of the mempool
+func (mm *miningManager) GetFeeEstimate() *miningmanagermodel.FeeRateEstimations {
+	return mm.blockTemplateBuilder.EstimateFeeRates()
+}
+
+// GetMempoolInfo describes the composition of the mempool, with the fee
+// rate histogram split at the given fee rates
+func (mm *miningManager) GetMempoolInfo(feeRateBucketBoundaries []float64) *miningmanagermodel.MempoolInfo {
+	return mm.mempool.MempoolInfo(feeRateBucketBoundaries)
+}
+
+// GetBlockCandidateStatus explains whether the given transaction is
+// included in block templates, and why not if it isn't
+func (mm *miningManager) GetBlockCandidateStatus(transactionID *externalapi.DomainTransactionID) *miningmanagermodel.BlockCandidateStatus {
+	return mm.mempool.BlockCandidateStatus(transactionID)
+}
+
+// GetDroppedTransaction returns the given transaction if it recently
+// expired or was evicted from the mempool
+func (mm *miningManager) GetDroppedTransaction(transactionID *externalapi.DomainTransactionID) (
+	*miningmanagermodel.DroppedTransaction, bool) {
+
+	return mm.mempool.DroppedTransaction(transactionID)
+}
+
+// GetDroppedTransactions returns the transactions that recently expired
+// or were evicted from the mempool, from the oldest to the most recent
+func (mm *miningManager) GetDroppedTransactions() []*miningmanagermodel.DroppedTransaction {
+	return mm.mempool.DroppedTransactions()
+}
+
+// PrioritiseTransaction adds feeDelta to the fee delta of the given
+// transaction, and returns the resulting fee delta. The fee delta changes
+// both the order the transaction is selected into block templates by and
+// the order it's evicted from a full mempool by, and a prioritised
+// transaction is never filtered out by the block candidate policy
+func (mm *miningManager) PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (
+	int64, error) {
+
+	totalFeeDelta, err := mm.mempool.PrioritiseTransaction(transactionID, feeDelta)
+	if err != nil {
+		return 0, err
+	}
+	mm.ClearBlockTemplate()
+	return totalFeeDelta, nil
+}
+
+// GetFeeDelta returns the fee delta of the given transaction, or 0 if it
+// wasn't prioritised
+func (mm *miningManager) GetFeeDelta(transactionID *externalapi.DomainTransactionID) int64 {
+	return mm.mempool.FeeDelta(transactionID)
+}
+
+// TestMempoolAccept checks whether each of the given transactions would be
+// accepted into the mempool, without inserting any of them
+func (mm *miningManager) TestMempoolAccept(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+	[]*miningmanagermodel.MempoolAcceptance, error) {
+
+	return mm.mempool.TestMempoolAccept(transactions, isHighPriority)
+}
+
+// SetOnMempoolChangedHandler sets the handler that's called whenever
+// transactions enter or leave the transaction pool
+func (mm *miningManager) SetOnMempoolChangedHandler(onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler) {
+	mm.mempool.SetOnMempoolChangedHandler(onMempoolChangedHandler)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stokesnetwork/stokes/domain/miningmanager/model"
 	"github.com/stokesnetwork/stokes/util"
 	"github.com/stokesnetwork/stokes/version"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -172,6 +173,173 @@ func TestDoubleSpendInMempool(t *testing.T) {
 	})
 }
 
+// TestTransactionReplacement verifies that a transaction double-spending a mempool transaction replaces it
+// only if it follows the replacement policy.
+func TestTransactionReplacement(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestTransactionReplacement")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumReplacementEvictionCount = 3
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		// Every transaction in the chain pays a fee of 1000 sompi
+		chain, err := createTxChain(tc, 4)
+		if err != nil {
+			t.Fatalf("Error creating transaction chain: %+v", err)
+		}
+		for _, transaction := range chain {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %v", err)
+			}
+		}
+
+		createReplacement := func(transactionToReplace *externalapi.DomainTransaction, feeDelta int64) *externalapi.DomainTransaction {
+			replacement := transactionToReplace.Clone()
+			replacement.ID = nil
+			replacement.Outputs[0].Value = uint64(int64(replacement.Outputs[0].Value) - feeDelta)
+			return replacement
+		}
+		expectRejectCode := func(err error, expectedRejectCode mempool.RejectCode) {
+			txRuleError := &mempool.TxRuleError{}
+			if !errors.As(err, txRuleError) || txRuleError.RejectCode != expectedRejectCode {
+				t.Fatalf("Expected a %s error, but got %+v", expectedRejectCode, err)
+			}
+		}
+
+		nonConflictingTransaction, err := testutils.CreateTransaction(chain[len(chain)-1], 1000)
+		if err != nil {
+			t.Fatalf("Error creating transaction: %+v", err)
+		}
+		_, _, err = miningManager.ValidateAndReplaceTransaction(nonConflictingTransaction, false)
+		expectRejectCode(err, mempool.RejectReplacement)
+
+		// Replacing chain[0] would evict 4 transactions while only 3 are allowed
+		_, _, err = miningManager.ValidateAndReplaceTransaction(createReplacement(chain[0], 10000), false)
+		expectRejectCode(err, mempool.RejectReplacement)
+
+		// A lower fee rate than that of the replaced transaction
+		_, _, err = miningManager.ValidateAndReplaceTransaction(createReplacement(chain[1], -1), false)
+		expectRejectCode(err, mempool.RejectInsufficientFee)
+
+		// A higher fee rate, but a lower fee than the 3000 sompi paid by the 3 evicted transactions
+		_, _, err = miningManager.ValidateAndReplaceTransaction(createReplacement(chain[1], 1000), false)
+		expectRejectCode(err, mempool.RejectInsufficientFee)
+
+		// A higher fee rate, and the same fee as the 3000 sompi paid by the 3 evicted transactions,
+		// but nothing on top of it to pay for relaying the replacement
+		_, _, err = miningManager.ValidateAndReplaceTransaction(createReplacement(chain[1], 2000), false)
+		expectRejectCode(err, mempool.RejectInsufficientFee)
+
+		// A higher fee than the replaced leaf transaction, but by less than the minimum relay fee
+		_, _, err = miningManager.ValidateAndReplaceTransaction(createReplacement(chain[3], 1), false)
+		expectRejectCode(err, mempool.RejectInsufficientFee)
+
+		replacement := createReplacement(chain[1], 5000)
+		acceptedTransactions, replacedTransaction, err := miningManager.ValidateAndReplaceTransaction(replacement, false)
+		if err != nil {
+			t.Fatalf("ValidateAndReplaceTransaction: %v", err)
+		}
+		if !consensushashing.TransactionID(replacedTransaction).Equal(consensushashing.TransactionID(chain[1])) {
+			t.Fatalf("Expected the replaced transaction to be %s, but got %s",
+				consensushashing.TransactionID(chain[1]), consensushashing.TransactionID(replacedTransaction))
+		}
+		if len(acceptedTransactions) != 1 || !contains(replacement, acceptedTransactions) {
+			t.Fatalf("Expected the replacement to be the only accepted transaction")
+		}
+
+		transactionsFromMempool, _ := miningManager.AllTransactions(true, false)
+		if len(transactionsFromMempool) != 2 {
+			t.Fatalf("Expected 2 transactions in the mempool, but got %d", len(transactionsFromMempool))
+		}
+		if !contains(chain[0], transactionsFromMempool) || !contains(replacement, transactionsFromMempool) {
+			t.Fatalf("Expected the mempool to contain the parent of the replaced transaction and the replacement")
+		}
+	})
+}
+
+// TestTransactionReplacementEvictedRightAway verifies that a replacement that the full mempool would evict
+// right away is rejected, and that the transaction it would have replaced is kept.
+func TestTransactionReplacementEvictedRightAway(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestTransactionReplacementEvictedRightAway")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		fundingTransactions := make([]*externalapi.DomainTransaction, 2)
+		for i := range fundingTransactions {
+			fundingTransactions[i], err = createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("Error creating funding transaction: %+v", err)
+			}
+		}
+		transactionToReplace, err := testutils.CreateTransaction(fundingTransactions[0], 1000)
+		if err != nil {
+			t.Fatalf("Error creating transaction: %+v", err)
+		}
+		highFeeTransaction, err := testutils.CreateTransaction(fundingTransactions[1], 1_000_000)
+		if err != nil {
+			t.Fatalf("Error creating transaction: %+v", err)
+		}
+
+		// The replacement pays a higher fee rate than the transaction it replaces, but it has an extra
+		// output, so it doesn't fit in a mempool that is already full, and its fee rate is lower than
+		// that of highFeeTransaction
+		replacement := transactionToReplace.Clone()
+		replacement.ID = nil
+		replacement.Outputs[0].Value -= 10_000
+		extraOutput := *replacement.Outputs[0]
+		extraOutput.Value /= 2
+		replacement.Outputs[0].Value -= extraOutput.Value
+		replacement.Outputs = append(replacement.Outputs, &extraOutput)
+
+		tc.PopulateMass(transactionToReplace)
+		tc.PopulateMass(highFeeTransaction)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTotalTransactionMass = transactionToReplace.Mass + highFeeTransaction.Mass
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		for _, transaction := range []*externalapi.DomainTransaction{transactionToReplace, highFeeTransaction} {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %v", err)
+			}
+		}
+
+		_, _, err = miningManager.ValidateAndReplaceTransaction(replacement, false)
+		txRuleError := &mempool.TxRuleError{}
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectInsufficientFee {
+			t.Fatalf("Expected a %s error, but got %+v", mempool.RejectInsufficientFee, err)
+		}
+
+		transactionsFromMempool, _ := miningManager.AllTransactions(true, false)
+		if len(transactionsFromMempool) != 2 || !contains(transactionToReplace, transactionsFromMempool) ||
+			!contains(highFeeTransaction, transactionsFromMempool) {
+			t.Fatalf("Expected the mempool to be left as it was after rejecting the replacement")
+		}
+	})
+}
+
 // TestHandleNewBlockTransactions verifies that all the transactions in the block were successfully removed from the mempool.
 func TestHandleNewBlockTransactions(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
@@ -379,6 +547,100 @@ func TestOrphanTransactions(t *testing.T) {
 	})
 }
 
+// TestMempoolPersistence verifies that a saved mempool is loaded back into a new mempool with its orphans and
+// high priority transactions, and that loaded transactions go through validation.
+func TestMempoolPersistence(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestMempoolPersistence")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+
+		chain, err := createTxChain(tc, 3)
+		if err != nil {
+			t.Fatalf("createTxChain: %+v", err)
+		}
+		for i, transaction := range chain {
+			isHighPriority := i == 1
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, isHighPriority, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		_, orphanTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("createParentAndChildrenTransactions: %+v", err)
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(orphanTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+
+		path := filepath.Join(t.TempDir(), "mempool.dat")
+		err = miningManager.SaveMempool(path)
+		if err != nil {
+			t.Fatalf("SaveMempool: %+v", err)
+		}
+
+		restartedMiningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params,
+			mempool.DefaultConfig(&consensusConfig.Params))
+		loadedCount, rejectedCount, err := restartedMiningManager.LoadMempool(path)
+		if err != nil {
+			t.Fatalf("LoadMempool: %+v", err)
+		}
+		if loadedCount != len(chain)+1 || rejectedCount != 0 {
+			t.Fatalf("Expected %d transactions to be loaded and none to be rejected, but got %d and %d",
+				len(chain)+1, loadedCount, rejectedCount)
+		}
+
+		transactionPoolTransactions, orphanPoolTransactions := restartedMiningManager.AllTransactions(true, true)
+		if len(transactionPoolTransactions) != len(chain) {
+			t.Fatalf("Expected %d transactions in the transaction pool, but got %d",
+				len(chain), len(transactionPoolTransactions))
+		}
+		for _, transaction := range chain {
+			if !contains(transaction, transactionPoolTransactions) {
+				t.Fatalf("Missing transaction %s in the transaction pool", consensushashing.TransactionID(transaction))
+			}
+		}
+		if len(orphanPoolTransactions) != 1 || !contains(orphanTransaction, orphanPoolTransactions) {
+			t.Fatalf("Expected the orphan pool to contain only transaction %s",
+				consensushashing.TransactionID(orphanTransaction))
+		}
+		highPriorityTransactions, err := restartedMiningManager.RevalidateHighPriorityTransactions()
+		if err != nil {
+			t.Fatalf("RevalidateHighPriorityTransactions: %+v", err)
+		}
+		if len(highPriorityTransactions) != 1 || !contains(chain[1], highPriorityTransactions) {
+			t.Fatalf("Expected only transaction %s to be high priority", consensushashing.TransactionID(chain[1]))
+		}
+
+		// Every transaction is validated again, so loading the same transactions twice rejects all of them
+		loadedCount, rejectedCount, err = restartedMiningManager.LoadMempool(path)
+		if err != nil {
+			t.Fatalf("LoadMempool: %+v", err)
+		}
+		if loadedCount != 0 || rejectedCount != len(chain)+1 {
+			t.Fatalf("Expected all %d transactions to be rejected, but got %d loaded and %d rejected",
+				len(chain)+1, loadedCount, rejectedCount)
+		}
+
+		loadedCount, rejectedCount, err = restartedMiningManager.LoadMempool(filepath.Join(t.TempDir(), "missing.dat"))
+		if err != nil || loadedCount != 0 || rejectedCount != 0 {
+			t.Fatalf("Expected a missing file to load nothing, but got %d, %d, %v", loadedCount, rejectedCount, err)
+		}
+	})
+}
+
 func TestHighPriorityTransactions(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
 		consensusConfig.BlockCoinbaseMaturity = 0
@@ -644,11 +906,13 @@ func TestRevalidateHighPriorityTransactionsWithChain(t *testing.T) {
 }
 
 // TestModifyBlockTemplate verifies that modifying a block template changes coinbase data correctly.
-func TestModifyBlockTemplate(t *testing.T) {
+// TestEvictionByFeeRate verifies that a full mempool evicts the transactions with the lowest package fee rates
+// along with their redeemers, and then rejects transactions that don't pay more than the evicted fee rate.
+func TestEvictionByFeeRate(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
 		consensusConfig.BlockCoinbaseMaturity = 0
 		factory := consensus.NewFactory()
-		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestModifyBlockTemplate")
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestEvictionByFeeRate")
 		if err != nil {
 			t.Fatalf("Error setting up TestConsensus: %+v", err)
 		}
@@ -658,122 +922,1293 @@ func TestModifyBlockTemplate(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
-
-		// Create some complex transactions. Logic taken from TestOrphanTransactions
 
-		// Before each parent transaction, We will add two blocks by consensus in order to fund the parent transactions.
-		parentTransactions, childTransactions, err := createArraysOfParentAndChildrenTransactions(tc)
-		if err != nil {
-			t.Fatalf("Error in createArraysOfParentAndChildrenTransactions: %v", err)
-		}
-		for _, orphanTransaction := range childTransactions {
-			_, err = miningManager.ValidateAndInsertTransaction(orphanTransaction, false, true)
+		createTransaction := func(fee uint64) *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
 			if err != nil {
-				t.Fatalf("ValidateAndInsertTransaction: %v", err)
+				t.Fatalf("createFundingTransaction: %+v", err)
 			}
+			transaction, err := testutils.CreateTransaction(fundingTransaction, fee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			return transaction
 		}
-		transactionsMempool, _ := miningManager.AllTransactions(true, false)
-		for _, transaction := range transactionsMempool {
-			if contains(transaction, childTransactions) {
-				t.Fatalf("Error: an orphan transaction is exist in the mempool")
+		expectInsufficientFee := func(err error) {
+			ruleError := &mempool.RuleError{}
+			if !errors.As(err, ruleError) {
+				t.Fatalf("Expected a RuleError, but got %+v", err)
+			}
+			txRuleError := &mempool.TxRuleError{}
+			if !errors.As(ruleError.Err, txRuleError) || txRuleError.RejectCode != mempool.RejectInsufficientFee {
+				t.Fatalf("Expected the transaction to be rejected for an insufficient fee, but got %+v", err)
 			}
 		}
 
-		emptyCoinbaseData := &externalapi.DomainCoinbaseData{
-			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
-			ExtraData:       nil}
-		block, _, err := miningManager.GetBlockTemplate(emptyCoinbaseData)
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTransactionCount = 3
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		lowFeeRateParent := createTransaction(1000)
+		lowFeeRateChild, err := testutils.CreateTransaction(lowFeeRateParent, 1500)
 		if err != nil {
-			t.Fatalf("Failed get a block template: %v", err)
+			t.Fatalf("CreateTransaction: %+v", err)
 		}
-
-		for _, transactionFromBlock := range block.Transactions[1:] {
-			for _, orphanTransaction := range childTransactions {
-				if consensushashing.TransactionID(transactionFromBlock) == consensushashing.TransactionID(orphanTransaction) {
-					t.Fatalf("Tranasaction with unknown parents is exist in a block that was built from GetTemplate option.")
-				}
+		middleFeeRate := createTransaction(3000)
+		highFeeRate := createTransaction(4000)
+		for _, transaction := range []*externalapi.DomainTransaction{lowFeeRateParent, lowFeeRateChild, middleFeeRate, highFeeRate} {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
 			}
 		}
 
-		// Run the purpose of this test, compare modified block templates
-		sweepCompareModifiedTemplateToBuilt(t, consensusConfig, miningManager.GetBlockTemplateBuilder())
-
-		// Create some more complex blocks and transactions. Logic taken from TestOrphanTransactions
-		tips, err := tc.Tips()
-		if err != nil {
-			t.Fatalf("Tips: %v.", err)
+		// The transaction with the lowest package fee rate is evicted along with its child
+		transactions, _ := miningManager.AllTransactions(true, false)
+		if len(transactions) != 2 || !contains(middleFeeRate, transactions) || !contains(highFeeRate, transactions) {
+			t.Fatalf("Expected only the transactions with the middle and high fee rates to remain in the mempool, "+
+				"but got %v", consensushashing.TransactionIDs(transactions))
 		}
-		blockParentsTransactionsHash, _, err := tc.AddBlock(tips, nil, parentTransactions)
+
+		// Transactions that don't pay more than the evicted fee rate are rejected, even though the mempool has room
+		_, err = miningManager.ValidateAndInsertTransaction(createTransaction(1000), false, false)
+		expectInsufficientFee(err)
+
+		_, err = miningManager.ValidateAndInsertTransaction(createTransaction(6000), false, false)
 		if err != nil {
-			t.Fatalf("AddBlock: %v", err)
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
 		}
 
-		_, _, err = tc.AddBlock([]*externalapi.DomainHash{blockParentsTransactionsHash}, nil, nil)
-		if err != nil {
-			t.Fatalf("AddBlock: %v", err)
+		// A transaction that pays more than the evicted fee rate, but has the lowest fee rate in the full mempool,
+		// is evicted right away
+		_, err = miningManager.ValidateAndInsertTransaction(createTransaction(2000), false, false)
+		expectInsufficientFee(err)
+		if miningManager.TransactionCount(true, false) != 3 {
+			t.Fatalf("Expected the mempool to hold 3 transactions, but it holds %d",
+				miningManager.TransactionCount(true, false))
 		}
 
-		blockParentsTransactions, _, err := tc.GetBlock(blockParentsTransactionsHash)
+		// The mempool is bounded by the total mass of its transactions as well
+		lowFeeRate := createTransaction(2000)
+		tc.PopulateMass(lowFeeRate)
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTotalTransactionMass = 2 * lowFeeRate.Mass
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		middleFeeRate = createTransaction(3000)
+		highFeeRate = createTransaction(4000)
+		for _, transaction := range []*externalapi.DomainTransaction{middleFeeRate, lowFeeRate, highFeeRate} {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		transactions, _ = miningManager.AllTransactions(true, false)
+		if len(transactions) != 2 || contains(lowFeeRate, transactions) {
+			t.Fatalf("Expected the transaction with the lowest fee rate to be evicted, but got %v",
+				consensushashing.TransactionIDs(transactions))
+		}
+
+		// A parent that a child pays for is evicted at the fee rate of their package, so a package submitted to a
+		// full mempool outbids a single transaction that pays more than the parent but less than the package
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTransactionCount = 3
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		middleFeeRate = createTransaction(3000)
+		highFeeRate = createTransaction(4000)
+		for _, transaction := range []*externalapi.DomainTransaction{middleFeeRate, highFeeRate} {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		lowFeeRateParent = createTransaction(1)
+		highFeeRateChild, err := testutils.CreateTransaction(lowFeeRateParent, 9000)
 		if err != nil {
-			t.Fatalf("GetBlock: %v", err)
+			t.Fatalf("CreateTransaction: %+v", err)
 		}
-		_, err = miningManager.HandleNewBlockTransactions(blockParentsTransactions.Transactions)
+		_, err = miningManager.ValidateAndInsertTransactionPackage(
+			[]*externalapi.DomainTransaction{lowFeeRateParent, highFeeRateChild}, false)
 		if err != nil {
-			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+			t.Fatalf("ValidateAndInsertTransactionPackage: %+v", err)
 		}
-		transactionsMempool, _ = miningManager.AllTransactions(true, false)
-		if len(transactionsMempool) != len(childTransactions) {
-			t.Fatalf("Expected %d transactions in the mempool but got %d", len(childTransactions), len(transactionsMempool))
+		transactions, _ = miningManager.AllTransactions(true, false)
+		if len(transactions) != 3 || contains(middleFeeRate, transactions) {
+			t.Fatalf("Expected the package to outbid the transaction with the middle fee rate, but got %v",
+				consensushashing.TransactionIDs(transactions))
 		}
+	})
+}
 
-		for _, transaction := range transactionsMempool {
-			if !contains(transaction, childTransactions) {
-				t.Fatalf("Error: the transaction %s, should be in the mempool since its not "+
-					"oprhan anymore.", consensushashing.TransactionID(transaction))
-			}
-		}
-		block, _, err = miningManager.GetBlockTemplate(emptyCoinbaseData)
+func TestTransactionPackage(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestTransactionPackage")
 		if err != nil {
-			t.Fatalf("GetBlockTemplate: %v", err)
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
 		}
+		defer teardown(false)
 
-		for _, transactionFromBlock := range block.Transactions[1:] {
-			isContained := false
-			for _, childTransaction := range childTransactions {
-				if *consensushashing.TransactionID(transactionFromBlock) == *consensushashing.TransactionID(childTransaction) {
-					isContained = true
-					break
-				}
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params,
+			mempool.DefaultConfig(&consensusConfig.Params))
+
+		createParentAndChild := func(parentFee uint64, childFee uint64) (parent, child *externalapi.DomainTransaction) {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
 			}
-			if !isContained {
-				t.Fatalf("Error: Unknown Transaction %s in a block.", consensushashing.TransactionID(transactionFromBlock))
+			parent, err = testutils.CreateTransaction(fundingTransaction, parentFee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			child, err = testutils.CreateTransaction(parent, childFee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			return parent, child
+		}
+		expectRejectCode := func(err error, expectedRejectCode mempool.RejectCode) {
+			ruleError := &mempool.RuleError{}
+			if !errors.As(err, ruleError) {
+				t.Fatalf("Expected a RuleError, but got %+v", err)
+			}
+			txRuleError := &mempool.TxRuleError{}
+			if !errors.As(ruleError.Err, txRuleError) || txRuleError.RejectCode != expectedRejectCode {
+				t.Fatalf("Expected reject code %s, but got %+v", expectedRejectCode, err)
 			}
 		}
 
-		// Run the purpose of this test, compare modified block templates
-		sweepCompareModifiedTemplateToBuilt(t, consensusConfig, miningManager.GetBlockTemplateBuilder())
+		// A parent that doesn't pay the minimum relay fee is rejected on its own, but accepted together with a
+		// child that pays for both
+		parent, child := createParentAndChild(1, 5000)
+		_, err = miningManager.ValidateAndInsertTransaction(parent.Clone(), false, false)
+		expectRejectCode(err, mempool.RejectInsufficientFee)
 
-		// Create a real coinbase to use
-		coinbaseUsual, err := generateNewCoinbase(consensusConfig.Prefix, opUsual)
+		acceptedTransactions, err := miningManager.ValidateAndInsertTransactionPackage(
+			[]*externalapi.DomainTransaction{parent, child}, false)
 		if err != nil {
-			t.Fatalf("Generate coinbase: %v.", err)
+			t.Fatalf("ValidateAndInsertTransactionPackage: %+v", err)
 		}
-		var emptyTransactions []*externalapi.DomainTransaction
-
-		// Create interesting DAG structures and rerun the template comparisons
-		tips, err = tc.Tips()
-		if err != nil {
-			t.Fatalf("Tips: %v.", err)
+		if len(acceptedTransactions) != 2 || !contains(parent, acceptedTransactions) || !contains(child, acceptedTransactions) {
+			t.Fatalf("Expected the parent and the child to be accepted, but got %v",
+				consensushashing.TransactionIDs(acceptedTransactions))
 		}
-		// Create a fork
-		_, _, err = tc.AddBlock(tips[:1], coinbaseUsual, emptyTransactions)
+
+		// The child can't be mined in the same block as its parent, so only the parent is a block candidate
+		block, _, err := miningManager.GetBlockTemplate(&externalapi.DomainCoinbaseData{
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+			ExtraData:       nil})
 		if err != nil {
-			t.Fatalf("AddBlock: %v", err)
+			t.Fatalf("Failed get a block template: %v", err)
 		}
-		chainTip, _, err := tc.AddBlock(tips[:1], coinbaseUsual, emptyTransactions)
-		if err != nil {
-			t.Fatalf("AddBlock: %v", err)
+		if !contains(parent, block.Transactions) || contains(child, block.Transactions) {
+			t.Fatalf("Expected the block template to include the parent and not the child")
+		}
+
+		// A package is rejected as a whole if the child is rejected, and if the package doesn't pay the minimum
+		// relay fee for its total mass
+		tc.PopulateMass(child)
+		childMinimumFee := child.Mass * uint64(mempool.DefaultConfig(&consensusConfig.Params).MinimumRelayTransactionFee) / 1000
+		for _, childFee := range []uint64{1, childMinimumFee} {
+			parent, child = createParentAndChild(1, childFee)
+			_, err = miningManager.ValidateAndInsertTransactionPackage([]*externalapi.DomainTransaction{parent, child}, false)
+			expectRejectCode(err, mempool.RejectInsufficientFee)
+			_, _, found := miningManager.GetTransaction(consensushashing.TransactionID(parent), true, true)
+			if found {
+				t.Fatalf("Expected the parent of a rejected package to be removed from the mempool")
+			}
+		}
+
+		// A package must consist of a child and its parents
+		unrelatedParent, _ := createParentAndChild(5000, 5000)
+		parent, child = createParentAndChild(1, 5000)
+		_, err = miningManager.ValidateAndInsertTransactionPackage(
+			[]*externalapi.DomainTransaction{unrelatedParent, parent, child}, false)
+		expectRejectCode(err, mempool.RejectInvalid)
+		_, err = miningManager.ValidateAndInsertTransactionPackage([]*externalapi.DomainTransaction{child}, false)
+		expectRejectCode(err, mempool.RejectInvalid)
+	})
+}
+
+func TestMempoolInfo(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestMempoolInfo")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		mempoolInfo := miningManager.GetMempoolInfo([]float64{2})
+		if mempoolInfo.TransactionCount != 0 || mempoolInfo.TotalMass != 0 || mempoolInfo.OldestTransactionDAAScore != 0 {
+			t.Fatalf("Expected an empty mempool, but got %+v", mempoolInfo)
+		}
+		expectedMinimumFeeRate := float64(mempoolConfig.MinimumRelayTransactionFee) / 1000
+		if mempoolInfo.MinimumFeeRate != expectedMinimumFeeRate {
+			t.Fatalf("Expected a minimum fee rate of %f, but got %f", expectedMinimumFeeRate, mempoolInfo.MinimumFeeRate)
+		}
+
+		// Two transactions with a fee rate below 2 sompi/gram, and one above it
+		totalMass := uint64(0)
+		for _, fee := range []uint64{600, 1000, 50_000} {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			transaction, err := testutils.CreateTransaction(fundingTransaction, fee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+			totalMass += transaction.Mass
+		}
+		_, orphan, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("createParentAndChildrenTransactions: %+v", err)
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(orphan, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+
+		mempoolInfo = miningManager.GetMempoolInfo([]float64{2})
+		if mempoolInfo.TransactionCount != 3 || mempoolInfo.OrphanCount != 1 {
+			t.Fatalf("Expected 3 transactions and 1 orphan, but got %d and %d",
+				mempoolInfo.TransactionCount, mempoolInfo.OrphanCount)
+		}
+		if mempoolInfo.TotalMass != totalMass {
+			t.Fatalf("Expected a total mass of %d, but got %d", totalMass, mempoolInfo.TotalMass)
+		}
+		if len(mempoolInfo.FeeRateHistogram) != 2 ||
+			mempoolInfo.FeeRateHistogram[0].MinimumFeeRate != 0 || mempoolInfo.FeeRateHistogram[0].Count != 2 ||
+			mempoolInfo.FeeRateHistogram[1].MinimumFeeRate != 2 || mempoolInfo.FeeRateHistogram[1].Count != 1 {
+			t.Fatalf("Unexpected fee rate histogram %+v", mempoolInfo.FeeRateHistogram)
+		}
+		if mempoolInfo.OldestTransactionDAAScore == 0 {
+			t.Fatalf("Expected the DAA score of the oldest transaction to be set")
+		}
+		if mempoolInfo.SpamTransactionCount != 0 || mempoolInfo.FilteredTransactionCount != 0 {
+			t.Fatalf("Expected no spam transactions, but got %d spam and %d filtered",
+				mempoolInfo.SpamTransactionCount, mempoolInfo.FilteredTransactionCount)
+		}
+	})
+}
+
+func TestMempoolChangeNotifications(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestMempoolChangeNotifications")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+
+		createTransaction := func(fee uint64) *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			transaction, err := testutils.CreateTransaction(fundingTransaction, fee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			return transaction
+		}
+		createDoubleSpend := func(transaction *externalapi.DomainTransaction, feeDelta uint64) *externalapi.DomainTransaction {
+			doubleSpend := transaction.Clone()
+			doubleSpend.ID = nil
+			doubleSpend.Outputs[0].Value -= feeDelta
+			return doubleSpend
+		}
+
+		// Every call to the handler is kept as a separate batch of changes
+		var changeBatches [][]*model.MempoolChange
+		onMempoolChanged := func(changes []*model.MempoolChange) {
+			changeBatches = append(changeBatches, changes)
+		}
+		type expectedChange struct {
+			transaction *externalapi.DomainTransaction
+			reason      model.MempoolChangeReason
+		}
+		expectChanges := func(operation string, expectedChanges ...expectedChange) {
+			if len(changeBatches) != 1 {
+				t.Fatalf("%s: expected a single call to the handler, but got %d", operation, len(changeBatches))
+			}
+			changes := changeBatches[0]
+			changeBatches = nil
+			if len(changes) != len(expectedChanges) {
+				t.Fatalf("%s: expected %d changes, but got %d", operation, len(expectedChanges), len(changes))
+			}
+			for i, change := range changes {
+				expectedID := consensushashing.TransactionID(expectedChanges[i].transaction)
+				changeID := consensushashing.TransactionID(change.Transaction)
+				if !changeID.Equal(expectedID) || change.Reason != expectedChanges[i].reason {
+					t.Fatalf("%s: expected change %d to be %s %s, but got %s %s", operation, i,
+						expectedID, expectedChanges[i].reason, changeID, change.Reason)
+				}
+			}
+		}
+
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTransactionCount = 3
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		miningManager.SetOnMempoolChangedHandler(onMempoolChanged)
+
+		// Every transaction in the chain pays a fee of 1000 sompi
+		chain, err := createTxChain(tc, 2)
+		if err != nil {
+			t.Fatalf("Error creating transaction chain: %+v", err)
+		}
+		for _, transaction := range chain {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+			expectChanges("insertion", expectedChange{transaction, model.MempoolChangeAdded})
+		}
+
+		replacement := createDoubleSpend(chain[1], 2000)
+		_, _, err = miningManager.ValidateAndReplaceTransaction(replacement, false)
+		if err != nil {
+			t.Fatalf("ValidateAndReplaceTransaction: %+v", err)
+		}
+		expectChanges("replacement",
+			expectedChange{chain[1], model.MempoolChangeReplaced},
+			expectedChange{replacement, model.MempoolChangeAdded})
+
+		// A rejected transaction doesn't change the mempool
+		_, err = miningManager.ValidateAndInsertTransaction(chain[1], false, false)
+		if err == nil {
+			t.Fatalf("Expected the replaced transaction to be rejected")
+		}
+		if len(changeBatches) != 0 {
+			t.Fatalf("Expected no changes for a rejected transaction, but got %d batches", len(changeBatches))
+		}
+
+		middleFeeRate := createTransaction(3000)
+		_, err = miningManager.ValidateAndInsertTransaction(middleFeeRate, false, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		expectChanges("insertion", expectedChange{middleFeeRate, model.MempoolChangeAdded})
+
+		// The mempool is full, so the transaction with the lowest package fee rate is evicted along with its child
+		highFeeRate := createTransaction(4000)
+		_, err = miningManager.ValidateAndInsertTransaction(highFeeRate, false, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		expectChanges("eviction",
+			expectedChange{highFeeRate, model.MempoolChangeAdded},
+			expectedChange{chain[0], model.MempoolChangeEvicted},
+			expectedChange{replacement, model.MempoolChangeEvicted})
+
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, middleFeeRate})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		expectChanges("block with a mempool transaction", expectedChange{middleFeeRate, model.MempoolChangeAccepted})
+
+		_, err = miningManager.HandleNewBlockTransactions(
+			[]*externalapi.DomainTransaction{nil, createDoubleSpend(highFeeRate, 1000)})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		expectChanges("block with a double spend", expectedChange{highFeeRate, model.MempoolChangeDoubleSpent})
+
+		// Transactions expire as soon as the virtual DAA score moves on, and the expiry scan runs on every block
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.TransactionExpireIntervalDAAScore = 0
+		mempoolConfig.TransactionExpireScanIntervalDAAScore = 0
+		mempoolConfig.TransactionExpireScanIntervalSeconds = 0
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		miningManager.SetOnMempoolChangedHandler(onMempoolChanged)
+
+		expiringTransaction := createTransaction(1000)
+		_, err = miningManager.ValidateAndInsertTransaction(expiringTransaction, false, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		expectChanges("insertion", expectedChange{expiringTransaction, model.MempoolChangeAdded})
+
+		// Creating a transaction adds blocks, which moves the virtual DAA score on
+		unrelatedTransaction := createTransaction(1000)
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, unrelatedTransaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		expectChanges("expiry scan", expectedChange{expiringTransaction, model.MempoolChangeExpired})
+	})
+}
+
+func TestDroppedTransactions(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestDroppedTransactions")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+
+		createTransaction := func(fee uint64) *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			transaction, err := testutils.CreateTransaction(fundingTransaction, fee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			return transaction
+		}
+		insertTransaction := func(miningManager miningmanager.MiningManager,
+			transaction *externalapi.DomainTransaction, allowOrphan bool) {
+
+			_, err := miningManager.ValidateAndInsertTransaction(transaction, false, allowOrphan)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		expectDropped := func(miningManager miningmanager.MiningManager, transaction *externalapi.DomainTransaction,
+			expectedReason model.MempoolChangeReason, expectedIsOrphan bool, expectedDAAScore uint64) {
+
+			transactionID := consensushashing.TransactionID(transaction)
+			droppedTransaction, ok := miningManager.GetDroppedTransaction(transactionID)
+			if !ok {
+				t.Fatalf("Expected transaction %s to be dropped", transactionID)
+			}
+			if droppedTransaction.Reason != expectedReason || droppedTransaction.IsOrphan != expectedIsOrphan ||
+				droppedTransaction.DroppedAtDAAScore != expectedDAAScore {
+				t.Fatalf("Expected transaction %s to be %s with isOrphan %t at DAA score %d, but it was %s with "+
+					"isOrphan %t at DAA score %d", transactionID, expectedReason, expectedIsOrphan, expectedDAAScore,
+					droppedTransaction.Reason, droppedTransaction.IsOrphan, droppedTransaction.DroppedAtDAAScore)
+			}
+		}
+		expectNotDropped := func(miningManager miningmanager.MiningManager, transaction *externalapi.DomainTransaction) {
+			transactionID := consensushashing.TransactionID(transaction)
+			if _, ok := miningManager.GetDroppedTransaction(transactionID); ok {
+				t.Fatalf("Expected transaction %s not to be dropped", transactionID)
+			}
+		}
+		virtualDAAScore := func() uint64 {
+			daaScore, err := tc.GetVirtualDAAScore()
+			if err != nil {
+				t.Fatalf("GetVirtualDAAScore: %+v", err)
+			}
+			return daaScore
+		}
+
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTransactionCount = 1
+		mempoolConfig.MaximumDroppedTransactionCount = 2
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		// The mempool is full, so each transaction evicts the previous one, which pays a lower fee rate
+		transactions := []*externalapi.DomainTransaction{createTransaction(1000), createTransaction(2000),
+			createTransaction(3000), createTransaction(4000)}
+		insertTransaction(miningManager, transactions[0], false)
+		insertTransaction(miningManager, transactions[1], false)
+		expectDropped(miningManager, transactions[0], model.MempoolChangeEvicted, false, virtualDAAScore())
+		expectNotDropped(miningManager, transactions[1])
+
+		// A dropped transaction that's included in a block is no longer reported
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, transactions[0]})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		expectNotDropped(miningManager, transactions[0])
+
+		// Only the most recently dropped transactions are kept
+		insertTransaction(miningManager, transactions[2], false)
+		droppedTransactions := miningManager.GetDroppedTransactions()
+		if len(droppedTransactions) != 1 ||
+			!droppedTransactions[0].TransactionID.Equal(consensushashing.TransactionID(transactions[1])) {
+			t.Fatalf("Expected only transaction %s to be dropped, but got %d dropped transactions",
+				consensushashing.TransactionID(transactions[1]), len(droppedTransactions))
+		}
+		insertTransaction(miningManager, transactions[3], false)
+		insertTransaction(miningManager, createTransaction(5000), false)
+		droppedTransactions = miningManager.GetDroppedTransactions()
+		if len(droppedTransactions) != 2 ||
+			!droppedTransactions[0].TransactionID.Equal(consensushashing.TransactionID(transactions[2])) ||
+			!droppedTransactions[1].TransactionID.Equal(consensushashing.TransactionID(transactions[3])) {
+			t.Fatalf("Expected transactions %s and %s to be dropped, in that order",
+				consensushashing.TransactionID(transactions[2]), consensushashing.TransactionID(transactions[3]))
+		}
+
+		// Transactions and orphans expire as soon as the virtual DAA score moves on, and the expiry scans run on
+		// every block
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.TransactionExpireIntervalDAAScore = 0
+		mempoolConfig.TransactionExpireScanIntervalDAAScore = 0
+		mempoolConfig.TransactionExpireScanIntervalSeconds = 0
+		mempoolConfig.OrphanExpireIntervalDAAScore = 0
+		mempoolConfig.OrphanExpireScanIntervalDAAScore = 0
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		expiringTransaction := createTransaction(1000)
+		_, expiringOrphan, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("createParentAndChildrenTransactions: %+v", err)
+		}
+		insertTransaction(miningManager, expiringTransaction, false)
+		insertTransaction(miningManager, expiringOrphan, true)
+
+		// Creating a transaction adds blocks, which moves the virtual DAA score on
+		unrelatedTransaction := createTransaction(1000)
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, unrelatedTransaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		expectDropped(miningManager, expiringTransaction, model.MempoolChangeExpired, false, virtualDAAScore())
+		expectDropped(miningManager, expiringOrphan, model.MempoolChangeExpired, true, virtualDAAScore())
+
+		// A dropped transaction that's sent again is no longer reported
+		insertTransaction(miningManager, expiringTransaction, false)
+		expectNotDropped(miningManager, expiringTransaction)
+
+		// Nothing is kept once the cache is disabled
+		mempoolConfig.MaximumDroppedTransactionCount = 0
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		insertTransaction(miningManager, expiringOrphan, true)
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, createTransaction(1000)})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		if len(miningManager.GetDroppedTransactions()) != 0 {
+			t.Fatalf("Expected no dropped transactions once the cache is disabled")
+		}
+	})
+}
+
+func TestPrioritiseTransaction(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestPrioritiseTransaction")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+
+		createTransaction := func(fee uint64) *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			transaction, err := testutils.CreateTransaction(fundingTransaction, fee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			return transaction
+		}
+		insertTransaction := func(miningManager miningmanager.MiningManager, transaction *externalapi.DomainTransaction) {
+			_, err := miningManager.ValidateAndInsertTransaction(transaction, false, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		prioritiseTransaction := func(miningManager miningmanager.MiningManager,
+			transaction *externalapi.DomainTransaction, feeDelta int64, expectedFeeDelta int64) {
+
+			transactionID := consensushashing.TransactionID(transaction)
+			totalFeeDelta, err := miningManager.PrioritiseTransaction(transactionID, feeDelta)
+			if err != nil {
+				t.Fatalf("PrioritiseTransaction: %+v", err)
+			}
+			if totalFeeDelta != expectedFeeDelta || miningManager.GetFeeDelta(transactionID) != expectedFeeDelta {
+				t.Fatalf("Expected the fee delta of transaction %s to be %d, but got %d", transactionID,
+					expectedFeeDelta, totalFeeDelta)
+			}
+		}
+
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTransactionCount = 2
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		// Fee deltas add up, and apply to a transaction that enters the mempool only later
+		lowFeeRate := createTransaction(1000)
+		prioritiseTransaction(miningManager, lowFeeRate, 10_000, 10_000)
+		prioritiseTransaction(miningManager, lowFeeRate, 5000, 15_000)
+		_, err = miningManager.PrioritiseTransaction(consensushashing.TransactionID(lowFeeRate), int64(constants.MaxSompi))
+		if err == nil {
+			t.Fatalf("Expected a fee delta out of range to be rejected")
+		}
+
+		// The fee delta changes the eviction order as well: the prioritised transaction is kept over a
+		// transaction that pays more, but less than its fee delta
+		middleFeeRate := createTransaction(2000)
+		highFeeRate := createTransaction(3000)
+		for _, transaction := range []*externalapi.DomainTransaction{lowFeeRate, middleFeeRate, highFeeRate} {
+			insertTransaction(miningManager, transaction)
+		}
+		transactions, _ := miningManager.AllTransactions(true, false)
+		if len(transactions) != 2 || !contains(lowFeeRate, transactions) || !contains(highFeeRate, transactions) {
+			t.Fatalf("Expected the prioritised transaction to be kept and the middle fee rate one to be evicted, "+
+				"but got %v", consensushashing.TransactionIDs(transactions))
+		}
+		status := miningManager.GetBlockCandidateStatus(consensushashing.TransactionID(lowFeeRate))
+		if !status.IsBlockCandidate || !strings.Contains(status.Reason, "prioritised") {
+			t.Fatalf("Expected the prioritised transaction to be a block candidate, but got %+v", status)
+		}
+
+		// A negative fee delta makes the transaction the first to be evicted
+		prioritiseTransaction(miningManager, lowFeeRate, -16_000, -1000)
+		insertTransaction(miningManager, createTransaction(10_000))
+		transactions, _ = miningManager.AllTransactions(true, false)
+		if contains(lowFeeRate, transactions) {
+			t.Fatalf("Expected the deprioritised transaction to be evicted")
+		}
+
+		// The fee delta is removed once the transaction is included in a block
+		prioritiseTransaction(miningManager, highFeeRate, 1000, 1000)
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, highFeeRate})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		if miningManager.GetFeeDelta(consensushashing.TransactionID(highFeeRate)) != 0 {
+			t.Fatalf("Expected the fee delta to be removed once the transaction is included in a block")
+		}
+
+		// The fee delta bypasses the spam filter as well: a transaction that the block candidate policy filters
+		// out is rejected, unless it's prioritised, in which case it's a regular block candidate
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.BlockCandidatePolicy.(*mempool.ExtraOutputsPolicy).ExemptCoinbaseSpends = false
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		filtered := createTransaction(10_000)
+		output := filtered.Outputs[0]
+		filtered.Outputs = nil
+		for i := 0; i < 4; i++ {
+			filtered.Outputs = append(filtered.Outputs, &externalapi.DomainTransactionOutput{
+				Value:           output.Value / 4,
+				ScriptPublicKey: output.ScriptPublicKey,
+			})
+		}
+		filtered.Fee = 0
+		_, err = miningManager.ValidateAndInsertTransaction(filtered, false, true)
+		txRuleError := &mempool.TxRuleError{}
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectSpamTx {
+			t.Fatalf("Expected a %s error, but got %+v", mempool.RejectSpamTx, err)
+		}
+		prioritiseTransaction(miningManager, filtered, 1, 1)
+		insertTransaction(miningManager, filtered)
+		status = miningManager.GetBlockCandidateStatus(consensushashing.TransactionID(filtered))
+		if !status.IsBlockCandidate || status.Kind != model.BlockCandidateRegular {
+			t.Fatalf("Expected the prioritised transaction to be a regular block candidate, but got %+v", status)
+		}
+		block, _, err := miningManager.GetBlockTemplate(&externalapi.DomainCoinbaseData{
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+		})
+		if err != nil {
+			t.Fatalf("GetBlockTemplate: %+v", err)
+		}
+		if !contains(filtered, block.Transactions) {
+			t.Fatalf("Expected the prioritised transaction to be in the block template")
+		}
+
+		// Only MaximumFeeDeltaCount fee deltas are kept, and the fee delta of a transaction that stays out of
+		// the mempool expires
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumFeeDeltaCount = 2
+		mempoolConfig.FeeDeltaExpireIntervalDAAScore = 0
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		inPool := createTransaction(1000)
+		notInPool := createTransaction(1000)
+		rejected := createTransaction(1000)
+		insertTransaction(miningManager, inPool)
+		prioritiseTransaction(miningManager, inPool, 1000, 1000)
+		prioritiseTransaction(miningManager, notInPool, 1000, 1000)
+		_, err = miningManager.PrioritiseTransaction(consensushashing.TransactionID(rejected), 1000)
+		if err == nil {
+			t.Fatalf("Expected a fee delta beyond MaximumFeeDeltaCount to be rejected")
+		}
+		prioritiseTransaction(miningManager, notInPool, 1000, 2000)
+
+		// Creating a transaction adds blocks, which moves the virtual DAA score on
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, createTransaction(1000)})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		if miningManager.GetFeeDelta(consensushashing.TransactionID(notInPool)) != 0 {
+			t.Fatalf("Expected the fee delta of a transaction that isn't in the mempool to expire")
+		}
+		if miningManager.GetFeeDelta(consensushashing.TransactionID(inPool)) != 1000 {
+			t.Fatalf("Expected the fee delta of a transaction in the mempool to be kept")
+		}
+		prioritiseTransaction(miningManager, rejected, 1000, 1000)
+	})
+}
+
+func TestMempoolAcceptDryRun(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestMempoolAcceptDryRun")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params,
+			mempool.DefaultConfig(&consensusConfig.Params))
+
+		createTransaction := func(parent *externalapi.DomainTransaction, fee uint64) *externalapi.DomainTransaction {
+			transaction, err := testutils.CreateTransaction(parent, fee)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			return transaction
+		}
+		createFunding := func() *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			return fundingTransaction
+		}
+		testMempoolAccept := func(transactions ...*externalapi.DomainTransaction) []*model.MempoolAcceptance {
+			acceptances, err := miningManager.TestMempoolAccept(transactions, false)
+			if err != nil {
+				t.Fatalf("TestMempoolAccept: %+v", err)
+			}
+			if len(acceptances) != len(transactions) {
+				t.Fatalf("Expected %d acceptances, but got %d", len(transactions), len(acceptances))
+			}
+			if miningManager.TransactionCount(true, true) != 1 {
+				t.Fatalf("Expected TestMempoolAccept to leave the mempool unchanged")
+			}
+			return acceptances
+		}
+		expectRejectCodes := func(name string, acceptance *model.MempoolAcceptance, expectedRejectCodes ...string) {
+			if acceptance.IsAccepted || len(acceptance.Rejections) != len(expectedRejectCodes) {
+				t.Fatalf("%s: expected the transaction to be rejected for %v, but got %+v", name,
+					expectedRejectCodes, acceptance)
+			}
+			for i, rejection := range acceptance.Rejections {
+				if rejection.RejectCode != expectedRejectCodes[i] || rejection.Reason == "" {
+					t.Fatalf("%s: expected the transaction to be rejected for %v, but got %s: %s", name,
+						expectedRejectCodes, rejection.RejectCode, rejection.Reason)
+				}
+			}
+		}
+
+		inMempool := createTransaction(createFunding(), 1000)
+		_, err = miningManager.ValidateAndInsertTransaction(inMempool, false, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+
+		// A batch of dependent transactions is accepted, with their mass and fee computed
+		parent := createTransaction(createFunding(), 1000)
+		child := createTransaction(parent, 2000)
+		grandchild := createTransaction(child, 3000)
+		acceptances := testMempoolAccept(parent, child, grandchild)
+		for i, acceptance := range acceptances {
+			expectedFee := uint64(i+1) * 1000
+			if !acceptance.IsAccepted || len(acceptance.Rejections) != 0 || acceptance.Fee != expectedFee ||
+				acceptance.Mass == 0 || acceptance.FeeRate != float64(expectedFee)/float64(acceptance.Mass) {
+				t.Fatalf("Expected transaction #%d to be accepted with a fee of %d, but got %+v", i, expectedFee,
+					acceptance)
+			}
+		}
+		if !acceptances[1].TransactionID.Equal(consensushashing.TransactionID(child)) {
+			t.Fatalf("Expected the acceptances to be in the order of the transactions")
+		}
+		if child.Fee != 0 || child.Inputs[0].UTXOEntry != nil {
+			t.Fatalf("Expected TestMempoolAccept not to populate the given transactions")
+		}
+
+		// A transaction that spends a transaction in the mempool is accepted, while the transaction itself
+		// is a duplicate
+		acceptances = testMempoolAccept(inMempool, createTransaction(inMempool, 1000))
+		expectRejectCodes("in mempool", acceptances[0], "REJECT_DUPLICATE")
+		if !acceptances[1].IsAccepted {
+			t.Fatalf("Expected the child of a transaction in the mempool to be accepted, but got %+v",
+				acceptances[1])
+		}
+
+		// Every reason a transaction is rejected for is reported, and the transactions that depend on a
+		// rejected one are rejected as well
+		lowFeeDoubleSpend := createTransaction(createFunding(), 0)
+		lowFeeDoubleSpend.Inputs[0].PreviousOutpoint = inMempool.Inputs[0].PreviousOutpoint
+		lowFeeDoubleSpendChild := createTransaction(lowFeeDoubleSpend, 1000)
+		funding := createFunding()
+		firstSpend := createTransaction(funding, 1000)
+		secondSpend := createTransaction(funding, 2000)
+		acceptances = testMempoolAccept(lowFeeDoubleSpend, lowFeeDoubleSpendChild, firstSpend, secondSpend)
+		expectRejectCodes("low fee double spend", acceptances[0], "REJECT_DUPLICATE", "REJECT_INSUFFICIENT_FEE")
+		expectRejectCodes("child of rejected", acceptances[1], "REJECT_BAD_ORPHAN")
+		if !strings.Contains(acceptances[1].Rejections[0].Reason, "rejected earlier in the batch") {
+			t.Fatalf("Expected the child to be rejected for its rejected parent, but got: %s",
+				acceptances[1].Rejections[0].Reason)
+		}
+		if !acceptances[2].IsAccepted {
+			t.Fatalf("Expected the first spend to be accepted, but got %+v", acceptances[2])
+		}
+		expectRejectCodes("second spend", acceptances[3], "REJECT_DUPLICATE")
+
+		// Every way a transaction isn't standard is reported, rather than only the first one
+		nonStandard := createTransaction(createFunding(), 0)
+		output := nonStandard.Outputs[0]
+		nonStandard.Outputs = []*externalapi.DomainTransactionOutput{
+			{Value: 1, ScriptPublicKey: output.ScriptPublicKey},
+			{Value: 1, ScriptPublicKey: output.ScriptPublicKey},
+			{Value: output.Value - 2, ScriptPublicKey: output.ScriptPublicKey},
+		}
+		acceptances = testMempoolAccept(nonStandard)
+		expectRejectCodes("non standard", acceptances[0], "REJECT_DUST", "REJECT_DUST", "REJECT_INSUFFICIENT_FEE")
+
+		// A transaction that the block candidate policy filters out of block templates is rejected as spam
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.BlockCandidatePolicy.(*mempool.ExtraOutputsPolicy).ExemptCoinbaseSpends = false
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		_, err = miningManager.ValidateAndInsertTransaction(createTransaction(createFunding(), 1000), false, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		filtered := createTransaction(createFunding(), 10_000)
+		output = filtered.Outputs[0]
+		filtered.Outputs = nil
+		for i := 0; i < 4; i++ {
+			filtered.Outputs = append(filtered.Outputs, &externalapi.DomainTransactionOutput{
+				Value:           output.Value / 4,
+				ScriptPublicKey: output.ScriptPublicKey,
+			})
+		}
+		acceptances = testMempoolAccept(filtered)
+		expectRejectCodes("filtered", acceptances[0], "REJECT_SPAM_TX")
+
+		// In a full mempool, a transaction is rejected if it would be evicted right away, same as it is when
+		// it's inserted
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumTransactionCount = 1
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		_, err = miningManager.ValidateAndInsertTransaction(createTransaction(createFunding(), 2000), false, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		lowFeeRate := createTransaction(createFunding(), 1000)
+		highFeeRate := createTransaction(createFunding(), 3000)
+		acceptances = testMempoolAccept(lowFeeRate, highFeeRate)
+		expectRejectCodes("evicted right away", acceptances[0], "REJECT_INSUFFICIENT_FEE")
+		if !strings.Contains(acceptances[0].Rejections[0].Reason, "evicted right away") {
+			t.Fatalf("Expected the transaction to be rejected for being evicted right away, but got: %s",
+				acceptances[0].Rejections[0].Reason)
+		}
+		if !acceptances[1].IsAccepted {
+			t.Fatalf("Expected a transaction that pays more than the mempool to be accepted, but got %+v",
+				acceptances[1])
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(lowFeeRate, false, false)
+		if err == nil || !strings.Contains(err.Error(), "evicted right away") {
+			t.Fatalf("Expected the transaction to be evicted right away when inserted, but got: %v", err)
+		}
+
+		tooManyTransactions := make([]*externalapi.DomainTransaction, mempool.MaximumTestMempoolAcceptCount+1)
+		_, err = miningManager.TestMempoolAccept(tooManyTransactions, false)
+		if err == nil {
+			t.Fatalf("Expected too many transactions to be rejected")
+		}
+	})
+}
+
+func TestBlockCandidateStatus(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestBlockCandidateStatus")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+
+		// All the transactions below spend coinbase outputs, so the policy mustn't exempt them
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.BlockCandidatePolicy = &mempool.ExtraOutputsPolicy{
+			FeePerExtraOutput:         constants.SompiPerKaspa,
+			MaximumUnpaidExtraOutputs: 2,
+			ExemptCoinbaseSpends:      false,
+		}
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+
+		createTransaction := func(numOutputs int) *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			transaction, err := testutils.CreateTransaction(fundingTransaction, 10_000)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			output := transaction.Outputs[0]
+			outputValue := output.Value / uint64(numOutputs)
+			transaction.Outputs = nil
+			for i := 0; i < numOutputs; i++ {
+				transaction.Outputs = append(transaction.Outputs, &externalapi.DomainTransactionOutput{
+					Value:           outputValue,
+					ScriptPublicKey: output.ScriptPublicKey,
+				})
+			}
+			transaction.Fee = 0
+			return transaction
+		}
+		insertTransaction := func(miningManager miningmanager.MiningManager, transaction *externalapi.DomainTransaction) {
+			_, err := miningManager.ValidateAndInsertTransaction(transaction, false, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		expectStatus := func(name string, transaction *externalapi.DomainTransaction, isBlockCandidate bool,
+			expectedKind model.BlockCandidateKind) *model.BlockCandidateStatus {
+
+			status := miningManager.GetBlockCandidateStatus(consensushashing.TransactionID(transaction))
+			if !status.IsInTransactionPool || status.HasParentsInMempool || status.IsBlockCandidate != isBlockCandidate ||
+				status.Kind != expectedKind {
+				t.Fatalf("%s: unexpected status %+v", name, status)
+			}
+			if status.Reason == "" {
+				t.Fatalf("%s: expected a reason", name)
+			}
+			return status
+		}
+
+		regular := createTransaction(1)
+		olderSpam := createTransaction(3)
+		newerSpam := createTransaction(3)
+		filtered := createTransaction(4)
+		for _, transaction := range []*externalapi.DomainTransaction{regular, olderSpam, newerSpam} {
+			insertTransaction(miningManager, transaction)
+		}
+		// A filtered transaction is rejected, so it's in the mempool only if it was admitted with a fee delta
+		// that it lost afterwards
+		_, err = miningManager.ValidateAndInsertTransaction(filtered, false, true)
+		txRuleError := &mempool.TxRuleError{}
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectSpamTx {
+			t.Fatalf("Expected a %s error, but got %+v", mempool.RejectSpamTx, err)
+		}
+		for _, feeDelta := range []int64{1, -1} {
+			_, err = miningManager.PrioritiseTransaction(consensushashing.TransactionID(filtered), feeDelta)
+			if err != nil {
+				t.Fatalf("PrioritiseTransaction: %+v", err)
+			}
+			if feeDelta > 0 {
+				insertTransaction(miningManager, filtered)
+			}
+		}
+		child, err := testutils.CreateTransaction(regular, 10_000)
+		if err != nil {
+			t.Fatalf("CreateTransaction: %+v", err)
+		}
+		insertTransaction(miningManager, child)
+		_, orphan, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("createParentAndChildrenTransactions: %+v", err)
+		}
+		insertTransaction(miningManager, orphan)
+
+		expectStatus("regular", regular, true, model.BlockCandidateRegular)
+		// Only the spam transaction that spends the oldest UTXOs is a block candidate
+		expectStatus("older spam", olderSpam, true, model.BlockCandidateSpam)
+		newerSpamStatus := expectStatus("newer spam", newerSpam, false, model.BlockCandidateSpam)
+		if !strings.Contains(newerSpamStatus.Reason, consensushashing.TransactionID(olderSpam).String()) {
+			t.Fatalf("Expected the reason of the newer spam transaction to name the older one, but got: %s",
+				newerSpamStatus.Reason)
+		}
+		expectStatus("filtered", filtered, false, model.BlockCandidateFiltered)
+
+		childStatus := miningManager.GetBlockCandidateStatus(consensushashing.TransactionID(child))
+		if !childStatus.IsInTransactionPool || !childStatus.HasParentsInMempool || childStatus.IsBlockCandidate {
+			t.Fatalf("Unexpected status of a transaction with parents in the mempool: %+v", childStatus)
+		}
+		orphanStatus := miningManager.GetBlockCandidateStatus(consensushashing.TransactionID(orphan))
+		if orphanStatus.IsInTransactionPool || !orphanStatus.IsOrphan || orphanStatus.IsBlockCandidate {
+			t.Fatalf("Unexpected status of an orphan: %+v", orphanStatus)
+		}
+		missingStatus := miningManager.GetBlockCandidateStatus(&externalapi.DomainTransactionID{})
+		if missingStatus.IsInTransactionPool || missingStatus.IsOrphan || missingStatus.Reason == "" {
+			t.Fatalf("Unexpected status of a transaction that isn't in the mempool: %+v", missingStatus)
+		}
+
+		mempoolInfo := miningManager.GetMempoolInfo(nil)
+		if mempoolInfo.SpamTransactionCount != 2 || mempoolInfo.FilteredTransactionCount != 1 {
+			t.Fatalf("Expected 2 spam transactions and 1 filtered, but got %d and %d",
+				mempoolInfo.SpamTransactionCount, mempoolInfo.FilteredTransactionCount)
+		}
+
+		// With the spam filter disabled, every transaction without parents in the mempool is a regular block
+		// candidate
+		mempoolConfig = mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.BlockCandidatePolicy.(*mempool.ExtraOutputsPolicy).FeePerExtraOutput = 0
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		for _, transaction := range []*externalapi.DomainTransaction{olderSpam, newerSpam, filtered} {
+			insertTransaction(miningManager, transaction)
+			expectStatus("spam filter disabled", transaction, true, model.BlockCandidateRegular)
+		}
+	})
+}
+
+// TestBatchPayoutAdmission verifies that a transaction that creates more outputs than it spends is rejected if the
+// BlockCandidatePolicy would filter it out of block templates, and that it's admitted and included in block templates
+// once the policy lets it through.
+func TestBatchPayoutAdmission(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestBatchPayoutAdmission")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+
+		// The payout spends an output of a transaction in the DAG rather than a coinbase output, and pays far less
+		// than SompiPerKaspa for each of its extra outputs
+		createBatchPayout := func(numOutputs int) *externalapi.DomainTransaction {
+			fundingTransaction, err := createFundingTransaction(tc)
+			if err != nil {
+				t.Fatalf("createFundingTransaction: %+v", err)
+			}
+			parentTransaction, err := testutils.CreateTransaction(fundingTransaction, 1000)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			tips, err := tc.Tips()
+			if err != nil {
+				t.Fatalf("Tips: %+v", err)
+			}
+			_, _, err = tc.AddBlock(tips, nil, []*externalapi.DomainTransaction{parentTransaction})
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+
+			payout, err := testutils.CreateTransaction(parentTransaction, 10_000)
+			if err != nil {
+				t.Fatalf("CreateTransaction: %+v", err)
+			}
+			output := payout.Outputs[0]
+			outputValue := output.Value / uint64(numOutputs)
+			payout.Outputs = nil
+			for i := 0; i < numOutputs; i++ {
+				payout.Outputs = append(payout.Outputs, &externalapi.DomainTransactionOutput{
+					Value:           outputValue,
+					ScriptPublicKey: output.ScriptPublicKey,
+				})
+			}
+			payout.Fee = 0
+			return payout
+		}
+
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params,
+			mempool.DefaultConfig(&consensusConfig.Params))
+		filteredPayout := createBatchPayout(10)
+		_, err = miningManager.ValidateAndInsertTransaction(filteredPayout, false, true)
+		txRuleError := &mempool.TxRuleError{}
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectSpamTx {
+			t.Fatalf("Expected a %s error, but got %+v", mempool.RejectSpamTx, err)
+		}
+		if !strings.Contains(err.Error(), "creates 9 more outputs than it spends") {
+			t.Fatalf("Expected the error to give the reason the policy filters the payout for, but got: %s", err)
+		}
+		status := miningManager.GetBlockCandidateStatus(consensushashing.TransactionID(filteredPayout))
+		if status.IsInTransactionPool || status.IsOrphan {
+			t.Fatalf("Expected the rejected payout to be out of the mempool, but got %+v", status)
+		}
+
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.BlockCandidatePolicy.(*mempool.ExtraOutputsPolicy).FeePerExtraOutput = 0
+		miningManager = miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		payout := createBatchPayout(10)
+		_, err = miningManager.ValidateAndInsertTransaction(payout, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		block, _, err := miningManager.GetBlockTemplate(&externalapi.DomainCoinbaseData{
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+			ExtraData:       nil})
+		if err != nil {
+			t.Fatalf("GetBlockTemplate: %+v", err)
+		}
+		if !contains(payout, block.Transactions) {
+			t.Fatalf("Expected the payout to be included in the block template once the spam filter is disabled")
+		}
+	})
+}
+
+func TestModifyBlockTemplate(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestModifyBlockTemplate")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+
+		// Create some complex transactions. Logic taken from TestOrphanTransactions
+
+		// Before each parent transaction, We will add two blocks by consensus in order to fund the parent transactions.
+		parentTransactions, childTransactions, err := createArraysOfParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error in createArraysOfParentAndChildrenTransactions: %v", err)
+		}
+		for _, orphanTransaction := range childTransactions {
+			_, err = miningManager.ValidateAndInsertTransaction(orphanTransaction, false, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %v", err)
+			}
+		}
+		transactionsMempool, _ := miningManager.AllTransactions(true, false)
+		for _, transaction := range transactionsMempool {
+			if contains(transaction, childTransactions) {
+				t.Fatalf("Error: an orphan transaction is exist in the mempool")
+			}
+		}
+
+		emptyCoinbaseData := &externalapi.DomainCoinbaseData{
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+			ExtraData:       nil}
+		block, _, err := miningManager.GetBlockTemplate(emptyCoinbaseData)
+		if err != nil {
+			t.Fatalf("Failed get a block template: %v", err)
+		}
+
+		for _, transactionFromBlock := range block.Transactions[1:] {
+			for _, orphanTransaction := range childTransactions {
+				if consensushashing.TransactionID(transactionFromBlock) == consensushashing.TransactionID(orphanTransaction) {
+					t.Fatalf("Tranasaction with unknown parents is exist in a block that was built from GetTemplate option.")
+				}
+			}
+		}
+
+		// Run the purpose of this test, compare modified block templates
+		sweepCompareModifiedTemplateToBuilt(t, consensusConfig, miningManager.GetBlockTemplateBuilder())
+
+		// Create some more complex blocks and transactions. Logic taken from TestOrphanTransactions
+		tips, err := tc.Tips()
+		if err != nil {
+			t.Fatalf("Tips: %v.", err)
+		}
+		blockParentsTransactionsHash, _, err := tc.AddBlock(tips, nil, parentTransactions)
+		if err != nil {
+			t.Fatalf("AddBlock: %v", err)
+		}
+
+		_, _, err = tc.AddBlock([]*externalapi.DomainHash{blockParentsTransactionsHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %v", err)
+		}
+
+		blockParentsTransactions, _, err := tc.GetBlock(blockParentsTransactionsHash)
+		if err != nil {
+			t.Fatalf("GetBlock: %v", err)
+		}
+		_, err = miningManager.HandleNewBlockTransactions(blockParentsTransactions.Transactions)
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		transactionsMempool, _ = miningManager.AllTransactions(true, false)
+		if len(transactionsMempool) != len(childTransactions) {
+			t.Fatalf("Expected %d transactions in the mempool but got %d", len(childTransactions), len(transactionsMempool))
+		}
+
+		for _, transaction := range transactionsMempool {
+			if !contains(transaction, childTransactions) {
+				t.Fatalf("Error: the transaction %s, should be in the mempool since its not "+
+					"oprhan anymore.", consensushashing.TransactionID(transaction))
+			}
+		}
+		block, _, err = miningManager.GetBlockTemplate(emptyCoinbaseData)
+		if err != nil {
+			t.Fatalf("GetBlockTemplate: %v", err)
+		}
+
+		for _, transactionFromBlock := range block.Transactions[1:] {
+			isContained := false
+			for _, childTransaction := range childTransactions {
+				if *consensushashing.TransactionID(transactionFromBlock) == *consensushashing.TransactionID(childTransaction) {
+					isContained = true
+					break
+				}
+			}
+			if !isContained {
+				t.Fatalf("Error: Unknown Transaction %s in a block.", consensushashing.TransactionID(transactionFromBlock))
+			}
+		}
+
+		// Run the purpose of this test, compare modified block templates
+		sweepCompareModifiedTemplateToBuilt(t, consensusConfig, miningManager.GetBlockTemplateBuilder())
+
+		// Create a real coinbase to use
+		coinbaseUsual, err := generateNewCoinbase(consensusConfig.Prefix, opUsual)
+		if err != nil {
+			t.Fatalf("Generate coinbase: %v.", err)
+		}
+		var emptyTransactions []*externalapi.DomainTransaction
+
+		// Create interesting DAG structures and rerun the template comparisons
+		tips, err = tc.Tips()
+		if err != nil {
+			t.Fatalf("Tips: %v.", err)
+		}
+		// Create a fork
+		_, _, err = tc.AddBlock(tips[:1], coinbaseUsual, emptyTransactions)
+		if err != nil {
+			t.Fatalf("AddBlock: %v", err)
+		}
+		chainTip, _, err := tc.AddBlock(tips[:1], coinbaseUsual, emptyTransactions)
+		if err != nil {
+			t.Fatalf("AddBlock: %v", err)
 		}
 
 		sweepCompareModifiedTemplateToBuilt(t, consensusConfig, miningManager.GetBlockTemplateBuilder())
@@ -978,7 +2413,7 @@ func createParentAndChildrenTransactions(tc testapi.TestConsensus) (txParent *ex
 	return chain[0], chain[1], nil
 }
 
-func createTxChain(tc testapi.TestConsensus, numTxs int) ([]*externalapi.DomainTransaction, error) {
+func createFundingTransaction(tc testapi.TestConsensus) (*externalapi.DomainTransaction, error) {
 	// We will add two blocks by consensus before the parent transactions, in order to fund the parent transactions.
 	tips, err := tc.Tips()
 	if err != nil {
@@ -1004,6 +2439,14 @@ func createTxChain(tc testapi.TestConsensus, numTxs int) ([]*externalapi.DomainT
 		return nil, errors.Wrap(err, "GetBlock: ")
 	}
 	fundingTransactionForParent := fundingBlockForParent.Transactions[transactionhelper.CoinbaseTransactionIndex]
+	return fundingTransactionForParent, nil
+}
+
+func createTxChain(tc testapi.TestConsensus, numTxs int) ([]*externalapi.DomainTransaction, error) {
+	fundingTransactionForParent, err := createFundingTransaction(tc)
+	if err != nil {
+		return nil, err
+	}
 
 	transactions := make([]*externalapi.DomainTransaction, numTxs)
 	transactions[0], err = testutils.CreateTransaction(fundingTransactionForParent, 1000)
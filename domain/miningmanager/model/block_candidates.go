@@ -0,0 +1,69 @@
+package model
+
+import "fmt"
+
+// TransactionSelectionAlpha is the exponent of the fee rate in the weight of a block
+// candidate transaction. When a block template is built, every transaction is drawn with
+// probability proportional to feeRate^TransactionSelectionAlpha. A smaller alpha makes
+// the distribution more uniform.
+const TransactionSelectionAlpha = 3
+
+// BlockCandidatesSummary summarizes the block candidate transactions in the mempool
+type BlockCandidatesSummary struct {
+	Count int
+
+	// TotalMass is the sum of the masses of the candidates
+	TotalMass uint64
+
+	// TotalWeight is the sum of feeRate^TransactionSelectionAlpha over the candidates
+	TotalWeight float64
+}
+
+// BlockCandidateKind is how block templates treat a transaction that has no parents in the mempool
+type BlockCandidateKind int
+
+const (
+	// BlockCandidateRegular transactions are always block candidates
+	BlockCandidateRegular BlockCandidateKind = iota
+
+	// BlockCandidateSpam transactions create outputs without paying for them. Only one of
+	// them, the one spending the oldest UTXOs, is a block candidate at any given time.
+	BlockCandidateSpam
+
+	// BlockCandidateFiltered transactions are never block candidates
+	BlockCandidateFiltered
+)
+
+var blockCandidateKindStrings = map[BlockCandidateKind]string{
+	BlockCandidateRegular:  "regular",
+	BlockCandidateSpam:     "spam",
+	BlockCandidateFiltered: "filtered",
+}
+
+func (kind BlockCandidateKind) String() string {
+	kindString, ok := blockCandidateKindStrings[kind]
+	if !ok {
+		return fmt.Sprintf("unknown(%d)", int(kind))
+	}
+	return kindString
+}
+
+// BlockCandidateStatus explains whether a transaction is a block candidate, and why not
+// if it isn't
+type BlockCandidateStatus struct {
+	IsInTransactionPool bool
+	IsOrphan            bool
+	IsBlockCandidate    bool
+
+	// HasParentsInMempool is set for transactions in the transaction pool that spend outputs
+	// of other transactions in the mempool. They can't be included in the same block as
+	// their parents.
+	HasParentsInMempool bool
+
+	// Kind is meaningful only for transactions in the transaction pool that have no parents
+	// in the mempool
+	Kind BlockCandidateKind
+
+	// Reason is a human-readable explanation of the status
+	Reason string
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+// DroppedTransaction is a transaction that recently left the mempool without being included in a
+// block, kept so that its sender can tell it apart from a transaction the node never saw
+type DroppedTransaction struct {
+	TransactionID *externalapi.DomainTransactionID
+
+	// Reason is either MempoolChangeExpired or MempoolChangeEvicted
+	Reason MempoolChangeReason
+
+	// IsOrphan is set if the transaction was dropped from the orphan pool
+	IsOrphan bool
+
+	Fee               uint64
+	Mass              uint64
+	DroppedAt         time.Time
+	DroppedAtDAAScore uint64
+}
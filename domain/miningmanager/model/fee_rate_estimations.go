@@ -0,0 +1,25 @@
+package model
+
+// FeeRateBucket is a single point on the fee-rate-to-time curve: paying
+// FeeRate sompi per gram of mass is expected to get a transaction included
+// in the DAG within EstimatedSeconds
+type FeeRateBucket struct {
+	FeeRate          float64
+	EstimatedSeconds float64
+}
+
+// FeeRateEstimations is a set of fee rate recommendations, ordered from the
+// fastest (and most expensive) bucket to the slowest (and cheapest) one
+type FeeRateEstimations struct {
+	// PriorityBucket is the fee rate required for inclusion within the next
+	// couple of blocks
+	PriorityBucket FeeRateBucket
+
+	// NormalBuckets are fee rates for sub-minute inclusion. The first bucket
+	// is always present
+	NormalBuckets []FeeRateBucket
+
+	// LowBuckets are fee rates for sub-hour inclusion. The first bucket is
+	// always present
+	LowBuckets []FeeRateBucket
+}
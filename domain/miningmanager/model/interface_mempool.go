@@ -1,6 +1,8 @@
 package model
 
 import (
+	"io"
+
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"github.com/stokesnetwork/stokes/domain/consensus/ruleerrors"
 )
@@ -10,8 +12,22 @@ import (
 type Mempool interface {
 	HandleNewBlockTransactions(txs []*externalapi.DomainTransaction) ([]*externalapi.DomainTransaction, error)
 	BlockCandidateTransactions() []*externalapi.DomainTransaction
+	SampleBlockCandidateTransactions(blockMaxMass uint64) []*externalapi.DomainTransaction
+	BlockCandidatesSummary() *BlockCandidatesSummary
+	MempoolInfo(feeRateBucketBoundaries []float64) *MempoolInfo
+	BlockCandidateStatus(transactionID *externalapi.DomainTransactionID) *BlockCandidateStatus
+	DroppedTransaction(transactionID *externalapi.DomainTransactionID) (*DroppedTransaction, bool)
+	DroppedTransactions() []*DroppedTransaction
+	PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (int64, error)
+	FeeDelta(transactionID *externalapi.DomainTransactionID) int64
+	TestMempoolAccept(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+		[]*MempoolAcceptance, error)
 	ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 		acceptedTransactions []*externalapi.DomainTransaction, err error)
+	ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+		acceptedTransactions []*externalapi.DomainTransaction, replacedTransaction *externalapi.DomainTransaction, err error)
+	ValidateAndInsertTransactionPackage(transactions []*externalapi.DomainTransaction, isHighPriority bool) (
+		acceptedTransactions []*externalapi.DomainTransaction, err error)
 	RemoveInvalidTransactions(err *ruleerrors.ErrInvalidTransactionsInNewBlock) error
 	GetTransaction(
 		transactionID *externalapi.DomainTransactionID,
@@ -40,4 +56,7 @@ type Mempool interface {
 		includeOrphanPool bool) int
 	RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error)
 	IsTransactionOutputDust(output *externalapi.DomainTransactionOutput) bool
+	SaveTransactions(writer io.Writer) error
+	LoadTransactions(reader io.Reader) (loadedCount int, rejectedCount int, err error)
+	SetOnMempoolChangedHandler(onMempoolChangedHandler OnMempoolChangedHandler)
 }
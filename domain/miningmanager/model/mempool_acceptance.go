@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+// MempoolAcceptance is the outcome of checking whether the mempool would accept a
+// transaction, without inserting it
+type MempoolAcceptance struct {
+	TransactionID *externalapi.DomainTransactionID
+	IsAccepted    bool
+
+	// Mass, Fee and FeeRate are set only if the transaction passed the consensus
+	// checks, since its fee can't be computed otherwise
+	Mass    uint64
+	Fee     uint64
+	FeeRate float64
+
+	Rejections []*MempoolRejection
+}
+
+// MempoolRejection is a single reason for a transaction not to be accepted
+type MempoolRejection struct {
+	// RejectCode is the name of the mempool reject code, such as REJECT_INSUFFICIENT_FEE
+	RejectCode string
+	Reason     string
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+// MempoolChangeReason is the reason a transaction entered or left the transaction pool
+type MempoolChangeReason int
+
+const (
+	// MempoolChangeAdded means the transaction entered the transaction pool, either right away
+	// or once all of its missing parents were found
+	MempoolChangeAdded MempoolChangeReason = iota
+
+	// MempoolChangeAccepted means the transaction left the transaction pool since it was
+	// included in a block
+	MempoolChangeAccepted
+
+	// MempoolChangeExpired means the transaction left the transaction pool since it stayed in
+	// it for too long
+	MempoolChangeExpired
+
+	// MempoolChangeReplaced means the transaction left the transaction pool since a transaction
+	// that double-spends it, or one of its ancestors, replaced it
+	MempoolChangeReplaced
+
+	// MempoolChangeEvicted means the transaction left the transaction pool since the mempool was
+	// full and its fee rate, or the fee rate of one of its ancestors, was among the lowest in it
+	MempoolChangeEvicted
+
+	// MempoolChangeDoubleSpent means the transaction left the transaction pool since a block
+	// double-spends it, or one of its ancestors
+	MempoolChangeDoubleSpent
+
+	// MempoolChangeInvalid means the transaction left the transaction pool since it, or one of
+	// its ancestors, turned out to be invalid
+	MempoolChangeInvalid
+)
+
+var mempoolChangeReasonStrings = map[MempoolChangeReason]string{
+	MempoolChangeAdded:       "added",
+	MempoolChangeAccepted:    "accepted",
+	MempoolChangeExpired:     "expired",
+	MempoolChangeReplaced:    "replaced",
+	MempoolChangeEvicted:     "evicted",
+	MempoolChangeDoubleSpent: "double-spent",
+	MempoolChangeInvalid:     "invalid",
+}
+
+func (reason MempoolChangeReason) String() string {
+	reasonString, ok := mempoolChangeReasonStrings[reason]
+	if !ok {
+		return fmt.Sprintf("unknown(%d)", int(reason))
+	}
+	return reasonString
+}
+
+// MempoolChange is a transaction that entered or left the transaction pool
+type MempoolChange struct {
+	Transaction *externalapi.DomainTransaction
+	Reason      MempoolChangeReason
+}
+
+// OnMempoolChangedHandler is called with the changes made to the transaction pool by a single
+// operation on the mempool, in the order they were made. It's called once the mempool is
+// unlocked, so it may call back into it.
+type OnMempoolChangedHandler func(changes []*MempoolChange)
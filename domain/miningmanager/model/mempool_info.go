@@ -0,0 +1,39 @@
+package model
+
+// MempoolInfo describes the composition of the mempool
+type MempoolInfo struct {
+	TransactionCount int
+	OrphanCount      int
+
+	// TotalMass is the sum of the masses of the transactions in the transaction pool
+	TotalMass uint64
+
+	// FeeRateHistogram counts the transactions in the transaction pool by their fee rate.
+	// Its buckets are ordered by fee rate, and the first one starts at 0.
+	FeeRateHistogram []FeeRateHistogramBucket
+
+	// OldestTransactionDAAScore is the virtual DAA score at the time the oldest transaction
+	// in the transaction pool was added, or 0 if it's empty
+	OldestTransactionDAAScore uint64
+
+	// MinimumFeeRate is the lowest fee rate, in sompi per gram, at which a transaction is
+	// currently accepted into the mempool
+	MinimumFeeRate float64
+
+	// SpamTransactionCount is the number of transactions without parents in the mempool that
+	// create more outputs than they spend without paying for them. Only one of them is a block
+	// candidate at any given time.
+	SpamTransactionCount int
+
+	// FilteredTransactionCount is the number of transactions without parents in the mempool
+	// that are never block candidates, since they create too many outputs without paying for them
+	FilteredTransactionCount int
+}
+
+// FeeRateHistogramBucket holds the transactions with a fee rate of at least MinimumFeeRate
+// sompi per gram, and lower than the MinimumFeeRate of the next bucket
+type FeeRateHistogramBucket struct {
+	MinimumFeeRate float64
+	Count          int
+	TotalMass      uint64
+}
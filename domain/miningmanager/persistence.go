@@ -0,0 +1,50 @@
+package miningmanager
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// SaveMempool writes the transactions of the mempool into the file at the given path,
+// replacing it only once all the transactions were written
+func (mm *miningManager) SaveMempool(path string) error {
+	temporaryPath := path + ".tmp"
+	file, err := os.Create(temporaryPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = mm.mempool.SaveTransactions(file)
+	if err == nil {
+		err = errors.WithStack(file.Sync())
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = errors.WithStack(closeErr)
+	}
+	if err != nil {
+		// The error of the save is the one worth returning, and a leftover temporary file
+		// is overwritten by the next save anyway
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+
+	return errors.WithStack(os.Rename(temporaryPath, path))
+}
+
+// LoadMempool inserts the transactions saved by SaveMempool at the given path back into the
+// mempool, validating each of them against the current virtual. A missing file means there's
+// nothing to load.
+func (mm *miningManager) LoadMempool(path string) (loadedCount int, rejectedCount int, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	return mm.mempool.LoadTransactions(file)
+}
@@ -0,0 +1,7 @@
+package txindex
+
+import (
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("TXIN")
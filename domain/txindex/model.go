@@ -0,0 +1,17 @@
+package txindex
+
+import (
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+// TXAcceptance describes where in the DAG a transaction was included and accepted
+type TXAcceptance struct {
+	// IncludingBlockHash is the hash of the block that contains the transaction
+	IncludingBlockHash *externalapi.DomainHash
+
+	// AcceptingBlockHash is the hash of the selected chain block that accepted the transaction
+	AcceptingBlockHash *externalapi.DomainHash
+
+	// AcceptingBlockBlueScore is the blue score of the accepting block
+	AcceptingBlockBlueScore uint64
+}
@@ -0,0 +1,141 @@
+package txindex
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+const (
+	lengthSize               = 8
+	blueScoreSize            = 8
+	serializedAcceptanceSize = 2*externalapi.DomainHashSize + blueScoreSize
+	acceptingBlockKeySize    = blueScoreSize + externalapi.DomainHashSize
+)
+
+func serializeTXAcceptance(acceptance *TXAcceptance) []byte {
+	serializedAcceptance := make([]byte, serializedAcceptanceSize)
+	copy(serializedAcceptance[:externalapi.DomainHashSize], acceptance.IncludingBlockHash.ByteSlice())
+	copy(serializedAcceptance[externalapi.DomainHashSize:2*externalapi.DomainHashSize],
+		acceptance.AcceptingBlockHash.ByteSlice())
+	binary.LittleEndian.PutUint64(serializedAcceptance[2*externalapi.DomainHashSize:], acceptance.AcceptingBlockBlueScore)
+	return serializedAcceptance
+}
+
+func deserializeTXAcceptance(serializedAcceptance []byte) (*TXAcceptance, error) {
+	if len(serializedAcceptance) != serializedAcceptanceSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected length %d while deserializing "+
+			"transaction acceptance", len(serializedAcceptance))
+	}
+
+	includingBlockHash, err := externalapi.NewDomainHashFromByteSlice(
+		serializedAcceptance[:externalapi.DomainHashSize])
+	if err != nil {
+		return nil, err
+	}
+	acceptingBlockHash, err := externalapi.NewDomainHashFromByteSlice(
+		serializedAcceptance[externalapi.DomainHashSize : 2*externalapi.DomainHashSize])
+	if err != nil {
+		return nil, err
+	}
+
+	return &TXAcceptance{
+		IncludingBlockHash:      includingBlockHash,
+		AcceptingBlockHash:      acceptingBlockHash,
+		AcceptingBlockBlueScore: binary.LittleEndian.Uint64(serializedAcceptance[2*externalapi.DomainHashSize:]),
+	}, nil
+}
+
+// acceptingBlockKeySuffix returns the key suffix of an accepting block. The blue score
+// is serialized in big-endian so that cursors iterate accepting blocks by ascending blue score
+func acceptingBlockKeySuffix(blueScore uint64, blockHash *externalapi.DomainHash) []byte {
+	suffix := make([]byte, acceptingBlockKeySize)
+	binary.BigEndian.PutUint64(suffix[:blueScoreSize], blueScore)
+	copy(suffix[blueScoreSize:], blockHash.ByteSlice())
+	return suffix
+}
+
+func parseAcceptingBlockKeySuffix(suffix []byte) (blueScore uint64, blockHash *externalapi.DomainHash, err error) {
+	if len(suffix) != acceptingBlockKeySize {
+		return 0, nil, errors.Errorf("unexpected accepting block key length %d", len(suffix))
+	}
+	blockHash, err = externalapi.NewDomainHashFromByteSlice(suffix[blueScoreSize:])
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.BigEndian.Uint64(suffix[:blueScoreSize]), blockHash, nil
+}
+
+func serializeTransactionIDs(transactionIDs []*externalapi.DomainTransactionID) []byte {
+	serializedIDs := make([]byte, lengthSize+externalapi.DomainHashSize*len(transactionIDs))
+	binary.LittleEndian.PutUint64(serializedIDs[:lengthSize], uint64(len(transactionIDs)))
+	for i, transactionID := range transactionIDs {
+		start := lengthSize + externalapi.DomainHashSize*i
+		end := start + externalapi.DomainHashSize
+		copy(serializedIDs[start:end], transactionID.ByteSlice())
+	}
+	return serializedIDs
+}
+
+func deserializeTransactionIDs(serializedIDs []byte) ([]*externalapi.DomainTransactionID, error) {
+	if len(serializedIDs) < lengthSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing transaction IDs")
+	}
+
+	length := binary.LittleEndian.Uint64(serializedIDs[:lengthSize])
+	transactionIDs := make([]*externalapi.DomainTransactionID, 0, length)
+	for i := uint64(0); i < length; i++ {
+		start := lengthSize + externalapi.DomainHashSize*i
+		end := start + externalapi.DomainHashSize
+
+		if end > uint64(len(serializedIDs)) {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing transaction IDs")
+		}
+
+		transactionID, err := externalapi.NewDomainTransactionIDFromByteSlice(serializedIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+
+	return transactionIDs, nil
+}
+
+func serializeHashes(hashes []*externalapi.DomainHash) []byte {
+	serializedHashes := make([]byte, lengthSize+externalapi.DomainHashSize*len(hashes))
+	binary.LittleEndian.PutUint64(serializedHashes[:lengthSize], uint64(len(hashes)))
+	for i, hash := range hashes {
+		start := lengthSize + externalapi.DomainHashSize*i
+		end := start + externalapi.DomainHashSize
+		copy(serializedHashes[start:end], hash.ByteSlice())
+	}
+	return serializedHashes
+}
+
+func deserializeHashes(serializedHashes []byte) ([]*externalapi.DomainHash, error) {
+	if len(serializedHashes) < lengthSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing hashes")
+	}
+
+	length := binary.LittleEndian.Uint64(serializedHashes[:lengthSize])
+	hashes := make([]*externalapi.DomainHash, 0, length)
+	for i := uint64(0); i < length; i++ {
+		start := lengthSize + externalapi.DomainHashSize*i
+		end := start + externalapi.DomainHashSize
+
+		if end > uint64(len(serializedHashes)) {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing hashes")
+		}
+
+		hash, err := externalapi.NewDomainHashFromByteSlice(serializedHashes[start:end])
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
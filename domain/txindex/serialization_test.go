@@ -0,0 +1,82 @@
+package txindex
+
+import (
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+)
+
+func Test_serializeTXAcceptance(t *testing.T) {
+	acceptance := &TXAcceptance{
+		IncludingBlockHash:      externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+		AcceptingBlockHash:      externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+		AcceptingBlockBlueScore: 12345,
+	}
+	result, err := deserializeTXAcceptance(serializeTXAcceptance(acceptance))
+	if err != nil {
+		t.Fatalf("Failed deserializing transaction acceptance: %v", err)
+	}
+	if !result.IncludingBlockHash.Equal(acceptance.IncludingBlockHash) ||
+		!result.AcceptingBlockHash.Equal(acceptance.AcceptingBlockHash) ||
+		result.AcceptingBlockBlueScore != acceptance.AcceptingBlockBlueScore {
+		t.Fatalf("Expected \n %+v \n==\n %+v\n", acceptance, result)
+	}
+
+	_, err = deserializeTXAcceptance(serializeTXAcceptance(acceptance)[1:])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
+
+func Test_serializeTransactionIDs(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+
+	for length := 0; length < 32; length++ {
+		transactionIDs := make([]*externalapi.DomainTransactionID, length)
+		for i := range transactionIDs {
+			var idBytes [externalapi.DomainHashSize]byte
+			r.Read(idBytes[:])
+			transactionIDs[i] = externalapi.NewDomainTransactionIDFromByteArray(&idBytes)
+		}
+		result, err := deserializeTransactionIDs(serializeTransactionIDs(transactionIDs))
+		if err != nil {
+			t.Fatalf("Failed deserializing transaction IDs: %v", err)
+		}
+		if len(result) != len(transactionIDs) {
+			t.Fatalf("Expected %d transaction IDs, got %d", len(transactionIDs), len(result))
+		}
+		for i := range result {
+			if !result[i].Equal(transactionIDs[i]) {
+				t.Fatalf("Expected \n %s \n==\n %s\n", transactionIDs[i], result[i])
+			}
+		}
+	}
+
+	serialized := serializeTransactionIDs([]*externalapi.DomainTransactionID{{}})
+	binary.LittleEndian.PutUint64(serialized[:lengthSize], 2)
+	_, err := deserializeTransactionIDs(serialized)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
+
+func Test_acceptingBlockKeySuffixOrder(t *testing.T) {
+	low := acceptingBlockKeySuffix(0xff, externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{0xff}))
+	high := acceptingBlockKeySuffix(0x100, externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{}))
+	if string(low) >= string(high) {
+		t.Fatalf("Expected accepting block keys to be ordered by blue score")
+	}
+
+	blockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{3})
+	blueScore, result, err := parseAcceptingBlockKeySuffix(acceptingBlockKeySuffix(42, blockHash))
+	if err != nil {
+		t.Fatalf("Failed parsing accepting block key: %v", err)
+	}
+	if blueScore != 42 || !result.Equal(blockHash) {
+		t.Fatalf("Expected (42, %s), got (%d, %s)", blockHash, blueScore, result)
+	}
+}
@@ -0,0 +1,224 @@
+package txindex
+
+import (
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database"
+)
+
+var txIndexBucket = database.MakeBucket([]byte("tx-index"))
+var acceptingBlocksBucket = database.MakeBucket([]byte("tx-index-accepting-blocks"))
+var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("tx-index-virtual-parents"))
+var pruningPointKey = database.MakeBucket([]byte("")).Key([]byte("tx-index-pruning-point"))
+
+// txIndexStore keeps two mappings:
+// 1. transaction ID -> the acceptance of the transaction
+// 2. accepting block (ordered by blue score) -> the IDs of the transactions it accepted
+//
+// The second mapping allows removing the transactions of a chain block on reorg or pruning
+// without requiring its acceptance data, which may already be deleted from the consensus.
+type txIndexStore struct {
+	database database.Database
+}
+
+func newTXIndexStore(database database.Database) *txIndexStore {
+	return &txIndexStore{
+		database: database,
+	}
+}
+
+func (tis *txIndexStore) transactionKey(transactionID *externalapi.DomainTransactionID) *database.Key {
+	return txIndexBucket.Key(transactionID.ByteSlice())
+}
+
+func (tis *txIndexStore) acceptingBlockKey(blueScore uint64, blockHash *externalapi.DomainHash) *database.Key {
+	return acceptingBlocksBucket.Key(acceptingBlockKeySuffix(blueScore, blockHash))
+}
+
+func (tis *txIndexStore) getTXAcceptance(dataAccessor database.DataAccessor,
+	transactionID *externalapi.DomainTransactionID) (*TXAcceptance, bool, error) {
+
+	serializedAcceptance, err := dataAccessor.Get(tis.transactionKey(transactionID))
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	acceptance, err := deserializeTXAcceptance(serializedAcceptance)
+	if err != nil {
+		return nil, false, err
+	}
+	return acceptance, true, nil
+}
+
+// addAcceptingBlock indexes all the given transactions as accepted by the given chain block
+func (tis *txIndexStore) addAcceptingBlock(dbTransaction database.Transaction, blockHash *externalapi.DomainHash,
+	blueScore uint64, transactionIDs []*externalapi.DomainTransactionID,
+	includingBlockHashes []*externalapi.DomainHash) error {
+
+	for i, transactionID := range transactionIDs {
+		acceptance := &TXAcceptance{
+			IncludingBlockHash:      includingBlockHashes[i],
+			AcceptingBlockHash:      blockHash,
+			AcceptingBlockBlueScore: blueScore,
+		}
+		err := dbTransaction.Put(tis.transactionKey(transactionID), serializeTXAcceptance(acceptance))
+		if err != nil {
+			return err
+		}
+	}
+
+	return dbTransaction.Put(tis.acceptingBlockKey(blueScore, blockHash), serializeTransactionIDs(transactionIDs))
+}
+
+// removeAcceptingBlock removes all the transactions accepted by the given chain block from the index.
+// It does nothing if the block was never indexed.
+func (tis *txIndexStore) removeAcceptingBlock(dbTransaction database.Transaction,
+	blockHash *externalapi.DomainHash, blueScore uint64) error {
+
+	key := tis.acceptingBlockKey(blueScore, blockHash)
+	serializedIDs, err := dbTransaction.Get(key)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	transactionIDs, err := deserializeTransactionIDs(serializedIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, transactionID := range transactionIDs {
+		acceptance, found, err := tis.getTXAcceptance(dbTransaction, transactionID)
+		if err != nil {
+			return err
+		}
+		// The transaction might have been re-accepted by another chain block in the meantime
+		if !found || !acceptance.AcceptingBlockHash.Equal(blockHash) {
+			continue
+		}
+		err = dbTransaction.Delete(tis.transactionKey(transactionID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return dbTransaction.Delete(key)
+}
+
+// removeAcceptingBlocksBelowBlueScore removes all the transactions accepted by chain blocks
+// with a blue score lower than the given one
+func (tis *txIndexStore) removeAcceptingBlocksBelowBlueScore(dbTransaction database.Transaction, blueScore uint64) (
+	removedBlockCount int, err error) {
+
+	cursor, err := dbTransaction.Cursor(acceptingBlocksBucket)
+	if err != nil {
+		return 0, err
+	}
+	type acceptingBlock struct {
+		blueScore uint64
+		blockHash *externalapi.DomainHash
+	}
+	var blocksToRemove []acceptingBlock
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			cursor.Close()
+			return 0, err
+		}
+		blockBlueScore, blockHash, err := parseAcceptingBlockKeySuffix(key.Suffix())
+		if err != nil {
+			cursor.Close()
+			return 0, err
+		}
+		if blockBlueScore >= blueScore {
+			break
+		}
+		blocksToRemove = append(blocksToRemove, acceptingBlock{blueScore: blockBlueScore, blockHash: blockHash})
+	}
+	err = cursor.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, block := range blocksToRemove {
+		err := tis.removeAcceptingBlock(dbTransaction, block.blockHash, block.blueScore)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(blocksToRemove), nil
+}
+
+func (tis *txIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
+	serializedVirtualParents, err := tis.database.Get(virtualParentsKey)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeHashes(serializedVirtualParents)
+}
+
+func (tis *txIndexStore) putVirtualParents(dataAccessor database.DataAccessor,
+	virtualParents []*externalapi.DomainHash) error {
+
+	return dataAccessor.Put(virtualParentsKey, serializeHashes(virtualParents))
+}
+
+func (tis *txIndexStore) getPruningPoint(dataAccessor database.DataAccessor) (*externalapi.DomainHash, error) {
+	serializedPruningPoint, err := dataAccessor.Get(pruningPointKey)
+	if err != nil {
+		return nil, err
+	}
+	return externalapi.NewDomainHashFromByteSlice(serializedPruningPoint)
+}
+
+func (tis *txIndexStore) putPruningPoint(dataAccessor database.DataAccessor, pruningPoint *externalapi.DomainHash) error {
+	return dataAccessor.Put(pruningPointKey, pruningPoint.ByteSlice())
+}
+
+func (tis *txIndexStore) deleteAll() error {
+	// First we delete the virtual parents, so if anything goes wrong, the transaction index will be marked as
+	// "not synced" and will be reset.
+	err := tis.database.Delete(virtualParentsKey)
+	if err != nil {
+		return err
+	}
+
+	err = tis.database.Delete(pruningPointKey)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range []*database.Bucket{txIndexBucket, acceptingBlocksBucket} {
+		err := tis.deleteBucket(bucket)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tis *txIndexStore) deleteBucket(bucket *database.Bucket) error {
+	cursor, err := tis.database.Cursor(bucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+
+		err = tis.database.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package txindex
+
+import (
+	"sync"
+
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database"
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+)
+
+// acceptanceDataBatchSize is the maximum number of chain blocks whose acceptance data
+// is requested from the consensus at once
+const acceptanceDataBatchSize = 100
+
+// TXIndex maintains an index between transaction IDs and the
+// blocks that included and accepted them
+type TXIndex struct {
+	domain     domain.Domain
+	store      *txIndexStore
+	isArchival bool
+
+	mutex sync.Mutex
+}
+
+// New creates a new transaction index.
+//
+// Unless isArchival is set, transactions accepted below the pruning point are
+// removed from the index along with the blocks that contain them.
+//
+// NOTE: While this is called no new blocks can be added to the consensus.
+func New(domain domain.Domain, database database.Database, isArchival bool) (*TXIndex, error) {
+	txIndex := &TXIndex{
+		domain:     domain,
+		store:      newTXIndexStore(database),
+		isArchival: isArchival,
+	}
+	isSynced, err := txIndex.isSynced()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSynced {
+		err := txIndex.Reset()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return txIndex, nil
+}
+
+// Reset deletes the whole transaction index and resyncs it from the
+// virtual selected parent chain above the pruning point.
+func (ti *TXIndex) Reset() error {
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	log.Infof("Starting transaction index reset")
+
+	err := ti.store.deleteAll()
+	if err != nil {
+		return err
+	}
+
+	virtualInfo, err := ti.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return err
+	}
+
+	pruningPoint, err := ti.domain.Consensus().PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	chainPath, err := ti.domain.Consensus().GetVirtualSelectedParentChainFromBlock(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(chainPath.Added); start += acceptanceDataBatchSize {
+		end := start + acceptanceDataBatchSize
+		if end > len(chainPath.Added) {
+			end = len(chainPath.Added)
+		}
+
+		err := ti.addAndCommitChainBlocks(chainPath.Added[start:end])
+		if err != nil {
+			return err
+		}
+		log.Debugf("Indexed the transactions of %d out of %d chain blocks", end, len(chainPath.Added))
+	}
+
+	err = ti.store.putPruningPoint(ti.store.database, pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	// This has to be done last to mark that the reset went smoothly and no reset has to be called next time.
+	err = ti.store.putVirtualParents(ti.store.database, virtualInfo.ParentHashes)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Finished transaction index reset")
+	return nil
+}
+
+func (ti *TXIndex) isSynced() (bool, error) {
+	txIndexVirtualParents, err := ti.store.getVirtualParents()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	virtualInfo, err := ti.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return false, err
+	}
+
+	return externalapi.HashesEqual(virtualInfo.ParentHashes, txIndexVirtualParents), nil
+}
+
+// Update updates the transaction index with the given DAG selected parent chain changes
+func (ti *TXIndex) Update(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.Update")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	var removed, added []*externalapi.DomainHash
+	if virtualChangeSet.VirtualSelectedParentChainChanges != nil {
+		removed = virtualChangeSet.VirtualSelectedParentChainChanges.Removed
+		added = virtualChangeSet.VirtualSelectedParentChainChanges.Added
+	}
+	log.Tracef("Updating transaction index with %d removed and %d added chain blocks", len(removed), len(added))
+
+	dbTransaction, err := ti.store.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = ti.removeChainBlocks(dbTransaction, removed)
+	if err != nil {
+		return err
+	}
+
+	err = ti.addChainBlocks(dbTransaction, added)
+	if err != nil {
+		return err
+	}
+
+	if !ti.isArchival {
+		err = ti.removePrunedChainBlocks(dbTransaction)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ti.store.putVirtualParents(dbTransaction, virtualChangeSet.VirtualParents)
+	if err != nil {
+		return err
+	}
+
+	return dbTransaction.Commit()
+}
+
+func (ti *TXIndex) addAndCommitChainBlocks(added []*externalapi.DomainHash) error {
+	dbTransaction, err := ti.store.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = ti.addChainBlocks(dbTransaction, added)
+	if err != nil {
+		return err
+	}
+
+	return dbTransaction.Commit()
+}
+
+// removeChainBlocks must be called before addChainBlocks within the same database transaction,
+// since reads within a database transaction do not observe its own writes
+func (ti *TXIndex) removeChainBlocks(dbTransaction database.Transaction, removed []*externalapi.DomainHash) error {
+	for _, blockHash := range removed {
+		blockInfo, err := ti.domain.Consensus().GetBlockInfo(blockHash)
+		if err != nil {
+			return err
+		}
+
+		log.Tracef("Removing the transactions accepted by chain block %s from the transaction index", blockHash)
+		err = ti.store.removeAcceptingBlock(dbTransaction, blockHash, blockInfo.BlueScore)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ti *TXIndex) addChainBlocks(dbTransaction database.Transaction, added []*externalapi.DomainHash) error {
+	if len(added) == 0 {
+		return nil
+	}
+
+	blocksAcceptanceData, err := ti.domain.Consensus().GetBlocksAcceptanceData(added)
+	if err != nil {
+		return err
+	}
+
+	for i, blockHash := range added {
+		blockInfo, err := ti.domain.Consensus().GetBlockInfo(blockHash)
+		if err != nil {
+			return err
+		}
+
+		var transactionIDs []*externalapi.DomainTransactionID
+		var includingBlockHashes []*externalapi.DomainHash
+		for _, blockAcceptanceData := range blocksAcceptanceData[i] {
+			for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+				if !transactionAcceptanceData.IsAccepted {
+					continue
+				}
+				transactionIDs = append(transactionIDs,
+					consensushashing.TransactionID(transactionAcceptanceData.Transaction))
+				includingBlockHashes = append(includingBlockHashes, blockAcceptanceData.BlockHash)
+			}
+		}
+
+		log.Tracef("Adding %d transactions accepted by chain block %s to the transaction index",
+			len(transactionIDs), blockHash)
+		err = ti.store.addAcceptingBlock(dbTransaction, blockHash, blockInfo.BlueScore,
+			transactionIDs, includingBlockHashes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removePrunedChainBlocks removes the transactions accepted below the pruning point
+// if it has moved since the last update. The bodies of the blocks that contain these
+// transactions are deleted by the consensus, so the index can no longer serve them.
+func (ti *TXIndex) removePrunedChainBlocks(dbTransaction database.Transaction) error {
+	pruningPoint, err := ti.domain.Consensus().PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	txIndexPruningPoint, err := ti.store.getPruningPoint(dbTransaction)
+	if err != nil && !database.IsNotFoundError(err) {
+		return err
+	}
+	if txIndexPruningPoint != nil && txIndexPruningPoint.Equal(pruningPoint) {
+		return nil
+	}
+
+	pruningPointInfo, err := ti.domain.Consensus().GetBlockInfo(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	removedBlockCount, err := ti.store.removeAcceptingBlocksBelowBlueScore(dbTransaction, pruningPointInfo.BlueScore)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Pruning point moved to %s, removed the transactions of %d chain blocks from the "+
+		"transaction index", pruningPoint, removedBlockCount)
+
+	return ti.store.putPruningPoint(dbTransaction, pruningPoint)
+}
+
+// TXAcceptance returns where the transaction with the given ID was included and accepted,
+// and whether it was found in the index at all
+func (ti *TXIndex) TXAcceptance(transactionID *externalapi.DomainTransactionID) (*TXAcceptance, bool, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.TXAcceptance")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	return ti.store.getTXAcceptance(ti.store.database, transactionID)
+}
@@ -0,0 +1,138 @@
+package txindex
+
+import (
+	"testing"
+
+	"github.com/stokesnetwork/stokes/domain"
+	"github.com/stokesnetwork/stokes/domain/consensus"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/model/testapi"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/consensushashing"
+	"github.com/stokesnetwork/stokes/domain/consensus/utils/testutils"
+)
+
+type fakeDomain struct {
+	domain.Domain
+	consensus externalapi.Consensus
+}
+
+func (d *fakeDomain) Consensus() externalapi.Consensus {
+	return d.consensus
+}
+
+func TestTXIndexReorg(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestTXIndexReorg")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		txIndex, err := New(&fakeDomain{consensus: tc}, tc.Database(), false)
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+
+		// addChain adds a chain of blocks on top of genesis, and updates the transaction index
+		// with every resulting virtual change. The extra data makes the coinbase transactions
+		// of different chains distinct.
+		addChain := func(length int, extraData byte) []*externalapi.DomainHash {
+			coinbaseData := &externalapi.DomainCoinbaseData{
+				ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+				ExtraData:       []byte{extraData},
+			}
+			chain := make([]*externalapi.DomainHash, length)
+			parent := consensusConfig.GenesisHash
+			for i := range chain {
+				blockHash, virtualChangeSet, err := tc.AddBlock([]*externalapi.DomainHash{parent}, coinbaseData, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				err = txIndex.Update(virtualChangeSet)
+				if err != nil {
+					t.Fatalf("Update: %+v", err)
+				}
+				chain[i] = blockHash
+				parent = blockHash
+			}
+			return chain
+		}
+
+		chainA := addChain(3, 'a')
+		chainACoinbaseID := coinbaseTransactionID(t, tc, chainA[0])
+
+		acceptance, found, err := txIndex.TXAcceptance(chainACoinbaseID)
+		if err != nil {
+			t.Fatalf("TXAcceptance: %+v", err)
+		}
+		if !found {
+			t.Fatalf("Expected the coinbase transaction of %s to be indexed", chainA[0])
+		}
+		if !acceptance.IncludingBlockHash.Equal(chainA[0]) {
+			t.Fatalf("Expected the including block to be %s, but got %s", chainA[0], acceptance.IncludingBlockHash)
+		}
+		if !acceptance.AcceptingBlockHash.Equal(chainA[1]) {
+			t.Fatalf("Expected the accepting block to be %s, but got %s", chainA[1], acceptance.AcceptingBlockHash)
+		}
+
+		// A longer chain that does not merge chain A reorgs the virtual selected parent chain
+		chainB := addChain(5, 'b')
+		chainBCoinbaseID := coinbaseTransactionID(t, tc, chainB[0])
+
+		virtualSelectedParent, err := tc.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !virtualSelectedParent.Equal(chainB[len(chainB)-1]) {
+			t.Fatalf("Expected the virtual selected parent to be the tip of chain B")
+		}
+
+		_, found, err = txIndex.TXAcceptance(chainACoinbaseID)
+		if err != nil {
+			t.Fatalf("TXAcceptance: %+v", err)
+		}
+		if found {
+			t.Fatalf("Expected the coinbase transaction of %s to be removed from the index after the reorg", chainA[0])
+		}
+
+		acceptance, found, err = txIndex.TXAcceptance(chainBCoinbaseID)
+		if err != nil {
+			t.Fatalf("TXAcceptance: %+v", err)
+		}
+		if !found {
+			t.Fatalf("Expected the coinbase transaction of %s to be indexed", chainB[0])
+		}
+		if !acceptance.AcceptingBlockHash.Equal(chainB[1]) {
+			t.Fatalf("Expected the accepting block to be %s, but got %s", chainB[1], acceptance.AcceptingBlockHash)
+		}
+
+		// Resetting the index should result in the same state
+		err = txIndex.Reset()
+		if err != nil {
+			t.Fatalf("Reset: %+v", err)
+		}
+		resetAcceptance, found, err := txIndex.TXAcceptance(chainBCoinbaseID)
+		if err != nil {
+			t.Fatalf("TXAcceptance: %+v", err)
+		}
+		if !found || !resetAcceptance.AcceptingBlockHash.Equal(acceptance.AcceptingBlockHash) ||
+			!resetAcceptance.IncludingBlockHash.Equal(acceptance.IncludingBlockHash) ||
+			resetAcceptance.AcceptingBlockBlueScore != acceptance.AcceptingBlockBlueScore {
+			t.Fatalf("Expected the coinbase transaction of %s to be indexed the same way after reset", chainB[0])
+		}
+	})
+}
+
+func coinbaseTransactionID(t *testing.T, tc testapi.TestConsensus,
+	blockHash *externalapi.DomainHash) *externalapi.DomainTransactionID {
+
+	block, found, err := tc.GetBlock(blockHash)
+	if err != nil {
+		t.Fatalf("GetBlock: %+v", err)
+	}
+	if !found {
+		t.Fatalf("Block %s was not found", blockHash)
+	}
+	return consensushashing.TransactionID(block.Transactions[0])
+}
@@ -8,6 +8,7 @@ import (
 	// _ "embed" is necessary for the go:embed feature.
 	_ "embed"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
@@ -35,6 +36,8 @@ const (
 	defaultErrLogFilename      = "kaspad_err.log"
 	defaultTargetOutboundPeers = 8
 	defaultMaxInboundPeers     = 117
+	defaultAnchorPeers         = 2
+	defaultTorControl          = "127.0.0.1:9051"
 	defaultBanDuration         = time.Hour * 24
 	defaultBanThreshold        = 100
 	//DefaultConnectTimeout is the default connection timeout when dialing
@@ -48,6 +51,11 @@ const (
 	blockMaxMassMax              = 10_000_000
 	defaultMinRelayTxFee         = 1e-5 // 1 sompi per byte
 	defaultMaxOrphanTransactions = 100
+	defaultSpamFeePerExtraOutput = 1.0
+	defaultMempoolExpiry         = 60
+	defaultOrphanExpiry          = 60
+	defaultMaxDroppedTxs         = 10_000
+	defaultDroppedTxExpiry       = 60 * 60
 	//DefaultMaxOrphanTxSize is the default maximum size for an orphan transaction
 	DefaultMaxOrphanTxSize  = 100_000
 	defaultSigCacheMaxSize  = 100_000
@@ -87,9 +95,10 @@ type Flags struct {
 	Listeners                       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 16111, testnet: 16211)"`
 	TargetOutboundPeers             int           `long:"outpeers" description:"Target number of outbound peers"`
 	MaxInboundPeers                 int           `long:"maxinpeers" description:"Max number of inbound peers"`
+	AnchorPeers                     uint          `long:"anchors" description:"Number of long-lived outbound peers to remember on shutdown and reconnect to first on startup -- 0 disables it"`
 	EnableBanning                   bool          `long:"enablebanning" description:"Enable banning of misbehaving peers"`
 	BanDuration                     time.Duration `long:"banduration" description:"How long to ban misbehaving peers. Valid time units are {s, m, h}. Minimum 1 second"`
-	BanThreshold                    uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
+	BanThreshold                    uint32        `long:"banthreshold" description:"Ban score at which misbehaving peers are banned. Ban scores decay over time."`
 	Whitelists                      []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
 	RPCListeners                    []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 16110, testnet: 16210)"`
 	RPCCert                         string        `long:"rpccert" description:"File containing the certificate file"`
@@ -106,12 +115,26 @@ type Flags struct {
 	Proxy                           string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser                       string        `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass                       string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
+	OnionProxy                      string        `long:"onion" description:"Connect to Tor onion services via SOCKS5 proxy (eg. 127.0.0.1:9050) -- Defaults to --proxy"`
+	OnionProxyUser                  string        `long:"onionuser" description:"Username for the onion proxy server"`
+	OnionProxyPass                  string        `long:"onionpass" default-mask:"-" description:"Password for the onion proxy server"`
+	NoOnion                         bool          `long:"noonion" description:"Do not connect to Tor onion services"`
+	ListenOnion                     bool          `long:"listenonion" description:"Publish an onion service for the P2P port through the Tor control port, and advertise its address to peers"`
+	OnionListener                   string        `long:"onionlisten" description:"Local interface/port the onion service of --listenonion forwards inbound peers to. Peers connecting to it are taken to be onion peers, so it must not be reachable from anywhere else (default: 127.0.0.1 and the P2P port plus one)"`
+	TorControl                      string        `long:"torcontrol" description:"Address of the Tor control port used by --listenonion"`
+	TorPassword                     string        `long:"torpassword" default-mask:"-" description:"Password for the Tor control port, if it uses HashedControlPassword authentication"`
 	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG"`
 	Profile                         string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 	LogLevel                        string        `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	Upnp                            bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	Upnp                            bool          `long:"upnp" description:"Use UPnP or NAT-PMP to map our listening port outside of NAT"`
 	MinRelayTxFee                   float64       `long:"minrelaytxfee" description:"The minimum transaction fee in KAS/kB to be considered a non-zero fee."`
 	MaxOrphanTxs                    uint64        `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	SpamFeePerExtraOutput           float64       `long:"spamfeeperextraoutput" description:"The fee in KAS per extra output that a transaction creating more outputs than it spends has to pay to be included in block templates like any other transaction -- 0 disables the spam filter"`
+	MempoolExpiry                   uint64        `long:"mempoolexpiry" description:"The number of seconds a transaction may stay in the mempool before it expires"`
+	OrphanExpiry                    uint64        `long:"orphanexpiry" description:"The number of seconds an orphan transaction may stay in the orphan pool before it expires"`
+	MaxDroppedTxs                   uint64        `long:"maxdroppedtx" description:"Max number of recently expired or evicted transactions to remember, so that they can be told apart from transactions that were never seen -- 0 disables it"`
+	DroppedTxExpiry                 uint64        `long:"droppedtxexpiry" description:"The number of seconds to remember an expired or evicted transaction for"`
+	NoPersistMempool                bool          `long:"nopersistmempool" description:"Do not save the mempool to mempool.dat in the app directory on shutdown and load it back on startup"`
 	BlockMaxMass                    uint64        `long:"blockmaxmass" description:"Maximum transaction mass to be used when creating a block"`
 	UserAgentComments               []string      `long:"uacomment" description:"Comment to add to the user agent -- See BIP 14 for more information."`
 	NoPeerBloomFilters              bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
@@ -122,6 +145,8 @@ type Flags struct {
 	ResetDatabase                   bool          `long:"reset-db" description:"Reset database before starting node. It's needed when switching between subnetworks."`
 	MaxUTXOCacheSize                uint64        `long:"maxutxocachesize" description:"Max size of loaded UTXO into ram from the disk in bytes"`
 	UTXOIndex                       bool          `long:"utxoindex" description:"Enable the UTXO index"`
+	TXIndex                         bool          `long:"txindex" description:"Enable the transaction index, which allows looking up accepted transactions by their ID"`
+	AddressHistoryIndex             bool          `long:"addresshistoryindex" description:"Enable the address history index, which records the transactions that credited or debited every address"`
 	IsArchivalNode                  bool          `long:"archival" description:"Run as an archival node: don't delete old block data when moving the pruning point (Warning: heavy disk usage)'"`
 	AllowSubmitBlockWhenNotSynced   bool          `long:"allow-submit-block-when-not-synced" hidden:"true" description:"Allow the node to accept blocks from RPC while not synced (this flag is mainly used for testing)"`
 	EnableSanityCheckPruningUTXOSet bool          `long:"enable-sanity-check-pruning-utxo" hidden:"true" description:"When moving the pruning point - check that the utxo set matches the utxo commitment"`
@@ -135,12 +160,14 @@ type Flags struct {
 // See loadConfig for details on the configuration load process.
 type Config struct {
 	*Flags
-	Lookup        func(string) ([]net.IP, error)
-	Dial          func(string, string, time.Duration) (net.Conn, error)
-	MiningAddrs   []util.Address
-	MinRelayTxFee util.Amount
-	Whitelists    []*net.IPNet
-	SubnetworkID  *externalapi.DomainSubnetworkID // nil in full nodes
+	Lookup                func(string) ([]net.IP, error)
+	Dial                  func(string, string, time.Duration) (net.Conn, error)
+	OnionDial             func(string, string, time.Duration) (net.Conn, error) // nil if onion services can't be reached
+	MiningAddrs           []util.Address
+	MinRelayTxFee         util.Amount
+	SpamFeePerExtraOutput util.Amount
+	Whitelists            []*net.IPNet
+	SubnetworkID          *externalapi.DomainSubnetworkID // nil in full nodes
 }
 
 // ServiceOptions defines the configuration options for the daemon as a service on
@@ -174,25 +201,32 @@ func newConfigParser(cfgFlags *Flags, options flags.Options) *flags.Parser {
 
 func defaultFlags() *Flags {
 	return &Flags{
-		ConfigFile:           defaultConfigFile,
-		LogLevel:             defaultLogLevel,
-		TargetOutboundPeers:  defaultTargetOutboundPeers,
-		MaxInboundPeers:      defaultMaxInboundPeers,
-		BanDuration:          defaultBanDuration,
-		BanThreshold:         defaultBanThreshold,
-		RPCMaxClients:        DefaultMaxRPCClients,
-		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
-		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
-		AppDir:               defaultDataDir,
-		RPCKey:               defaultRPCKeyFile,
-		RPCCert:              defaultRPCCertFile,
-		BlockMaxMass:         defaultBlockMaxMass,
-		MaxOrphanTxs:         defaultMaxOrphanTransactions,
-		SigCacheMaxSize:      defaultSigCacheMaxSize,
-		MinRelayTxFee:        defaultMinRelayTxFee,
-		MaxUTXOCacheSize:     defaultMaxUTXOCacheSize,
-		ServiceOptions:       &ServiceOptions{},
-		ProtocolVersion:      defaultProtocolVersion,
+		ConfigFile:            defaultConfigFile,
+		LogLevel:              defaultLogLevel,
+		TargetOutboundPeers:   defaultTargetOutboundPeers,
+		MaxInboundPeers:       defaultMaxInboundPeers,
+		AnchorPeers:           defaultAnchorPeers,
+		TorControl:            defaultTorControl,
+		BanDuration:           defaultBanDuration,
+		BanThreshold:          defaultBanThreshold,
+		RPCMaxClients:         DefaultMaxRPCClients,
+		RPCMaxWebsockets:      defaultMaxRPCWebsockets,
+		RPCMaxConcurrentReqs:  defaultMaxRPCConcurrentReqs,
+		AppDir:                defaultDataDir,
+		RPCKey:                defaultRPCKeyFile,
+		RPCCert:               defaultRPCCertFile,
+		BlockMaxMass:          defaultBlockMaxMass,
+		MaxOrphanTxs:          defaultMaxOrphanTransactions,
+		SigCacheMaxSize:       defaultSigCacheMaxSize,
+		MinRelayTxFee:         defaultMinRelayTxFee,
+		SpamFeePerExtraOutput: defaultSpamFeePerExtraOutput,
+		MempoolExpiry:         defaultMempoolExpiry,
+		OrphanExpiry:          defaultOrphanExpiry,
+		MaxDroppedTxs:         defaultMaxDroppedTxs,
+		DroppedTxExpiry:       defaultDroppedTxExpiry,
+		MaxUTXOCacheSize:      defaultMaxUTXOCacheSize,
+		ServiceOptions:        &ServiceOptions{},
+		ProtocolVersion:       defaultProtocolVersion,
 	}
 }
 
@@ -374,7 +408,7 @@ func LoadConfig() (*Config, error) {
 	}
 
 	// Validate any given whitelisted IP addresses and networks.
-	if len(cfg.Whitelists) > 0 {
+	if len(cfg.Flags.Whitelists) > 0 {
 		var ip net.IP
 		cfg.Whitelists = make([]*net.IPNet, 0, len(cfg.Flags.Whitelists))
 
@@ -427,6 +461,16 @@ func LoadConfig() (*Config, error) {
 		cfg.TargetOutboundPeers = 0
 	}
 
+	// --listenonion needs a listener for the onion service to forward to
+	if cfg.ListenOnion && cfg.DisableListen {
+		str := "%s: the --listenonion option requires listening -- " +
+			"specify a listen interface via --listen"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Add the default listener if none were specified. The default
 	// listener is all addresses on the listen port for the network
 	// we are to connect to.
@@ -477,6 +521,39 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Validate the spamfeeperextraoutput.
+	if cfg.Flags.SpamFeePerExtraOutput < 0 {
+		str := "%s: The spamfeeperextraoutput option must not be negative -- parsed [%f]"
+		err := errors.Errorf(str, funcName, cfg.Flags.SpamFeePerExtraOutput)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	cfg.SpamFeePerExtraOutput, err = util.NewAmount(cfg.Flags.SpamFeePerExtraOutput)
+	if err != nil {
+		str := "%s: invalid spamfeeperextraoutput: %s"
+		err := errors.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Disallow expiring transactions as soon as they enter the mempool.
+	if cfg.MempoolExpiry == 0 {
+		str := "%s: The mempoolexpiry option must be greater than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.MempoolExpiry)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.OrphanExpiry == 0 {
+		str := "%s: The orphanexpiry option must be greater than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.OrphanExpiry)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Limit the max block mass to a sane value.
 	if cfg.BlockMaxMass < blockMaxMassMin || cfg.BlockMaxMass >
 		blockMaxMassMax {
@@ -518,6 +595,18 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Inbound onion peers all come from the local Tor client, so they're told apart from each
+	// other and from local peers by the listener that only the onion service forwards to
+	if cfg.ListenOnion {
+		cfg.OnionListener, err = onionListener(cfg)
+		if err != nil {
+			err := errors.Errorf("%s: %s", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
 	// Disallow --addpeer and --connect used together
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: --addpeer and --connect can not be used together"
@@ -566,6 +655,34 @@ func LoadConfig() (*Config, error) {
 		cfg.Dial = proxy.DialTimeout
 	}
 
+	// Onion services are connected to through --onion, or through --proxy
+	// if --onion isn't specified. --noonion disables them altogether.
+	if !cfg.NoOnion {
+		onionProxy := &socks.Proxy{
+			Addr:     cfg.OnionProxy,
+			Username: cfg.OnionProxyUser,
+			Password: cfg.OnionProxyPass,
+		}
+		if cfg.OnionProxy == "" {
+			onionProxy = &socks.Proxy{
+				Addr:     cfg.Proxy,
+				Username: cfg.ProxyUser,
+				Password: cfg.ProxyPass,
+			}
+		}
+		if onionProxy.Addr != "" {
+			_, _, err := net.SplitHostPort(onionProxy.Addr)
+			if err != nil {
+				str := "%s: Onion proxy address '%s' is invalid: %s"
+				err := errors.Errorf(str, funcName, onionProxy.Addr, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, err
+			}
+			cfg.OnionDial = onionProxy.DialTimeout
+		}
+	}
+
 	// Warn about missing config file only after all other configuration is
 	// done. This prevents the warning on help messages and invalid
 	// options. Note this should go directly before the return.
@@ -575,6 +692,49 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// onionListener returns the normalized address of the listener the onion service forwards to,
+// which defaults to the loopback interface and the port after the P2P port. Inbound peers are taken
+// to be onion peers by the port they connect to, so it can't be the port of any other listener.
+func onionListener(cfg *Config) (string, error) {
+	_, p2pPortString, err := net.SplitHostPort(cfg.Listeners[0])
+	if err != nil {
+		return "", err
+	}
+	p2pPort, err := strconv.ParseUint(p2pPortString, 10, 16)
+	if err != nil {
+		return "", err
+	}
+	if p2pPort == math.MaxUint16 {
+		return "", errors.Errorf("there's no port after the P2P port %d for the onion listener -- "+
+			"specify one via --onionlisten", p2pPort)
+	}
+	defaultPort := strconv.FormatUint(p2pPort+1, 10)
+
+	listener := cfg.OnionListener
+	if listener == "" {
+		listener = "127.0.0.1"
+	}
+	listener, err = network.NormalizeAddress(listener, defaultPort)
+	if err != nil {
+		return "", err
+	}
+	_, port, err := net.SplitHostPort(listener)
+	if err != nil {
+		return "", err
+	}
+	for _, otherListener := range append(append([]string{}, cfg.Listeners...), cfg.RPCListeners...) {
+		_, otherPort, err := net.SplitHostPort(otherListener)
+		if err != nil {
+			return "", err
+		}
+		if otherPort == port {
+			return "", errors.Errorf("the onion listener %s shares its port with the listener %s -- "+
+				"specify another one via --onionlisten", listener, otherListener)
+		}
+	}
+	return listener, nil
+}
+
 // createDefaultConfig copies the file sample-kaspad.conf to the given destination path,
 // and populates it with some randomly generated RPC username and password.
 func createDefaultConfigFile(destinationPath string) error {
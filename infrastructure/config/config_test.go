@@ -7,9 +7,11 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/jessevdk/go-flags"
 	"github.com/stokesnetwork/stokes/domain/consensus/utils/subnetworks"
 
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
+	"github.com/stokesnetwork/stokes/domain/dagconfig"
 )
 
 func TestCreateDefaultConfigFile(t *testing.T) {
@@ -70,3 +72,126 @@ func TestConstants(t *testing.T) {
 		t.Errorf("subnetworks.SubnetworkIDRegistry value was changed from 2, therefore you probably need to update the help text for SubnetworkID")
 	}
 }
+
+func TestOverrideCheckpoints(t *testing.T) {
+	hashA := "0000000000000000000000000000000000000000000000000000000000000001"
+	hashB := "0000000000000000000000000000000000000000000000000000000000000002"
+
+	networkFlags := &NetworkFlags{
+		Checkpoints:     []string{"200:" + hashA, "100:" + hashA},
+		ActiveNetParams: &dagconfig.SimnetParams,
+	}
+	err := networkFlags.overrideCheckpoints()
+	if err != nil {
+		t.Fatalf("overrideCheckpoints: %+v", err)
+	}
+	if networkFlags.ActiveNetParams == &dagconfig.SimnetParams {
+		t.Fatalf("Expected the active params to be a copy of the global simnet params")
+	}
+	if len(dagconfig.SimnetParams.Checkpoints) != 0 {
+		t.Fatalf("Expected the checkpoints of the global simnet params to remain unchanged")
+	}
+
+	// A checkpoint given with --checkpoint replaces the network's checkpoint at the same DAA score
+	overriddenParams := networkFlags.ActiveNetParams
+	networkFlags = &NetworkFlags{
+		Checkpoints:     []string{"100:" + hashB},
+		ActiveNetParams: overriddenParams,
+	}
+	err = networkFlags.overrideCheckpoints()
+	if err != nil {
+		t.Fatalf("overrideCheckpoints: %+v", err)
+	}
+
+	checkpoints := networkFlags.ActiveNetParams.Checkpoints
+	if len(checkpoints) != 2 {
+		t.Fatalf("Expected 2 checkpoints, but got %d", len(checkpoints))
+	}
+	if checkpoints[0].DAAScore != 100 || checkpoints[0].Hash.String() != hashB {
+		t.Fatalf("Expected the checkpoint at DAA score 100 to be overridden, but got %d:%s",
+			checkpoints[0].DAAScore, checkpoints[0].Hash)
+	}
+	if checkpoints[1].DAAScore != 200 || checkpoints[1].Hash.String() != hashA {
+		t.Fatalf("Unexpected checkpoint %d:%s", checkpoints[1].DAAScore, checkpoints[1].Hash)
+	}
+	if overriddenParams.Checkpoints[0].Hash.String() != hashA {
+		t.Fatalf("Expected the checkpoints of the previous params to remain unchanged")
+	}
+
+	for _, invalidCheckpoint := range []string{"100", "abc:" + hashA, "100:abc", "100:" + hashA + ":1"} {
+		networkFlags := &NetworkFlags{Checkpoints: []string{invalidCheckpoint}, ActiveNetParams: &dagconfig.SimnetParams}
+		err := networkFlags.overrideCheckpoints()
+		if err == nil {
+			t.Fatalf("Expected checkpoint %s to be rejected", invalidCheckpoint)
+		}
+	}
+}
+
+func TestOnionListener(t *testing.T) {
+	tests := []struct {
+		name             string
+		onionListener    string
+		expectedListener string
+		expectedErr      bool
+	}{
+		{name: "default", expectedListener: "127.0.0.1:17112"},
+		{name: "default port", onionListener: "127.0.0.2", expectedListener: "127.0.0.2:17112"},
+		{name: "given", onionListener: "127.0.0.1:9999", expectedListener: "127.0.0.1:9999"},
+		{name: "P2P port", onionListener: "127.0.0.1:17111", expectedErr: true},
+		{name: "RPC port", onionListener: "127.0.0.1:17110", expectedErr: true},
+	}
+	for _, test := range tests {
+		cfg := &Config{Flags: &Flags{
+			Listeners:     []string{"0.0.0.0:17111"},
+			RPCListeners:  []string{"127.0.0.1:17110"},
+			OnionListener: test.onionListener,
+		}}
+		listener, err := onionListener(cfg)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, but got the listener %s", test.name, listener)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if listener != test.expectedListener {
+			t.Errorf("%s: expected the listener %s, but got %s", test.name, test.expectedListener, listener)
+		}
+	}
+}
+
+func TestResolveNetworkWithNetParamsFile(t *testing.T) {
+	netParamsFile := filepath.Join(t.TempDir(), "netparams.json")
+	err := ioutil.WriteFile(netParamsFile, []byte(`{
+		"name": "stokes-configtestnet",
+		"net": 1129594699,
+		"rpcPort": "17710",
+		"defaultPort": "17711",
+		"prefix": "stokessim",
+		"powMax": "7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"genesis": {"timeInMilliseconds": 1760309945665, "bits": 545259519, "coinbasePayload": "00"},
+		"finalityDurationInMilliSeconds": 600000,
+		"mergeDepth": 300
+	}`), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+
+	networkFlags := &NetworkFlags{NetParamsFile: netParamsFile, Simnet: true}
+	err = networkFlags.ResolveNetwork(flags.NewParser(networkFlags, flags.None))
+	if err == nil {
+		t.Fatalf("Expected --netparams to be rejected along with another network")
+	}
+
+	networkFlags = &NetworkFlags{NetParamsFile: netParamsFile}
+	err = networkFlags.ResolveNetwork(flags.NewParser(networkFlags, flags.None))
+	if err != nil {
+		t.Fatalf("ResolveNetwork: %+v", err)
+	}
+	if networkFlags.NetParams().Name != "stokes-configtestnet" {
+		t.Fatalf("Expected the custom network to be active, but got %s", networkFlags.NetParams().Name)
+	}
+}
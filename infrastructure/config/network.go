@@ -6,6 +6,9 @@ import (
 	"github.com/stokesnetwork/stokes/domain/consensus/model/externalapi"
 	"math/big"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jessevdk/go-flags"
@@ -16,10 +19,12 @@ import (
 
 // NetworkFlags holds the network configuration, that is which network is selected.
 type NetworkFlags struct {
-	Testnet               bool   `long:"testnet" description:"Use the test network"`
-	Simnet                bool   `long:"simnet" description:"Use the simulation test network"`
-	Devnet                bool   `long:"devnet" description:"Use the development test network"`
-	OverrideDAGParamsFile string `long:"override-dag-params-file" description:"Overrides DAG params (allowed only on devnet)"`
+	Testnet               bool     `long:"testnet" description:"Use the test network"`
+	Simnet                bool     `long:"simnet" description:"Use the simulation test network"`
+	Devnet                bool     `long:"devnet" description:"Use the development test network"`
+	NetParamsFile         string   `long:"netparams" description:"Use a custom network defined in the given JSON params file"`
+	OverrideDAGParamsFile string   `long:"override-dag-params-file" description:"Overrides DAG params (allowed only on devnet)"`
+	Checkpoints           []string `long:"checkpoint" description:"Add a checkpoint, or override the network's checkpoint at the same DAA score, in the format <DAA score>:<block hash>"`
 
 	ActiveNetParams *dagconfig.Params
 }
@@ -74,8 +79,11 @@ func (networkFlags *NetworkFlags) ResolveNetwork(parser *flags.Parser) error {
 		numNets++
 		networkFlags.ActiveNetParams = &dagconfig.DevnetParams
 	}
+	if networkFlags.NetParamsFile != "" {
+		numNets++
+	}
 	if numNets > 1 {
-		message := "Multiple networks parameters (testnet, simnet, devnet, etc.) cannot be used" +
+		message := "Multiple networks parameters (testnet, simnet, devnet, netparams, etc.) cannot be used" +
 			"together. Please choose only one network"
 		err := errors.Errorf(message)
 		fmt.Fprintln(os.Stderr, err)
@@ -83,11 +91,24 @@ func (networkFlags *NetworkFlags) ResolveNetwork(parser *flags.Parser) error {
 		return err
 	}
 
+	if networkFlags.NetParamsFile != "" {
+		err := networkFlags.loadNetParamsFile()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+	}
+
 	err := networkFlags.overrideDAGParams()
 	if err != nil {
 		return err
 	}
 
+	err = networkFlags.overrideCheckpoints()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -96,6 +117,21 @@ func (networkFlags *NetworkFlags) NetParams() *dagconfig.Params {
 	return networkFlags.ActiveNetParams
 }
 
+// loadNetParamsFile loads the params of a custom network, and registers the network so
+// that its magic can't be used by another network
+func (networkFlags *NetworkFlags) loadNetParamsFile() error {
+	params, err := dagconfig.LoadParamsFile(networkFlags.NetParamsFile)
+	if err != nil {
+		return err
+	}
+	err = dagconfig.Register(params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to register the network %s", params.Name)
+	}
+	networkFlags.ActiveNetParams = params
+	return nil
+}
+
 func (networkFlags *NetworkFlags) overrideDAGParams() error {
 
 	if networkFlags.OverrideDAGParamsFile == "" {
@@ -216,3 +252,56 @@ func (networkFlags *NetworkFlags) overrideDAGParams() error {
 
 	return nil
 }
+
+// overrideCheckpoints adds the checkpoints given with --checkpoint to the network's checkpoints.
+// A given checkpoint replaces the network's checkpoint at the same DAA score, if there is one.
+func (networkFlags *NetworkFlags) overrideCheckpoints() error {
+	if len(networkFlags.Checkpoints) == 0 {
+		return nil
+	}
+
+	checkpointsByDAAScore := make(map[uint64]externalapi.Checkpoint)
+	for _, checkpoint := range networkFlags.ActiveNetParams.Checkpoints {
+		checkpointsByDAAScore[checkpoint.DAAScore] = checkpoint
+	}
+	for _, checkpointString := range networkFlags.Checkpoints {
+		checkpoint, err := parseCheckpoint(checkpointString)
+		if err != nil {
+			return err
+		}
+		checkpointsByDAAScore[checkpoint.DAAScore] = checkpoint
+	}
+
+	checkpoints := make([]externalapi.Checkpoint, 0, len(checkpointsByDAAScore))
+	for _, checkpoint := range checkpointsByDAAScore {
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].DAAScore < checkpoints[j].DAAScore
+	})
+
+	// The active params may be the global params of a network in dagconfig, so they're copied rather than
+	// modified in place
+	params := *networkFlags.ActiveNetParams
+	params.Checkpoints = checkpoints
+	networkFlags.ActiveNetParams = &params
+
+	return nil
+}
+
+func parseCheckpoint(checkpointString string) (externalapi.Checkpoint, error) {
+	parts := strings.Split(checkpointString, ":")
+	if len(parts) != 2 {
+		return externalapi.Checkpoint{}, errors.Errorf("invalid checkpoint %s: expected <DAA score>:<block hash>",
+			checkpointString)
+	}
+	daaScore, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return externalapi.Checkpoint{}, errors.Wrapf(err, "invalid DAA score in checkpoint %s", checkpointString)
+	}
+	hash, err := externalapi.NewDomainHashFromString(parts[1])
+	if err != nil {
+		return externalapi.Checkpoint{}, errors.Wrapf(err, "invalid block hash in checkpoint %s", checkpointString)
+	}
+	return externalapi.Checkpoint{DAAScore: daaScore, Hash: hash}, nil
+}
@@ -0,0 +1,291 @@
+package addressmanager
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"net"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+// The address manager keeps the addresses it knows in two tables of buckets, the same way
+// Bitcoin's addrman does, so that a peer that floods us with addresses can't crowd out the rest:
+//
+//   - The new table holds addresses we haven't connected to yet. An address is placed by its own
+//     network group and by the network group of its source - the peer that told us about it - and
+//     the addresses from any one source group are confined to newBucketsPerSourceGroup buckets.
+//   - The tried table holds addresses we've connected to. An address is placed by its own network
+//     group, and the addresses of any one group are confined to triedBucketsPerGroup buckets.
+//
+// Buckets and the positions in them are picked with a secret key, so that nobody can craft
+// addresses that land in particular positions. An address that lands in an occupied position
+// in the new table only replaces the address there if that address keeps failing to connect.
+const (
+	newBucketCount           = 1024
+	triedBucketCount         = 256
+	bucketSize               = 64
+	newBucketsPerSourceGroup = 64
+	triedBucketsPerGroup     = 8
+)
+
+type tablePosition struct {
+	bucket int
+	slot   int
+}
+
+type addressTable struct {
+	buckets []map[int]*address
+}
+
+func newAddressTable(bucketCount int) *addressTable {
+	return &addressTable{
+		buckets: make([]map[int]*address, bucketCount),
+	}
+}
+
+func (table *addressTable) get(position tablePosition) (*address, bool) {
+	address, ok := table.buckets[position.bucket][position.slot]
+	return address, ok
+}
+
+func (table *addressTable) set(position tablePosition, entry *address) {
+	if table.buckets[position.bucket] == nil {
+		table.buckets[position.bucket] = make(map[int]*address)
+	}
+	table.buckets[position.bucket][position.slot] = entry
+}
+
+// remove removes the address with the given key from the given position, if it's there
+func (table *addressTable) remove(position tablePosition, key addressKey) {
+	address, ok := table.get(position)
+	if !ok || netAddressKey(address.netAddress) != key {
+		return
+	}
+	delete(table.buckets[position.bucket], position.slot)
+}
+
+// nonEmptyBuckets returns the indexes of the buckets that hold any addresses, in random order
+func (table *addressTable) nonEmptyBuckets() []int {
+	nonEmptyBuckets := make([]int, 0)
+	for bucket, addresses := range table.buckets {
+		if len(addresses) > 0 {
+			nonEmptyBuckets = append(nonEmptyBuckets, bucket)
+		}
+	}
+	rand.Shuffle(len(nonEmptyBuckets), func(i, j int) {
+		nonEmptyBuckets[i], nonEmptyBuckets[j] = nonEmptyBuckets[j], nonEmptyBuckets[i]
+	})
+	return nonEmptyBuckets
+}
+
+func (am *AddressManager) bucketingHash(data ...[]byte) uint64 {
+	hasher := sha256.New()
+	hasher.Write(am.bucketingKey)
+	for _, datum := range data {
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(datum)))
+		hasher.Write(length[:])
+		hasher.Write(datum)
+	}
+	return binary.LittleEndian.Uint64(hasher.Sum(nil))
+}
+
+func uint64Bytes(value uint64) []byte {
+	bytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bytes, value)
+	return bytes
+}
+
+// ipGroupKey returns the group key of the address with the given IP. The IPs of overlay
+// addresses are made of their network and the start of their overlay address, which is
+// enough to find their group.
+func (am *AddressManager) ipGroupKey(ip ipv6) []byte {
+	netAddress := &appmessage.NetAddress{IP: net.IP(ip[:])}
+	if networkID := appmessage.OverlayNetworkOfIP(netAddress.IP); networkID != appmessage.NetworkIDIP {
+		netAddress.NetworkID = networkID
+		netAddress.OverlayAddress = ip[appmessage.OverlayIPPrefixSize:]
+	}
+	return []byte(am.GroupKey(netAddress))
+}
+
+func (am *AddressManager) newTablePosition(key addressKey, source ipv6) tablePosition {
+	group := am.ipGroupKey(key.address)
+	sourceGroup := am.ipGroupKey(source)
+	sourceGroupBucket := am.bucketingHash([]byte("new-source"), group, sourceGroup) % newBucketsPerSourceGroup
+	bucket := am.bucketingHash([]byte("new-bucket"), sourceGroup, uint64Bytes(sourceGroupBucket)) % newBucketCount
+	slot := am.bucketingHash([]byte("new-slot"), uint64Bytes(bucket), am.store.serializeAddressKey(key)) % bucketSize
+	return tablePosition{bucket: int(bucket), slot: int(slot)}
+}
+
+func (am *AddressManager) triedTablePosition(key addressKey) tablePosition {
+	serializedKey := am.store.serializeAddressKey(key)
+	groupBucket := am.bucketingHash([]byte("tried-group"), serializedKey) % triedBucketsPerGroup
+	bucket := am.bucketingHash([]byte("tried-bucket"), am.ipGroupKey(key.address),
+		uint64Bytes(groupBucket)) % triedBucketCount
+	slot := am.bucketingHash([]byte("tried-slot"), uint64Bytes(bucket), serializedKey) % bucketSize
+	return tablePosition{bucket: int(bucket), slot: int(slot)}
+}
+
+// tablePosition returns the table the given address belongs in and its position there
+func (am *AddressManager) tablePosition(key addressKey, address *address) (*addressTable, tablePosition) {
+	if address.isTried {
+		return am.triedTable, am.triedTablePosition(key)
+	}
+	return am.newTable, am.newTablePosition(key, address.source)
+}
+
+// restoreTables places the addresses loaded from the database in the tables. Addresses that
+// collide with others are moved from the tried table to the new one, or removed from the new one.
+func (am *AddressManager) restoreTables() error {
+	for _, address := range am.store.getAllNotBanned() {
+		if !address.isTried {
+			continue
+		}
+		key := netAddressKey(address.netAddress)
+		position := am.triedTablePosition(key)
+		if _, ok := am.triedTable.get(position); !ok {
+			am.triedTable.set(position, address)
+			continue
+		}
+		address.isTried = false
+		err := am.store.updateNotBanned(key, address)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, address := range am.store.getAllNotBanned() {
+		if address.isTried {
+			continue
+		}
+		key := netAddressKey(address.netAddress)
+		position := am.newTablePosition(key, address.source)
+		if _, ok := am.newTable.get(position); !ok {
+			am.newTable.set(position, address)
+			continue
+		}
+		err := am.store.remove(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeInNewTableNoLock places the given address in the new table, removing the address that
+// occupies its position, if any
+func (am *AddressManager) placeInNewTableNoLock(key addressKey, address *address) error {
+	position := am.newTablePosition(key, address.source)
+	if occupant, ok := am.newTable.get(position); ok {
+		err := am.removeAddressNoLock(occupant.netAddress)
+		if err != nil {
+			return err
+		}
+	}
+	am.newTable.set(position, address)
+	return nil
+}
+
+// moveToTriedTableNoLock moves the given address from the new table to the tried table. If its
+// position in the tried table is occupied, the address there is moved back to the new table.
+func (am *AddressManager) moveToTriedTableNoLock(key addressKey, address *address) error {
+	am.newTable.remove(am.newTablePosition(key, address.source), key)
+
+	position := am.triedTablePosition(key)
+	if evicted, ok := am.triedTable.get(position); ok {
+		evictedKey := netAddressKey(evicted.netAddress)
+		am.triedTable.remove(position, evictedKey)
+		evicted.isTried = false
+		err := am.placeInNewTableNoLock(evictedKey, evicted)
+		if err != nil {
+			return err
+		}
+		err = am.store.updateNotBanned(evictedKey, evicted)
+		if err != nil {
+			return err
+		}
+	}
+
+	address.isTried = true
+	am.triedTable.set(position, address)
+	return nil
+}
+
+// isReachable returns whether we can connect to the given address. Overlay addresses are
+// kept and gossiped either way, but onion services can only be connected to through a Tor
+// proxy, and I2P destinations not at all.
+func (am *AddressManager) isReachable(netAddress *appmessage.NetAddress) bool {
+	switch netAddress.NetworkID {
+	case appmessage.NetworkIDIP:
+		return true
+	case appmessage.NetworkIDTorV3:
+		return am.cfg.OnionReachable
+	default:
+		return false
+	}
+}
+
+// randomAddressesNoLock picks up to count addresses, no two from the same network group, by going
+// over the non-empty buckets of both tables in random order and picking at most one address from
+// each bucket on every pass. Picking by bucket rather than by address means that an attacker who
+// filled the buckets of their own source groups is no more likely to be picked than anyone else.
+func (am *AddressManager) randomAddressesNoLock(count int, exceptions []*appmessage.NetAddress,
+	groupExceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
+
+	excludedKeys := netAddressesKeys(exceptions)
+	usedGroups := make(map[string]struct{})
+	for _, groupException := range groupExceptions {
+		usedGroups[am.GroupKey(groupException)] = struct{}{}
+	}
+	isGroupAvailable := func(group string) bool {
+		if group == localGroupKey || group == unroutableGroupKey {
+			// Local and unroutable addresses are only known in test setups, where there's
+			// no group diversity to be had
+			return true
+		}
+		_, ok := usedGroups[group]
+		return !ok
+	}
+
+	result := make([]*appmessage.NetAddress, 0, count)
+	for len(result) < count {
+		pickedInPass := false
+		newBuckets := am.newTable.nonEmptyBuckets()
+		triedBuckets := am.triedTable.nonEmptyBuckets()
+		for len(result) < count && (len(newBuckets) > 0 || len(triedBuckets) > 0) {
+			// Pick from either table with equal chance
+			var addresses map[int]*address
+			if len(triedBuckets) == 0 || (len(newBuckets) > 0 && rand.Intn(2) == 0) {
+				addresses = am.newTable.buckets[newBuckets[0]]
+				newBuckets = newBuckets[1:]
+			} else {
+				addresses = am.triedTable.buckets[triedBuckets[0]]
+				triedBuckets = triedBuckets[1:]
+			}
+
+			candidates := make([]*address, 0, len(addresses))
+			for _, address := range addresses {
+				key := netAddressKey(address.netAddress)
+				if excludedKeys[key] || !am.isReachable(address.netAddress) ||
+					!isGroupAvailable(am.GroupKey(address.netAddress)) {
+					continue
+				}
+				candidates = append(candidates, address)
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+
+			picked := am.random.RandomAddresses(candidates, 1)[0]
+			result = append(result, picked)
+			excludedKeys[netAddressKey(picked)] = true
+			usedGroups[am.GroupKey(picked)] = struct{}{}
+			pickedInPass = true
+		}
+		if !pickedInPass {
+			break
+		}
+	}
+	return result
+}
@@ -16,8 +16,11 @@ import (
 )
 
 const (
-	maxAddresses                   = 4096
 	connectionFailedCountForRemove = 4
+
+	// connectionFailedCountForReplace is the connection failure count from which an address in
+	// the new table gives up its position to a newly heard of address that lands on it
+	connectionFailedCountForReplace = 2
 )
 
 // addressRandomizer is the interface for the randomizer needed for the AddressManager.
@@ -34,6 +37,14 @@ type addressKey struct {
 type address struct {
 	netAddress            *appmessage.NetAddress
 	connectionFailedCount uint64
+	isTried               bool
+	source                ipv6
+}
+
+func toIPv6(ip net.IP) ipv6 {
+	var result ipv6
+	copy(result[:], ip.To16())
+	return result
 }
 
 type ipv6 [net.IPv6len]byte
@@ -58,6 +69,9 @@ func netAddressKey(netAddress *appmessage.NetAddress) addressKey {
 // peers on the Kaspa network.
 type AddressManager struct {
 	store          *addressStore
+	newTable       *addressTable
+	triedTable     *addressTable
+	bucketingKey   []byte
 	localAddresses *localAddressManager
 	mutex          sync.Mutex
 	cfg            *Config
@@ -70,72 +84,105 @@ func New(cfg *Config, database database.Database) (*AddressManager, error) {
 	if err != nil {
 		return nil, err
 	}
+	bucketingKey, err := addressStore.bucketingKey()
+	if err != nil {
+		return nil, err
+	}
 	localAddresses, err := newLocalAddressManager(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AddressManager{
+	addressManager := &AddressManager{
 		store:          addressStore,
+		newTable:       newAddressTable(newBucketCount),
+		triedTable:     newAddressTable(triedBucketCount),
+		bucketingKey:   bucketingKey,
 		localAddresses: localAddresses,
 		random:         NewAddressRandomize(connectionFailedCountForRemove),
 		cfg:            cfg,
-	}, nil
+	}
+	err = addressManager.restoreTables()
+	if err != nil {
+		return nil, err
+	}
+	return addressManager, nil
 }
 
-func (am *AddressManager) addAddressNoLock(netAddress *appmessage.NetAddress) error {
+func (am *AddressManager) addAddressNoLock(netAddress *appmessage.NetAddress, source *appmessage.NetAddress) error {
 	if !IsRoutable(netAddress, am.cfg.AcceptUnroutable) {
 		return nil
 	}
 
 	key := netAddressKey(netAddress)
-	// We mark `connectionFailedCount` as 0 only after first success
-	address := &address{netAddress: netAddress, connectionFailedCount: 1}
-	err := am.store.add(key, address)
-	if err != nil {
-		return err
+	if am.store.isNotBanned(key) {
+		return nil
 	}
 
-	if am.store.notBannedCount() > maxAddresses {
-		allAddresses := am.store.getAllNotBanned()
-
-		maxConnectionFailedCount := uint64(0)
-		toRemove := allAddresses[0]
-		for _, address := range allAddresses[1:] {
-			if address.connectionFailedCount > maxConnectionFailedCount {
-				maxConnectionFailedCount = address.connectionFailedCount
-				toRemove = address
-			}
+	// We mark `connectionFailedCount` as 0 only after first success
+	address := &address{netAddress: netAddress, connectionFailedCount: 1, source: toIPv6(source.IP)}
+	position := am.newTablePosition(key, address.source)
+	if occupant, ok := am.newTable.get(position); ok {
+		if occupant.connectionFailedCount < connectionFailedCountForReplace {
+			return nil
 		}
-
-		err := am.removeAddressNoLock(toRemove.netAddress)
+		err := am.removeAddressNoLock(occupant.netAddress)
 		if err != nil {
 			return err
 		}
 	}
+
+	err := am.store.add(key, address)
+	if err != nil {
+		return err
+	}
+	am.newTable.set(position, address)
 	return nil
 }
 
-func (am *AddressManager) removeAddressNoLock(address *appmessage.NetAddress) error {
-	key := netAddressKey(address)
+func (am *AddressManager) removeAddressNoLock(netAddress *appmessage.NetAddress) error {
+	key := netAddressKey(netAddress)
+	address, ok := am.store.getNotBanned(key)
+	if ok {
+		table, position := am.tablePosition(key, address)
+		table.remove(position, key)
+	}
 	return am.store.remove(key)
 }
 
-// AddAddress adds address to the address manager
+// AddAddress adds address to the address manager, as its own source
 func (am *AddressManager) AddAddress(address *appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.addAddressNoLock(address)
+	return am.addAddressNoLock(address, address)
 }
 
-// AddAddresses adds addresses to the address manager
+// AddAddresses adds addresses to the address manager, each as its own source
 func (am *AddressManager) AddAddresses(addresses ...*appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
 	for _, address := range addresses {
-		err := am.addAddressNoLock(address)
+		err := am.addAddressNoLock(address, address)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAddressesFromSource adds addresses that were received from source to the address manager.
+// All the addresses received from the network group of a source share a limited number of buckets,
+// so that a single peer can't flood the address manager.
+func (am *AddressManager) AddAddressesFromSource(source *appmessage.NetAddress,
+	addresses ...*appmessage.NetAddress) error {
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for _, address := range addresses {
+		err := am.addAddressNoLock(address, source)
 		if err != nil {
 			return err
 		}
@@ -160,14 +207,14 @@ func (am *AddressManager) MarkConnectionFailure(address *appmessage.NetAddress)
 	key := netAddressKey(address)
 	entry, ok := am.store.getNotBanned(key)
 	if !ok {
-		return errors.Errorf("address %s is not registered with the address manager", address.TCPAddress())
+		return errors.Errorf("address %s is not registered with the address manager", address)
 	}
 	entry.connectionFailedCount = entry.connectionFailedCount + 1
 
 	if entry.connectionFailedCount >= connectionFailedCountForRemove {
 		log.Debugf("Address %s has failed %d connection attempts - removing from address manager",
 			address, entry.connectionFailedCount)
-		return am.store.remove(key)
+		return am.removeAddressNoLock(address)
 	}
 	return am.store.updateNotBanned(key, entry)
 }
@@ -181,9 +228,15 @@ func (am *AddressManager) MarkConnectionSuccess(address *appmessage.NetAddress)
 	key := netAddressKey(address)
 	entry, ok := am.store.getNotBanned(key)
 	if !ok {
-		return errors.Errorf("address %s is not registered with the address manager", address.TCPAddress())
+		return errors.Errorf("address %s is not registered with the address manager", address)
 	}
 	entry.connectionFailedCount = 0
+	if !entry.isTried {
+		err := am.moveToTriedTableNoLock(key, entry)
+		if err != nil {
+			return err
+		}
+	}
 	return am.store.updateNotBanned(key, entry)
 }
 
@@ -203,18 +256,16 @@ func (am *AddressManager) BannedAddresses() []*appmessage.NetAddress {
 	return am.store.getAllBannedNetAddresses()
 }
 
-// notBannedAddressesWithException returns all not banned addresses with excpetion
-func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessage.NetAddress) []*address {
+// RandomAddresses returns up to count addresses at random that aren't banned, aren't in exceptions
+// and can be connected to - see isReachable. No two of the returned addresses share a network group, and none of them shares a network group
+// with any of groupExceptions.
+func (am *AddressManager) RandomAddresses(count int, exceptions []*appmessage.NetAddress,
+	groupExceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
+
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.store.getAllNotBannedNetAddressesWithout(exceptions)
-}
-
-// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions
-func (am *AddressManager) RandomAddresses(count int, exceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
-	validAddresses := am.notBannedAddressesWithException(exceptions)
-	return am.random.RandomAddresses(validAddresses, count)
+	return am.randomAddressesNoLock(count, exceptions, groupExceptions)
 }
 
 // BestLocalAddress returns the most appropriate local address to use
@@ -223,21 +274,33 @@ func (am *AddressManager) BestLocalAddress(remoteAddress *appmessage.NetAddress)
 	return am.localAddresses.bestLocalAddress(remoteAddress)
 }
 
+// AddLocalNetAddress adds netAddress to the local addresses advertised to peers, with the
+// priority of the method it was discovered by
+func (am *AddressManager) AddLocalNetAddress(netAddress *appmessage.NetAddress, priority AddressPriority) error {
+	return am.localAddresses.addLocalNetAddress(netAddress, priority)
+}
+
+// RemoveLocalNetAddress removes netAddress from the local addresses advertised to peers, for
+// when it's no longer reachable, such as an external address that a port mapping moved away from
+func (am *AddressManager) RemoveLocalNetAddress(netAddress *appmessage.NetAddress) {
+	am.localAddresses.removeLocalNetAddress(netAddress)
+}
+
 // Ban marks the given address as banned
 func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
 	keyToBan := netAddressKey(addressToBan)
-	keysToDelete := make([]addressKey, 0)
+	addressesToDelete := make([]*appmessage.NetAddress, 0)
 	for _, address := range am.store.getAllNotBannedNetAddresses() {
 		key := netAddressKey(address)
 		if key.address.equal(keyToBan.address) {
-			keysToDelete = append(keysToDelete, key)
+			addressesToDelete = append(addressesToDelete, address)
 		}
 	}
-	for _, key := range keysToDelete {
-		err := am.store.remove(key)
+	for _, address := range addressesToDelete {
+		err := am.removeAddressNoLock(address)
 		if err != nil {
 			return err
 		}
@@ -255,7 +318,7 @@ func (am *AddressManager) Unban(address *appmessage.NetAddress) error {
 	key := netAddressKey(address)
 	if !am.store.isBanned(key) {
 		return errors.Wrapf(ErrAddressNotFound, "address %s "+
-			"is not registered with the address manager as banned", address.TCPAddress())
+			"is not registered with the address manager as banned", address)
 	}
 
 	return am.store.removeBanned(key)
@@ -274,7 +337,7 @@ func (am *AddressManager) IsBanned(address *appmessage.NetAddress) (bool, error)
 	if !am.store.isBanned(key) {
 		if !am.store.isNotBanned(key) {
 			return false, errors.Wrapf(ErrAddressNotFound, "address %s "+
-				"is not registered with the address manager", address.TCPAddress())
+				"is not registered with the address manager", address)
 		}
 		return false, nil
 	}
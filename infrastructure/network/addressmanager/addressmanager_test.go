@@ -11,6 +11,7 @@ import (
 
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/infrastructure/config"
+	"github.com/stokesnetwork/stokes/infrastructure/db/database"
 	"github.com/stokesnetwork/stokes/infrastructure/db/database/ldb"
 	"github.com/stokesnetwork/stokes/util/mstime"
 )
@@ -117,6 +118,36 @@ func TestBestLocalAddress(t *testing.T) {
 	}
 }
 
+func TestRemoveLocalNetAddress(t *testing.T) {
+	amgr, teardown := newAddressManagerForTest(t, "TestRemoveLocalNetAddress")
+	defer teardown()
+
+	remoteAddress := &appmessage.NetAddress{IP: net.ParseIP("204.124.1.1")}
+	oldAddress := &appmessage.NetAddress{IP: net.ParseIP("204.124.8.100"), Port: 16111}
+	newAddress := &appmessage.NetAddress{IP: net.ParseIP("204.124.8.200"), Port: 16111}
+
+	err := amgr.AddLocalNetAddress(oldAddress, UpnpPrio)
+	if err != nil {
+		t.Fatalf("AddLocalNetAddress: %s", err)
+	}
+	if got := amgr.BestLocalAddress(remoteAddress); !got.IP.Equal(oldAddress.IP) {
+		t.Fatalf("Expected the best local address to be %s, but got %s", oldAddress.IP, got.IP)
+	}
+
+	// The removed address is no longer advertised, and the one that replaces it is
+	amgr.RemoveLocalNetAddress(oldAddress)
+	if got := amgr.BestLocalAddress(remoteAddress); !got.IP.Equal(net.IPv4zero) {
+		t.Fatalf("Expected no local address to be advertised once it was removed, but got %s", got.IP)
+	}
+	err = amgr.AddLocalNetAddress(newAddress, UpnpPrio)
+	if err != nil {
+		t.Fatalf("AddLocalNetAddress: %s", err)
+	}
+	if got := amgr.BestLocalAddress(remoteAddress); !got.IP.Equal(newAddress.IP) {
+		t.Fatalf("Expected the best local address to be %s, but got %s", newAddress.IP, got.IP)
+	}
+}
+
 func TestAddressManager(t *testing.T) {
 	addressManager, teardown := newAddressManagerForTest(t, "TestAddressManager")
 	defer teardown()
@@ -326,49 +357,373 @@ func TestOverfillAddressManager(t *testing.T) {
 		return testAddresses
 	}
 
-	// Add a single test address to the address manager
-	testAddress := &appmessage.NetAddress{IP: net.IP{5, 6, 0, 0}, Timestamp: mstime.Now()}
-	err := addressManager.AddAddress(testAddress)
+	// Add many addresses of a single network group, each as its own source, to the address
+	// manager. They all land in the same bucket of the new table.
+	addresses := generateTestAddresses(128 * 128)
+	err := addressManager.AddAddresses(addresses...)
+	if err != nil {
+		t.Fatalf("AddAddresses: %s", err)
+	}
+
+	// Make sure that the address manager kept at most a bucket's worth of them
+	returnedAddresses := addressManager.Addresses()
+	if len(returnedAddresses) == 0 || len(returnedAddresses) > bucketSize {
+		t.Fatalf("Unexpected address amount. Want: between 1 and %d, got: %d", bucketSize, len(returnedAddresses))
+	}
+
+	// Mark one of the kept addresses as a connection failure
+	failedAddress := returnedAddresses[0]
+	err = addressManager.MarkConnectionFailure(failedAddress)
 	if err != nil {
-		t.Fatalf("AddAddress: %s", err)
+		t.Fatalf("MarkConnectionFailure: %s", err)
 	}
 
-	// Add `maxAddresses-1` addresses to the address manager
-	addresses := generateTestAddresses(maxAddresses - 1)
+	// Add the addresses again. The ones that collided with the failed address now replace it.
 	err = addressManager.AddAddresses(addresses...)
 	if err != nil {
 		t.Fatalf("AddAddresses: %s", err)
 	}
 
-	// Make sure that it now contains exactly `maxAddresses` entries
-	returnedAddresses := addressManager.Addresses()
-	if len(returnedAddresses) != maxAddresses {
-		t.Fatalf("Unexpected address amount. Want: %d, got: %d", maxAddresses, len(returnedAddresses))
+	// Make sure that the address manager still holds the same amount of addresses
+	newReturnedAddresses := addressManager.Addresses()
+	if len(newReturnedAddresses) != len(returnedAddresses) {
+		t.Fatalf("Unexpected address amount. Want: %d, got: %d", len(returnedAddresses), len(newReturnedAddresses))
+	}
+
+	// Make sure that the failed address is no longer in the address manager
+	for _, address := range newReturnedAddresses {
+		if address.IP.Equal(failedAddress.IP) {
+			t.Fatalf("Unexpectedly found the failed address in the returned addresses")
+		}
+	}
+}
+
+func TestAddressFlooding(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestAddressFlooding")
+	defer teardown()
+
+	// Learn of honest addresses from many network groups, and connect to half of them
+	const honestAddressCount = 256
+	honestAddresses := make(map[addressKey]struct{}, honestAddressCount)
+	for i := 0; i < honestAddressCount; i++ {
+		honestAddress := &appmessage.NetAddress{IP: net.IP{45, byte(i), 0, 1}, Port: 16111, Timestamp: mstime.Now()}
+		err := addressManager.AddAddress(honestAddress)
+		if err != nil {
+			t.Fatalf("AddAddress: %s", err)
+		}
+		if i%2 == 0 {
+			err = addressManager.MarkConnectionSuccess(honestAddress)
+			if err != nil {
+				t.Fatalf("MarkConnectionSuccess: %s", err)
+			}
+		}
+		honestAddresses[netAddressKey(honestAddress)] = struct{}{}
+	}
+
+	// An attacker floods us with addresses from thousands of network groups
+	attacker := &appmessage.NetAddress{IP: net.IP{66, 66, 0, 1}, Port: 16111}
+	const floodMessageCount = 100
+	for i := 0; i < floodMessageCount; i++ {
+		floodAddresses := make([]*appmessage.NetAddress, 0, GetAddressesMax)
+		for j := 0; j < GetAddressesMax; j++ {
+			floodAddresses = append(floodAddresses, &appmessage.NetAddress{
+				IP:        net.IP{byte(130 + j%80), byte(j / 80), byte(i), 1},
+				Port:      16111,
+				Timestamp: mstime.Now(),
+			})
+		}
+		err := addressManager.AddAddressesFromSource(attacker, floodAddresses...)
+		if err != nil {
+			t.Fatalf("AddAddressesFromSource: %s", err)
+		}
+	}
+
+	// No honest address was pushed out, and the flood takes up only the buckets of its source group
+	keptHonestAddressCount := 0
+	keptAttackerAddressCount := 0
+	for _, address := range addressManager.Addresses() {
+		if _, ok := honestAddresses[netAddressKey(address)]; ok {
+			keptHonestAddressCount++
+		} else {
+			keptAttackerAddressCount++
+		}
+	}
+	if keptHonestAddressCount != honestAddressCount {
+		t.Fatalf("Expected all %d honest addresses to be kept, but %d were", honestAddressCount, keptHonestAddressCount)
+	}
+	if keptAttackerAddressCount > newBucketsPerSourceGroup*bucketSize {
+		t.Fatalf("Expected at most %d attacker addresses to be kept, but %d were",
+			newBucketsPerSourceGroup*bucketSize, keptAttackerAddressCount)
 	}
 
-	// Mark the first test address as a connection failure
-	err = addressManager.MarkConnectionFailure(testAddress)
+	// Even though most of the known addresses are the attacker's, most of the addresses picked
+	// for outgoing connections are honest
+	const outgoingConnectionCount = 8
+	const sampleCount = 200
+	pickedAttackerAddressCount := 0
+	for i := 0; i < sampleCount; i++ {
+		for _, address := range addressManager.RandomAddresses(outgoingConnectionCount, nil, nil) {
+			if _, ok := honestAddresses[netAddressKey(address)]; !ok {
+				pickedAttackerAddressCount++
+			}
+		}
+	}
+	attackerShare := float64(pickedAttackerAddressCount) / (sampleCount * outgoingConnectionCount)
+	if attackerShare > 0.5 {
+		t.Fatalf("Expected the attacker to get less than half of the outgoing connections, but got %.2f "+
+			"of them while holding %.2f of the addresses", attackerShare,
+			float64(keptAttackerAddressCount)/float64(keptAttackerAddressCount+keptHonestAddressCount))
+	}
+}
+
+func TestRandomAddressesNetworkGroups(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestRandomAddressesNetworkGroups")
+	defer teardown()
+
+	for _, firstOctets := range [][2]byte{{1, 2}, {5, 6}, {9, 9}} {
+		for i := byte(0); i < 10; i++ {
+			err := addressManager.AddAddress(&appmessage.NetAddress{
+				IP:        net.IP{firstOctets[0], firstOctets[1], i, 1},
+				Timestamp: mstime.Now(),
+			})
+			if err != nil {
+				t.Fatalf("AddAddress: %s", err)
+			}
+		}
+	}
+
+	// We're already connected to a peer in 9.9.0.0/16
+	outgoingAddresses := []*appmessage.NetAddress{{IP: net.IP{9, 9, 100, 1}}}
+	for i := 0; i < 20; i++ {
+		addresses := addressManager.RandomAddresses(10, nil, outgoingAddresses)
+		if len(addresses) != 2 {
+			t.Fatalf("Expected one address from each of the 2 other network groups, but got %d addresses",
+				len(addresses))
+		}
+		groups := make(map[string]struct{})
+		for _, address := range addresses {
+			group := addressManager.GroupKey(address)
+			if group == addressManager.GroupKey(outgoingAddresses[0]) {
+				t.Fatalf("Got address %s from the network group of an outgoing peer", address.IP)
+			}
+			if _, ok := groups[group]; ok {
+				t.Fatalf("Got two addresses from network group %s", group)
+			}
+			groups[group] = struct{}{}
+		}
+	}
+}
+
+func TestRestoreAddressTables(t *testing.T) {
+	cfg := config.DefaultConfig()
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
 	if err != nil {
-		t.Fatalf("MarkConnectionFailure: %s", err)
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	source := &appmessage.NetAddress{IP: net.ParseIP("66.66.0.1")}
+	triedAddress := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Timestamp: mstime.Now()}
+	newAddress := &appmessage.NetAddress{IP: net.ParseIP("5.6.8.8"), Timestamp: mstime.Now()}
+	err = addressManager.AddAddressesFromSource(source, triedAddress, newAddress)
+	if err != nil {
+		t.Fatalf("AddAddressesFromSource: %s", err)
+	}
+	err = addressManager.MarkConnectionSuccess(triedAddress)
+	if err != nil {
+		t.Fatalf("MarkConnectionSuccess: %s", err)
+	}
+	bucketingKey := addressManager.bucketingKey
+
+	// Recreate the address manager with the same database
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	if !reflect.DeepEqual(addressManager.bucketingKey, bucketingKey) {
+		t.Fatalf("The bucketing key changed after a restart")
+	}
+
+	triedKey := netAddressKey(triedAddress)
+	restoredTriedAddress, ok := addressManager.triedTable.get(addressManager.triedTablePosition(triedKey))
+	if !ok || netAddressKey(restoredTriedAddress.netAddress) != triedKey {
+		t.Fatalf("Expected %s to be restored to the tried table", triedAddress.IP)
+	}
+	newKey := netAddressKey(newAddress)
+	restoredNewAddress, ok := addressManager.newTable.get(addressManager.newTablePosition(newKey, toIPv6(source.IP)))
+	if !ok || netAddressKey(restoredNewAddress.netAddress) != newKey {
+		t.Fatalf("Expected %s to be restored to the new table under its source", newAddress.IP)
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestAnchors")
+	defer teardown()
+
+	anchors, err := addressManager.TakeAnchors()
+	if err != nil {
+		t.Fatalf("TakeAnchors: %s", err)
+	}
+	if len(anchors) != 0 {
+		t.Fatalf("Expected no anchors, but got %d", len(anchors))
+	}
+
+	expectedAnchors := []*appmessage.NetAddress{
+		{IP: net.ParseIP("1.2.3.4"), Port: 16111},
+		{IP: net.ParseIP("2602:100:abcd::102"), Port: 12345},
+	}
+	err = addressManager.SetAnchors(expectedAnchors)
+	if err != nil {
+		t.Fatalf("SetAnchors: %s", err)
+	}
+
+	anchors, err = addressManager.TakeAnchors()
+	if err != nil {
+		t.Fatalf("TakeAnchors: %s", err)
+	}
+	if len(anchors) != len(expectedAnchors) {
+		t.Fatalf("Expected %d anchors, but got %d", len(expectedAnchors), len(anchors))
+	}
+	for i, anchor := range anchors {
+		if !anchor.IP.Equal(expectedAnchors[i].IP) || anchor.Port != expectedAnchors[i].Port {
+			t.Fatalf("Expected anchor %s, but got %s", expectedAnchors[i].TCPAddress(), anchor.TCPAddress())
+		}
+	}
+
+	// Anchors are only taken once
+	anchors, err = addressManager.TakeAnchors()
+	if err != nil {
+		t.Fatalf("TakeAnchors: %s", err)
+	}
+	if len(anchors) != 0 {
+		t.Fatalf("Expected the anchors to be forgotten once taken, but got %d", len(anchors))
+	}
+
+	// Anchors stored in an unknown format, such as the unversioned address keys they were first
+	// stored as, are dropped
+	var unversionedAnchors []byte
+	for _, anchor := range expectedAnchors {
+		unversionedAnchors = append(unversionedAnchors,
+			addressManager.store.serializeAddressKey(netAddressKey(anchor))...)
+	}
+	err = addressManager.store.database.Put(anchorsKey, unversionedAnchors)
+	if err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	anchors, err = addressManager.TakeAnchors()
+	if err != nil {
+		t.Fatalf("TakeAnchors: %s", err)
+	}
+	if len(anchors) != 0 {
+		t.Fatalf("Expected anchors in an unknown format to be dropped, but got %d", len(anchors))
+	}
+	_, err = addressManager.store.database.Get(anchorsKey)
+	if !database.IsNotFoundError(err) {
+		t.Fatalf("Expected anchors in an unknown format to be deleted, but got: %v", err)
+	}
+}
+
+func TestOverlayAddresses(t *testing.T) {
+	cfg := config.DefaultConfig()
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	overlayAddress := func(networkID appmessage.NetworkID, firstByte byte) *appmessage.NetAddress {
+		address := make([]byte, appmessage.OverlayAddressSize)
+		address[0] = firstByte
+		netAddress, err := appmessage.NewOverlayNetAddress(mstime.Now(), networkID, address, 16111)
+		if err != nil {
+			t.Fatalf("NewOverlayNetAddress: %s", err)
+		}
+		return netAddress
+	}
+	onionAddress := overlayAddress(appmessage.NetworkIDTorV3, 0x10)
+	i2pAddress := overlayAddress(appmessage.NetworkIDI2P, 0x20)
+	ipAddress := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Port: 16111, Timestamp: mstime.Now()}
+
+	if addressManager.GroupKey(onionAddress) != "onion/1" || addressManager.GroupKey(i2pAddress) != "i2p/2" {
+		t.Fatalf("Unexpected group keys %s and %s", addressManager.GroupKey(onionAddress), addressManager.GroupKey(i2pAddress))
+	}
+	// An IP in the range of onion addresses that isn't an onion address isn't routable
+	fakeOnionAddress := &appmessage.NetAddress{IP: onionAddress.IP, Port: 16111, Timestamp: mstime.Now()}
+	if IsRoutable(fakeOnionAddress, false) {
+		t.Fatalf("Expected an IP in the range of onion addresses to not be routable")
+	}
+
+	err = addressManager.AddAddresses(onionAddress, i2pAddress, ipAddress)
+	if err != nil {
+		t.Fatalf("AddAddresses: %s", err)
+	}
+	if len(addressManager.Addresses()) != 3 {
+		t.Fatalf("Expected all addresses to be kept, but got %d", len(addressManager.Addresses()))
+	}
+
+	// Onion services are only connected to through a Tor proxy, and I2P destinations not at all
+	randomAddresses := addressManager.RandomAddresses(10, nil, nil)
+	if len(randomAddresses) != 1 || !randomAddresses[0].IP.Equal(ipAddress.IP) {
+		t.Fatalf("Expected only the IP address to be picked, but got %v", randomAddresses)
+	}
+	addressManager.cfg.OnionReachable = true
+	randomAddresses = addressManager.RandomAddresses(10, nil, nil)
+	if len(randomAddresses) != 2 {
+		t.Fatalf("Expected the IP and onion addresses to be picked, but got %v", randomAddresses)
+	}
+	for _, randomAddress := range randomAddresses {
+		if randomAddress.NetworkID == appmessage.NetworkIDI2P {
+			t.Fatalf("Expected the I2P address not to be picked")
+		}
 	}
 
-	// Add one more address to the address manager
-	err = addressManager.AddAddress(&appmessage.NetAddress{IP: net.IP{7, 8, 0, 0}, Timestamp: mstime.Now()})
+	err = addressManager.SetAnchors([]*appmessage.NetAddress{onionAddress, ipAddress})
 	if err != nil {
-		t.Fatalf("AddAddress: %s", err)
+		t.Fatalf("SetAnchors: %s", err)
 	}
 
-	// Make sure that it now still contains exactly `maxAddresses` entries
-	returnedAddresses = addressManager.Addresses()
-	if len(returnedAddresses) != maxAddresses {
-		t.Fatalf("Unexpected address amount. Want: %d, got: %d", maxAddresses, len(returnedAddresses))
+	// Overlay addresses and anchors survive a restart
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
 	}
 
-	// Make sure that the first address is no longer in the
-	// connection manager
-	for _, address := range returnedAddresses {
-		if address.IP.Equal(testAddress.IP) {
-			t.Fatalf("Unexpectedly found testAddress returned addresses")
+	restoredOverlayAddresses := make(map[string]bool)
+	for _, address := range addressManager.Addresses() {
+		if address.IsOverlay() {
+			restoredOverlayAddresses[address.String()] = true
 		}
 	}
+	if len(restoredOverlayAddresses) != 2 || !restoredOverlayAddresses[onionAddress.String()] ||
+		!restoredOverlayAddresses[i2pAddress.String()] {
+		t.Fatalf("Expected the overlay addresses to be restored, but got %v", restoredOverlayAddresses)
+	}
+
+	anchors, err := addressManager.TakeAnchors()
+	if err != nil {
+		t.Fatalf("TakeAnchors: %s", err)
+	}
+	if len(anchors) != 2 || anchors[0].String() != onionAddress.String() || anchors[1].String() != ipAddress.String() {
+		t.Fatalf("Expected the anchors %s and %s, but got %v", onionAddress, ipAddress, anchors)
+	}
 }
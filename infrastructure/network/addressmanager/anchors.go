@@ -0,0 +1,30 @@
+package addressmanager
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// SetAnchors replaces the stored addresses of the anchor peers - the long-lived outgoing peers
+// that the node reconnects to first after a restart
+func (am *AddressManager) SetAnchors(anchors []*appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	return am.store.setAnchors(anchors)
+}
+
+// TakeAnchors returns the stored addresses of the anchor peers and forgets them, so that
+// a node that keeps crashing before it gets to store new anchors doesn't keep reconnecting
+// to the same peers
+func (am *AddressManager) TakeAnchors() ([]*appmessage.NetAddress, error) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	anchors, err := am.store.anchors()
+	if err != nil {
+		return nil, err
+	}
+	err = am.store.setAnchors(nil)
+	if err != nil {
+		return nil, err
+	}
+	return anchors, nil
+}
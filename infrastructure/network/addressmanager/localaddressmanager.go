@@ -84,6 +84,14 @@ func (lam *localAddressManager) addLocalNetAddress(netAddress *appmessage.NetAdd
 	return nil
 }
 
+// removeLocalNetAddress stops advertising netAddress, for when it's no longer reachable
+func (lam *localAddressManager) removeLocalNetAddress(netAddress *appmessage.NetAddress) {
+	lam.mutex.Lock()
+	defer lam.mutex.Unlock()
+
+	delete(lam.localAddresses, netAddressKey(netAddress))
+}
+
 // bestLocalAddress returns the most appropriate local address to use
 // for the given remote address.
 func (lam *localAddressManager) bestLocalAddress(remoteAddress *appmessage.NetAddress) *appmessage.NetAddress {
@@ -312,6 +320,9 @@ func reachabilityFrom(localAddress, remoteAddress *appmessage.NetAddress, accept
 	)
 
 	IsRoutable := func(na *appmessage.NetAddress) bool {
+		if na.IsOverlay() {
+			return true
+		}
 		if acceptUnroutable {
 			return !IsLocal(na)
 		}
@@ -326,6 +337,21 @@ func reachabilityFrom(localAddress, remoteAddress *appmessage.NetAddress, accept
 		return Unreachable
 	}
 
+	// Our overlay addresses are the best ones to give peers in the same overlay network,
+	// and the last resort for anyone else
+	if remoteAddress.IsOverlay() {
+		if localAddress.NetworkID == remoteAddress.NetworkID {
+			return Private
+		}
+		if IsRoutable(localAddress) && IsIPv4(localAddress) {
+			return Ipv4
+		}
+		return Default
+	}
+	if localAddress.IsOverlay() {
+		return Default
+	}
+
 	if IsRFC4380(remoteAddress) {
 		if !IsRoutable(localAddress) {
 			return Default
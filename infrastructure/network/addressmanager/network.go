@@ -5,6 +5,7 @@
 package addressmanager
 
 import (
+	"fmt"
 	"net"
 
 	"github.com/stokesnetwork/stokes/app/appmessage"
@@ -208,6 +209,9 @@ func IsValid(na *appmessage.NetAddress) bool {
 // the public internet. This is true as long as the address is valid and is not
 // in any reserved ranges.
 func IsRoutable(na *appmessage.NetAddress, acceptUnroutable bool) bool {
+	if na.IsOverlay() {
+		return true
+	}
 	if acceptUnroutable {
 		return !IsLocal(na)
 	}
@@ -218,16 +222,25 @@ func IsRoutable(na *appmessage.NetAddress, acceptUnroutable bool) bool {
 		IsLocal(na) || (IsRFC4193(na)))
 }
 
+const (
+	localGroupKey      = "local"
+	unroutableGroupKey = "unroutable"
+)
+
 // GroupKey returns a string representing the network group an address is part
-// of. This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
+// of. This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the overlay
+// network and the first four bits of the address for Tor and I2P, the string
 // "local" for a local address, and the string "unroutable" for an unroutable
 // address.
 func (am *AddressManager) GroupKey(na *appmessage.NetAddress) string {
+	if na.IsOverlay() {
+		return fmt.Sprintf("%s/%d", na.NetworkID, na.OverlayAddress[0]>>4)
+	}
 	if IsLocal(na) {
-		return "local"
+		return localGroupKey
 	}
 	if !IsRoutable(na, am.cfg.AcceptUnroutable) {
-		return "unroutable"
+		return unroutableGroupKey
 	}
 	if IsIPv4(na) {
 		return na.IP.Mask(net.CIDRMask(16, 32)).String()
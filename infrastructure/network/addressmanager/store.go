@@ -1,6 +1,7 @@
 package addressmanager
 
 import (
+	"crypto/rand"
 	"encoding/binary"
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/infrastructure/db/database"
@@ -11,6 +12,11 @@ import (
 
 var notBannedAddressBucket = database.MakeBucket([]byte("not-banned-addresses"))
 var bannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
+var addressManagerBucket = database.MakeBucket([]byte("address-manager"))
+var bucketingKeyKey = addressManagerBucket.Key([]byte("bucketing-key"))
+var anchorsKey = addressManagerBucket.Key([]byte("anchors"))
+
+const bucketingKeySize = 32
 
 type addressStore struct {
 	database           database.Database
@@ -87,8 +93,74 @@ func (as *addressStore) restoreBannedAddresses() error {
 	return nil
 }
 
-func (as *addressStore) notBannedCount() int {
-	return len(as.notBannedAddresses)
+// bucketingKey returns the secret key the address manager picks buckets with, and creates it if
+// there's none yet. It's kept in the database, so that the stored addresses land in the same
+// buckets after a restart.
+func (as *addressStore) bucketingKey() ([]byte, error) {
+	bucketingKey, err := as.database.Get(bucketingKeyKey)
+	if err == nil && len(bucketingKey) == bucketingKeySize {
+		return bucketingKey, nil
+	}
+	if err != nil && !database.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	bucketingKey = make([]byte, bucketingKeySize)
+	_, err = rand.Read(bucketingKey)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = as.database.Put(bucketingKeyKey, bucketingKey)
+	if err != nil {
+		return nil, err
+	}
+	return bucketingKey, nil
+}
+
+func (as *addressStore) setAnchors(anchors []*appmessage.NetAddress) error {
+	if len(anchors) == 0 {
+		return as.database.Delete(anchorsKey)
+	}
+
+	serializedAnchors := make([]byte, 0, 1+len(anchors)*serializedAnchorSize)
+	serializedAnchors = append(serializedAnchors, anchorsFormatVersion)
+	for _, anchor := range anchors {
+		serializedAnchors = append(serializedAnchors, as.serializeAddressKey(netAddressKey(anchor))...)
+		serializedAnchors = append(serializedAnchors, serializeOverlay(anchor)...)
+	}
+	return as.database.Put(anchorsKey, serializedAnchors)
+}
+
+func (as *addressStore) anchors() ([]*appmessage.NetAddress, error) {
+	serializedAnchors, err := as.database.Get(anchorsKey)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Anchors are only a hint of which peers to reconnect to first, so anchors in a format this
+	// version doesn't know, such as the unversioned one anchors were first stored in, are dropped
+	if len(serializedAnchors) == 0 || serializedAnchors[0] != anchorsFormatVersion ||
+		(len(serializedAnchors)-1)%serializedAnchorSize != 0 {
+
+		log.Infof("Dropping the stored anchor peers, since they're stored in an unknown format")
+		return nil, as.database.Delete(anchorsKey)
+	}
+	serializedAnchors = serializedAnchors[1:]
+
+	anchors := make([]*appmessage.NetAddress, 0, len(serializedAnchors)/serializedAnchorSize)
+	for len(serializedAnchors) >= serializedAnchorSize {
+		key := as.deserializeAddressKey(serializedAnchors[:serializedAddressKeySize])
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, key.address[:])
+		anchor := appmessage.NewNetAddressIPPort(ip, key.port)
+		deserializeOverlay(anchor, serializedAnchors[serializedAddressKeySize:serializedAnchorSize])
+		anchors = append(anchors, anchor)
+		serializedAnchors = serializedAnchors[serializedAnchorSize:]
+	}
+	return anchors, nil
 }
 
 func (as *addressStore) add(key addressKey, address *address) error {
@@ -106,7 +178,7 @@ func (as *addressStore) add(key addressKey, address *address) error {
 // updateNotBanned updates the not-banned address collection
 func (as *addressStore) updateNotBanned(key addressKey, address *address) error {
 	if _, ok := as.notBannedAddresses[key]; !ok {
-		return errors.Errorf("address %s is not in the store", address.netAddress.TCPAddress())
+		return errors.Errorf("address %s is not in the store", address.netAddress)
 	}
 
 	as.notBannedAddresses[key] = address
@@ -144,18 +216,6 @@ func (as *addressStore) getAllNotBannedNetAddresses() []*appmessage.NetAddress {
 	return addresses
 }
 
-func (as *addressStore) getAllNotBannedNetAddressesWithout(ignoredAddresses []*appmessage.NetAddress) []*address {
-	ignoredKeys := netAddressesKeys(ignoredAddresses)
-
-	addresses := make([]*address, 0, len(as.notBannedAddresses))
-	for key, address := range as.notBannedAddresses {
-		if !ignoredKeys[key] {
-			addresses = append(addresses, address)
-		}
-	}
-	return addresses
-}
-
 func (as *addressStore) isNotBanned(key addressKey) bool {
 	_, ok := as.notBannedAddresses[key]
 	return ok
@@ -217,9 +277,17 @@ func (as *addressStore) bannedDatabaseKey(key addressKey) *database.Key {
 	return bannedAddressBucket.Key(key.address[:])
 }
 
+const serializedAddressKeySize = 16 + 2 // ipv6 + port
+
+// serializedAnchorSize is the size of the address key and overlay of an anchor peer
+const serializedAnchorSize = serializedAddressKeySize + serializedOverlaySize
+
+// anchorsFormatVersion is the first byte of the stored anchors, followed by their serialized address
+// keys and overlays. It should change whenever the size or the layout of serialized anchors does.
+const anchorsFormatVersion byte = 1
+
 func (as *addressStore) serializeAddressKey(key addressKey) []byte {
-	serializedSize := 16 + 2 // ipv6 + port
-	serializedKey := make([]byte, serializedSize)
+	serializedKey := make([]byte, serializedAddressKeySize)
 
 	copy(serializedKey[:], key.address[:])
 	binary.LittleEndian.PutUint16(serializedKey[16:], key.port)
@@ -239,14 +307,55 @@ func (as *addressStore) deserializeAddressKey(serializedKey []byte) addressKey {
 	}
 }
 
+const (
+	// serializedAddressSizeWithoutSource is the size of addresses stored before the address manager
+	// kept its addresses in buckets: ipv6 + port + timestamp + connectionFailedCount
+	serializedAddressSizeWithoutSource = 16 + 2 + 8 + 8
+
+	// serializedAddressSizeWithoutOverlay adds isTried + source ipv6
+	serializedAddressSizeWithoutOverlay = serializedAddressSizeWithoutSource + 1 + 16
+
+	// serializedAddressSize adds the overlay network and address
+	serializedAddressSize = serializedAddressSizeWithoutOverlay + serializedOverlaySize
+)
+
+// serializedOverlaySize is the size of the overlay network ID and address of an address.
+// Addresses that aren't in an overlay network are serialized as zeros.
+const serializedOverlaySize = 1 + appmessage.OverlayAddressSize
+
+func serializeOverlay(netAddress *appmessage.NetAddress) []byte {
+	serializedOverlay := make([]byte, serializedOverlaySize)
+	if netAddress.IsOverlay() {
+		serializedOverlay[0] = byte(netAddress.NetworkID)
+		copy(serializedOverlay[1:], netAddress.OverlayAddress)
+	}
+	return serializedOverlay
+}
+
+// deserializeOverlay sets the overlay network and address of the given address,
+// if it's in an overlay network
+func deserializeOverlay(netAddress *appmessage.NetAddress, serializedOverlay []byte) {
+	networkID := appmessage.NetworkID(serializedOverlay[0])
+	if networkID == appmessage.NetworkIDIP {
+		return
+	}
+	netAddress.NetworkID = networkID
+	netAddress.OverlayAddress = make([]byte, appmessage.OverlayAddressSize)
+	copy(netAddress.OverlayAddress, serializedOverlay[1:])
+}
+
 func (as *addressStore) serializeAddress(address *address) []byte {
-	serializedSize := 16 + 2 + 8 + 8 // ipv6 + port + timestamp + connectionFailedCount
-	serializedNetAddress := make([]byte, serializedSize)
+	serializedNetAddress := make([]byte, serializedAddressSize)
 
 	copy(serializedNetAddress[:], address.netAddress.IP.To16()[:])
 	binary.LittleEndian.PutUint16(serializedNetAddress[16:], address.netAddress.Port)
 	binary.LittleEndian.PutUint64(serializedNetAddress[18:], uint64(address.netAddress.Timestamp.UnixMilliseconds()))
 	binary.LittleEndian.PutUint64(serializedNetAddress[26:], uint64(address.connectionFailedCount))
+	if address.isTried {
+		serializedNetAddress[34] = 1
+	}
+	copy(serializedNetAddress[35:], address.source[:])
+	copy(serializedNetAddress[serializedAddressSizeWithoutOverlay:], serializeOverlay(address.netAddress))
 
 	return serializedNetAddress
 }
@@ -259,12 +368,27 @@ func (as *addressStore) deserializeAddress(serializedAddress []byte) *address {
 	timestamp := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedAddress[18:])))
 	connectionFailedCount := binary.LittleEndian.Uint64(serializedAddress[26:])
 
+	// Addresses stored without a source are treated as their own source
+	isTried := false
+	source := toIPv6(ip)
+	if len(serializedAddress) >= serializedAddressSizeWithoutOverlay {
+		isTried = serializedAddress[34] == 1
+		copy(source[:], serializedAddress[35:])
+	}
+
+	netAddress := &appmessage.NetAddress{
+		IP:        ip,
+		Port:      port,
+		Timestamp: timestamp,
+	}
+	if len(serializedAddress) >= serializedAddressSize {
+		deserializeOverlay(netAddress, serializedAddress[serializedAddressSizeWithoutOverlay:])
+	}
+
 	return &address{
-		netAddress: &appmessage.NetAddress{
-			IP:        ip,
-			Port:      port,
-			Timestamp: timestamp,
-		},
+		netAddress:            netAddress,
 		connectionFailedCount: connectionFailedCount,
+		isTried:               isTried,
+		source:                source,
 	}
 }
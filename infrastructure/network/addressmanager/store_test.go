@@ -45,3 +45,37 @@ func TestAddressSerialization(t *testing.T) {
 			"testAddress:%+v\ndeserializedTestAddress:%+v", testAddress, deserializedTestAddress)
 	}
 }
+
+func TestOverlayAddressSerialization(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestOverlayAddressSerialization")
+	defer teardown()
+	addressStore := addressManager.store
+
+	onionAddress := make([]byte, appmessage.OverlayAddressSize)
+	for i := range onionAddress {
+		onionAddress[i] = byte(255 - i)
+	}
+	netAddress, err := appmessage.NewOverlayNetAddress(mstime.Now(), appmessage.NetworkIDTorV3, onionAddress, 16111)
+	if err != nil {
+		t.Fatalf("NewOverlayNetAddress: %s", err)
+	}
+	testAddress := &address{
+		netAddress:            netAddress,
+		connectionFailedCount: 1,
+		isTried:               true,
+		source:                toIPv6(net.ParseIP("1.2.3.4")),
+	}
+
+	serializedTestAddress := addressStore.serializeAddress(testAddress)
+	deserializedTestAddress := addressStore.deserializeAddress(serializedTestAddress)
+	if !reflect.DeepEqual(testAddress, deserializedTestAddress) {
+		t.Fatalf("testAddress and deserializedTestAddress are not equal\n"+
+			"testAddress:%+v\ndeserializedTestAddress:%+v", testAddress, deserializedTestAddress)
+	}
+
+	// Addresses stored before overlay addresses were supported are read as IP addresses
+	deserializedTestAddress = addressStore.deserializeAddress(serializedTestAddress[:serializedAddressSizeWithoutOverlay])
+	if deserializedTestAddress.netAddress.IsOverlay() || !deserializedTestAddress.isTried {
+		t.Fatalf("Unexpected address deserialized from the old format: %+v", deserializedTestAddress)
+	}
+}
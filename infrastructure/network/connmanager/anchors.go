@@ -0,0 +1,108 @@
+package connmanager
+
+import (
+	"sort"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+// minimumAnchorConnectionDuration is how long an outgoing peer has to stay connected
+// to be remembered as an anchor peer
+const minimumAnchorConnectionDuration = 10 * time.Minute
+
+// loadAnchors takes the anchor peers stored on the last shutdown, to be connected to before any others
+func (c *ConnectionManager) loadAnchors() {
+	anchors, err := c.addressManager.TakeAnchors()
+	if err != nil {
+		log.Warnf("Couldn't load the anchor peers: %s", err)
+		return
+	}
+	if len(anchors) > int(c.cfg.AnchorPeers) {
+		anchors = anchors[:c.cfg.AnchorPeers]
+	}
+	if len(anchors) > 0 {
+		log.Infof("Reconnecting to %d anchor peers", len(anchors))
+	}
+	c.pendingAnchors = anchors
+}
+
+// connectToAnchors makes a single attempt to connect to each of the pending anchor peers, as long as
+// outgoing connections are needed, and returns the addresses of the ones it connected to
+func (c *ConnectionManager) connectToAnchors(connectedAddresses []*appmessage.NetAddress) []*appmessage.NetAddress {
+	connectedAddressStrings := make(map[string]struct{}, len(connectedAddresses))
+	for _, connectedAddress := range connectedAddresses {
+		connectedAddressStrings[connectedAddress.String()] = struct{}{}
+	}
+
+	connectedAnchors := make([]*appmessage.NetAddress, 0, len(c.pendingAnchors))
+	for _, anchor := range c.pendingAnchors {
+		if len(c.activeOutgoing) >= c.targetOutgoing {
+			break
+		}
+		if _, ok := connectedAddressStrings[anchor.String()]; ok {
+			continue
+		}
+		isBanned, err := c.addressManager.IsBanned(anchor)
+		if err == nil && isBanned {
+			continue
+		}
+		if c.connectToOutgoing(anchor) {
+			connectedAnchors = append(connectedAnchors, anchor)
+		}
+	}
+	c.pendingAnchors = nil
+	return connectedAnchors
+}
+
+// saveAnchors stores the longest-lived outgoing peers that are still connected and have no ban score,
+// to be reconnected to first on the next startup
+func (c *ConnectionManager) saveAnchors() {
+	if c.cfg.AnchorPeers == 0 {
+		return
+	}
+
+	liveConnections := convertToSet(c.netAdapter.P2PConnections())
+	c.activeOutgoingLock.Lock()
+	anchors := selectAnchors(c.activeOutgoing, int(c.cfg.AnchorPeers), time.Now(),
+		func(address string) bool {
+			_, ok := liveConnections.get(address)
+			return ok
+		},
+		c.addressBanScore)
+	c.activeOutgoingLock.Unlock()
+
+	err := c.addressManager.SetAnchors(anchors)
+	if err != nil {
+		log.Warnf("Couldn't save the anchor peers: %s", err)
+		return
+	}
+	log.Infof("Saved %d anchor peers", len(anchors))
+}
+
+// selectAnchors returns the addresses of up to count of the given outgoing connections that are live,
+// have been connected for at least minimumAnchorConnectionDuration and have no ban score, longest-lived first
+func selectAnchors(activeOutgoing map[string]*outgoingConnection, count int, now time.Time,
+	isLive func(address string) bool, banScore func(netAddress *appmessage.NetAddress) uint32) []*appmessage.NetAddress {
+
+	candidates := make([]*outgoingConnection, 0, len(activeOutgoing))
+	for address, connection := range activeOutgoing {
+		if !isLive(address) || now.Sub(connection.connectedAt) < minimumAnchorConnectionDuration ||
+			banScore(connection.netAddress) > 0 {
+			continue
+		}
+		candidates = append(candidates, connection)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].connectedAt.Before(candidates[j].connectedAt)
+	})
+
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	anchors := make([]*appmessage.NetAddress, len(candidates))
+	for i, candidate := range candidates {
+		anchors[i] = candidate.netAddress
+	}
+	return anchors
+}
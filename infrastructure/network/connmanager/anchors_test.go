@@ -0,0 +1,59 @@
+package connmanager
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func TestSelectAnchors(t *testing.T) {
+	now := time.Now()
+	newOutgoingConnection := func(ip string, connectedFor time.Duration) *outgoingConnection {
+		return &outgoingConnection{
+			netAddress:  appmessage.NewNetAddressIPPort(net.ParseIP(ip), 16111),
+			connectedAt: now.Add(-connectedFor),
+		}
+	}
+	activeOutgoing := map[string]*outgoingConnection{
+		"1.1.1.1:16111": newOutgoingConnection("1.1.1.1", time.Hour),
+		"2.2.2.2:16111": newOutgoingConnection("2.2.2.2", 3*time.Hour),
+		"3.3.3.3:16111": newOutgoingConnection("3.3.3.3", 2*time.Hour),
+		"4.4.4.4:16111": newOutgoingConnection("4.4.4.4", 4*time.Hour),
+		"5.5.5.5:16111": newOutgoingConnection("5.5.5.5", 5*time.Hour),
+		"6.6.6.6:16111": newOutgoingConnection("6.6.6.6", minimumAnchorConnectionDuration/2),
+	}
+	isLive := func(address string) bool {
+		// 5.5.5.5 disconnected
+		return address != "5.5.5.5:16111"
+	}
+	banScore := func(netAddress *appmessage.NetAddress) uint32 {
+		// 4.4.4.4 misbehaved
+		if netAddress.IP.Equal(net.ParseIP("4.4.4.4")) {
+			return 1
+		}
+		return 0
+	}
+
+	tests := []struct {
+		count           int
+		expectedAnchors []string
+	}{
+		{count: 0, expectedAnchors: []string{}},
+		{count: 2, expectedAnchors: []string{"2.2.2.2", "3.3.3.3"}},
+		{count: 10, expectedAnchors: []string{"2.2.2.2", "3.3.3.3", "1.1.1.1"}},
+	}
+	for _, test := range tests {
+		anchors := selectAnchors(activeOutgoing, test.count, now, isLive, banScore)
+		if len(anchors) != len(test.expectedAnchors) {
+			t.Fatalf("count %d: expected %d anchors, but got %d", test.count, len(test.expectedAnchors), len(anchors))
+		}
+		for i, anchor := range anchors {
+			if !anchor.IP.Equal(net.ParseIP(test.expectedAnchors[i])) {
+				t.Fatalf("count %d: expected anchor %d to be %s, but got %s",
+					test.count, i, test.expectedAnchors[i], anchor.IP)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package connmanager
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter"
+)
+
+// banScoreHalfLife is how long it takes a ban score to decay to half its value,
+// so that the occasional offences of an honest peer never add up to a ban
+const banScoreHalfLife = time.Hour
+
+// minimumBanScore is the ban score below which a peer's ban score is forgotten
+const minimumBanScore = 1
+
+type banScore struct {
+	value      float64
+	lastUpdate time.Time
+}
+
+func (score *banScore) valueAt(now time.Time) float64 {
+	elapsed := now.Sub(score.lastUpdate)
+	if elapsed <= 0 {
+		return score.value
+	}
+	return score.value * math.Pow(0.5, elapsed.Seconds()/banScoreHalfLife.Seconds())
+}
+
+// banScores holds the ban scores of misbehaving peers, by the keys banScoreKey returns. They're kept
+// by IP rather than by connection, since a peer is disconnected for every offence and may then reconnect.
+type banScores struct {
+	lock  sync.Mutex
+	byKey map[string]*banScore
+}
+
+func newBanScores() *banScores {
+	return &banScores{
+		byKey: make(map[string]*banScore),
+	}
+}
+
+// banScoreKey returns the key the ban score of the given connection is kept by: its IP, or the
+// connection itself for an inbound onion peer, since all of those come from the local Tor client
+func banScoreKey(netConnection *netadapter.NetConnection) string {
+	if netConnection.IsOnionInbound() {
+		return "onion/" + netConnection.Address()
+	}
+	return netConnection.NetAddress().IP.String()
+}
+
+// add adds the given score to the ban score of the given key, and returns the resulting ban score
+func (scores *banScores) add(key string, value uint32, now time.Time) uint32 {
+	scores.lock.Lock()
+	defer scores.lock.Unlock()
+
+	scores.forgetDecayed(now)
+
+	score, ok := scores.byKey[key]
+	if !ok {
+		score = &banScore{}
+		scores.byKey[key] = score
+	}
+	score.value = score.valueAt(now) + float64(value)
+	score.lastUpdate = now
+	return uint32(score.value)
+}
+
+func (scores *banScores) get(key string, now time.Time) uint32 {
+	scores.lock.Lock()
+	defer scores.lock.Unlock()
+
+	score, ok := scores.byKey[key]
+	if !ok {
+		return 0
+	}
+	return uint32(score.valueAt(now))
+}
+
+func (scores *banScores) reset(key string) {
+	scores.lock.Lock()
+	defer scores.lock.Unlock()
+
+	delete(scores.byKey, key)
+}
+
+func (scores *banScores) forgetDecayed(now time.Time) {
+	for key, score := range scores.byKey {
+		if score.valueAt(now) < minimumBanScore {
+			delete(scores.byKey, key)
+		}
+	}
+}
+
+// AddBanScore adds banScore to the ban score of the given connection, and returns the resulting ban
+// score along with whether it reached the ban threshold. Ban scores decay over time, and a whitelisted
+// IP never reaches the ban threshold. Inbound onion peers come from the IP of the local Tor client,
+// so the whitelist doesn't apply to them.
+func (c *ConnectionManager) AddBanScore(netConnection *netadapter.NetConnection, banScore uint32) (
+	newBanScore uint32, reachedBanThreshold bool) {
+
+	newBanScore = c.banScores.add(banScoreKey(netConnection), banScore, time.Now())
+	isWhitelisted := !netConnection.IsOnionInbound() && c.IsWhitelisted(netConnection.NetAddress().IP)
+	return newBanScore, newBanScore >= c.cfg.BanThreshold && !isWhitelisted
+}
+
+// BanScore returns the current ban score of the given connection
+func (c *ConnectionManager) BanScore(netConnection *netadapter.NetConnection) uint32 {
+	return c.banScores.get(banScoreKey(netConnection), time.Now())
+}
+
+// addressBanScore returns the current ban score of the IP of the given address. It doesn't apply to
+// inbound onion peers, whose ban scores are kept by connection.
+func (c *ConnectionManager) addressBanScore(netAddress *appmessage.NetAddress) uint32 {
+	return c.banScores.get(netAddress.IP.String(), time.Now())
+}
+
+// IsWhitelisted returns whether the given IP is in one of the networks that are never banned
+func (c *ConnectionManager) IsWhitelisted(ip net.IP) bool {
+	for _, whitelist := range c.cfg.Whitelists {
+		if whitelist.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package connmanager
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stokesnetwork/stokes/infrastructure/config"
+)
+
+func TestBanScores(t *testing.T) {
+	scores := newBanScores()
+	ip := "203.0.113.1"
+	otherIP := "203.0.113.2"
+	now := time.Now()
+
+	if score := scores.add(ip, 20, now); score != 20 {
+		t.Fatalf("Expected a ban score of 20, but got %d", score)
+	}
+	if score := scores.add(ip, 30, now); score != 50 {
+		t.Fatalf("Expected ban scores to add up to 50, but got %d", score)
+	}
+	if score := scores.get(otherIP, now); score != 0 {
+		t.Fatalf("Expected another IP to have no ban score, but got %d", score)
+	}
+
+	// Ban scores halve every banScoreHalfLife
+	now = now.Add(banScoreHalfLife)
+	if score := scores.get(ip, now); score != 25 {
+		t.Fatalf("Expected the ban score to decay to 25, but got %d", score)
+	}
+	if score := scores.add(ip, 100, now); score != 125 {
+		t.Fatalf("Expected a ban score of 125, but got %d", score)
+	}
+
+	// A ban score that decays away is forgotten once another ban score is added
+	scores.add(otherIP, 10, now)
+	now = now.Add(10 * banScoreHalfLife)
+	scores.add(otherIP, 10, now)
+	if _, ok := scores.byKey[ip]; ok {
+		t.Fatalf("Expected the decayed ban score to be forgotten")
+	}
+	if score := scores.get(otherIP, now); score != 10 {
+		t.Fatalf("Expected a ban score of 10, but got %d", score)
+	}
+
+	scores.reset(otherIP)
+	if score := scores.get(otherIP, now); score != 0 {
+		t.Fatalf("Expected the ban score to be reset, but got %d", score)
+	}
+}
+
+func TestIsWhitelisted(t *testing.T) {
+	_, ipv4Network, _ := net.ParseCIDR("192.168.0.0/24")
+	_, ipv6Network, _ := net.ParseCIDR("fd00::/16")
+	c := &ConnectionManager{
+		cfg: &config.Config{
+			Whitelists: []*net.IPNet{ipv4Network, ipv6Network},
+		},
+	}
+
+	tests := []struct {
+		ip                    string
+		expectedIsWhitelisted bool
+	}{
+		{ip: "192.168.0.1", expectedIsWhitelisted: true},
+		{ip: "192.168.1.1", expectedIsWhitelisted: false},
+		{ip: "fd00::1", expectedIsWhitelisted: true},
+		{ip: "fe80::1", expectedIsWhitelisted: false},
+	}
+	for _, test := range tests {
+		isWhitelisted := c.IsWhitelisted(net.ParseIP(test.ip))
+		if isWhitelisted != test.expectedIsWhitelisted {
+			t.Errorf("%s: expected isWhitelisted %t, but got %t", test.ip, test.expectedIsWhitelisted, isWhitelisted)
+		}
+	}
+}
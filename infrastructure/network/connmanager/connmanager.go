@@ -34,16 +34,20 @@ type ConnectionManager struct {
 
 	activeRequested  map[string]*connectionRequest
 	pendingRequested map[string]*connectionRequest
-	activeOutgoing   map[string]struct{}
+	activeOutgoing   map[string]*outgoingConnection
 	targetOutgoing   int
+	pendingAnchors   []*appmessage.NetAddress
 	activeIncoming   map[string]struct{}
 	maxIncoming      int
 
 	stop                   uint32
 	connectionRequestsLock sync.RWMutex
+	activeOutgoingLock     sync.Mutex
 
 	resetLoopChan chan struct{}
 	loopTicker    *time.Ticker
+
+	banScores *banScores
 }
 
 // New instantiates a new instance of a ConnectionManager
@@ -54,10 +58,11 @@ func New(cfg *config.Config, netAdapter *netadapter.NetAdapter, addressManager *
 		addressManager:   addressManager,
 		activeRequested:  map[string]*connectionRequest{},
 		pendingRequested: map[string]*connectionRequest{},
-		activeOutgoing:   map[string]struct{}{},
+		activeOutgoing:   map[string]*outgoingConnection{},
 		activeIncoming:   map[string]struct{}{},
 		resetLoopChan:    make(chan struct{}),
 		loopTicker:       time.NewTicker(connectionsLoopInterval),
+		banScores:        newBanScores(),
 	}
 
 	connectPeers := cfg.AddPeers
@@ -80,6 +85,7 @@ func New(cfg *config.Config, netAdapter *netadapter.NetAdapter, addressManager *
 
 // Start begins the operation of the ConnectionManager
 func (c *ConnectionManager) Start() {
+	c.loadAnchors()
 	spawn("ConnectionManager.connectionsLoop", c.connectionsLoop)
 }
 
@@ -87,6 +93,7 @@ func (c *ConnectionManager) Start() {
 func (c *ConnectionManager) Stop() {
 	atomic.StoreUint32(&c.stop, 1)
 
+	c.saveAnchors()
 	for _, connection := range c.netAdapter.P2PConnections() {
 		connection.Disconnect()
 	}
@@ -136,13 +143,30 @@ func (c *ConnectionManager) ConnectionCount() int {
 // ErrCannotBanPermanent is the error returned when trying to ban a permanent peer.
 var ErrCannotBanPermanent = errors.New("ErrCannotBanPermanent")
 
-// Ban marks the given netConnection as banned
+// ErrCannotBanWhitelisted is the error returned when trying to ban a peer in a whitelisted network.
+var ErrCannotBanWhitelisted = errors.New("ErrCannotBanWhitelisted")
+
+// Ban marks the given netConnection as banned. An inbound onion peer can't be told apart from the
+// other inbound onion peers once it reconnects, so only its ban score is reset, and it's up to the
+// caller to disconnect it.
 func (c *ConnectionManager) Ban(netConnection *netadapter.NetConnection) error {
+	if netConnection.IsOnionInbound() {
+		c.banScores.reset(banScoreKey(netConnection))
+		return nil
+	}
 	if c.isPermanent(netConnection.Address()) {
 		return errors.Wrapf(ErrCannotBanPermanent, "Cannot ban %s because it's a permanent connection", netConnection.Address())
 	}
+	if c.IsWhitelisted(netConnection.NetAddress().IP) {
+		return errors.Wrapf(ErrCannotBanWhitelisted, "Cannot ban %s because it's whitelisted", netConnection.Address())
+	}
 
-	return c.addressManager.Ban(netConnection.NetAddress())
+	err := c.addressManager.Ban(netConnection.NetAddress())
+	if err != nil {
+		return err
+	}
+	c.banScores.reset(banScoreKey(netConnection))
+	return nil
 }
 
 // BanByIP bans the given IP and disconnects from all the connection with that IP.
@@ -166,9 +190,11 @@ func (c *ConnectionManager) BanByIP(ip net.IP) error {
 	return c.addressManager.Ban(appmessage.NewNetAddressIPPort(ip, 0))
 }
 
-// IsBanned returns whether the given netConnection is banned
+// IsBanned returns whether the given netConnection is banned. Inbound onion peers are never banned,
+// since they all come from the IP of the local Tor client.
 func (c *ConnectionManager) IsBanned(netConnection *netadapter.NetConnection) (bool, error) {
-	if c.isPermanent(netConnection.Address()) {
+	if netConnection.IsOnionInbound() || c.isPermanent(netConnection.Address()) ||
+		c.IsWhitelisted(netConnection.NetAddress().IP) {
 		return false, nil
 	}
 
@@ -1,19 +1,34 @@
 package connmanager
 
-import "github.com/stokesnetwork/stokes/app/appmessage"
+import (
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+// outgoingConnection is an outgoing connection that the connection manager opened by itself,
+// rather than because it was requested to
+type outgoingConnection struct {
+	netAddress  *appmessage.NetAddress
+	connectedAt time.Time
+}
 
 // checkOutgoingConnections goes over all activeOutgoing and makes sure they are still active.
 // Then it opens connections so that we have targetOutgoing active connections
 func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
+	outgoingAddresses := make([]*appmessage.NetAddress, 0, len(c.activeOutgoing))
 	for address := range c.activeOutgoing {
 		connection, ok := connSet.get(address)
 		if ok { // connection is still connected
 			connSet.remove(connection)
+			outgoingAddresses = append(outgoingAddresses, connection.NetAddress())
 			continue
 		}
 
 		// if connection is dead - remove from list of active ones
+		c.activeOutgoingLock.Lock()
 		delete(c.activeOutgoing, address)
+		c.activeOutgoingLock.Unlock()
 	}
 
 	connections := c.netAdapter.P2PConnections()
@@ -30,24 +45,21 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 	log.Debugf("Have got %d outgoing connections out of target %d, adding %d more",
 		liveConnections, c.targetOutgoing, c.targetOutgoing-liveConnections)
 
+	// The anchor peers from before the last restart are connected to before any others
+	if len(c.pendingAnchors) > 0 {
+		outgoingAddresses = append(outgoingAddresses, c.connectToAnchors(connectedAddresses)...)
+		if c.targetOutgoing == len(c.activeOutgoing) {
+			return
+		}
+	}
+
 	connectionsNeededCount := c.targetOutgoing - len(c.activeOutgoing)
-	netAddresses := c.addressManager.RandomAddresses(connectionsNeededCount, connectedAddresses)
+	// Outgoing peers are picked from distinct network groups, so that an attacker who controls
+	// a few network groups can't take up all the outgoing connections
+	netAddresses := c.addressManager.RandomAddresses(connectionsNeededCount, connectedAddresses, outgoingAddresses)
 
 	for _, netAddress := range netAddresses {
-		addressString := netAddress.TCPAddress().String()
-
-		log.Debugf("Connecting to %s because we have %d outgoing connections and the target is "+
-			"%d", addressString, len(c.activeOutgoing), c.targetOutgoing)
-
-		err := c.initiateConnection(addressString)
-		if err != nil {
-			log.Debugf("Couldn't connect to %s: %s", addressString, err)
-			c.addressManager.MarkConnectionFailure(netAddress)
-			continue
-		}
-		c.addressManager.MarkConnectionSuccess(netAddress)
-
-		c.activeOutgoing[addressString] = struct{}{}
+		c.connectToOutgoing(netAddress)
 	}
 
 	if len(netAddresses) < connectionsNeededCount {
@@ -59,3 +71,28 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 		c.seedFromDNS()
 	}
 }
+
+// connectToOutgoing opens an outgoing connection to the given address, and returns whether it succeeded
+func (c *ConnectionManager) connectToOutgoing(netAddress *appmessage.NetAddress) bool {
+	addressString := netAddress.String()
+
+	log.Debugf("Connecting to %s because we have %d outgoing connections and the target is "+
+		"%d", addressString, len(c.activeOutgoing), c.targetOutgoing)
+
+	err := c.initiateConnection(addressString)
+	if err != nil {
+		log.Debugf("Couldn't connect to %s: %s", addressString, err)
+		c.addressManager.MarkConnectionFailure(netAddress)
+		return false
+	}
+	c.addressManager.MarkConnectionSuccess(netAddress)
+
+	c.activeOutgoingLock.Lock()
+	defer c.activeOutgoingLock.Unlock()
+
+	c.activeOutgoing[addressString] = &outgoingConnection{
+		netAddress:  netAddress,
+		connectedAt: time.Now(),
+	}
+	return true
+}
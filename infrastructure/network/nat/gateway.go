@@ -0,0 +1,63 @@
+package nat
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const routeTablePath = "/proc/net/route"
+
+// routeFlagGateway is the RTF_GATEWAY flag of a route, which marks routes through a gateway
+const routeFlagGateway = 0x2
+
+// defaultGateway returns the IPv4 address of the default gateway of this host. It's only
+// supported where the routing table is exposed in /proc/net/route.
+func defaultGateway() (net.IP, error) {
+	routeTable, err := os.Open(routeTablePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't read the routing table")
+	}
+	defer routeTable.Close()
+
+	return parseDefaultGateway(routeTable)
+}
+
+// parseDefaultGateway finds the default route in a routing table in the format of
+// /proc/net/route, and returns its gateway
+func parseDefaultGateway(routeTable io.Reader) (net.IP, error) {
+	scanner := bufio.NewScanner(routeTable)
+	// Skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		destination, gateway, flags := fields[1], fields[2], fields[3]
+		if destination != "00000000" {
+			continue
+		}
+		flagsValue, err := strconv.ParseUint(flags, 16, 16)
+		if err != nil || flagsValue&routeFlagGateway == 0 {
+			continue
+		}
+		gatewayBytes, err := hex.DecodeString(gateway)
+		if err != nil || len(gatewayBytes) != net.IPv4len {
+			continue
+		}
+		// The routing table holds addresses in host byte order, which is little endian
+		// wherever it's read from
+		return net.IPv4(gatewayBytes[3], gatewayBytes[2], gatewayBytes[1], gatewayBytes[0]), nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return nil, errors.New("there's no default route")
+}
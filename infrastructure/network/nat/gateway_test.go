@@ -0,0 +1,27 @@
+package nat
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseDefaultGateway(t *testing.T) {
+	routeTable := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"eth0\t0000A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n" +
+		"eth0\t00000000\t0100A8C0\t0003\t0\t0\t0\t00000000\t0\t0\t0\n"
+	gateway, err := parseDefaultGateway(strings.NewReader(routeTable))
+	if err != nil {
+		t.Fatalf("parseDefaultGateway: %s", err)
+	}
+	if !gateway.Equal(net.IPv4(192, 168, 0, 1)) {
+		t.Fatalf("Expected gateway 192.168.0.1, but got %s", gateway)
+	}
+
+	routeTable = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"eth0\t0000A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n"
+	_, err = parseDefaultGateway(strings.NewReader(routeTable))
+	if err == nil {
+		t.Fatalf("Expected an error for a routing table with no default route")
+	}
+}
@@ -0,0 +1,9 @@
+package nat
+
+import (
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+	"github.com/stokesnetwork/stokes/util/panics"
+)
+
+var log = logger.RegisterSubSystem("NATT")
+var spawn = panics.GoroutineWrapperFunc(log)
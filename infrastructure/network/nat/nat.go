@@ -0,0 +1,46 @@
+package nat
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NAT is a gateway that can map a TCP port on its external address to a port on this host
+type NAT interface {
+	// ExternalIP returns the IP address of the gateway as seen from outside the NAT
+	ExternalIP() (net.IP, error)
+
+	// AddPortMapping maps externalPort on the gateway to internalPort on this host for the given
+	// lifetime, and returns the external port that was actually mapped, which some gateways
+	// choose differently
+	AddPortMapping(internalPort uint16, externalPort uint16, description string, lifetime time.Duration) (uint16, error)
+
+	// DeletePortMapping deletes a mapping previously added with AddPortMapping
+	DeletePortMapping(internalPort uint16, externalPort uint16) error
+
+	// String returns the name of the protocol used to talk to the gateway
+	String() string
+}
+
+// Discover looks for a gateway that supports UPnP IGD, and failing that, for one that
+// supports NAT-PMP
+func Discover(timeout time.Duration) (NAT, error) {
+	upnpNAT, upnpErr := discoverUPnP(ssdpMulticastAddress, timeout)
+	if upnpErr == nil {
+		return upnpNAT, nil
+	}
+	log.Debugf("UPnP discovery failed: %s", upnpErr)
+
+	gateway, err := defaultGateway()
+	if err != nil {
+		return nil, errors.Wrapf(err, "no UPnP gateway found (%s), and the default gateway is unknown", upnpErr)
+	}
+	natPMPNAT := newNATPMP(&net.UDPAddr{IP: gateway, Port: natPMPPort}, timeout)
+	_, err = natPMPNAT.ExternalIP()
+	if err != nil {
+		return nil, errors.Wrapf(err, "no UPnP gateway found (%s), and %s doesn't speak NAT-PMP", upnpErr, gateway)
+	}
+	return natPMPNAT, nil
+}
@@ -0,0 +1,144 @@
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The NAT-PMP protocol is specified in RFC 6886
+
+const (
+	natPMPPort    = 5351
+	natPMPVersion = 0
+
+	natPMPOpExternalAddress = 0
+	natPMPOpMapTCP          = 2
+	natPMPResponseOpOffset  = 128
+
+	natPMPExternalAddressResponseLength = 12
+	natPMPMapResponseLength             = 16
+
+	// natPMPInitialRetryInterval is how long to wait for the first response before resending a
+	// request. Every following wait is twice as long as the one before it.
+	natPMPInitialRetryInterval = 250 * time.Millisecond
+)
+
+var natPMPResultCodeDescriptions = map[uint16]string{
+	1: "unsupported version",
+	2: "not authorized or refused",
+	3: "network failure",
+	4: "out of resources",
+	5: "unsupported opcode",
+}
+
+type natPMP struct {
+	gateway *net.UDPAddr
+	timeout time.Duration
+}
+
+func newNATPMP(gateway *net.UDPAddr, timeout time.Duration) *natPMP {
+	return &natPMP{
+		gateway: gateway,
+		timeout: timeout,
+	}
+}
+
+func (n *natPMP) String() string {
+	return "NAT-PMP"
+}
+
+// ExternalIP implements the NAT interface
+func (n *natPMP) ExternalIP() (net.IP, error) {
+	response, err := n.request([]byte{natPMPVersion, natPMPOpExternalAddress}, natPMPExternalAddressResponseLength)
+	if err != nil {
+		return nil, err
+	}
+	return net.IPv4(response[8], response[9], response[10], response[11]), nil
+}
+
+// AddPortMapping implements the NAT interface
+func (n *natPMP) AddPortMapping(internalPort uint16, externalPort uint16, _ string, lifetime time.Duration) (
+	uint16, error) {
+
+	response, err := n.mapTCP(internalPort, externalPort, uint32(lifetime/time.Second))
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(response[10:12]), nil
+}
+
+// DeletePortMapping implements the NAT interface
+func (n *natPMP) DeletePortMapping(internalPort uint16, _ uint16) error {
+	// A mapping is deleted by requesting it again with a lifetime of 0 and an external port of 0
+	_, err := n.mapTCP(internalPort, 0, 0)
+	return err
+}
+
+func (n *natPMP) mapTCP(internalPort uint16, externalPort uint16, lifetimeSeconds uint32) ([]byte, error) {
+	request := make([]byte, 12)
+	request[0] = natPMPVersion
+	request[1] = natPMPOpMapTCP
+	binary.BigEndian.PutUint16(request[4:6], internalPort)
+	binary.BigEndian.PutUint16(request[6:8], externalPort)
+	binary.BigEndian.PutUint32(request[8:12], lifetimeSeconds)
+	return n.request(request, natPMPMapResponseLength)
+}
+
+// request sends the given request to the gateway, resending it with an exponential backoff
+// until a response arrives or the timeout expires
+func (n *natPMP) request(request []byte, responseLength int) ([]byte, error) {
+	connection, err := net.DialUDP("udp4", nil, n.gateway)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer connection.Close()
+
+	deadline := time.Now().Add(n.timeout)
+	retryInterval := natPMPInitialRetryInterval
+	response := make([]byte, natPMPMapResponseLength)
+	for time.Now().Before(deadline) {
+		_, err = connection.Write(request)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		readDeadline := time.Now().Add(retryInterval)
+		if readDeadline.After(deadline) {
+			readDeadline = deadline
+		}
+		err = connection.SetReadDeadline(readDeadline)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for {
+			responseSize, err := connection.Read(response)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return nil, errors.WithStack(err)
+			}
+			if responseSize < responseLength || response[0] != natPMPVersion ||
+				response[1] != request[1]+natPMPResponseOpOffset {
+				// Not a response to this request
+				continue
+			}
+			resultCode := binary.BigEndian.Uint16(response[2:4])
+			if resultCode != 0 {
+				description, ok := natPMPResultCodeDescriptions[resultCode]
+				if !ok {
+					description = "unknown error"
+				}
+				return nil, errors.Errorf("the gateway refused the NAT-PMP request with result code %d: %s",
+					resultCode, description)
+			}
+			return response[:responseLength], nil
+		}
+		retryInterval *= 2
+	}
+	return nil, errors.Errorf("no NAT-PMP response from %s within %s", n.gateway, n.timeout)
+}
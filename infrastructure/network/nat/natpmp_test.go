@@ -0,0 +1,156 @@
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNATPMPGateway is a NAT-PMP gateway listening on a local UDP port. It maps every requested
+// port to the port after it, and drops the first request it receives, to exercise retries.
+type fakeNATPMPGateway struct {
+	connection *net.UDPConn
+
+	lock            sync.Mutex
+	resultCode      uint16
+	mappings        map[uint16]uint32
+	droppedRequests int
+}
+
+func newFakeNATPMPGateway(t *testing.T) *fakeNATPMPGateway {
+	connection, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %s", err)
+	}
+	gateway := &fakeNATPMPGateway{
+		connection: connection,
+		mappings:   make(map[uint16]uint32),
+	}
+	go gateway.serve()
+	return gateway
+}
+
+func (gateway *fakeNATPMPGateway) address() *net.UDPAddr {
+	return gateway.connection.LocalAddr().(*net.UDPAddr)
+}
+
+func (gateway *fakeNATPMPGateway) serve() {
+	request := make([]byte, 12)
+	for {
+		size, address, err := gateway.connection.ReadFrom(request)
+		if err != nil {
+			return
+		}
+		gateway.lock.Lock()
+		if gateway.droppedRequests == 0 {
+			gateway.droppedRequests++
+			gateway.lock.Unlock()
+			continue
+		}
+		if size < 2 {
+			gateway.lock.Unlock()
+			continue
+		}
+
+		var response []byte
+		switch request[1] {
+		case natPMPOpExternalAddress:
+			response = make([]byte, 12)
+			copy(response[8:12], net.IPv4(198, 51, 100, 3).To4())
+		case natPMPOpMapTCP:
+			response = make([]byte, 16)
+			internalPort := binary.BigEndian.Uint16(request[4:6])
+			lifetime := binary.BigEndian.Uint32(request[8:12])
+			binary.BigEndian.PutUint16(response[8:10], internalPort)
+			if lifetime == 0 {
+				delete(gateway.mappings, internalPort)
+			} else {
+				gateway.mappings[internalPort] = lifetime
+				binary.BigEndian.PutUint16(response[10:12], internalPort+1)
+			}
+			binary.BigEndian.PutUint32(response[12:16], lifetime)
+		default:
+			response = make([]byte, 8)
+			binary.BigEndian.PutUint16(response[2:4], 5)
+		}
+		response[1] = request[1] + natPMPResponseOpOffset
+		if gateway.resultCode != 0 {
+			binary.BigEndian.PutUint16(response[2:4], gateway.resultCode)
+		}
+		gateway.lock.Unlock()
+
+		// Responses to other requests are ignored
+		_, _ = gateway.connection.WriteTo([]byte{natPMPVersion, 255, 0, 0}, address)
+		_, _ = gateway.connection.WriteTo(response, address)
+	}
+}
+
+func (gateway *fakeNATPMPGateway) mapping(internalPort uint16) (uint32, bool) {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+
+	lifetime, ok := gateway.mappings[internalPort]
+	return lifetime, ok
+}
+
+func TestNATPMP(t *testing.T) {
+	gateway := newFakeNATPMPGateway(t)
+	defer gateway.connection.Close()
+
+	nat := newNATPMP(gateway.address(), 5*time.Second)
+	externalIP, err := nat.ExternalIP()
+	if err != nil {
+		t.Fatalf("ExternalIP: %s", err)
+	}
+	if !externalIP.Equal(net.IPv4(198, 51, 100, 3)) {
+		t.Fatalf("Unexpected external IP %s", externalIP)
+	}
+
+	externalPort, err := nat.AddPortMapping(16111, 16111, "stokes", 20*time.Minute)
+	if err != nil {
+		t.Fatalf("AddPortMapping: %s", err)
+	}
+	if externalPort != 16112 {
+		t.Fatalf("Expected the port chosen by the gateway, 16112, but got %d", externalPort)
+	}
+	lifetime, ok := gateway.mapping(16111)
+	if !ok {
+		t.Fatalf("The gateway didn't map the port")
+	}
+	if lifetime != 1200 {
+		t.Fatalf("Expected a lifetime of 1200 seconds, but got %d", lifetime)
+	}
+
+	err = nat.DeletePortMapping(16111, externalPort)
+	if err != nil {
+		t.Fatalf("DeletePortMapping: %s", err)
+	}
+	if _, ok := gateway.mapping(16111); ok {
+		t.Fatalf("The gateway didn't delete the mapping")
+	}
+}
+
+func TestNATPMPErrors(t *testing.T) {
+	gateway := newFakeNATPMPGateway(t)
+	defer gateway.connection.Close()
+	gateway.lock.Lock()
+	gateway.resultCode = 2
+	gateway.lock.Unlock()
+
+	nat := newNATPMP(gateway.address(), 5*time.Second)
+	_, err := nat.AddPortMapping(16111, 16111, "stokes", 20*time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "not authorized or refused") {
+		t.Fatalf("Expected a refusal, but got %v", err)
+	}
+
+	// Nothing answers on the port of a closed gateway
+	gateway.connection.Close()
+	nat = newNATPMP(gateway.address(), 300*time.Millisecond)
+	_, err = nat.ExternalIP()
+	if err == nil {
+		t.Fatalf("Expected an error when the gateway doesn't respond")
+	}
+}
@@ -0,0 +1,165 @@
+package nat
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+const (
+	// portMappingLifetime is the lease a port is mapped for. The mapping is renewed halfway through
+	// its lease, so that it expires soon after the node stops even if it never got to delete it.
+	portMappingLifetime = 20 * time.Minute
+
+	// discoveryTimeout is how long to wait for gateways to respond
+	discoveryTimeout = 5 * time.Second
+
+	// discoveryRetryInterval is how long to wait before looking for a gateway again after
+	// none was found or mapping the port failed
+	discoveryRetryInterval = 5 * time.Minute
+
+	portMappingDescription = "stokes"
+)
+
+// PortMapper maps a TCP port through the gateway of the local network and keeps the mapping alive
+// for as long as it's running, so that peers outside the NAT can connect to this node
+type PortMapper struct {
+	internalPort      uint16
+	onExternalAddress func(netAddress *appmessage.NetAddress)
+
+	discover       func() (NAT, error)
+	lifetime       time.Duration
+	retryInterval  time.Duration
+	stop           chan struct{}
+	done           chan struct{}
+	stopOnce       sync.Once
+	started        int32
+	externalPort   uint16
+	isMapped       bool
+	currentGateway NAT
+	needsDiscovery bool
+	externalIP     net.IP
+	reportedPort   uint16
+}
+
+// NewPortMapper returns a PortMapper for the given port. onExternalAddress is called with the
+// external address of the port whenever it's mapped to a new one.
+// Use Start() to begin mapping the port
+func NewPortMapper(internalPort uint16, onExternalAddress func(netAddress *appmessage.NetAddress)) *PortMapper {
+	return &PortMapper{
+		internalPort:      internalPort,
+		onExternalAddress: onExternalAddress,
+		discover: func() (NAT, error) {
+			return Discover(discoveryTimeout)
+		},
+		lifetime:      portMappingLifetime,
+		retryInterval: discoveryRetryInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start begins looking for a gateway and mapping the port through it
+func (pm *PortMapper) Start() {
+	if !atomic.CompareAndSwapInt32(&pm.started, 0, 1) {
+		return
+	}
+	spawn("PortMapper.run", pm.run)
+}
+
+// Stop stops renewing the port mapping and deletes it from the gateway
+func (pm *PortMapper) Stop() {
+	pm.stopOnce.Do(func() {
+		close(pm.stop)
+	})
+	if atomic.LoadInt32(&pm.started) == 1 {
+		<-pm.done
+	}
+}
+
+func (pm *PortMapper) run() {
+	defer close(pm.done)
+	defer pm.deleteMapping()
+
+	for {
+		wait := pm.lifetime / 2
+		err := pm.mapPort()
+		if err != nil {
+			log.Warnf("Failed to map port %d through the NAT, retrying in %s: %s", pm.internalPort, pm.retryInterval, err)
+			pm.needsDiscovery = true
+			wait = pm.retryInterval
+		}
+
+		select {
+		case <-pm.stop:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// mapPort maps the port through the current gateway, looking for one if there's none, and
+// reports the external address if it changed
+func (pm *PortMapper) mapPort() error {
+	if pm.currentGateway == nil || pm.needsDiscovery {
+		gateway, err := pm.discover()
+		if err != nil {
+			return err
+		}
+		log.Infof("Found a %s gateway", gateway)
+		pm.currentGateway = gateway
+		pm.needsDiscovery = false
+	}
+
+	// Ask for the port mapped before, so that the advertised address stays the same
+	requestedExternalPort := pm.internalPort
+	if pm.isMapped {
+		requestedExternalPort = pm.externalPort
+	}
+	externalPort, err := pm.currentGateway.AddPortMapping(pm.internalPort, requestedExternalPort,
+		portMappingDescription, pm.lifetime)
+	if err != nil {
+		return err
+	}
+	if pm.isMapped && externalPort != pm.externalPort {
+		// The old mapping may still be alive until its lease runs out
+		err := pm.currentGateway.DeletePortMapping(pm.internalPort, pm.externalPort)
+		if err != nil {
+			log.Debugf("Failed to delete the old mapping of port %d: %s", pm.externalPort, err)
+		}
+	}
+	pm.externalPort = externalPort
+	pm.isMapped = true
+
+	externalIP, err := pm.currentGateway.ExternalIP()
+	if err != nil {
+		return err
+	}
+	if externalIP.Equal(pm.externalIP) && externalPort == pm.reportedPort {
+		log.Debugf("Renewed the mapping of port %d through %s", pm.internalPort, pm.currentGateway)
+		return nil
+	}
+	pm.externalIP = externalIP
+	pm.reportedPort = externalPort
+
+	log.Infof("Mapped port %d to %s through %s", pm.internalPort,
+		net.JoinHostPort(externalIP.String(), strconv.Itoa(int(externalPort))), pm.currentGateway)
+	pm.onExternalAddress(appmessage.NewNetAddressIPPort(externalIP, externalPort))
+	return nil
+}
+
+func (pm *PortMapper) deleteMapping() {
+	if !pm.isMapped || pm.currentGateway == nil {
+		return
+	}
+	err := pm.currentGateway.DeletePortMapping(pm.internalPort, pm.externalPort)
+	if err != nil {
+		log.Warnf("Failed to delete the mapping of port %d through %s: %s", pm.internalPort, pm.currentGateway, err)
+		return
+	}
+	log.Infof("Deleted the mapping of port %d through %s", pm.internalPort, pm.currentGateway)
+}
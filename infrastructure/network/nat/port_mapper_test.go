@@ -0,0 +1,164 @@
+package nat
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+type fakeNAT struct {
+	lock                sync.Mutex
+	externalIP          net.IP
+	portOffset          uint16
+	mappings            map[uint16]uint16
+	addPortMappingCalls int
+	shouldFail          bool
+}
+
+func (n *fakeNAT) String() string {
+	return "fake"
+}
+
+func (n *fakeNAT) ExternalIP() (net.IP, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.externalIP, nil
+}
+
+func (n *fakeNAT) AddPortMapping(internalPort uint16, externalPort uint16, _ string, _ time.Duration) (uint16, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.addPortMappingCalls++
+	if n.shouldFail {
+		return 0, errors.New("the gateway is down")
+	}
+	// The gateway chooses its own external port when portOffset is set
+	if n.portOffset != 0 {
+		externalPort = internalPort + n.portOffset
+	}
+	n.mappings[externalPort] = internalPort
+	return externalPort, nil
+}
+
+func (n *fakeNAT) DeletePortMapping(_ uint16, externalPort uint16) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	delete(n.mappings, externalPort)
+	return nil
+}
+
+func (n *fakeNAT) set(change func()) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	change()
+}
+
+func (n *fakeNAT) calls() (addPortMappingCalls int, mappings map[uint16]uint16) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	mappings = make(map[uint16]uint16, len(n.mappings))
+	for externalPort, internalPort := range n.mappings {
+		mappings[externalPort] = internalPort
+	}
+	return n.addPortMappingCalls, mappings
+}
+
+func waitFor(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", description)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPortMapper(t *testing.T) {
+	nat := &fakeNAT{
+		externalIP: net.IPv4(203, 0, 113, 9),
+		mappings:   make(map[uint16]uint16),
+	}
+	discoveries := 0
+	externalAddresses := make(chan *appmessage.NetAddress, 10)
+
+	portMapper := NewPortMapper(16111, func(netAddress *appmessage.NetAddress) {
+		externalAddresses <- netAddress
+	})
+	portMapper.discover = func() (NAT, error) {
+		nat.lock.Lock()
+		defer nat.lock.Unlock()
+
+		discoveries++
+		if discoveries == 1 {
+			return nil, errors.New("no gateway yet")
+		}
+		return nat, nil
+	}
+	portMapper.lifetime = 20 * time.Millisecond
+	portMapper.retryInterval = 10 * time.Millisecond
+	portMapper.Start()
+
+	expectExternalAddress := func(ip net.IP, port uint16) {
+		select {
+		case netAddress := <-externalAddresses:
+			if !netAddress.IP.Equal(ip) || netAddress.Port != port {
+				t.Fatalf("Expected external address %s:%d, but got %s", ip, port, netAddress.TCPAddress())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for external address %s:%d", ip, port)
+		}
+	}
+
+	// The port mapper keeps looking for a gateway until it finds one
+	expectExternalAddress(net.IPv4(203, 0, 113, 9), 16111)
+
+	// Renewing the mapping doesn't report the same address again
+	waitFor(t, "the mapping to be renewed", func() bool {
+		addPortMappingCalls, _ := nat.calls()
+		return addPortMappingCalls >= 3
+	})
+	select {
+	case netAddress := <-externalAddresses:
+		t.Fatalf("Unexpected external address %s", netAddress.TCPAddress())
+	default:
+	}
+
+	// A new external IP is reported
+	nat.set(func() { nat.externalIP = net.IPv4(203, 0, 113, 10) })
+	expectExternalAddress(net.IPv4(203, 0, 113, 10), 16111)
+
+	// If the gateway maps a different port, the new port is reported and the old mapping is deleted
+	nat.set(func() { nat.portOffset = 1 })
+	expectExternalAddress(net.IPv4(203, 0, 113, 10), 16112)
+	_, mappings := nat.calls()
+	if _, ok := mappings[16111]; ok {
+		t.Fatalf("Expected the old mapping to be deleted")
+	}
+
+	// If renewing fails, the port mapper looks for a gateway again
+	nat.set(func() {
+		nat.portOffset = 0
+		nat.shouldFail = true
+	})
+	waitFor(t, "another discovery", func() bool {
+		nat.lock.Lock()
+		defer nat.lock.Unlock()
+		return discoveries >= 3
+	})
+	nat.set(func() { nat.shouldFail = false })
+
+	portMapper.Stop()
+	_, mappings = nat.calls()
+	if len(mappings) != 0 {
+		t.Fatalf("Expected the mapping to be deleted when the port mapper stops, but got %v", mappings)
+	}
+}
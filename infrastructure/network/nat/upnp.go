@@ -0,0 +1,327 @@
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The UPnP Internet Gateway Device protocol is specified by the Open Connectivity Foundation
+// at https://openconnectivity.org/developer/specifications/upnp-resources/upnp/
+
+var ssdpMulticastAddress = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+const ssdpSearchTarget = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+
+// upnpErrorOnlyPermanentLeasesSupported is returned by gateways that refuse mappings with
+// a lease duration
+const upnpErrorOnlyPermanentLeasesSupported = 725
+
+// upnpConnectionServiceTypes are the services that can map ports, by order of preference
+var upnpConnectionServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+type upnpNAT struct {
+	controlURL  string
+	serviceType string
+	internalIP  net.IP
+	httpClient  *http.Client
+}
+
+func (u *upnpNAT) String() string {
+	return "UPnP"
+}
+
+// discoverUPnP multicasts an SSDP search for internet gateway devices to the given address,
+// and returns the first one that responds and offers a connection service
+func discoverUPnP(ssdpAddress *net.UDPAddr, timeout time.Duration) (*upnpNAT, error) {
+	connection, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer connection.Close()
+
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		fmt.Sprintf("HOST: %s\r\n", ssdpAddress) +
+		fmt.Sprintf("ST: %s\r\n", ssdpSearchTarget) +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n\r\n"
+	_, err = connection.WriteTo([]byte(search), ssdpAddress)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	err = connection.SetReadDeadline(deadline)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	httpClient := &http.Client{Timeout: timeout}
+	buffer := make([]byte, 2048)
+	for {
+		responseSize, _, err := connection.ReadFrom(buffer)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return nil, errors.Errorf("no UPnP internet gateway device responded within %s", timeout)
+			}
+			return nil, errors.WithStack(err)
+		}
+		response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buffer[:responseSize])), nil)
+		if err != nil {
+			continue
+		}
+		response.Body.Close()
+		location := response.Header.Get("Location")
+		if response.StatusCode != http.StatusOK || location == "" ||
+			!strings.Contains(response.Header.Get("St"), "InternetGatewayDevice") {
+			continue
+		}
+		nat, err := newUPnPNAT(location, httpClient)
+		if err != nil {
+			log.Debugf("Ignoring UPnP device at %s: %s", location, err)
+			continue
+		}
+		return nat, nil
+	}
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpDevice struct {
+	Services []upnpService `xml:"serviceList>service"`
+	Devices  []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpDeviceDescription struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+func (device *upnpDevice) findService(serviceType string) (*upnpService, bool) {
+	for i := range device.Services {
+		if device.Services[i].ServiceType == serviceType {
+			return &device.Services[i], true
+		}
+	}
+	for i := range device.Devices {
+		if service, ok := device.Devices[i].findService(serviceType); ok {
+			return service, true
+		}
+	}
+	return nil, false
+}
+
+// newUPnPNAT fetches the device description at the given location and finds its connection service
+func newUPnPNAT(location string, httpClient *http.Client) (*upnpNAT, error) {
+	response, err := httpClient.Get(location)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fetching the device description returned status %s", response.Status)
+	}
+	description := &upnpDeviceDescription{}
+	err = xml.NewDecoder(response.Body).Decode(description)
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed device description")
+	}
+
+	baseURL, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if description.URLBase != "" {
+		baseURL, err = url.Parse(description.URLBase)
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed URLBase")
+		}
+	}
+
+	for _, serviceType := range upnpConnectionServiceTypes {
+		service, ok := description.Device.findService(serviceType)
+		if !ok {
+			continue
+		}
+		controlURL, err := baseURL.Parse(service.ControlURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed controlURL")
+		}
+		internalIP, err := internalIPTowards(controlURL.Host)
+		if err != nil {
+			return nil, err
+		}
+		return &upnpNAT{
+			controlURL:  controlURL.String(),
+			serviceType: serviceType,
+			internalIP:  internalIP,
+			httpClient:  httpClient,
+		}, nil
+	}
+	return nil, errors.New("the device offers no WAN connection service")
+}
+
+// internalIPTowards returns the IP of the interface that this host uses to reach the given host
+func internalIPTowards(hostPort string) (net.IP, error) {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host, port = hostPort, "80"
+	}
+	// Dialing UDP sends nothing, but it does pick the outgoing interface
+	connection, err := net.Dial("udp4", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer connection.Close()
+	return connection.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// ExternalIP implements the NAT interface
+func (u *upnpNAT) ExternalIP() (net.IP, error) {
+	response := &struct {
+		ExternalIPAddress string `xml:"NewExternalIPAddress"`
+	}{}
+	err := u.soapRequest("GetExternalIPAddress", nil, response)
+	if err != nil {
+		return nil, err
+	}
+	externalIP := net.ParseIP(response.ExternalIPAddress)
+	if externalIP == nil {
+		return nil, errors.Errorf("the gateway returned a malformed external IP %q", response.ExternalIPAddress)
+	}
+	return externalIP, nil
+}
+
+// AddPortMapping implements the NAT interface
+func (u *upnpNAT) AddPortMapping(internalPort uint16, externalPort uint16, description string,
+	lifetime time.Duration) (uint16, error) {
+
+	addPortMapping := func(lifetime time.Duration) error {
+		return u.soapRequest("AddPortMapping", []soapArgument{
+			{"NewRemoteHost", ""},
+			{"NewExternalPort", strconv.Itoa(int(externalPort))},
+			{"NewProtocol", "TCP"},
+			{"NewInternalPort", strconv.Itoa(int(internalPort))},
+			{"NewInternalClient", u.internalIP.String()},
+			{"NewEnabled", "1"},
+			{"NewPortMappingDescription", description},
+			{"NewLeaseDuration", strconv.Itoa(int(lifetime / time.Second))},
+		}, nil)
+	}
+	err := addPortMapping(lifetime)
+	if err != nil {
+		upnpErr := &upnpError{}
+		if !errors.As(err, &upnpErr) || upnpErr.Code != upnpErrorOnlyPermanentLeasesSupported {
+			return 0, err
+		}
+		// The mapping is then deleted when the port mapper stops
+		err = addPortMapping(0)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return externalPort, nil
+}
+
+// DeletePortMapping implements the NAT interface
+func (u *upnpNAT) DeletePortMapping(_ uint16, externalPort uint16) error {
+	return u.soapRequest("DeletePortMapping", []soapArgument{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(externalPort))},
+		{"NewProtocol", "TCP"},
+	}, nil)
+}
+
+type soapArgument struct {
+	name  string
+	value string
+}
+
+type upnpError struct {
+	Code        int    `xml:"detail>UPnPError>errorCode"`
+	Description string `xml:"detail>UPnPError>errorDescription"`
+}
+
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("UPnP error %d: %s", e.Code, e.Description)
+}
+
+type soapEnvelope struct {
+	Body struct {
+		Content []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// soapRequest invokes the given action of the connection service, and decodes its response into
+// the given response, if it's not nil
+func (u *upnpNAT) soapRequest(action string, arguments []soapArgument, response interface{}) error {
+	requestBody := &bytes.Buffer{}
+	requestBody.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(requestBody, `<u:%s xmlns:u="%s">`, action, u.serviceType)
+	for _, argument := range arguments {
+		fmt.Fprintf(requestBody, "<%s>", argument.name)
+		err := xml.EscapeText(requestBody, []byte(argument.value))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Fprintf(requestBody, "</%s>", argument.name)
+	}
+	fmt.Fprintf(requestBody, `</u:%s></s:Body></s:Envelope>`, action)
+
+	request, err := http.NewRequest(http.MethodPost, u.controlURL, requestBody)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	request.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	request.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, u.serviceType, action))
+
+	httpResponse, err := u.httpClient.Do(request)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer httpResponse.Body.Close()
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	envelope := &soapEnvelope{}
+	envelopeErr := xml.Unmarshal(responseBody, envelope)
+	if httpResponse.StatusCode != http.StatusOK {
+		upnpErr := &upnpError{}
+		if envelopeErr == nil && xml.Unmarshal(envelope.Body.Content, upnpErr) == nil && upnpErr.Code != 0 {
+			return errors.Wrapf(upnpErr, "%s failed", action)
+		}
+		return errors.Errorf("%s failed with status %s", action, httpResponse.Status)
+	}
+	if response == nil {
+		return nil
+	}
+	if envelopeErr != nil {
+		return errors.Wrapf(envelopeErr, "malformed %s response", action)
+	}
+	err = xml.Unmarshal(envelope.Body.Content, response)
+	if err != nil {
+		return errors.Wrapf(err, "malformed %s response", action)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package nat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const fakeGatewayDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+	<device>
+		<deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+		<serviceList>
+			<service>
+				<serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType>
+				<controlURL>/ctl/L3F</controlURL>
+			</service>
+		</serviceList>
+		<deviceList>
+			<device>
+				<deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+				<deviceList>
+					<device>
+						<deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+						<serviceList>
+							<service>
+								<serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+								<controlURL>/ctl/IPConn</controlURL>
+							</service>
+						</serviceList>
+					</device>
+				</deviceList>
+			</device>
+		</deviceList>
+	</device>
+</root>`
+
+type fakeUPnPMapping struct {
+	internalPort   string
+	internalClient string
+	description    string
+	leaseDuration  string
+}
+
+// fakeUPnPGateway is an internet gateway device that answers SSDP searches on a local UDP port
+// and serves its description and connection service over HTTP
+type fakeUPnPGateway struct {
+	t                   *testing.T
+	ssdpConnection      *net.UDPConn
+	httpServer          *httptest.Server
+	onlyPermanentLeases bool
+	lock                sync.Mutex
+	mappings            map[string]*fakeUPnPMapping
+	receivedSOAPActions []string
+}
+
+func newFakeUPnPGateway(t *testing.T) *fakeUPnPGateway {
+	gateway := &fakeUPnPGateway{
+		t:        t,
+		mappings: make(map[string]*fakeUPnPMapping),
+	}
+	gateway.httpServer = httptest.NewServer(http.HandlerFunc(gateway.serveHTTP))
+
+	var err error
+	gateway.ssdpConnection, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %s", err)
+	}
+	go gateway.serveSSDP()
+	return gateway
+}
+
+func (gateway *fakeUPnPGateway) close() {
+	gateway.ssdpConnection.Close()
+	gateway.httpServer.Close()
+}
+
+func (gateway *fakeUPnPGateway) ssdpAddress() *net.UDPAddr {
+	return gateway.ssdpConnection.LocalAddr().(*net.UDPAddr)
+}
+
+func (gateway *fakeUPnPGateway) serveSSDP() {
+	buffer := make([]byte, 2048)
+	for {
+		size, address, err := gateway.ssdpConnection.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		if !strings.HasPrefix(string(buffer[:size]), "M-SEARCH") {
+			continue
+		}
+		// Other devices on the network respond too
+		_, _ = gateway.ssdpConnection.WriteTo([]byte("HTTP/1.1 200 OK\r\n"+
+			"ST: urn:schemas-upnp-org:device:MediaServer:1\r\n"+
+			"LOCATION: http://127.0.0.1:1/nothing.xml\r\n\r\n"), address)
+		_, _ = gateway.ssdpConnection.WriteTo([]byte("HTTP/1.1 200 OK\r\n"+
+			"CACHE-CONTROL: max-age=120\r\n"+
+			"ST: "+ssdpSearchTarget+"\r\n"+
+			"LOCATION: "+gateway.httpServer.URL+"/rootDesc.xml\r\n\r\n"), address)
+	}
+}
+
+func (gateway *fakeUPnPGateway) serveHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch request.URL.Path {
+	case "/rootDesc.xml":
+		_, _ = io.WriteString(writer, fakeGatewayDescription)
+	case "/ctl/IPConn":
+		gateway.serveSOAP(writer, request)
+	default:
+		http.NotFound(writer, request)
+	}
+}
+
+func (gateway *fakeUPnPGateway) serveSOAP(writer http.ResponseWriter, request *http.Request) {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+
+	gateway.receivedSOAPActions = append(gateway.receivedSOAPActions, request.Header.Get("SOAPAction"))
+	envelope := &soapEnvelope{}
+	body, _ := io.ReadAll(request.Body)
+	err := xml.Unmarshal(body, envelope)
+	if err != nil {
+		gateway.t.Errorf("malformed SOAP request: %s", err)
+		return
+	}
+	arguments := &struct {
+		XMLName        xml.Name
+		ExternalPort   string `xml:"NewExternalPort"`
+		Protocol       string `xml:"NewProtocol"`
+		InternalPort   string `xml:"NewInternalPort"`
+		InternalClient string `xml:"NewInternalClient"`
+		Description    string `xml:"NewPortMappingDescription"`
+		LeaseDuration  string `xml:"NewLeaseDuration"`
+	}{}
+	err = xml.Unmarshal(envelope.Body.Content, arguments)
+	if err != nil {
+		gateway.t.Errorf("malformed SOAP request: %s", err)
+		return
+	}
+
+	respond := func(content string) {
+		_, _ = fmt.Fprintf(writer, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">`+
+			`<s:Body>%s</s:Body></s:Envelope>`, content)
+	}
+	respondWithError := func(code int, description string) {
+		writer.WriteHeader(http.StatusInternalServerError)
+		respond(fmt.Sprintf(`<s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring>`+
+			`<detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode>`+
+			`<errorDescription>%s</errorDescription></UPnPError></detail></s:Fault>`, code, description))
+	}
+
+	switch arguments.XMLName.Local {
+	case "GetExternalIPAddress":
+		respond(`<u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">` +
+			`<NewExternalIPAddress>203.0.113.7</NewExternalIPAddress></u:GetExternalIPAddressResponse>`)
+	case "AddPortMapping":
+		if arguments.Protocol != "TCP" {
+			respondWithError(402, "Invalid Args")
+			return
+		}
+		if gateway.onlyPermanentLeases && arguments.LeaseDuration != "0" {
+			respondWithError(upnpErrorOnlyPermanentLeasesSupported, "OnlyPermanentLeasesSupported")
+			return
+		}
+		gateway.mappings[arguments.ExternalPort] = &fakeUPnPMapping{
+			internalPort:   arguments.InternalPort,
+			internalClient: arguments.InternalClient,
+			description:    arguments.Description,
+			leaseDuration:  arguments.LeaseDuration,
+		}
+		respond(`<u:AddPortMappingResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1"/>`)
+	case "DeletePortMapping":
+		if _, ok := gateway.mappings[arguments.ExternalPort]; !ok {
+			respondWithError(714, "NoSuchEntryInArray")
+			return
+		}
+		delete(gateway.mappings, arguments.ExternalPort)
+		respond(`<u:DeletePortMappingResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1"/>`)
+	default:
+		respondWithError(401, "Invalid Action")
+	}
+}
+
+func (gateway *fakeUPnPGateway) mapping(externalPort string) (*fakeUPnPMapping, bool) {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+
+	mapping, ok := gateway.mappings[externalPort]
+	return mapping, ok
+}
+
+func TestUPnP(t *testing.T) {
+	gateway := newFakeUPnPGateway(t)
+	defer gateway.close()
+
+	nat, err := discoverUPnP(gateway.ssdpAddress(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("discoverUPnP: %s", err)
+	}
+	if nat.serviceType != "urn:schemas-upnp-org:service:WANIPConnection:1" {
+		t.Fatalf("Unexpected service type %s", nat.serviceType)
+	}
+	if nat.controlURL != gateway.httpServer.URL+"/ctl/IPConn" {
+		t.Fatalf("Unexpected control URL %s", nat.controlURL)
+	}
+
+	externalIP, err := nat.ExternalIP()
+	if err != nil {
+		t.Fatalf("ExternalIP: %s", err)
+	}
+	if !externalIP.Equal(net.IPv4(203, 0, 113, 7)) {
+		t.Fatalf("Unexpected external IP %s", externalIP)
+	}
+
+	externalPort, err := nat.AddPortMapping(16111, 16112, "stokes & co", 20*time.Minute)
+	if err != nil {
+		t.Fatalf("AddPortMapping: %s", err)
+	}
+	if externalPort != 16112 {
+		t.Fatalf("Unexpected external port %d", externalPort)
+	}
+	mapping, ok := gateway.mapping("16112")
+	if !ok {
+		t.Fatalf("The gateway didn't map the port")
+	}
+	expectedMapping := fakeUPnPMapping{
+		internalPort:   "16111",
+		internalClient: "127.0.0.1",
+		description:    "stokes & co",
+		leaseDuration:  "1200",
+	}
+	if *mapping != expectedMapping {
+		t.Fatalf("Expected mapping %+v, but got %+v", expectedMapping, *mapping)
+	}
+	expectedSOAPAction := `"urn:schemas-upnp-org:service:WANIPConnection:1#AddPortMapping"`
+	if gateway.receivedSOAPActions[1] != expectedSOAPAction {
+		t.Fatalf("Expected SOAPAction %s, but got %s", expectedSOAPAction, gateway.receivedSOAPActions[1])
+	}
+
+	err = nat.DeletePortMapping(16111, 16112)
+	if err != nil {
+		t.Fatalf("DeletePortMapping: %s", err)
+	}
+	if _, ok := gateway.mapping("16112"); ok {
+		t.Fatalf("The gateway didn't delete the mapping")
+	}
+
+	// Deleting a mapping that doesn't exist returns the error of the gateway
+	err = nat.DeletePortMapping(16111, 16112)
+	if err == nil || !strings.Contains(err.Error(), "UPnP error 714: NoSuchEntryInArray") {
+		t.Fatalf("Expected a NoSuchEntryInArray error, but got %v", err)
+	}
+}
+
+func TestUPnPOnlyPermanentLeases(t *testing.T) {
+	gateway := newFakeUPnPGateway(t)
+	defer gateway.close()
+	gateway.onlyPermanentLeases = true
+
+	nat, err := discoverUPnP(gateway.ssdpAddress(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("discoverUPnP: %s", err)
+	}
+	_, err = nat.AddPortMapping(16111, 16111, "stokes", 20*time.Minute)
+	if err != nil {
+		t.Fatalf("AddPortMapping: %s", err)
+	}
+	mapping, ok := gateway.mapping("16111")
+	if !ok {
+		t.Fatalf("The gateway didn't map the port")
+	}
+	if mapping.leaseDuration != "0" {
+		t.Fatalf("Expected a permanent lease, but got a lease of %s seconds", mapping.leaseDuration)
+	}
+}
+
+func TestUPnPNoGateway(t *testing.T) {
+	connection, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %s", err)
+	}
+	defer connection.Close()
+
+	_, err = discoverUPnP(connection.LocalAddr().(*net.UDPAddr), 200*time.Millisecond)
+	if err == nil {
+		t.Fatalf("Expected discovery to fail when nothing responds")
+	}
+}
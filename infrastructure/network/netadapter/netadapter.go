@@ -1,8 +1,10 @@
 package netadapter
 
 import (
+	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/infrastructure/config"
@@ -42,7 +44,7 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners)
+	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners, cfg.OnionListener, p2pDialFunc(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +67,31 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	return &adapter, nil
 }
 
+// p2pDialFunc returns the function to connect to peers with: through the onion proxy
+// for onion services, and through cfg.Dial for anyone else
+func p2pDialFunc(cfg *config.Config) grpcserver.DialFunc {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if appmessage.IsOverlayHost(host) {
+			networkID, _, err := appmessage.DecodeOverlayHost(host)
+			if err != nil {
+				return nil, err
+			}
+			if networkID != appmessage.NetworkIDTorV3 || cfg.OnionDial == nil {
+				return nil, errors.Errorf("can't connect to %s: no proxy to reach its network through", address)
+			}
+			return cfg.OnionDial(network, address, timeout)
+		}
+		if cfg.Dial == nil {
+			return net.DialTimeout(network, address, timeout)
+		}
+		return cfg.Dial(network, address, timeout)
+	}
+}
+
 // Start begins the operation of the NetAdapter
 func (na *NetAdapter) Start() error {
 	if na.p2pRouterInitializer == nil {
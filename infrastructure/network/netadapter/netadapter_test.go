@@ -2,10 +2,14 @@ package netadapter
 
 import (
 	"fmt"
+	"net"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/stokesnetwork/stokes/app/appmessage"
 
 	"github.com/stokesnetwork/stokes/infrastructure/config"
@@ -164,3 +168,107 @@ func TestNetAdapter(t *testing.T) {
 		t.Fatalf("TestNetAdapter: error expected at attempt to stop adapter second time, but got nothing")
 	}
 }
+
+func TestOnionInboundConnections(t *testing.T) {
+	const (
+		timeout       = time.Second * 5
+		address       = "127.0.0.1:3010"
+		onionListener = "127.0.0.1:3011"
+	)
+
+	cfg := config.DefaultConfig()
+	cfg.Listeners = []string{address}
+	cfg.OnionListener = onionListener
+	adapter, err := NewNetAdapter(cfg)
+	if err != nil {
+		t.Fatalf("NetAdapter instantiation failed: %+v", err)
+	}
+	isOnionInbound := make(chan bool, 2)
+	adapter.SetP2PRouterInitializer(func(router *router.Router, connection *NetConnection) {
+		isOnionInbound <- connection.IsOnionInbound()
+	})
+	adapter.SetRPCRouterInitializer(func(router *router.Router, connection *NetConnection) {})
+	err = adapter.Start()
+	if err != nil {
+		t.Fatalf("Start() failed: %+v", err)
+	}
+	defer adapter.Stop()
+
+	peerCfg := config.DefaultConfig()
+	peerCfg.Listeners = []string{"127.0.0.1:3012"}
+	peer, err := NewNetAdapter(peerCfg)
+	if err != nil {
+		t.Fatalf("NetAdapter instantiation failed: %+v", err)
+	}
+	peer.SetP2PRouterInitializer(func(router *router.Router, connection *NetConnection) {
+		if connection.IsOnionInbound() {
+			t.Errorf("Expected an outbound connection not to be an inbound onion connection")
+		}
+	})
+	peer.SetRPCRouterInitializer(func(router *router.Router, connection *NetConnection) {})
+	err = peer.Start()
+	if err != nil {
+		t.Fatalf("Start() failed: %+v", err)
+	}
+	defer peer.Stop()
+
+	// Only the connections that come in through the onion listener are inbound onion connections,
+	// even though all of them come from the same IP
+	for _, test := range []struct {
+		address                string
+		expectedIsOnionInbound bool
+	}{
+		{address: onionListener, expectedIsOnionInbound: true},
+		{address: address, expectedIsOnionInbound: false},
+	} {
+		err = peer.P2PConnect(test.address)
+		if err != nil {
+			t.Fatalf("Connection to %s failed: %+v", test.address, err)
+		}
+		select {
+		case got := <-isOnionInbound:
+			if got != test.expectedIsOnionInbound {
+				t.Fatalf("Expected the connection through %s to have isOnionInbound %t, but got %t",
+					test.address, test.expectedIsOnionInbound, got)
+			}
+		case <-time.After(timeout):
+			t.Fatalf("Timed out waiting for the connection through %s", test.address)
+		}
+	}
+}
+
+func TestP2PDialFunc(t *testing.T) {
+	const onionAddress = "2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion:16111"
+
+	var dialedThrough []string
+	dialer := func(name string) func(string, string, time.Duration) (net.Conn, error) {
+		return func(_, address string, _ time.Duration) (net.Conn, error) {
+			dialedThrough = append(dialedThrough, name+" "+address)
+			return nil, errors.New("not connecting in tests")
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Dial = dialer("proxy")
+	dial := p2pDialFunc(cfg)
+
+	// Without an onion proxy, onion services aren't dialed at all
+	_, err := dial("tcp", onionAddress, time.Second)
+	if err == nil || len(dialedThrough) != 0 {
+		t.Fatalf("Expected onion services not to be dialed without an onion proxy, but dialed %v", dialedThrough)
+	}
+
+	cfg.OnionDial = dialer("onion")
+	_, _ = dial("tcp", onionAddress, time.Second)
+	_, _ = dial("tcp", "1.2.3.4:16111", time.Second)
+	expected := []string{"onion " + onionAddress, "proxy 1.2.3.4:16111"}
+	if !reflect.DeepEqual(dialedThrough, expected) {
+		t.Fatalf("Expected dials %v, but got %v", expected, dialedThrough)
+	}
+
+	// I2P destinations can't be dialed
+	_, err = dial("tcp", "aaaqeayeaudaocajbifqydiob4ibceqtcqkrmfyydenbwha5dypq.b32.i2p:0", time.Second)
+	if err == nil || len(dialedThrough) != len(expected) {
+		t.Fatalf("Expected I2P destinations not to be dialed")
+	}
+}
@@ -75,9 +75,16 @@ func (c *NetConnection) IsOutbound() bool {
 	return c.connection.IsOutbound()
 }
 
+// IsOnionInbound returns whether the connection is an inbound connection from an onion peer,
+// forwarded by the onion service of this node
+func (c *NetConnection) IsOnionInbound() bool {
+	return c.connection.IsOnionInbound()
+}
+
 // NetAddress returns the NetAddress associated with this connection
 func (c *NetConnection) NetAddress() *appmessage.NetAddress {
-	return appmessage.NewNetAddress(c.connection.Address())
+	netAddress := *c.connection.Address()
+	return &netAddress
 }
 
 func (c *NetConnection) setOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
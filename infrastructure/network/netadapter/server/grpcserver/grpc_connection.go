@@ -1,10 +1,10 @@
 package grpcserver
 
 import (
-	"net"
 	"sync"
 	"sync/atomic"
 
+	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/server/grpcserver/protowire"
 	"github.com/pkg/errors"
@@ -17,10 +17,11 @@ import (
 
 type gRPCConnection struct {
 	server                   *gRPCServer
-	address                  *net.TCPAddr
+	address                  *appmessage.NetAddress
 	stream                   grpcStream
 	router                   *router.Router
 	lowLevelClientConnection *grpc.ClientConn
+	isOnionInbound           bool
 
 	// streamLock protects concurrent access to stream.
 	// Note that it's an RWMutex. Despite what the name
@@ -41,7 +42,7 @@ type grpcStream interface {
 	Recv() (*protowire.KaspadMessage, error)
 }
 
-func newConnection(server *gRPCServer, address *net.TCPAddr, stream grpcStream,
+func newConnection(server *gRPCServer, address *appmessage.NetAddress, stream grpcStream,
 	lowLevelClientConnection *grpc.ClientConn) *gRPCConnection {
 	connection := &gRPCConnection{
 		server:                   server,
@@ -100,6 +101,13 @@ func (c *gRPCConnection) IsOutbound() bool {
 	return c.lowLevelClientConnection != nil
 }
 
+// IsOnionInbound returns whether the connection came in through the onion listener
+//
+// This is part of the Connection interface
+func (c *gRPCConnection) IsOnionInbound() bool {
+	return c.isOnionInbound
+}
+
 // Disconnect disconnects the connection
 // Calling this function a second time doesn't do anything
 //
@@ -123,7 +131,7 @@ func (c *gRPCConnection) Disconnect() {
 	}
 }
 
-func (c *gRPCConnection) Address() *net.TCPAddr {
+func (c *gRPCConnection) Address() *appmessage.NetAddress {
 	return c.address
 }
 
@@ -3,6 +3,7 @@ package grpcserver
 import (
 	"context"
 	"fmt"
+	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/server"
 	"github.com/stokesnetwork/stokes/util/panics"
 	"github.com/pkg/errors"
@@ -17,6 +18,11 @@ type gRPCServer struct {
 	onConnectedHandler server.OnConnectedHandler
 	listeningAddresses []string
 	server             *grpc.Server
+
+	// onionListeningAddress is the address only the onion service forwards to, if there's one.
+	// Connections accepted on its port are marked as inbound onion connections.
+	onionListeningAddress string
+	onionPort             int
 	name               string
 
 	maxInboundConnections      int
@@ -43,19 +49,27 @@ func (s *gRPCServer) Start() error {
 	}
 
 	for _, listenAddress := range s.listeningAddresses {
-		err := s.listenOn(listenAddress)
+		_, err := s.listenOn(listenAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.onionListeningAddress != "" {
+		listener, err := s.listenOn(s.onionListeningAddress)
 		if err != nil {
 			return err
 		}
+		s.onionPort = listener.Addr().(*net.TCPAddr).Port
 	}
 
 	return nil
 }
 
-func (s *gRPCServer) listenOn(listenAddr string) error {
+func (s *gRPCServer) listenOn(listenAddr string) (net.Listener, error) {
 	listener, err := net.Listen("tcp", listenAddr)
 	if err != nil {
-		return errors.Wrapf(err, "%s error listening on %s", s.name, listenAddr)
+		return nil, errors.Wrapf(err, "%s error listening on %s", s.name, listenAddr)
 	}
 
 	spawn(fmt.Sprintf("%s.gRPCServer.listenOn-Serve", s.name), func() {
@@ -66,7 +80,7 @@ func (s *gRPCServer) listenOn(listenAddr string) error {
 	})
 
 	log.Infof("%s Server listening on %s", s.name, listener.Addr())
-	return nil
+	return listener, nil
 }
 
 func (s *gRPCServer) Stop() error {
@@ -109,7 +123,8 @@ func (s *gRPCServer) handleInboundConnection(ctx context.Context, stream grpcStr
 		return errors.Errorf("non-tcp connections are not supported")
 	}
 
-	connection := newConnection(s, tcpAddress, stream, nil)
+	connection := newConnection(s, appmessage.NewNetAddress(tcpAddress), stream, nil)
+	connection.isOnionInbound = s.isOnionListener(peerInfo.LocalAddr)
 
 	err = s.onConnectedHandler(connection)
 	if err != nil {
@@ -122,6 +137,17 @@ func (s *gRPCServer) handleInboundConnection(ctx context.Context, stream grpcStr
 	return nil
 }
 
+// isOnionListener returns whether the given local address of an inbound connection belongs to the
+// onion listener. The onion listener doesn't share its port with any other listener, so it's told
+// apart by its port alone, whichever interface it listens on.
+func (s *gRPCServer) isOnionListener(localAddress net.Addr) bool {
+	if s.onionListeningAddress == "" {
+		return false
+	}
+	tcpAddress, ok := localAddress.(*net.TCPAddr)
+	return ok && tcpAddress.Port == s.onionPort
+}
+
 func (s *gRPCServer) incrementInboundConnectionCountAndLimitIfRequired() (int, error) {
 	s.inboundConnectionCountLock.Lock()
 	defer s.inboundConnectionCountLock.Unlock()
@@ -2,22 +2,29 @@ package grpcserver
 
 import (
 	"context"
+	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/server"
 	"github.com/stokesnetwork/stokes/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/stokesnetwork/stokes/util/mstime"
 	"github.com/stokesnetwork/stokes/util/panics"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/peer"
 	"net"
+	"strconv"
 	"time"
 )
 
 type p2pServer struct {
 	protowire.UnimplementedP2PServer
 	gRPCServer
+	dial DialFunc
 }
 
+// DialFunc is a function that opens a network connection to the given address
+type DialFunc func(network, address string, timeout time.Duration) (net.Conn, error)
+
 const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
 
 // p2pMaxInboundConnections is the max amount of inbound connections for the P2P server.
@@ -26,10 +33,17 @@ const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
 // is handled in the ConnectionManager instead.
 const p2pMaxInboundConnections = 0
 
-// NewP2PServer creates a new P2PServer
-func NewP2PServer(listeningAddresses []string) (server.P2PServer, error) {
+// overlayDialTimeout is the dial timeout for peers in overlay networks, whose
+// circuits take a while to build
+const overlayDialTimeout = 30 * time.Second
+
+// NewP2PServer creates a new P2PServer that connects to peers with the given dial function. If
+// onionListeningAddress isn't empty, it listens on it as well, and marks the connections accepted
+// on it as inbound onion connections.
+func NewP2PServer(listeningAddresses []string, onionListeningAddress string, dial DialFunc) (server.P2PServer, error) {
 	gRPCServer := newGRPCServer(listeningAddresses, p2pMaxMessageSize, p2pMaxInboundConnections, "P2P")
-	p2pServer := &p2pServer{gRPCServer: *gRPCServer}
+	gRPCServer.onionListeningAddress = onionListeningAddress
+	p2pServer := &p2pServer{gRPCServer: *gRPCServer, dial: dial}
 	protowire.RegisterP2PServer(gRPCServer.server, p2pServer)
 	return p2pServer, nil
 }
@@ -45,11 +59,22 @@ func (p *p2pServer) MessageStream(stream protowire.P2P_MessageStreamServer) erro
 func (p *p2pServer) Connect(address string) (server.Connection, error) {
 	log.Debugf("%s Dialing to %s", p.name, address)
 
-	const dialTimeout = 1 * time.Second
+	dialTimeout := 1 * time.Second
+	if host, _, err := net.SplitHostPort(address); err == nil && appmessage.IsOverlayHost(host) {
+		dialTimeout = overlayDialTimeout
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
 	defer cancel()
 
-	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	contextDialer := func(ctx context.Context, address string) (net.Conn, error) {
+		timeout := dialTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		return p.dial("tcp", address, timeout)
+	}
+	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithContextDialer(contextDialer))
 	if err != nil {
 		return nil, errors.Wrapf(err, "%s error connecting to %s", p.name, address)
 	}
@@ -65,12 +90,12 @@ func (p *p2pServer) Connect(address string) (server.Connection, error) {
 	if !ok {
 		return nil, errors.Errorf("%s error getting stream peer info from context for %s", p.name, address)
 	}
-	tcpAddress, ok := peerInfo.Addr.(*net.TCPAddr)
-	if !ok {
-		return nil, errors.Errorf("non-tcp addresses are not supported")
+	netAddress, err := dialedNetAddress(address, peerInfo.Addr)
+	if err != nil {
+		return nil, err
 	}
 
-	connection := newConnection(&p.gRPCServer, tcpAddress, stream, gRPCClientConnection)
+	connection := newConnection(&p.gRPCServer, netAddress, stream, gRPCClientConnection)
 
 	err = p.onConnectedHandler(connection)
 	if err != nil {
@@ -81,3 +106,35 @@ func (p *p2pServer) Connect(address string) (server.Connection, error) {
 
 	return connection, nil
 }
+
+// dialedNetAddress returns the address of a peer we connected to. This is the address we dialed
+// rather than the address of the other side of the socket, which is the proxy's address when
+// connecting through a proxy, and the only address there is for peers in overlay networks.
+func dialedNetAddress(address string, peerAddress net.Addr) (*appmessage.NetAddress, error) {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed address %s", address)
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed port in %s", address)
+	}
+
+	if appmessage.IsOverlayHost(host) {
+		networkID, overlayAddress, err := appmessage.DecodeOverlayHost(host)
+		if err != nil {
+			return nil, err
+		}
+		return appmessage.NewOverlayNetAddress(mstime.Now(), networkID, overlayAddress, uint16(port))
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return appmessage.NewNetAddressIPPort(ip, uint16(port)), nil
+	}
+
+	// The address is a host name, so the best we can do is the address it resolved to
+	tcpAddress, ok := peerAddress.(*net.TCPAddr)
+	if !ok {
+		return nil, errors.Errorf("non-tcp addresses are not supported")
+	}
+	return appmessage.NewNetAddress(tcpAddress), nil
+}
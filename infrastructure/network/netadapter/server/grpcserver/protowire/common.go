@@ -103,6 +103,13 @@ func (x *NetAddress) toAppMessage() (*appmessage.NetAddress, error) {
 	if x.Port > math.MaxUint16 {
 		return nil, errors.Errorf("port number is larger than %d", math.MaxUint16)
 	}
+	if x.NetworkId != uint32(appmessage.NetworkIDIP) {
+		if x.NetworkId > math.MaxUint8 {
+			return nil, errors.Errorf("network ID is larger than %d", math.MaxUint8)
+		}
+		return appmessage.NewOverlayNetAddress(mstime.UnixMilliseconds(x.Timestamp),
+			appmessage.NetworkID(x.NetworkId), x.OverlayAddress, uint16(x.Port))
+	}
 	return &appmessage.NetAddress{
 		Timestamp: mstime.UnixMilliseconds(x.Timestamp),
 		IP:        x.Ip,
@@ -111,6 +118,14 @@ func (x *NetAddress) toAppMessage() (*appmessage.NetAddress, error) {
 }
 
 func appMessageNetAddressToProto(address *appmessage.NetAddress) *NetAddress {
+	if address.IsOverlay() {
+		return &NetAddress{
+			Timestamp:      address.Timestamp.UnixMilliseconds(),
+			Port:           uint32(address.Port),
+			NetworkId:      uint32(address.NetworkID),
+			OverlayAddress: address.OverlayAddress,
+		}
+	}
 	return &NetAddress{
 		Timestamp: address.Timestamp.UnixMilliseconds(),
 		Ip:        address.IP,
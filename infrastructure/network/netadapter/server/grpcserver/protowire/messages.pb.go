@@ -176,6 +176,29 @@ type KaspadMessage struct {
 	//	*KaspadMessage_GetFeeEstimateResponse
 	//	*KaspadMessage_GetFeeEstimateExperimentalResponse
 	//	*KaspadMessage_GetCurrentBlockColorResponse
+	//	*KaspadMessage_GetTransactionRequest
+	//	*KaspadMessage_GetTransactionResponse
+	//	*KaspadMessage_GetTransactionAcceptanceRequest
+	//	*KaspadMessage_GetTransactionAcceptanceResponse
+	//	*KaspadMessage_GetAddressTransactionsRequest
+	//	*KaspadMessage_GetAddressTransactionsResponse
+	//	*KaspadMessage_GetEmissionInfoRequest
+	//	*KaspadMessage_GetEmissionInfoResponse
+	//	*KaspadMessage_SubmitTransactionPackageRequest
+	//	*KaspadMessage_SubmitTransactionPackageResponse
+	//	*KaspadMessage_GetMempoolInfoRequest
+	//	*KaspadMessage_GetMempoolInfoResponse
+	//	*KaspadMessage_NotifyMempoolChangedRequest
+	//	*KaspadMessage_NotifyMempoolChangedResponse
+	//	*KaspadMessage_MempoolChangedNotification
+	//	*KaspadMessage_GetBlockCandidateStatusRequest
+	//	*KaspadMessage_GetBlockCandidateStatusResponse
+	//	*KaspadMessage_GetDroppedTransactionsRequest
+	//	*KaspadMessage_GetDroppedTransactionsResponse
+	//	*KaspadMessage_PrioritiseTransactionRequest
+	//	*KaspadMessage_PrioritiseTransactionResponse
+	//	*KaspadMessage_TestMempoolAcceptRequest
+	//	*KaspadMessage_TestMempoolAcceptResponse
 	Payload       isKaspadMessage_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -1586,6 +1609,213 @@ func (x *KaspadMessage) GetGetCurrentBlockColorResponse() *GetCurrentBlockColorR
 	return nil
 }
 
+func (x *KaspadMessage) GetGetTransactionRequest() *GetTransactionRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetTransactionRequest); ok {
+			return x.GetTransactionRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionResponse() *GetTransactionResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetTransactionResponse); ok {
+			return x.GetTransactionResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionAcceptanceRequest() *GetTransactionAcceptanceRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetTransactionAcceptanceRequest); ok {
+			return x.GetTransactionAcceptanceRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionAcceptanceResponse() *GetTransactionAcceptanceResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetTransactionAcceptanceResponse); ok {
+			return x.GetTransactionAcceptanceResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetAddressTransactionsRequest() *GetAddressTransactionsRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetAddressTransactionsRequest); ok {
+			return x.GetAddressTransactionsRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetAddressTransactionsResponse() *GetAddressTransactionsResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetAddressTransactionsResponse); ok {
+			return x.GetAddressTransactionsResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetEmissionInfoRequest() *GetEmissionInfoRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetEmissionInfoRequest); ok {
+			return x.GetEmissionInfoRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetEmissionInfoResponse() *GetEmissionInfoResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetEmissionInfoResponse); ok {
+			return x.GetEmissionInfoResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSubmitTransactionPackageRequest() *SubmitTransactionPackageRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_SubmitTransactionPackageRequest); ok {
+			return x.SubmitTransactionPackageRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSubmitTransactionPackageResponse() *SubmitTransactionPackageResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_SubmitTransactionPackageResponse); ok {
+			return x.SubmitTransactionPackageResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetMempoolInfoRequest() *GetMempoolInfoRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetMempoolInfoRequest); ok {
+			return x.GetMempoolInfoRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetMempoolInfoResponse() *GetMempoolInfoResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetMempoolInfoResponse); ok {
+			return x.GetMempoolInfoResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetNotifyMempoolChangedRequest() *NotifyMempoolChangedRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_NotifyMempoolChangedRequest); ok {
+			return x.NotifyMempoolChangedRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetNotifyMempoolChangedResponse() *NotifyMempoolChangedResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_NotifyMempoolChangedResponse); ok {
+			return x.NotifyMempoolChangedResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetMempoolChangedNotification() *MempoolChangedNotificationMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_MempoolChangedNotification); ok {
+			return x.MempoolChangedNotification
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetBlockCandidateStatusRequest() *GetBlockCandidateStatusRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetBlockCandidateStatusRequest); ok {
+			return x.GetBlockCandidateStatusRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetBlockCandidateStatusResponse() *GetBlockCandidateStatusResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetBlockCandidateStatusResponse); ok {
+			return x.GetBlockCandidateStatusResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetDroppedTransactionsRequest() *GetDroppedTransactionsRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetDroppedTransactionsRequest); ok {
+			return x.GetDroppedTransactionsRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetDroppedTransactionsResponse() *GetDroppedTransactionsResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_GetDroppedTransactionsResponse); ok {
+			return x.GetDroppedTransactionsResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetPrioritiseTransactionRequest() *PrioritiseTransactionRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_PrioritiseTransactionRequest); ok {
+			return x.PrioritiseTransactionRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetPrioritiseTransactionResponse() *PrioritiseTransactionResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_PrioritiseTransactionResponse); ok {
+			return x.PrioritiseTransactionResponse
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetTestMempoolAcceptRequest() *TestMempoolAcceptRequestMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_TestMempoolAcceptRequest); ok {
+			return x.TestMempoolAcceptRequest
+		}
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetTestMempoolAcceptResponse() *TestMempoolAcceptResponseMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*KaspadMessage_TestMempoolAcceptResponse); ok {
+			return x.TestMempoolAcceptResponse
+		}
+	}
+	return nil
+}
+
 type isKaspadMessage_Payload interface {
 	isKaspadMessage_Payload()
 }
@@ -2198,6 +2428,98 @@ type KaspadMessage_GetCurrentBlockColorResponse struct {
 	GetCurrentBlockColorResponse *GetCurrentBlockColorResponseMessage `protobuf:"bytes,1111,opt,name=getCurrentBlockColorResponse,proto3,oneof"`
 }
 
+type KaspadMessage_GetTransactionRequest struct {
+	GetTransactionRequest *GetTransactionRequestMessage `protobuf:"bytes,1112,opt,name=getTransactionRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionResponse struct {
+	GetTransactionResponse *GetTransactionResponseMessage `protobuf:"bytes,1113,opt,name=getTransactionResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionAcceptanceRequest struct {
+	GetTransactionAcceptanceRequest *GetTransactionAcceptanceRequestMessage `protobuf:"bytes,1114,opt,name=getTransactionAcceptanceRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionAcceptanceResponse struct {
+	GetTransactionAcceptanceResponse *GetTransactionAcceptanceResponseMessage `protobuf:"bytes,1115,opt,name=getTransactionAcceptanceResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetAddressTransactionsRequest struct {
+	GetAddressTransactionsRequest *GetAddressTransactionsRequestMessage `protobuf:"bytes,1116,opt,name=getAddressTransactionsRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetAddressTransactionsResponse struct {
+	GetAddressTransactionsResponse *GetAddressTransactionsResponseMessage `protobuf:"bytes,1117,opt,name=getAddressTransactionsResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetEmissionInfoRequest struct {
+	GetEmissionInfoRequest *GetEmissionInfoRequestMessage `protobuf:"bytes,1118,opt,name=getEmissionInfoRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetEmissionInfoResponse struct {
+	GetEmissionInfoResponse *GetEmissionInfoResponseMessage `protobuf:"bytes,1119,opt,name=getEmissionInfoResponse,proto3,oneof"`
+}
+
+type KaspadMessage_SubmitTransactionPackageRequest struct {
+	SubmitTransactionPackageRequest *SubmitTransactionPackageRequestMessage `protobuf:"bytes,1120,opt,name=submitTransactionPackageRequest,proto3,oneof"`
+}
+
+type KaspadMessage_SubmitTransactionPackageResponse struct {
+	SubmitTransactionPackageResponse *SubmitTransactionPackageResponseMessage `protobuf:"bytes,1121,opt,name=submitTransactionPackageResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetMempoolInfoRequest struct {
+	GetMempoolInfoRequest *GetMempoolInfoRequestMessage `protobuf:"bytes,1122,opt,name=getMempoolInfoRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetMempoolInfoResponse struct {
+	GetMempoolInfoResponse *GetMempoolInfoResponseMessage `protobuf:"bytes,1123,opt,name=getMempoolInfoResponse,proto3,oneof"`
+}
+
+type KaspadMessage_NotifyMempoolChangedRequest struct {
+	NotifyMempoolChangedRequest *NotifyMempoolChangedRequestMessage `protobuf:"bytes,1124,opt,name=notifyMempoolChangedRequest,proto3,oneof"`
+}
+
+type KaspadMessage_NotifyMempoolChangedResponse struct {
+	NotifyMempoolChangedResponse *NotifyMempoolChangedResponseMessage `protobuf:"bytes,1125,opt,name=notifyMempoolChangedResponse,proto3,oneof"`
+}
+
+type KaspadMessage_MempoolChangedNotification struct {
+	MempoolChangedNotification *MempoolChangedNotificationMessage `protobuf:"bytes,1126,opt,name=mempoolChangedNotification,proto3,oneof"`
+}
+
+type KaspadMessage_GetBlockCandidateStatusRequest struct {
+	GetBlockCandidateStatusRequest *GetBlockCandidateStatusRequestMessage `protobuf:"bytes,1127,opt,name=getBlockCandidateStatusRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetBlockCandidateStatusResponse struct {
+	GetBlockCandidateStatusResponse *GetBlockCandidateStatusResponseMessage `protobuf:"bytes,1128,opt,name=getBlockCandidateStatusResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetDroppedTransactionsRequest struct {
+	GetDroppedTransactionsRequest *GetDroppedTransactionsRequestMessage `protobuf:"bytes,1129,opt,name=getDroppedTransactionsRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetDroppedTransactionsResponse struct {
+	GetDroppedTransactionsResponse *GetDroppedTransactionsResponseMessage `protobuf:"bytes,1130,opt,name=getDroppedTransactionsResponse,proto3,oneof"`
+}
+
+type KaspadMessage_PrioritiseTransactionRequest struct {
+	PrioritiseTransactionRequest *PrioritiseTransactionRequestMessage `protobuf:"bytes,1131,opt,name=prioritiseTransactionRequest,proto3,oneof"`
+}
+
+type KaspadMessage_PrioritiseTransactionResponse struct {
+	PrioritiseTransactionResponse *PrioritiseTransactionResponseMessage `protobuf:"bytes,1132,opt,name=prioritiseTransactionResponse,proto3,oneof"`
+}
+
+type KaspadMessage_TestMempoolAcceptRequest struct {
+	TestMempoolAcceptRequest *TestMempoolAcceptRequestMessage `protobuf:"bytes,1133,opt,name=testMempoolAcceptRequest,proto3,oneof"`
+}
+
+type KaspadMessage_TestMempoolAcceptResponse struct {
+	TestMempoolAcceptResponse *TestMempoolAcceptResponseMessage `protobuf:"bytes,1134,opt,name=testMempoolAcceptResponse,proto3,oneof"`
+}
+
 func (*KaspadMessage_Addresses) isKaspadMessage_Payload() {}
 
 func (*KaspadMessage_Block) isKaspadMessage_Payload() {}
@@ -2502,13 +2824,59 @@ func (*KaspadMessage_GetFeeEstimateExperimentalResponse) isKaspadMessage_Payload
 
 func (*KaspadMessage_GetCurrentBlockColorResponse) isKaspadMessage_Payload() {}
 
+func (*KaspadMessage_GetTransactionRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionAcceptanceRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionAcceptanceResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetAddressTransactionsRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetAddressTransactionsResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetEmissionInfoRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetEmissionInfoResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SubmitTransactionPackageRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SubmitTransactionPackageResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetMempoolInfoRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetMempoolInfoResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_NotifyMempoolChangedRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_NotifyMempoolChangedResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_MempoolChangedNotification) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetBlockCandidateStatusRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetBlockCandidateStatusResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetDroppedTransactionsRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetDroppedTransactionsResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_PrioritiseTransactionRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_PrioritiseTransactionResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_TestMempoolAcceptRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_TestMempoolAcceptResponse) isKaspadMessage_Payload() {}
+
 var File_messages_proto protoreflect.FileDescriptor
 
 var file_messages_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x1a, 0x09, 0x70, 0x32, 0x70,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x09, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x80, 0x80, 0x01, 0x0a, 0x0d, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73,
+	0x6f, 0x22, 0xf4, 0x94, 0x01, 0x0a, 0x0d, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73,
 	0x73, 0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
 	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
 	0x69, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x4d, 0x65, 0x73,
@@ -3531,21 +3899,188 @@ var file_messages_proto_rawDesc = []byte{
 	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
 	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1c, 0x67, 0x65,
 	0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6c,
-	0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61,
-	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0x50, 0x0a, 0x03, 0x50, 0x32, 0x50, 0x12, 0x49, 0x0a, 0x0d,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e,
+	0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x15, 0x67, 0x65,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0xd8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x63, 0x0a, 0x16,
+	0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xd9, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x7e, 0x0a, 0x1f, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0xda, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x1f, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x81, 0x01, 0x0a, 0x20, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xdb, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x20, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x78, 0x0a, 0x1d, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xdc, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x1d, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x7b, 0x0a, 0x1e, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0xdd, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1e, 0x67, 0x65,
+	0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x16,
+	0x67, 0x65, 0x74, 0x45, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xde, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67, 0x65, 0x74, 0x45, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x66, 0x0a, 0x17, 0x67, 0x65, 0x74, 0x45, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xdf, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
+	0x47, 0x65, 0x74, 0x45, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x17, 0x67, 0x65, 0x74, 0x45, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7e, 0x0a, 0x1f, 0x73, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xe0, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
+	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1f, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x81, 0x01, 0x0a, 0x20, 0x73, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xe1,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72,
+	0x65, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x20, 0x73, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a,
+	0x15, 0x67, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xe2, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x63, 0x0a, 0x16, 0x67, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xe3, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74,
+	0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67, 0x65,
+	0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x1b, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65,
+	0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0xe4, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1b, 0x6e, 0x6f, 0x74,
+	0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x75, 0x0a, 0x1c, 0x6e, 0x6f, 0x74, 0x69,
+	0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xe5, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48,
+	0x00, 0x52, 0x1c, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x6f, 0x0a, 0x1a, 0x6d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x64, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0xe6, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65,
+	0x2e, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x48, 0x00, 0x52, 0x1a, 0x6d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x7b, 0x0a, 0x1e, 0x67, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x61, 0x6e, 0x64,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x18, 0xe7, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x61,
+	0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1e, 0x67,
+	0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x7e, 0x0a,
+	0x1f, 0x67, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x18, 0xe8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
+	0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x61, 0x6e, 0x64,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1f, 0x67, 0x65,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x78, 0x0a,
+	0x1d, 0x67, 0x65, 0x74, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xe9,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1d, 0x67, 0x65, 0x74, 0x44, 0x72, 0x6f,
+	0x70, 0x70, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x7b, 0x0a, 0x1e, 0x67, 0x65, 0x74, 0x44, 0x72,
+	0x6f, 0x70, 0x70, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xea, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x30, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74,
+	0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x48, 0x00, 0x52, 0x1e, 0x67, 0x65, 0x74, 0x44, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x75, 0x0a, 0x1c, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69,
+	0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0xeb, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69,
+	0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1c, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x78, 0x0a, 0x1d, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xec, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
+	0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1d, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69,
+	0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x18, 0x74, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x18, 0xed, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x77, 0x69, 0x72, 0x65, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x18, 0x74, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x6d, 0x70,
+	0x6f, 0x6f, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x6c, 0x0a, 0x19, 0x74, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x41,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xee, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65,
+	0x2e, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x41, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x19, 0x74, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x09,
+	0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0x50, 0x0a, 0x03, 0x50, 0x32, 0x50,
+	0x12, 0x49, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61,
+	0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x50, 0x0a, 0x03, 0x52,
+	0x50, 0x43, 0x12, 0x49, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
+	0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
-	0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x50, 0x0a, 0x03, 0x52, 0x50, 0x43, 0x12, 0x49,
-	0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
-	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70,
-	0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x6e, 0x65, 0x74,
-	0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72,
-	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a,
+	0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x73, 0x70,
+	0x61, 0x6e, 0x65, 0x74, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x77, 0x69, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -3714,6 +4249,29 @@ var file_messages_proto_goTypes = []any{
 	(*GetFeeEstimateResponseMessage)(nil),                              // 149: protowire.GetFeeEstimateResponseMessage
 	(*GetFeeEstimateExperimentalResponseMessage)(nil),                  // 150: protowire.GetFeeEstimateExperimentalResponseMessage
 	(*GetCurrentBlockColorResponseMessage)(nil),                        // 151: protowire.GetCurrentBlockColorResponseMessage
+	(*GetTransactionRequestMessage)(nil),                               // 152: protowire.GetTransactionRequestMessage
+	(*GetTransactionResponseMessage)(nil),                              // 153: protowire.GetTransactionResponseMessage
+	(*GetTransactionAcceptanceRequestMessage)(nil),                     // 154: protowire.GetTransactionAcceptanceRequestMessage
+	(*GetTransactionAcceptanceResponseMessage)(nil),                    // 155: protowire.GetTransactionAcceptanceResponseMessage
+	(*GetAddressTransactionsRequestMessage)(nil),                       // 156: protowire.GetAddressTransactionsRequestMessage
+	(*GetAddressTransactionsResponseMessage)(nil),                      // 157: protowire.GetAddressTransactionsResponseMessage
+	(*GetEmissionInfoRequestMessage)(nil),                              // 158: protowire.GetEmissionInfoRequestMessage
+	(*GetEmissionInfoResponseMessage)(nil),                             // 159: protowire.GetEmissionInfoResponseMessage
+	(*SubmitTransactionPackageRequestMessage)(nil),                     // 160: protowire.SubmitTransactionPackageRequestMessage
+	(*SubmitTransactionPackageResponseMessage)(nil),                    // 161: protowire.SubmitTransactionPackageResponseMessage
+	(*GetMempoolInfoRequestMessage)(nil),                               // 162: protowire.GetMempoolInfoRequestMessage
+	(*GetMempoolInfoResponseMessage)(nil),                              // 163: protowire.GetMempoolInfoResponseMessage
+	(*NotifyMempoolChangedRequestMessage)(nil),                         // 164: protowire.NotifyMempoolChangedRequestMessage
+	(*NotifyMempoolChangedResponseMessage)(nil),                        // 165: protowire.NotifyMempoolChangedResponseMessage
+	(*MempoolChangedNotificationMessage)(nil),                          // 166: protowire.MempoolChangedNotificationMessage
+	(*GetBlockCandidateStatusRequestMessage)(nil),                      // 167: protowire.GetBlockCandidateStatusRequestMessage
+	(*GetBlockCandidateStatusResponseMessage)(nil),                     // 168: protowire.GetBlockCandidateStatusResponseMessage
+	(*GetDroppedTransactionsRequestMessage)(nil),                       // 169: protowire.GetDroppedTransactionsRequestMessage
+	(*GetDroppedTransactionsResponseMessage)(nil),                      // 170: protowire.GetDroppedTransactionsResponseMessage
+	(*PrioritiseTransactionRequestMessage)(nil),                        // 171: protowire.PrioritiseTransactionRequestMessage
+	(*PrioritiseTransactionResponseMessage)(nil),                       // 172: protowire.PrioritiseTransactionResponseMessage
+	(*TestMempoolAcceptRequestMessage)(nil),                            // 173: protowire.TestMempoolAcceptRequestMessage
+	(*TestMempoolAcceptResponseMessage)(nil),                           // 174: protowire.TestMempoolAcceptResponseMessage
 }
 var file_messages_proto_depIdxs = []int32{
 	1,   // 0: protowire.KaspadMessage.addresses:type_name -> protowire.AddressesMessage
@@ -3868,15 +4426,38 @@ var file_messages_proto_depIdxs = []int32{
 	149, // 149: protowire.KaspadMessage.getFeeEstimateResponse:type_name -> protowire.GetFeeEstimateResponseMessage
 	150, // 150: protowire.KaspadMessage.getFeeEstimateExperimentalResponse:type_name -> protowire.GetFeeEstimateExperimentalResponseMessage
 	151, // 151: protowire.KaspadMessage.getCurrentBlockColorResponse:type_name -> protowire.GetCurrentBlockColorResponseMessage
-	0,   // 152: protowire.P2P.MessageStream:input_type -> protowire.KaspadMessage
-	0,   // 153: protowire.RPC.MessageStream:input_type -> protowire.KaspadMessage
-	0,   // 154: protowire.P2P.MessageStream:output_type -> protowire.KaspadMessage
-	0,   // 155: protowire.RPC.MessageStream:output_type -> protowire.KaspadMessage
-	154, // [154:156] is the sub-list for method output_type
-	152, // [152:154] is the sub-list for method input_type
-	152, // [152:152] is the sub-list for extension type_name
-	152, // [152:152] is the sub-list for extension extendee
-	0,   // [0:152] is the sub-list for field type_name
+	152, // 152: protowire.KaspadMessage.getTransactionRequest:type_name -> protowire.GetTransactionRequestMessage
+	153, // 153: protowire.KaspadMessage.getTransactionResponse:type_name -> protowire.GetTransactionResponseMessage
+	154, // 154: protowire.KaspadMessage.getTransactionAcceptanceRequest:type_name -> protowire.GetTransactionAcceptanceRequestMessage
+	155, // 155: protowire.KaspadMessage.getTransactionAcceptanceResponse:type_name -> protowire.GetTransactionAcceptanceResponseMessage
+	156, // 156: protowire.KaspadMessage.getAddressTransactionsRequest:type_name -> protowire.GetAddressTransactionsRequestMessage
+	157, // 157: protowire.KaspadMessage.getAddressTransactionsResponse:type_name -> protowire.GetAddressTransactionsResponseMessage
+	158, // 158: protowire.KaspadMessage.getEmissionInfoRequest:type_name -> protowire.GetEmissionInfoRequestMessage
+	159, // 159: protowire.KaspadMessage.getEmissionInfoResponse:type_name -> protowire.GetEmissionInfoResponseMessage
+	160, // 160: protowire.KaspadMessage.submitTransactionPackageRequest:type_name -> protowire.SubmitTransactionPackageRequestMessage
+	161, // 161: protowire.KaspadMessage.submitTransactionPackageResponse:type_name -> protowire.SubmitTransactionPackageResponseMessage
+	162, // 162: protowire.KaspadMessage.getMempoolInfoRequest:type_name -> protowire.GetMempoolInfoRequestMessage
+	163, // 163: protowire.KaspadMessage.getMempoolInfoResponse:type_name -> protowire.GetMempoolInfoResponseMessage
+	164, // 164: protowire.KaspadMessage.notifyMempoolChangedRequest:type_name -> protowire.NotifyMempoolChangedRequestMessage
+	165, // 165: protowire.KaspadMessage.notifyMempoolChangedResponse:type_name -> protowire.NotifyMempoolChangedResponseMessage
+	166, // 166: protowire.KaspadMessage.mempoolChangedNotification:type_name -> protowire.MempoolChangedNotificationMessage
+	167, // 167: protowire.KaspadMessage.getBlockCandidateStatusRequest:type_name -> protowire.GetBlockCandidateStatusRequestMessage
+	168, // 168: protowire.KaspadMessage.getBlockCandidateStatusResponse:type_name -> protowire.GetBlockCandidateStatusResponseMessage
+	169, // 169: protowire.KaspadMessage.getDroppedTransactionsRequest:type_name -> protowire.GetDroppedTransactionsRequestMessage
+	170, // 170: protowire.KaspadMessage.getDroppedTransactionsResponse:type_name -> protowire.GetDroppedTransactionsResponseMessage
+	171, // 171: protowire.KaspadMessage.prioritiseTransactionRequest:type_name -> protowire.PrioritiseTransactionRequestMessage
+	172, // 172: protowire.KaspadMessage.prioritiseTransactionResponse:type_name -> protowire.PrioritiseTransactionResponseMessage
+	173, // 173: protowire.KaspadMessage.testMempoolAcceptRequest:type_name -> protowire.TestMempoolAcceptRequestMessage
+	174, // 174: protowire.KaspadMessage.testMempoolAcceptResponse:type_name -> protowire.TestMempoolAcceptResponseMessage
+	0,   // 175: protowire.P2P.MessageStream:input_type -> protowire.KaspadMessage
+	0,   // 176: protowire.RPC.MessageStream:input_type -> protowire.KaspadMessage
+	0,   // 177: protowire.P2P.MessageStream:output_type -> protowire.KaspadMessage
+	0,   // 178: protowire.RPC.MessageStream:output_type -> protowire.KaspadMessage
+	177, // [177:179] is the sub-list for method output_type
+	175, // [175:177] is the sub-list for method input_type
+	175, // [175:175] is the sub-list for extension type_name
+	175, // [175:175] is the sub-list for extension extendee
+	0,   // [0:175] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -4039,6 +4620,29 @@ func file_messages_proto_init() {
 		(*KaspadMessage_GetFeeEstimateResponse)(nil),
 		(*KaspadMessage_GetFeeEstimateExperimentalResponse)(nil),
 		(*KaspadMessage_GetCurrentBlockColorResponse)(nil),
+		(*KaspadMessage_GetTransactionRequest)(nil),
+		(*KaspadMessage_GetTransactionResponse)(nil),
+		(*KaspadMessage_GetTransactionAcceptanceRequest)(nil),
+		(*KaspadMessage_GetTransactionAcceptanceResponse)(nil),
+		(*KaspadMessage_GetAddressTransactionsRequest)(nil),
+		(*KaspadMessage_GetAddressTransactionsResponse)(nil),
+		(*KaspadMessage_GetEmissionInfoRequest)(nil),
+		(*KaspadMessage_GetEmissionInfoResponse)(nil),
+		(*KaspadMessage_SubmitTransactionPackageRequest)(nil),
+		(*KaspadMessage_SubmitTransactionPackageResponse)(nil),
+		(*KaspadMessage_GetMempoolInfoRequest)(nil),
+		(*KaspadMessage_GetMempoolInfoResponse)(nil),
+		(*KaspadMessage_NotifyMempoolChangedRequest)(nil),
+		(*KaspadMessage_NotifyMempoolChangedResponse)(nil),
+		(*KaspadMessage_MempoolChangedNotification)(nil),
+		(*KaspadMessage_GetBlockCandidateStatusRequest)(nil),
+		(*KaspadMessage_GetBlockCandidateStatusResponse)(nil),
+		(*KaspadMessage_GetDroppedTransactionsRequest)(nil),
+		(*KaspadMessage_GetDroppedTransactionsResponse)(nil),
+		(*KaspadMessage_PrioritiseTransactionRequest)(nil),
+		(*KaspadMessage_PrioritiseTransactionResponse)(nil),
+		(*KaspadMessage_TestMempoolAcceptRequest)(nil),
+		(*KaspadMessage_TestMempoolAcceptResponse)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1,6 +1,8 @@
 package protowire
 
 import (
+	"math"
+
 	"github.com/stokesnetwork/stokes/app/appmessage"
 	"github.com/pkg/errors"
 )
@@ -27,13 +29,19 @@ func (x *AddressesMessage) toAppMessage() ([]*appmessage.NetAddress, error) {
 		return nil, errors.Errorf("too many addresses for message "+
 			"[count %d, max %d]", len(x.AddressList), appmessage.MaxAddressesPerMsg)
 	}
-	addressList := make([]*appmessage.NetAddress, len(x.AddressList))
-	for i, address := range x.AddressList {
-		var err error
-		addressList[i], err = address.toAppMessage()
+	addressList := make([]*appmessage.NetAddress, 0, len(x.AddressList))
+	for _, address := range x.AddressList {
+		// Addresses in networks we don't know of are skipped, so that new networks
+		// can be gossiped without disconnecting older peers
+		if address != nil && address.NetworkId != uint32(appmessage.NetworkIDIP) &&
+			(address.NetworkId > math.MaxUint8 || !appmessage.IsKnownOverlayNetwork(appmessage.NetworkID(address.NetworkId))) {
+			continue
+		}
+		appMessageAddress, err := address.toAppMessage()
 		if err != nil {
 			return nil, err
 		}
+		addressList = append(addressList, appMessageAddress)
 	}
 	return addressList, nil
 }
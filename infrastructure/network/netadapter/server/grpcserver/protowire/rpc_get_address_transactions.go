@@ -0,0 +1,103 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_GetAddressTransactionsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetAddressTransactionsRequest is nil")
+	}
+	return x.GetAddressTransactionsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetAddressTransactionsRequest) fromAppMessage(message *appmessage.GetAddressTransactionsRequestMessage) error {
+	x.GetAddressTransactionsRequest = &GetAddressTransactionsRequestMessage{
+		Address:   message.Address,
+		PageToken: message.PageToken,
+		Limit:     message.Limit,
+	}
+	return nil
+}
+
+func (x *GetAddressTransactionsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetAddressTransactionsRequestMessage is nil")
+	}
+	return &appmessage.GetAddressTransactionsRequestMessage{
+		Address:   x.Address,
+		PageToken: x.PageToken,
+		Limit:     x.Limit,
+	}, nil
+}
+
+func (x *KaspadMessage_GetAddressTransactionsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetAddressTransactionsResponse is nil")
+	}
+	return x.GetAddressTransactionsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetAddressTransactionsResponse) fromAppMessage(message *appmessage.GetAddressTransactionsResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	transactions := make([]*RpcAddressTransaction, len(message.Transactions))
+	for i, transaction := range message.Transactions {
+		transactions[i] = &RpcAddressTransaction{
+			TransactionId:     transaction.TransactionID,
+			AcceptingDaaScore: transaction.AcceptingDAAScore,
+			IsCredit:          transaction.IsCredit,
+			IsDebit:           transaction.IsDebit,
+		}
+	}
+	x.GetAddressTransactionsResponse = &GetAddressTransactionsResponseMessage{
+		Transactions:  transactions,
+		NextPageToken: message.NextPageToken,
+		Error:         rpcErr,
+	}
+	return nil
+}
+
+func (x *GetAddressTransactionsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetAddressTransactionsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Transactions) != 0 {
+		return nil, errors.New("GetAddressTransactionsResponseMessage contains both an error and a response")
+	}
+
+	transactions := make([]*appmessage.AddressTransaction, len(x.Transactions))
+	for i, transaction := range x.Transactions {
+		transactions[i], err = transaction.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetAddressTransactionsResponseMessage{
+		Transactions:  transactions,
+		NextPageToken: x.NextPageToken,
+		Error:         rpcErr,
+	}, nil
+}
+
+func (x *RpcAddressTransaction) toAppMessage() (*appmessage.AddressTransaction, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcAddressTransaction is nil")
+	}
+	return &appmessage.AddressTransaction{
+		TransactionID:     x.TransactionId,
+		AcceptingDAAScore: x.AcceptingDaaScore,
+		IsCredit:          x.IsCredit,
+		IsDebit:           x.IsDebit,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_GetBlockCandidateStatusRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetBlockCandidateStatusRequest is nil")
+	}
+	return x.GetBlockCandidateStatusRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetBlockCandidateStatusRequest) fromAppMessage(message *appmessage.GetBlockCandidateStatusRequestMessage) error {
+	x.GetBlockCandidateStatusRequest = &GetBlockCandidateStatusRequestMessage{
+		TransactionId: message.TransactionID,
+	}
+	return nil
+}
+
+func (x *GetBlockCandidateStatusRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBlockCandidateStatusRequestMessage is nil")
+	}
+	return &appmessage.GetBlockCandidateStatusRequestMessage{
+		TransactionID: x.TransactionId,
+	}, nil
+}
+
+func (x *KaspadMessage_GetBlockCandidateStatusResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetBlockCandidateStatusResponse is nil")
+	}
+	return x.GetBlockCandidateStatusResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetBlockCandidateStatusResponse) fromAppMessage(message *appmessage.GetBlockCandidateStatusResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.GetBlockCandidateStatusResponse = &GetBlockCandidateStatusResponseMessage{
+		IsInTransactionPool: message.IsInTransactionPool,
+		IsOrphan:            message.IsOrphan,
+		IsBlockCandidate:    message.IsBlockCandidate,
+		Kind:                message.Kind,
+		Reason:              message.Reason,
+		Error:               err,
+	}
+	return nil
+}
+
+func (x *GetBlockCandidateStatusResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBlockCandidateStatusResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.GetBlockCandidateStatusResponseMessage{
+		IsInTransactionPool: x.IsInTransactionPool,
+		IsOrphan:            x.IsOrphan,
+		IsBlockCandidate:    x.IsBlockCandidate,
+		Kind:                x.Kind,
+		Reason:              x.Reason,
+		Error:               rpcErr,
+	}, nil
+}
@@ -37,6 +37,7 @@ func (x *KaspadMessage_GetConnectedPeerInfoResponse) fromAppMessage(message *app
 			AdvertisedProtocolVersion: info.AdvertisedProtocolVersion,
 			TimeConnected:             info.TimeConnected,
 			IsIbdPeer:                 info.IsIBDPeer,
+			BanScore:                  info.BanScore,
 		}
 	}
 	x.GetConnectedPeerInfoResponse = &GetConnectedPeerInfoResponseMessage{
@@ -86,7 +87,8 @@ func (x *GetConnectedPeerInfoMessage) toAppMessage() (*appmessage.GetConnectedPe
 		TimeOffset:                x.TimeOffset,
 		UserAgent:                 x.UserAgent,
 		AdvertisedProtocolVersion: x.AdvertisedProtocolVersion,
-		TimeConnected:             x.TimeOffset,
+		TimeConnected:             x.TimeConnected,
 		IsIBDPeer:                 x.IsIbdPeer,
+		BanScore:                  x.BanScore,
 	}, nil
 }
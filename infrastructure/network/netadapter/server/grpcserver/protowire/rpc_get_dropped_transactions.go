@@ -0,0 +1,102 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_GetDroppedTransactionsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetDroppedTransactionsRequest is nil")
+	}
+	return x.GetDroppedTransactionsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetDroppedTransactionsRequest) fromAppMessage(message *appmessage.GetDroppedTransactionsRequestMessage) error {
+	x.GetDroppedTransactionsRequest = &GetDroppedTransactionsRequestMessage{
+		TransactionIds: message.TransactionIDs,
+	}
+	return nil
+}
+
+func (x *GetDroppedTransactionsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDroppedTransactionsRequestMessage is nil")
+	}
+	return &appmessage.GetDroppedTransactionsRequestMessage{
+		TransactionIDs: x.TransactionIds,
+	}, nil
+}
+
+func (x *KaspadMessage_GetDroppedTransactionsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetDroppedTransactionsResponse is nil")
+	}
+	return x.GetDroppedTransactionsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetDroppedTransactionsResponse) fromAppMessage(message *appmessage.GetDroppedTransactionsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	droppedTransactions := make([]*RpcDroppedTransaction, len(message.DroppedTransactions))
+	for i, droppedTransaction := range message.DroppedTransactions {
+		droppedTransactions[i] = &RpcDroppedTransaction{}
+		droppedTransactions[i].fromAppMessage(droppedTransaction)
+	}
+	x.GetDroppedTransactionsResponse = &GetDroppedTransactionsResponseMessage{
+		DroppedTransactions: droppedTransactions,
+		Error:               err,
+	}
+	return nil
+}
+
+func (x *GetDroppedTransactionsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDroppedTransactionsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	droppedTransactions := make([]*appmessage.RPCDroppedTransaction, len(x.DroppedTransactions))
+	for i, droppedTransaction := range x.DroppedTransactions {
+		droppedTransactions[i], err = droppedTransaction.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.GetDroppedTransactionsResponseMessage{
+		DroppedTransactions: droppedTransactions,
+		Error:               rpcErr,
+	}, nil
+}
+
+func (x *RpcDroppedTransaction) toAppMessage() (*appmessage.RPCDroppedTransaction, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcDroppedTransaction is nil")
+	}
+	return &appmessage.RPCDroppedTransaction{
+		TransactionID:      x.TransactionId,
+		Reason:             x.Reason,
+		IsOrphan:           x.IsOrphan,
+		Fee:                x.Fee,
+		Mass:               x.Mass,
+		DroppedAtTimestamp: x.DroppedAtTimestamp,
+		DroppedAtDAAScore:  x.DroppedAtDaaScore,
+	}, nil
+}
+
+func (x *RpcDroppedTransaction) fromAppMessage(message *appmessage.RPCDroppedTransaction) {
+	*x = RpcDroppedTransaction{
+		TransactionId:      message.TransactionID,
+		Reason:             message.Reason,
+		IsOrphan:           message.IsOrphan,
+		Fee:                message.Fee,
+		Mass:               message.Mass,
+		DroppedAtTimestamp: message.DroppedAtTimestamp,
+		DroppedAtDaaScore:  message.DroppedAtDAAScore,
+	}
+}
@@ -0,0 +1,64 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_GetEmissionInfoRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetEmissionInfoRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_GetEmissionInfoRequest) fromAppMessage(_ *appmessage.GetEmissionInfoRequestMessage) error {
+	x.GetEmissionInfoRequest = &GetEmissionInfoRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_GetEmissionInfoResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetEmissionInfoResponse is nil")
+	}
+	return x.GetEmissionInfoResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetEmissionInfoResponse) fromAppMessage(message *appmessage.GetEmissionInfoResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.GetEmissionInfoResponse = &GetEmissionInfoResponseMessage{
+		VirtualDaaScore:               message.VirtualDAAScore,
+		CurrentEra:                    message.CurrentEra,
+		CurrentSubsidy:                message.CurrentSubsidy,
+		HalvingIntervalDaaScore:       message.HalvingIntervalDAAScore,
+		NextHalvingDaaScore:           message.NextHalvingDAAScore,
+		NextSubsidy:                   message.NextSubsidy,
+		EstimatedSecondsToNextHalving: message.EstimatedSecondsToNextHalving,
+
+		Error: err,
+	}
+	return nil
+}
+
+func (x *GetEmissionInfoResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetEmissionInfoResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	return &appmessage.GetEmissionInfoResponseMessage{
+		VirtualDAAScore:               x.VirtualDaaScore,
+		CurrentEra:                    x.CurrentEra,
+		CurrentSubsidy:                x.CurrentSubsidy,
+		HalvingIntervalDAAScore:       x.HalvingIntervalDaaScore,
+		NextHalvingDAAScore:           x.NextHalvingDaaScore,
+		NextSubsidy:                   x.NextSubsidy,
+		EstimatedSecondsToNextHalving: x.EstimatedSecondsToNextHalving,
+
+		Error: rpcErr,
+	}, nil
+}
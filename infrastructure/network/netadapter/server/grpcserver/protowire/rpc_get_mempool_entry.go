@@ -52,9 +52,15 @@ func (x *KaspadMessage_GetMempoolEntryResponse) fromAppMessage(message *appmessa
 			return err
 		}
 	}
+	var droppedTransaction *RpcDroppedTransaction
+	if message.DroppedTransaction != nil {
+		droppedTransaction = new(RpcDroppedTransaction)
+		droppedTransaction.fromAppMessage(message.DroppedTransaction)
+	}
 	x.GetMempoolEntryResponse = &GetMempoolEntryResponseMessage{
-		Entry: entry,
-		Error: rpcErr,
+		Entry:              entry,
+		DroppedTransaction: droppedTransaction,
+		Error:              rpcErr,
 	}
 	return nil
 }
@@ -78,9 +84,16 @@ func (x *GetMempoolEntryResponseMessage) toAppMessage() (appmessage.Message, err
 		return nil, errors.New("GetMempoolEntryResponseMessage contains both an error and a response")
 	}
 
+	droppedTransaction, err := x.DroppedTransaction.toAppMessage()
+	// DroppedTransaction is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
 	return &appmessage.GetMempoolEntryResponseMessage{
-		Entry: entry,
-		Error: rpcErr,
+		Entry:              entry,
+		DroppedTransaction: droppedTransaction,
+		Error:              rpcErr,
 	}, nil
 }
 
@@ -96,6 +109,7 @@ func (x *MempoolEntry) toAppMessage() (*appmessage.MempoolEntry, error) {
 		Fee:         x.Fee,
 		Transaction: transaction,
 		IsOrphan:    x.IsOrphan,
+		FeeDelta:    x.FeeDelta,
 	}, nil
 }
 
@@ -109,6 +123,7 @@ func (x *MempoolEntry) fromAppMessage(message *appmessage.MempoolEntry) error {
 		Fee:         message.Fee,
 		Transaction: transaction,
 		IsOrphan:    message.IsOrphan,
+		FeeDelta:    message.FeeDelta,
 	}
 	return nil
 }
@@ -0,0 +1,99 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_GetMempoolInfoRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolInfoRequest is nil")
+	}
+	return x.GetMempoolInfoRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolInfoRequest) fromAppMessage(message *appmessage.GetMempoolInfoRequestMessage) error {
+	x.GetMempoolInfoRequest = &GetMempoolInfoRequestMessage{
+		FeeRateBucketBoundaries: message.FeeRateBucketBoundaries,
+	}
+	return nil
+}
+
+func (x *GetMempoolInfoRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolInfoRequestMessage is nil")
+	}
+	return &appmessage.GetMempoolInfoRequestMessage{
+		FeeRateBucketBoundaries: x.FeeRateBucketBoundaries,
+	}, nil
+}
+
+func (x *KaspadMessage_GetMempoolInfoResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolInfoResponse is nil")
+	}
+	return x.GetMempoolInfoResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolInfoResponse) fromAppMessage(message *appmessage.GetMempoolInfoResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	feeRateHistogram := make([]*RpcMempoolFeeRateBucket, len(message.FeeRateHistogram))
+	for i, bucket := range message.FeeRateHistogram {
+		feeRateHistogram[i] = &RpcMempoolFeeRateBucket{
+			MinimumFeerate:   bucket.MinimumFeerate,
+			TransactionCount: bucket.TransactionCount,
+			TotalMass:        bucket.TotalMass,
+		}
+	}
+	x.GetMempoolInfoResponse = &GetMempoolInfoResponseMessage{
+		TransactionCount:                     message.TransactionCount,
+		OrphanCount:                          message.OrphanCount,
+		TotalMass:                            message.TotalMass,
+		FeeRateHistogram:                     feeRateHistogram,
+		OldestTransactionDaaScore:            message.OldestTransactionDAAScore,
+		EstimatedOldestTransactionAgeSeconds: message.EstimatedOldestTransactionAgeSeconds,
+		MinimumFeerate:                       message.MinimumFeerate,
+		SpamTransactionCount:                 message.SpamTransactionCount,
+		FilteredTransactionCount:             message.FilteredTransactionCount,
+		Error:                                err,
+	}
+	return nil
+}
+
+func (x *GetMempoolInfoResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolInfoResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	feeRateHistogram := make([]*appmessage.RPCMempoolFeeRateBucket, len(x.FeeRateHistogram))
+	for i, bucket := range x.FeeRateHistogram {
+		if bucket == nil {
+			return nil, errors.Wrapf(errorNil, "RpcMempoolFeeRateBucket is nil")
+		}
+		feeRateHistogram[i] = &appmessage.RPCMempoolFeeRateBucket{
+			MinimumFeerate:   bucket.MinimumFeerate,
+			TransactionCount: bucket.TransactionCount,
+			TotalMass:        bucket.TotalMass,
+		}
+	}
+	return &appmessage.GetMempoolInfoResponseMessage{
+		TransactionCount:                     x.TransactionCount,
+		OrphanCount:                          x.OrphanCount,
+		TotalMass:                            x.TotalMass,
+		FeeRateHistogram:                     feeRateHistogram,
+		OldestTransactionDAAScore:            x.OldestTransactionDaaScore,
+		EstimatedOldestTransactionAgeSeconds: x.EstimatedOldestTransactionAgeSeconds,
+		MinimumFeerate:                       x.MinimumFeerate,
+		SpamTransactionCount:                 x.SpamTransactionCount,
+		FilteredTransactionCount:             x.FilteredTransactionCount,
+		Error:                                rpcErr,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_GetTransactionRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionRequest is nil")
+	}
+	return x.GetTransactionRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionRequest) fromAppMessage(message *appmessage.GetTransactionRequestMessage) error {
+	x.GetTransactionRequest = &GetTransactionRequestMessage{
+		TransactionId: message.TransactionID,
+	}
+	return nil
+}
+
+func (x *GetTransactionRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionRequestMessage is nil")
+	}
+	return &appmessage.GetTransactionRequestMessage{
+		TransactionID: x.TransactionId,
+	}, nil
+}
+
+func (x *KaspadMessage_GetTransactionResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionResponse is nil")
+	}
+	return x.GetTransactionResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionResponse) fromAppMessage(message *appmessage.GetTransactionResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	var transaction *RpcTransaction
+	if message.Transaction != nil {
+		transaction = new(RpcTransaction)
+		transaction.fromAppMessage(message.Transaction)
+	}
+	var acceptance *RpcTransactionAcceptance
+	if message.Acceptance != nil {
+		acceptance = new(RpcTransactionAcceptance)
+		acceptance.fromAppMessage(message.Acceptance)
+	}
+	x.GetTransactionResponse = &GetTransactionResponseMessage{
+		Transaction: transaction,
+		Acceptance:  acceptance,
+		Error:       rpcErr,
+	}
+	return nil
+}
+
+func (x *GetTransactionResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && (x.Transaction != nil || x.Acceptance != nil) {
+		return nil, errors.New("GetTransactionResponseMessage contains both an error and a response")
+	}
+
+	var transaction *appmessage.RPCTransaction
+	if x.Transaction != nil {
+		transaction, err = x.Transaction.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var acceptance *appmessage.TransactionAcceptance
+	if x.Acceptance != nil {
+		acceptance, err = x.Acceptance.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetTransactionResponseMessage{
+		Transaction: transaction,
+		Acceptance:  acceptance,
+		Error:       rpcErr,
+	}, nil
+}
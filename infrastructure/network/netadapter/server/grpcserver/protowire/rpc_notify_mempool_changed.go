@@ -0,0 +1,122 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_NotifyMempoolChangedRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyMempoolChangedRequest is nil")
+	}
+	return x.NotifyMempoolChangedRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_NotifyMempoolChangedRequest) fromAppMessage(message *appmessage.NotifyMempoolChangedRequestMessage) error {
+	x.NotifyMempoolChangedRequest = &NotifyMempoolChangedRequestMessage{
+		Addresses: message.Addresses,
+	}
+	return nil
+}
+
+func (x *NotifyMempoolChangedRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyMempoolChangedRequestMessage is nil")
+	}
+	return &appmessage.NotifyMempoolChangedRequestMessage{
+		Addresses: x.Addresses,
+	}, nil
+}
+
+func (x *KaspadMessage_NotifyMempoolChangedResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyMempoolChangedResponse is nil")
+	}
+	return x.NotifyMempoolChangedResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_NotifyMempoolChangedResponse) fromAppMessage(message *appmessage.NotifyMempoolChangedResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.NotifyMempoolChangedResponse = &NotifyMempoolChangedResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *NotifyMempoolChangedResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyMempoolChangedResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.NotifyMempoolChangedResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
+func (x *KaspadMessage_MempoolChangedNotification) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_MempoolChangedNotification is nil")
+	}
+	return x.MempoolChangedNotification.toAppMessage()
+}
+
+func (x *KaspadMessage_MempoolChangedNotification) fromAppMessage(message *appmessage.MempoolChangedNotificationMessage) error {
+	changes := make([]*RpcMempoolChange, len(message.Changes))
+	for i, change := range message.Changes {
+		changes[i] = &RpcMempoolChange{}
+		changes[i].fromAppMessage(change)
+	}
+	x.MempoolChangedNotification = &MempoolChangedNotificationMessage{
+		Changes: changes,
+	}
+	return nil
+}
+
+func (x *MempoolChangedNotificationMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "MempoolChangedNotificationMessage is nil")
+	}
+	changes := make([]*appmessage.RPCMempoolChange, len(x.Changes))
+	for i, change := range x.Changes {
+		appChange, err := change.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = appChange
+	}
+	return &appmessage.MempoolChangedNotificationMessage{
+		Changes: changes,
+	}, nil
+}
+
+func (x *RpcMempoolChange) toAppMessage() (*appmessage.RPCMempoolChange, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcMempoolChange is nil")
+	}
+	transaction, err := x.Transaction.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &appmessage.RPCMempoolChange{
+		TransactionID: x.TransactionId,
+		Reason:        x.Reason,
+		Transaction:   transaction,
+	}, nil
+}
+
+func (x *RpcMempoolChange) fromAppMessage(message *appmessage.RPCMempoolChange) {
+	transaction := &RpcTransaction{}
+	transaction.fromAppMessage(message.Transaction)
+	*x = RpcMempoolChange{
+		TransactionId: message.TransactionID,
+		Reason:        message.Reason,
+		Transaction:   transaction,
+	}
+}
@@ -0,0 +1,65 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_PrioritiseTransactionRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_PrioritiseTransactionRequest is nil")
+	}
+	return x.PrioritiseTransactionRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_PrioritiseTransactionRequest) fromAppMessage(message *appmessage.PrioritiseTransactionRequestMessage) error {
+	x.PrioritiseTransactionRequest = &PrioritiseTransactionRequestMessage{
+		TransactionId: message.TransactionID,
+		FeeDelta:      message.FeeDelta,
+	}
+	return nil
+}
+
+func (x *PrioritiseTransactionRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "PrioritiseTransactionRequestMessage is nil")
+	}
+	return &appmessage.PrioritiseTransactionRequestMessage{
+		TransactionID: x.TransactionId,
+		FeeDelta:      x.FeeDelta,
+	}, nil
+}
+
+func (x *KaspadMessage_PrioritiseTransactionResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_PrioritiseTransactionResponse is nil")
+	}
+	return x.PrioritiseTransactionResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_PrioritiseTransactionResponse) fromAppMessage(message *appmessage.PrioritiseTransactionResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.PrioritiseTransactionResponse = &PrioritiseTransactionResponseMessage{
+		FeeDelta: message.FeeDelta,
+		Error:    err,
+	}
+	return nil
+}
+
+func (x *PrioritiseTransactionResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "PrioritiseTransactionResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.PrioritiseTransactionResponseMessage{
+		FeeDelta: x.FeeDelta,
+		Error:    rpcErr,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package protowire
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+func (x *KaspadMessage_TestMempoolAcceptRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_TestMempoolAcceptRequest is nil")
+	}
+	return x.TestMempoolAcceptRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_TestMempoolAcceptRequest) fromAppMessage(message *appmessage.TestMempoolAcceptRequestMessage) error {
+	transactions := make([]*RpcTransaction, len(message.Transactions))
+	for i, transaction := range message.Transactions {
+		transactions[i] = &RpcTransaction{}
+		transactions[i].fromAppMessage(transaction)
+	}
+	x.TestMempoolAcceptRequest = &TestMempoolAcceptRequestMessage{
+		Transactions: transactions,
+	}
+	return nil
+}
+
+func (x *TestMempoolAcceptRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "TestMempoolAcceptRequestMessage is nil")
+	}
+	transactions := make([]*appmessage.RPCTransaction, len(x.Transactions))
+	for i, transaction := range x.Transactions {
+		rpcTransaction, err := transaction.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		transactions[i] = rpcTransaction
+	}
+	return &appmessage.TestMempoolAcceptRequestMessage{
+		Transactions: transactions,
+	}, nil
+}
+
+func (x *KaspadMessage_TestMempoolAcceptResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_TestMempoolAcceptResponse is nil")
+	}
+	return x.TestMempoolAcceptResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_TestMempoolAcceptResponse) fromAppMessage(message *appmessage.TestMempoolAcceptResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	acceptances := make([]*RpcMempoolAcceptance, len(message.Acceptances))
+	for i, acceptance := range message.Acceptances {
+		acceptances[i] = &RpcMempoolAcceptance{}
+		acceptances[i].fromAppMessage(acceptance)
+	}
+	x.TestMempoolAcceptResponse = &TestMempoolAcceptResponseMessage{
+		Acceptances: acceptances,
+		Error:       err,
+	}
+	return nil
+}
+
+func (x *TestMempoolAcceptResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "TestMempoolAcceptResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	acceptances := make([]*appmessage.RPCMempoolAcceptance, len(x.Acceptances))
+	for i, acceptance := range x.Acceptances {
+		acceptances[i], err = acceptance.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.TestMempoolAcceptResponseMessage{
+		Acceptances: acceptances,
+		Error:       rpcErr,
+	}, nil
+}
+
+func (x *RpcMempoolAcceptance) toAppMessage() (*appmessage.RPCMempoolAcceptance, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcMempoolAcceptance is nil")
+	}
+	rejections := make([]*appmessage.RPCMempoolRejection, len(x.Rejections))
+	for i, rejection := range x.Rejections {
+		if rejection == nil {
+			return nil, errors.Wrapf(errorNil, "RpcMempoolRejection is nil")
+		}
+		rejections[i] = &appmessage.RPCMempoolRejection{
+			RejectCode: rejection.RejectCode,
+			Reason:     rejection.Reason,
+		}
+	}
+	return &appmessage.RPCMempoolAcceptance{
+		TransactionID: x.TransactionId,
+		IsAccepted:    x.IsAccepted,
+		Mass:          x.Mass,
+		Fee:           x.Fee,
+		FeeRate:       x.FeeRate,
+		Rejections:    rejections,
+	}, nil
+}
+
+func (x *RpcMempoolAcceptance) fromAppMessage(message *appmessage.RPCMempoolAcceptance) {
+	rejections := make([]*RpcMempoolRejection, len(message.Rejections))
+	for i, rejection := range message.Rejections {
+		rejections[i] = &RpcMempoolRejection{
+			RejectCode: rejection.RejectCode,
+			Reason:     rejection.Reason,
+		}
+	}
+	*x = RpcMempoolAcceptance{
+		TransactionId: message.TransactionID,
+		IsAccepted:    message.IsAccepted,
+		Mass:          message.Mass,
+		Fee:           message.Fee,
+		FeeRate:       message.FeeRate,
+		Rejections:    rejections,
+	}
+}
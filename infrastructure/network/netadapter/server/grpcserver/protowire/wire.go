@@ -975,6 +975,13 @@ func toRPCPayload(message appmessage.Message) (isKaspadMessage_Payload, error) {
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.GetFeeEstimateResponseMessage:
+		payload := new(KaspadMessage_GetFeeEstimateResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	case *appmessage.SubmitTransactionReplacementRequestMessage:
 		payload := new(KaspadMessage_SubmitTransactionReplacementRequest)
 		err := payload.fromAppMessage(message)
@@ -989,6 +996,167 @@ func toRPCPayload(message appmessage.Message) (isKaspadMessage_Payload, error) {
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.GetTransactionRequestMessage:
+		payload := new(KaspadMessage_GetTransactionRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetTransactionResponseMessage:
+		payload := new(KaspadMessage_GetTransactionResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetTransactionAcceptanceRequestMessage:
+		payload := new(KaspadMessage_GetTransactionAcceptanceRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetTransactionAcceptanceResponseMessage:
+		payload := new(KaspadMessage_GetTransactionAcceptanceResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetAddressTransactionsRequestMessage:
+		payload := new(KaspadMessage_GetAddressTransactionsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetAddressTransactionsResponseMessage:
+		payload := new(KaspadMessage_GetAddressTransactionsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetEmissionInfoRequestMessage:
+		payload := new(KaspadMessage_GetEmissionInfoRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetEmissionInfoResponseMessage:
+		payload := new(KaspadMessage_GetEmissionInfoResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.SubmitTransactionPackageRequestMessage:
+		payload := new(KaspadMessage_SubmitTransactionPackageRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.SubmitTransactionPackageResponseMessage:
+		payload := new(KaspadMessage_SubmitTransactionPackageResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolInfoRequestMessage:
+		payload := new(KaspadMessage_GetMempoolInfoRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolInfoResponseMessage:
+		payload := new(KaspadMessage_GetMempoolInfoResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.NotifyMempoolChangedRequestMessage:
+		payload := new(KaspadMessage_NotifyMempoolChangedRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.NotifyMempoolChangedResponseMessage:
+		payload := new(KaspadMessage_NotifyMempoolChangedResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.MempoolChangedNotificationMessage:
+		payload := new(KaspadMessage_MempoolChangedNotification)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBlockCandidateStatusRequestMessage:
+		payload := new(KaspadMessage_GetBlockCandidateStatusRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBlockCandidateStatusResponseMessage:
+		payload := new(KaspadMessage_GetBlockCandidateStatusResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDroppedTransactionsRequestMessage:
+		payload := new(KaspadMessage_GetDroppedTransactionsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDroppedTransactionsResponseMessage:
+		payload := new(KaspadMessage_GetDroppedTransactionsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.PrioritiseTransactionRequestMessage:
+		payload := new(KaspadMessage_PrioritiseTransactionRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.PrioritiseTransactionResponseMessage:
+		payload := new(KaspadMessage_PrioritiseTransactionResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.TestMempoolAcceptRequestMessage:
+		payload := new(KaspadMessage_TestMempoolAcceptRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.TestMempoolAcceptResponseMessage:
+		payload := new(KaspadMessage_TestMempoolAcceptResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	default:
 		return nil, nil
 	}
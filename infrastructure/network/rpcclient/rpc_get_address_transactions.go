@@ -0,0 +1,22 @@
+package rpcclient
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// GetAddressTransactions sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetAddressTransactions(address string, pageToken string,
+	limit uint32) (*appmessage.GetAddressTransactionsResponseMessage, error) {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetAddressTransactionsRequestMessage(address, pageToken, limit))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetAddressTransactionsResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getAddressTransactionsResponse := response.(*appmessage.GetAddressTransactionsResponseMessage)
+	if getAddressTransactionsResponse.Error != nil {
+		return nil, c.convertRPCError(getAddressTransactionsResponse.Error)
+	}
+	return getAddressTransactionsResponse, nil
+}
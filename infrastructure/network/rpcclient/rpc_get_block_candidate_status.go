@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// GetBlockCandidateStatus sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetBlockCandidateStatus(transactionID string) (*appmessage.GetBlockCandidateStatusResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBlockCandidateStatusRequestMessage(transactionID))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetBlockCandidateStatusResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getBlockCandidateStatusResponse := response.(*appmessage.GetBlockCandidateStatusResponseMessage)
+	if getBlockCandidateStatusResponse.Error != nil {
+		return nil, c.convertRPCError(getBlockCandidateStatusResponse.Error)
+	}
+	return getBlockCandidateStatusResponse, nil
+}
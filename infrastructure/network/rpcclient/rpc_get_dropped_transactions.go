@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// GetDroppedTransactions sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetDroppedTransactions(transactionIDs []string) (*appmessage.GetDroppedTransactionsResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetDroppedTransactionsRequestMessage(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetDroppedTransactionsResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getDroppedTransactionsResponse := response.(*appmessage.GetDroppedTransactionsResponseMessage)
+	if getDroppedTransactionsResponse.Error != nil {
+		return nil, c.convertRPCError(getDroppedTransactionsResponse.Error)
+	}
+	return getDroppedTransactionsResponse, nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// GetEmissionInfo sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetEmissionInfo() (*appmessage.GetEmissionInfoResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetEmissionInfoRequestMessage())
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetEmissionInfoResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getEmissionInfoResponse := response.(*appmessage.GetEmissionInfoResponseMessage)
+	if getEmissionInfoResponse.Error != nil {
+		return nil, c.convertRPCError(getEmissionInfoResponse.Error)
+	}
+	return getEmissionInfoResponse, nil
+}
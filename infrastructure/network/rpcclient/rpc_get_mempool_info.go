@@ -0,0 +1,21 @@
+package rpcclient
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// GetMempoolInfo sends an RPC request respective to the function's name and returns the RPC server's response.
+// If feeRateBucketBoundaries is empty, the server splits the fee rate histogram at its default boundaries.
+func (c *RPCClient) GetMempoolInfo(feeRateBucketBoundaries []float64) (*appmessage.GetMempoolInfoResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetMempoolInfoRequestMessage(feeRateBucketBoundaries))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetMempoolInfoResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getMempoolInfoResponse := response.(*appmessage.GetMempoolInfoResponseMessage)
+	if getMempoolInfoResponse.Error != nil {
+		return nil, c.convertRPCError(getMempoolInfoResponse.Error)
+	}
+	return getMempoolInfoResponse, nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/stokesnetwork/stokes/app/appmessage"
+
+// GetTransaction sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetTransaction(transactionID string) (*appmessage.GetTransactionResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetTransactionRequestMessage(transactionID))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetTransactionResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getTransactionResponse := response.(*appmessage.GetTransactionResponseMessage)
+	if getTransactionResponse.Error != nil {
+		return nil, c.convertRPCError(getTransactionResponse.Error)
+	}
+	return getTransactionResponse, nil
+}
@@ -0,0 +1,40 @@
+package rpcclient
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	routerpkg "github.com/stokesnetwork/stokes/infrastructure/network/netadapter/router"
+)
+
+// RegisterForMempoolChangedNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
+// Additionally, it starts listening for the appropriate notification using the given handler function
+func (c *RPCClient) RegisterForMempoolChangedNotifications(addresses []string,
+	onMempoolChanged func(notification *appmessage.MempoolChangedNotificationMessage)) error {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewNotifyMempoolChangedRequestMessage(addresses))
+	if err != nil {
+		return err
+	}
+	response, err := c.route(appmessage.CmdNotifyMempoolChangedResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return err
+	}
+	notifyMempoolChangedResponse := response.(*appmessage.NotifyMempoolChangedResponseMessage)
+	if notifyMempoolChangedResponse.Error != nil {
+		return c.convertRPCError(notifyMempoolChangedResponse.Error)
+	}
+	spawn("RegisterForMempoolChangedNotifications", func() {
+		for {
+			notification, err := c.route(appmessage.CmdMempoolChangedNotificationMessage).Dequeue()
+			if err != nil {
+				if errors.Is(err, routerpkg.ErrRouteClosed) {
+					break
+				}
+				panic(err)
+			}
+			mempoolChangedNotification := notification.(*appmessage.MempoolChangedNotificationMessage)
+			onMempoolChanged(mempoolChangedNotification)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,48 @@
+package rpcclient
+
+import (
+	"github.com/stokesnetwork/stokes/app/appmessage"
+)
+
+// SubmitTransactionPackage sends an RPC request respective to the function's name and returns the RPC server's response.
+// transactionIDs are the IDs of the given transactions, in the same order.
+func (c *RPCClient) SubmitTransactionPackage(transactions []*appmessage.RPCTransaction, transactionIDs []string) (*appmessage.SubmitTransactionPackageResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewSubmitTransactionPackageRequestMessage(transactions))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		response, err := c.route(appmessage.CmdSubmitTransactionPackageResponseMessage).DequeueWithTimeout(c.timeout)
+		if err != nil {
+			return nil, err
+		}
+		submitTransactionPackageResponse := response.(*appmessage.SubmitTransactionPackageResponseMessage)
+		// Match the response to the expected IDs. If they are different it means we got an old response which we
+		// previously timed-out on, so we log and continue waiting for the correct current response.
+		// The IDs are missing only if the server couldn't parse the transactions, in which case the
+		// response is taken as the response to this request.
+		isParseError := len(submitTransactionPackageResponse.TransactionIDs) == 0 && submitTransactionPackageResponse.Error != nil
+		if !isParseError && !equalTransactionIDs(submitTransactionPackageResponse.TransactionIDs, transactionIDs) {
+			log.Warnf("SubmitTransactionPackage: received a response for previous request with IDs %s",
+				submitTransactionPackageResponse.TransactionIDs)
+			continue
+		}
+		if submitTransactionPackageResponse.Error != nil {
+			return nil, c.convertRPCError(submitTransactionPackageResponse.Error)
+		}
+
+		return submitTransactionPackageResponse, nil
+	}
+}
+
+func equalTransactionIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
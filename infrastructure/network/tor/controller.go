@@ -0,0 +1,316 @@
+package tor
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	statusOK = 250
+
+	// The keys of the HMACs of SAFECOOKIE authentication, as given in the Tor control protocol spec
+	safeCookieServerKey = "Tor safe cookie authentication server-to-controller hash"
+	safeCookieClientKey = "Tor safe cookie authentication controller-to-server hash"
+
+	safeCookieNonceSize = 32
+	cookieSize          = 32
+)
+
+// Controller is a client of the control port of a Tor process. It isn't safe for concurrent use.
+type Controller struct {
+	connection net.Conn
+	reader     *textproto.Reader
+	timeout    time.Duration
+}
+
+// reply is a reply of the control port: its status and the text of each of its lines
+type reply struct {
+	status int
+	lines  []string
+}
+
+// DialController connects to the Tor control port at the given address. Every command sent
+// through the returned controller has to be answered within the given timeout.
+func DialController(address string, timeout time.Duration) (*Controller, error) {
+	connection, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't connect to the Tor control port at %s", address)
+	}
+	return &Controller{
+		connection: connection,
+		reader:     textproto.NewReader(bufio.NewReader(connection)),
+		timeout:    timeout,
+	}, nil
+}
+
+// Close closes the connection to the control port. Tor removes the onion
+// services that were added through it.
+func (c *Controller) Close() error {
+	return c.connection.Close()
+}
+
+// command sends the given command and returns its reply. Replies with an error status are
+// returned as errors.
+func (c *Controller) command(command string) (*reply, error) {
+	err := c.connection.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.connection.Write([]byte(command + "\r\n"))
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't write to the Tor control port")
+	}
+	reply, err := c.readReply()
+	if err != nil {
+		return nil, err
+	}
+	if reply.status != statusOK {
+		verb := strings.SplitN(command, " ", 2)[0]
+		return nil, errors.Errorf("the Tor control port rejected %s: %d %s",
+			verb, reply.status, strings.Join(reply.lines, " "))
+	}
+	return reply, nil
+}
+
+// readReply reads a reply of the control port. Every line of a reply starts with its status,
+// followed by '-' for mid reply lines, '+' for lines followed by data and ' ' for the last line.
+func (c *Controller) readReply() (*reply, error) {
+	reply := &reply{}
+	for {
+		line, err := c.reader.ReadLine()
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't read from the Tor control port")
+		}
+		if len(line) < 4 {
+			return nil, errors.Errorf("malformed reply line from the Tor control port: %q", line)
+		}
+		status, err := strconv.Atoi(line[:3])
+		if err != nil {
+			return nil, errors.Errorf("malformed reply line from the Tor control port: %q", line)
+		}
+		reply.status = status
+		reply.lines = append(reply.lines, line[4:])
+
+		switch line[3] {
+		case ' ':
+			return reply, nil
+		case '-':
+		case '+':
+			data, err := c.reader.ReadDotLines()
+			if err != nil {
+				return nil, errors.Wrap(err, "couldn't read from the Tor control port")
+			}
+			reply.lines = append(reply.lines, data...)
+		default:
+			return nil, errors.Errorf("malformed reply line from the Tor control port: %q", line)
+		}
+	}
+}
+
+// Authenticate authenticates with the control port with the strongest method it supports:
+// none if it requires none, the given password if one is given, and the cookie file of Tor otherwise
+func (c *Controller) Authenticate(password string) error {
+	reply, err := c.command("PROTOCOLINFO 1")
+	if err != nil {
+		return err
+	}
+	methods := make(map[string]bool)
+	cookieFile := ""
+	for _, line := range reply.lines {
+		if !strings.HasPrefix(line, "AUTH ") {
+			continue
+		}
+		arguments := parseArguments(strings.TrimPrefix(line, "AUTH "))
+		for _, method := range strings.Split(arguments["METHODS"], ",") {
+			methods[method] = true
+		}
+		cookieFile = arguments["COOKIEFILE"]
+	}
+
+	switch {
+	case methods["NULL"]:
+		_, err = c.command("AUTHENTICATE")
+	case password != "" && methods["HASHEDPASSWORD"]:
+		_, err = c.command("AUTHENTICATE " + quote(password))
+	case methods["SAFECOOKIE"] && cookieFile != "":
+		err = c.authenticateSafeCookie(cookieFile)
+	case methods["COOKIE"] && cookieFile != "":
+		var cookie []byte
+		cookie, err = readCookie(cookieFile)
+		if err == nil {
+			_, err = c.command("AUTHENTICATE " + hex.EncodeToString(cookie))
+		}
+	case methods["HASHEDPASSWORD"]:
+		return errors.New("the Tor control port requires a password - set it with --torpassword")
+	default:
+		return errors.New("the Tor control port supports none of the authentication methods we know")
+	}
+	return err
+}
+
+// authenticateSafeCookie authenticates by proving knowledge of the cookie without sending it,
+// and makes sure the control port knows it too
+func (c *Controller) authenticateSafeCookie(cookieFile string) error {
+	cookie, err := readCookie(cookieFile)
+	if err != nil {
+		return err
+	}
+	clientNonce := make([]byte, safeCookieNonceSize)
+	_, err = rand.Read(clientNonce)
+	if err != nil {
+		return err
+	}
+
+	reply, err := c.command("AUTHCHALLENGE SAFECOOKIE " + hex.EncodeToString(clientNonce))
+	if err != nil {
+		return err
+	}
+	arguments := parseArguments(strings.TrimPrefix(reply.lines[len(reply.lines)-1], "AUTHCHALLENGE "))
+	serverHash, err := hex.DecodeString(arguments["SERVERHASH"])
+	if err != nil {
+		return errors.New("malformed SERVERHASH in AUTHCHALLENGE reply")
+	}
+	serverNonce, err := hex.DecodeString(arguments["SERVERNONCE"])
+	if err != nil || len(serverNonce) == 0 {
+		return errors.New("malformed SERVERNONCE in AUTHCHALLENGE reply")
+	}
+
+	message := make([]byte, 0, len(cookie)+len(clientNonce)+len(serverNonce))
+	message = append(message, cookie...)
+	message = append(message, clientNonce...)
+	message = append(message, serverNonce...)
+	if !hmac.Equal(serverHash, safeCookieHMAC(safeCookieServerKey, message)) {
+		return errors.New("the Tor control port doesn't know the authentication cookie")
+	}
+	_, err = c.command("AUTHENTICATE " + hex.EncodeToString(safeCookieHMAC(safeCookieClientKey, message)))
+	return err
+}
+
+func safeCookieHMAC(key string, message []byte) []byte {
+	hasher := hmac.New(sha256.New, []byte(key))
+	hasher.Write(message)
+	return hasher.Sum(nil)
+}
+
+func readCookie(cookieFile string) ([]byte, error) {
+	cookie, err := os.ReadFile(cookieFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read the Tor authentication cookie")
+	}
+	if len(cookie) != cookieSize {
+		return nil, errors.Errorf("the Tor authentication cookie at %s is %d bytes long, but expected %d bytes",
+			cookieFile, len(cookie), cookieSize)
+	}
+	return cookie, nil
+}
+
+// AddOnion adds an onion service that forwards connections to virtualPort to the given target.
+// privateKey is the key of the service as returned by an earlier call, or "" for a new service.
+// Returns the ID of the service - its host name without ".onion" - and the private key of a new service.
+func (c *Controller) AddOnion(privateKey string, virtualPort uint16, target string) (serviceID string,
+	newPrivateKey string, err error) {
+
+	key := "NEW:ED25519-V3"
+	if privateKey != "" {
+		key = privateKey
+	}
+	reply, err := c.command(fmt.Sprintf("ADD_ONION %s Port=%d,%s", key, virtualPort, target))
+	if err != nil {
+		return "", "", err
+	}
+	for _, line := range reply.lines {
+		switch {
+		case strings.HasPrefix(line, "ServiceID="):
+			serviceID = strings.TrimPrefix(line, "ServiceID=")
+		case strings.HasPrefix(line, "PrivateKey="):
+			newPrivateKey = strings.TrimPrefix(line, "PrivateKey=")
+		}
+	}
+	if serviceID == "" {
+		return "", "", errors.New("the Tor control port didn't return the ID of the onion service")
+	}
+	return serviceID, newPrivateKey, nil
+}
+
+// DelOnion removes the onion service with the given ID
+func (c *Controller) DelOnion(serviceID string) error {
+	_, err := c.command("DEL_ONION " + serviceID)
+	return err
+}
+
+// Ping makes sure the control port is still there
+func (c *Controller) Ping() error {
+	_, err := c.command("GETINFO version")
+	return err
+}
+
+// parseArguments parses the KEY=VALUE arguments of a reply line, where values may be quoted
+func parseArguments(line string) map[string]string {
+	arguments := make(map[string]string)
+	for len(line) > 0 {
+		line = strings.TrimLeft(line, " ")
+		equals := strings.IndexAny(line, "= ")
+		if equals < 0 || line[equals] == ' ' {
+			// An argument without a value
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				break
+			}
+			line = line[end:]
+			continue
+		}
+		key := line[:equals]
+		line = line[equals+1:]
+
+		value := ""
+		if strings.HasPrefix(line, `"`) {
+			value, line = unquote(line)
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			value, line = line[:end], line[end:]
+		}
+		arguments[key] = value
+	}
+	return arguments
+}
+
+// unquote returns the value of the quoted string at the start of the given line, and the rest of the line
+func unquote(line string) (value string, rest string) {
+	var builder strings.Builder
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				builder.WriteByte(line[i])
+			}
+		case '"':
+			return builder.String(), line[i+1:]
+		default:
+			builder.WriteByte(line[i])
+		}
+	}
+	return builder.String(), ""
+}
+
+// quote returns the given string as a quoted string of the control protocol
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,370 @@
+package tor
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/util/mstime"
+)
+
+// testServiceKey is the public key of the onion service published by fakeControlPort
+var testServiceKey = []byte{
+	0xd1, 0xb3, 0x8b, 0x83, 0xa8, 0x3b, 0x3e, 0xd9, 0x18, 0xc5, 0xbb, 0x69, 0xdd, 0x44, 0x4a, 0xd5,
+	0x6b, 0xc8, 0xd5, 0x83, 0x5a, 0x91, 0x4d, 0xe7, 0x34, 0x47, 0x47, 0x4e, 0x5f, 0x02, 0x59, 0x1b,
+}
+
+const testPrivateKey = "ED25519-V3:c2VjcmV0"
+
+// fakeControlPort is a stand-in for the control port of Tor, listening on a local TCP port.
+// It supports the given authentication methods, and publishes testServiceKey for ADD_ONION.
+type fakeControlPort struct {
+	t          *testing.T
+	listener   net.Listener
+	methods    string
+	password   string
+	cookieFile string
+	cookie     []byte
+
+	lock       sync.Mutex
+	commands   []string
+	connection net.Conn
+	services   map[string]string
+}
+
+func newFakeControlPort(t *testing.T, methods string) *fakeControlPort {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	controlPort := &fakeControlPort{
+		t:          t,
+		listener:   listener,
+		methods:    methods,
+		password:   "correct horse",
+		cookieFile: filepath.Join(t.TempDir(), "control_auth_cookie"),
+		cookie:     []byte(strings.Repeat("c", cookieSize)),
+		services:   make(map[string]string),
+	}
+	err = os.WriteFile(controlPort.cookieFile, controlPort.cookie, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	go controlPort.serve()
+	return controlPort
+}
+
+func (controlPort *fakeControlPort) address() string {
+	return controlPort.listener.Addr().String()
+}
+
+func (controlPort *fakeControlPort) close() {
+	controlPort.listener.Close()
+	controlPort.dropConnection()
+}
+
+// dropConnection closes the current connection to the control port, as happens when Tor restarts
+func (controlPort *fakeControlPort) dropConnection() {
+	controlPort.lock.Lock()
+	defer controlPort.lock.Unlock()
+
+	if controlPort.connection != nil {
+		controlPort.connection.Close()
+	}
+	// Tor removes the services of the connection
+	controlPort.services = make(map[string]string)
+}
+
+func (controlPort *fakeControlPort) receivedCommands() []string {
+	controlPort.lock.Lock()
+	defer controlPort.lock.Unlock()
+
+	return append([]string(nil), controlPort.commands...)
+}
+
+func (controlPort *fakeControlPort) serviceCount() int {
+	controlPort.lock.Lock()
+	defer controlPort.lock.Unlock()
+
+	return len(controlPort.services)
+}
+
+func (controlPort *fakeControlPort) serve() {
+	for {
+		connection, err := controlPort.listener.Accept()
+		if err != nil {
+			return
+		}
+		controlPort.lock.Lock()
+		controlPort.connection = connection
+		controlPort.lock.Unlock()
+		controlPort.serveConnection(connection)
+	}
+}
+
+func (controlPort *fakeControlPort) serveConnection(connection net.Conn) {
+	defer connection.Close()
+
+	reader := bufio.NewReader(connection)
+	authenticated := false
+	var clientNonce, serverNonce []byte
+	write := func(lines ...string) {
+		_, _ = connection.Write([]byte(strings.Join(lines, "\r\n") + "\r\n"))
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		controlPort.lock.Lock()
+		controlPort.commands = append(controlPort.commands, line)
+		controlPort.lock.Unlock()
+		verb, argument, _ := strings.Cut(line, " ")
+
+		switch {
+		case verb == "PROTOCOLINFO":
+			write("250-PROTOCOLINFO 1",
+				fmt.Sprintf(`250-AUTH METHODS=%s COOKIEFILE="%s"`,
+					controlPort.methods, strings.ReplaceAll(controlPort.cookieFile, `"`, `\"`)),
+				`250-VERSION Tor="0.4.8.9"`,
+				"250 OK")
+
+		case verb == "AUTHCHALLENGE":
+			clientNonce, err = hex.DecodeString(strings.TrimPrefix(argument, "SAFECOOKIE "))
+			if err != nil {
+				write("513 Invalid base16 client nonce")
+				continue
+			}
+			serverNonce = []byte(strings.Repeat("s", safeCookieNonceSize))
+			serverHash := safeCookieHMAC(safeCookieServerKey, controlPort.safeCookieMessage(clientNonce, serverNonce))
+			write(fmt.Sprintf("250 AUTHCHALLENGE SERVERHASH=%s SERVERNONCE=%s",
+				strings.ToUpper(hex.EncodeToString(serverHash)), hex.EncodeToString(serverNonce)))
+
+		case verb == "AUTHENTICATE":
+			authenticated = controlPort.checkAuthentication(argument, clientNonce, serverNonce)
+			if !authenticated {
+				write("515 Authentication failed: Wrong length on authentication cookie.")
+				return
+			}
+			write("250 OK")
+
+		case !authenticated:
+			write("514 Authentication required.")
+			return
+
+		case verb == "ADD_ONION":
+			if !strings.HasSuffix(argument, " Port=16111,127.0.0.1:16111") {
+				write("512 Invalid VIRTPORT/TARGET")
+				continue
+			}
+			serviceAddress, _ := appmessage.NewOverlayNetAddress(mstime.Now(), appmessage.NetworkIDTorV3,
+				testServiceKey, 16111)
+			serviceID := strings.TrimSuffix(serviceAddress.Host(), ".onion")
+			controlPort.lock.Lock()
+			controlPort.services[serviceID] = argument
+			controlPort.lock.Unlock()
+			if strings.HasPrefix(argument, "NEW:") {
+				write("250-ServiceID="+serviceID, "250-PrivateKey="+testPrivateKey, "250 OK")
+			} else {
+				write("250-ServiceID="+serviceID, "250 OK")
+			}
+
+		case verb == "DEL_ONION":
+			controlPort.lock.Lock()
+			_, ok := controlPort.services[argument]
+			delete(controlPort.services, argument)
+			controlPort.lock.Unlock()
+			if !ok {
+				write("552 Unknown Onion Service id")
+				continue
+			}
+			write("250 OK")
+
+		case verb == "GETINFO":
+			write("250-version=0.4.8.9", "250 OK")
+
+		default:
+			write(fmt.Sprintf(`510 Unrecognized command "%s"`, verb))
+		}
+	}
+}
+
+func (controlPort *fakeControlPort) safeCookieMessage(clientNonce []byte, serverNonce []byte) []byte {
+	message := append([]byte(nil), controlPort.cookie...)
+	message = append(message, clientNonce...)
+	return append(message, serverNonce...)
+}
+
+func (controlPort *fakeControlPort) checkAuthentication(argument string, clientNonce []byte, serverNonce []byte) bool {
+	switch {
+	case strings.Contains(controlPort.methods, "NULL"):
+		return true
+	case strings.HasPrefix(argument, `"`):
+		password, _ := unquote(argument)
+		return strings.Contains(controlPort.methods, "HASHEDPASSWORD") && password == controlPort.password
+	}
+	response, err := hex.DecodeString(argument)
+	if err != nil {
+		return false
+	}
+	if clientNonce != nil {
+		expected := safeCookieHMAC(safeCookieClientKey, controlPort.safeCookieMessage(clientNonce, serverNonce))
+		return hmac.Equal(response, expected)
+	}
+	return strings.Contains(controlPort.methods, "COOKIE") && hmac.Equal(response, controlPort.cookie)
+}
+
+func TestControllerAuthentication(t *testing.T) {
+	tests := []struct {
+		methods        string
+		password       string
+		expectedMethod string
+		expectedError  string
+	}{
+		{methods: "NULL", expectedMethod: "AUTHENTICATE"},
+		{methods: "HASHEDPASSWORD", password: "correct horse", expectedMethod: `AUTHENTICATE "correct horse"`},
+		{methods: "HASHEDPASSWORD", password: "wrong horse", expectedError: "515 Authentication failed"},
+		{methods: "HASHEDPASSWORD", expectedError: "requires a password"},
+		{methods: "COOKIE,SAFECOOKIE", expectedMethod: "AUTHCHALLENGE SAFECOOKIE"},
+		{methods: "COOKIE,SAFECOOKIE,HASHEDPASSWORD", password: "correct horse", expectedMethod: `AUTHENTICATE "correct horse"`},
+		{methods: "COOKIE", expectedMethod: "AUTHENTICATE " + hex.EncodeToString([]byte(strings.Repeat("c", cookieSize)))},
+		{methods: "SOMETHINGNEW", expectedError: "none of the authentication methods"},
+	}
+
+	for _, test := range tests {
+		controlPort := newFakeControlPort(t, test.methods)
+		controller, err := DialController(controlPort.address(), 5*time.Second)
+		if err != nil {
+			t.Fatalf("DialController: %s", err)
+		}
+		err = controller.Authenticate(test.password)
+		controller.Close()
+		controlPort.close()
+
+		if test.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Fatalf("%s: expected an error containing %q, but got %v", test.methods, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Authenticate: %s", test.methods, err)
+		}
+		commands := controlPort.receivedCommands()
+		if len(commands) < 2 || !strings.HasPrefix(commands[1], test.expectedMethod) {
+			t.Fatalf("%s: expected authentication with %q, but got commands %q", test.methods, test.expectedMethod, commands)
+		}
+	}
+}
+
+func TestParseArguments(t *testing.T) {
+	arguments := parseArguments(`METHODS=COOKIE,SAFECOOKIE FLAG COOKIEFILE="/var/lib/tor/a \"quoted\" \\ path" LAST=1`)
+	expected := map[string]string{
+		"METHODS":    "COOKIE,SAFECOOKIE",
+		"COOKIEFILE": `/var/lib/tor/a "quoted" \ path`,
+		"LAST":       "1",
+	}
+	if len(arguments) != len(expected) {
+		t.Fatalf("Expected arguments %q, but got %q", expected, arguments)
+	}
+	for key, value := range expected {
+		if arguments[key] != value {
+			t.Fatalf("Expected %s=%q, but got %q", key, value, arguments[key])
+		}
+	}
+	if quote(`a "b" \c`) != `"a \"b\" \\c"` {
+		t.Fatalf("Unexpected quoted string %s", quote(`a "b" \c`))
+	}
+}
+
+func TestOnionService(t *testing.T) {
+	controlPort := newFakeControlPort(t, "COOKIE,SAFECOOKIE")
+	defer controlPort.close()
+
+	privateKeyPath := filepath.Join(t.TempDir(), "onion_v3_private_key")
+	addresses := make(chan *appmessage.NetAddress, 10)
+	service := NewOnionService(controlPort.address(), "", privateKeyPath, 16111, "127.0.0.1:16111",
+		func(netAddress *appmessage.NetAddress) {
+			addresses <- netAddress
+		})
+	service.checkInterval = 10 * time.Millisecond
+	service.Start()
+
+	var serviceAddress *appmessage.NetAddress
+	select {
+	case serviceAddress = <-addresses:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the onion service to be published")
+	}
+	if serviceAddress.NetworkID != appmessage.NetworkIDTorV3 || serviceAddress.Port != 16111 ||
+		string(serviceAddress.OverlayAddress) != string(testServiceKey) {
+		t.Fatalf("Unexpected onion service address %s", serviceAddress)
+	}
+	privateKey, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		t.Fatalf("The private key of the onion service wasn't saved: %s", err)
+	}
+	if strings.TrimSpace(string(privateKey)) != testPrivateKey {
+		t.Fatalf("Unexpected private key %s", privateKey)
+	}
+
+	// When Tor restarts, the service is published again under the same address, which isn't reported again
+	controlPort.dropConnection()
+	waitFor(t, "the onion service to be published again", func() bool {
+		return controlPort.serviceCount() == 1
+	})
+	select {
+	case netAddress := <-addresses:
+		t.Fatalf("Unexpected onion service address %s", netAddress)
+	default:
+	}
+
+	service.Stop()
+	if controlPort.serviceCount() != 0 {
+		t.Fatalf("Expected the onion service to be removed when the service stops")
+	}
+
+	// The saved private key is used after a restart
+	service = NewOnionService(controlPort.address(), "", privateKeyPath, 16111, "127.0.0.1:16111",
+		func(netAddress *appmessage.NetAddress) {
+			addresses <- netAddress
+		})
+	service.Start()
+	defer service.Stop()
+	select {
+	case <-addresses:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the onion service to be published")
+	}
+	commands := controlPort.receivedCommands()
+	lastAddOnion := ""
+	for _, command := range commands {
+		if strings.HasPrefix(command, "ADD_ONION") {
+			lastAddOnion = command
+		}
+	}
+	if lastAddOnion != "ADD_ONION "+testPrivateKey+" Port=16111,127.0.0.1:16111" {
+		t.Fatalf("Expected the saved private key to be used, but got %s", lastAddOnion)
+	}
+}
+
+func waitFor(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", description)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,9 @@
+package tor
+
+import (
+	"github.com/stokesnetwork/stokes/infrastructure/logger"
+	"github.com/stokesnetwork/stokes/util/panics"
+)
+
+var log = logger.RegisterSubSystem("TORC")
+var spawn = panics.GoroutineWrapperFunc(log)
@@ -0,0 +1,199 @@
+package tor
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stokesnetwork/stokes/app/appmessage"
+	"github.com/stokesnetwork/stokes/util/mstime"
+)
+
+const (
+	// controlTimeout is how long the control port has to answer a command
+	controlTimeout = 10 * time.Second
+
+	// checkInterval is how often the connection to the control port is checked, and how long to
+	// wait before trying again after failing to publish the onion service
+	checkInterval = time.Minute
+)
+
+// OnionService publishes an onion service for a TCP port through the Tor control port, and keeps
+// it published for as long as it's running, so that peers can connect to this node through Tor.
+// The service is tied to the connection to the control port, so Tor removes it if the node dies.
+type OnionService struct {
+	controlAddress string
+	password       string
+	privateKeyPath string
+	virtualPort    uint16
+	target         string
+	onAddress      func(netAddress *appmessage.NetAddress)
+
+	checkInterval time.Duration
+	stop          chan struct{}
+	done          chan struct{}
+	stopOnce      sync.Once
+	started       int32
+	controller    *Controller
+	serviceID     string
+	reportedID    string
+}
+
+// NewOnionService returns an OnionService that forwards connections to virtualPort of the
+// service to the given target address. The private key of the service is kept in
+// privateKeyPath, so that its address stays the same across restarts. onAddress is called
+// with the address of the service whenever it's published under a new one.
+// Use Start() to publish the service
+func NewOnionService(controlAddress string, password string, privateKeyPath string, virtualPort uint16,
+	target string, onAddress func(netAddress *appmessage.NetAddress)) *OnionService {
+
+	return &OnionService{
+		controlAddress: controlAddress,
+		password:       password,
+		privateKeyPath: privateKeyPath,
+		virtualPort:    virtualPort,
+		target:         target,
+		onAddress:      onAddress,
+		checkInterval:  checkInterval,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// Start begins publishing the onion service
+func (service *OnionService) Start() {
+	if !atomic.CompareAndSwapInt32(&service.started, 0, 1) {
+		return
+	}
+	spawn("OnionService.run", service.run)
+}
+
+// Stop removes the onion service and disconnects from the control port
+func (service *OnionService) Stop() {
+	service.stopOnce.Do(func() {
+		close(service.stop)
+	})
+	if atomic.LoadInt32(&service.started) == 1 {
+		<-service.done
+	}
+}
+
+func (service *OnionService) run() {
+	defer close(service.done)
+	defer service.unpublish()
+
+	for {
+		err := service.check()
+		if err != nil {
+			log.Warnf("Failed to publish the onion service through the Tor control port at %s, "+
+				"retrying in %s: %s", service.controlAddress, service.checkInterval, err)
+			service.disconnect()
+		}
+
+		select {
+		case <-service.stop:
+			return
+		case <-time.After(service.checkInterval):
+		}
+	}
+}
+
+// check publishes the onion service if it isn't published, or makes sure that the control port,
+// and with it the service, is still there if it is
+func (service *OnionService) check() error {
+	if service.controller != nil {
+		return service.controller.Ping()
+	}
+	return service.publish()
+}
+
+func (service *OnionService) publish() error {
+	controller, err := DialController(service.controlAddress, controlTimeout)
+	if err != nil {
+		return err
+	}
+	service.controller = controller
+	err = controller.Authenticate(service.password)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := service.loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	serviceID, newPrivateKey, err := controller.AddOnion(privateKey, service.virtualPort, service.target)
+	if err != nil {
+		return err
+	}
+	service.serviceID = serviceID
+	if newPrivateKey != "" {
+		err := service.savePrivateKey(newPrivateKey)
+		if err != nil {
+			// The service works either way, it's only going to have a new address after a restart
+			log.Warnf("Failed to save the private key of the onion service: %s", err)
+		}
+	}
+
+	networkID, overlayAddress, err := appmessage.DecodeOverlayHost(serviceID + ".onion")
+	if err != nil {
+		return err
+	}
+	netAddress, err := appmessage.NewOverlayNetAddress(mstime.Now(), networkID, overlayAddress, service.virtualPort)
+	if err != nil {
+		return err
+	}
+	if serviceID == service.reportedID {
+		log.Infof("Published the onion service %s again", netAddress)
+		return nil
+	}
+	service.reportedID = serviceID
+	log.Infof("Published the onion service %s, forwarding to %s", netAddress, service.target)
+	service.onAddress(netAddress)
+	return nil
+}
+
+func (service *OnionService) unpublish() {
+	if service.controller == nil {
+		return
+	}
+	if service.serviceID != "" {
+		err := service.controller.DelOnion(service.serviceID)
+		if err != nil {
+			log.Warnf("Failed to remove the onion service: %s", err)
+		} else {
+			log.Infof("Removed the onion service %s.onion", service.serviceID)
+		}
+	}
+	service.disconnect()
+}
+
+func (service *OnionService) disconnect() {
+	if service.controller == nil {
+		return
+	}
+	err := service.controller.Close()
+	if err != nil {
+		log.Debugf("Failed to close the connection to the Tor control port: %s", err)
+	}
+	service.controller = nil
+	service.serviceID = ""
+}
+
+func (service *OnionService) loadPrivateKey() (string, error) {
+	privateKey, err := os.ReadFile(service.privateKeyPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "couldn't read the private key of the onion service")
+	}
+	return strings.TrimSpace(string(privateKey)), nil
+}
+
+func (service *OnionService) savePrivateKey(privateKey string) error {
+	return os.WriteFile(service.privateKeyPath, []byte(privateKey+"\n"), 0600)
+}
@@ -12,6 +12,7 @@ import (
 const (
 	mempoolSizeLimit        = 1_000_000
 	overfillMempoolByAmount = 1_000
+	highFeeAmount           = 1_000
 )
 
 func TestMempoolLimits(t *testing.T) {
@@ -41,7 +42,7 @@ func TestMempoolLimits(t *testing.T) {
 
 	// Fill up the mempool to the brim
 	submitAnAmountOfTransactionsToTheMempool(t, rpcClient, payAddressKeyPair,
-		payToPayAddressScript, fundingTransactions, mempoolSizeLimit, false)
+		payToPayAddressScript, fundingTransactions, mempoolSizeLimit, transactionFee, false)
 
 	// Make sure that the mempool size is exactly the limit
 	mempoolSize := getMempoolSize(t, rpcClient)
@@ -50,14 +51,19 @@ func TestMempoolLimits(t *testing.T) {
 			mempoolSizeLimit, mempoolSize)
 	}
 
-	// Add some more transactions to the mempool. We expect the
-	// mempool to either not grow or even to shrink, since an eviction
-	// may also remove any dependant (chained) transactions.
-	// Note that we pass ignoreOrphanRejects: true because we
+	// Add some more transactions to the mempool, paying the same fee
+	// rate as the transactions already in it. We expect the mempool to
+	// either not grow or even to shrink, since an eviction may also
+	// remove any dependant (chained) transactions.
+	// Note that we pass ignoreFullMempoolRejects: true because we
 	// expect some of the submitted transactions to depend on
-	// transactions that had been evicted from the mempool
-	submitAnAmountOfTransactionsToTheMempool(t, rpcClient, payAddressKeyPair,
-		payToPayAddressScript, fundingTransactions, overfillMempoolByAmount, true)
+	// transactions that had been evicted from the mempool, and the
+	// rest to be rejected for not paying more than the evicted ones
+	_, insufficientFeeRejectCount := submitAnAmountOfTransactionsToTheMempool(t, rpcClient, payAddressKeyPair,
+		payToPayAddressScript, fundingTransactions, overfillMempoolByAmount, transactionFee, true)
+	if insufficientFeeRejectCount == 0 {
+		t.Fatalf("Expected transactions paying the evicted fee rate to be rejected by the full mempool")
+	}
 
 	// Make sure that the mempool size is the limit or smaller
 	mempoolSize = getMempoolSize(t, rpcClient)
@@ -66,6 +72,23 @@ func TestMempoolLimits(t *testing.T) {
 			mempoolSizeLimit, mempoolSize)
 	}
 
+	// Transactions that pay a higher fee rate than the rest are accepted
+	// into the full mempool, and the transactions with the lowest fee
+	// rate are evicted in their place
+	highFeeTransactionIDs, _ := submitAnAmountOfTransactionsToTheMempool(t, rpcClient, payAddressKeyPair,
+		payToPayAddressScript, fundingTransactions, highFeeAmount, highTransactionFee, false)
+	for _, transactionID := range highFeeTransactionIDs {
+		_, err := rpcClient.GetMempoolEntry(transactionID, false, false)
+		if err != nil {
+			t.Fatalf("Expected high fee transaction %s to remain in the mempool: %+v", transactionID, err)
+		}
+	}
+	mempoolSize = getMempoolSize(t, rpcClient)
+	if mempoolSize > mempoolSizeLimit {
+		t.Fatalf("Unexpected mempool size. Want at most: %d, got: %d",
+			mempoolSizeLimit, mempoolSize)
+	}
+
 	// Empty mempool out by continuously adding blocks to the DAG
 	emptyOutMempool(t, rpcClient)
 
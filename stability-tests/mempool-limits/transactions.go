@@ -26,6 +26,7 @@ const (
 	fundingCoinbaseTransactionAmount = 1000
 	outputsPerTransaction            = 3
 	transactionFee                   = 1000
+	highTransactionFee               = 10 * transactionFee
 	coinbaseMaturity                 = 100
 )
 
@@ -60,9 +61,13 @@ func generateFundingCoinbaseTransactions(t *testing.T, rpcClient *rpcclient.RPCC
 	return fundingCoinbaseTransactions
 }
 
+// submitAnAmountOfTransactionsToTheMempool submits amountToSubmit transactions that pay the given fee
+// each. It returns the IDs of the transactions that were accepted and the number of transactions that
+// were rejected for paying an insufficient fee, which is allowed only if ignoreFullMempoolRejects is set.
 func submitAnAmountOfTransactionsToTheMempool(t *testing.T, rpcClient *rpcclient.RPCClient,
 	payAddressKeyPair *secp256k1.SchnorrKeyPair, payToPayAddressScript *externalapi.ScriptPublicKey,
-	fundingTransactions *fundingCoinbaseTransactions, amountToSubmit int, ignoreOrphanRejects bool) {
+	fundingTransactions *fundingCoinbaseTransactions, amountToSubmit int, fee uint64, ignoreFullMempoolRejects bool) (
+	acceptedTransactionIDs []string, insufficientFeeRejectCount int) {
 
 	log.Infof("Generating %d transactions", amountToSubmit)
 	transactions := make([]*externalapi.DomainTransaction, 0)
@@ -74,7 +79,8 @@ func submitAnAmountOfTransactionsToTheMempool(t *testing.T, rpcClient *rpcclient
 		for len(transactions) < amountToSubmit && len(unspentTransactions) > 0 {
 			var transactionToSpend *externalapi.DomainTransaction
 			transactionToSpend, unspentTransactions = unspentTransactions[0], unspentTransactions[1:]
-			spendingTransactions := generateTransactionsWithMultipleOutputs(t, payAddressKeyPair, payToPayAddressScript, transactionToSpend)
+			spendingTransactions := generateTransactionsWithMultipleOutputs(t, payAddressKeyPair, payToPayAddressScript,
+				transactionToSpend, fee)
 			transactions = append(transactions, spendingTransactions...)
 			unspentTransactions = append(unspentTransactions, spendingTransactions...)
 		}
@@ -86,15 +92,23 @@ func submitAnAmountOfTransactionsToTheMempool(t *testing.T, rpcClient *rpcclient
 
 	for i, transaction := range transactions {
 		rpcTransaction := appmessage.DomainTransactionToRPCTransaction(transaction)
-		_, err := rpcClient.SubmitTransaction(rpcTransaction, consensushashing.TransactionID(transaction).String(), false)
+		transactionID := consensushashing.TransactionID(transaction).String()
+		_, err := rpcClient.SubmitTransaction(rpcTransaction, transactionID, false)
 		if err != nil {
-			if ignoreOrphanRejects && strings.Contains(err.Error(), "orphan") {
+			// Once transactions are evicted, the transactions that spend them are orphans
+			if ignoreFullMempoolRejects && strings.Contains(err.Error(), "orphan") {
+				continue
+			}
+			if ignoreFullMempoolRejects && strings.Contains(err.Error(), "mempool is full") {
+				insufficientFeeRejectCount++
 				continue
 			}
 			t.Fatalf("SubmitTransaction: %+v", err)
 		}
+		acceptedTransactionIDs = append(acceptedTransactionIDs, transactionID)
 		log.Infof("Submitted %d transactions", i+1)
 	}
+	return acceptedTransactionIDs, insufficientFeeRejectCount
 }
 
 func mineBlockAndGetCoinbaseTransaction(t *testing.T, rpcClient *rpcclient.RPCClient) *externalapi.DomainTransaction {
@@ -116,14 +130,14 @@ func mineBlockAndGetCoinbaseTransaction(t *testing.T, rpcClient *rpcclient.RPCCl
 
 func generateTransactionsWithMultipleOutputs(t *testing.T,
 	payAddressKeyPair *secp256k1.SchnorrKeyPair, payToPayAddressScript *externalapi.ScriptPublicKey,
-	fundingTransaction *externalapi.DomainTransaction) []*externalapi.DomainTransaction {
+	fundingTransaction *externalapi.DomainTransaction, fee uint64) []*externalapi.DomainTransaction {
 
 	var transactions []*externalapi.DomainTransaction
 	for fundingTransactionOutputIndex, fundingTransactionOutput := range fundingTransaction.Outputs {
-		if fundingTransactionOutput.Value < transactionFee {
+		if fundingTransactionOutput.Value < fee {
 			continue
 		}
-		outputValue := (fundingTransactionOutput.Value - transactionFee) / outputsPerTransaction
+		outputValue := (fundingTransactionOutput.Value - fee) / outputsPerTransaction
 
 		fundingTransactionID := consensushashing.TransactionID(fundingTransaction)
 		spendingTransactionInputs := []*externalapi.DomainTransactionInput{